@@ -1,10 +1,69 @@
+// Command xk6-tempo provides helper tooling for the xk6-tempo k6 extension.
+//
+// The extension itself is registered by importing github.com/rvargasp/xk6-tempo
+// in an xk6 build; this binary only hosts auxiliary subcommands such as scaffold.
 package main
 
 import (
-	"github.com/rvargasp/xk6-tempo/pkg/tempo"
-	"go.k6.io/k6/js/modules"
+	"fmt"
+	"os"
 )
 
-func init() {
-	modules.Register("k6/x/tempo", tempo.New)
+// command is a subcommand entry point
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{
+		name:    "scaffold",
+		summary: "Generate a ready-to-run k6 script for a common scenario",
+		run:     runScaffold,
+	},
+	{
+		name:    "types",
+		summary: "Generate TypeScript definitions for the k6/x/tempo JS API",
+		run:     runTypes,
+	},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		usage()
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "xk6-tempo %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "xk6-tempo: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage prints the list of available subcommands
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: xk6-tempo <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'xk6-tempo <command> -h' for command flags.")
 }
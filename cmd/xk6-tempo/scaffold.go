@@ -0,0 +1,135 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/rvargasp/xk6-tempo/pkg/tempo"
+)
+
+//go:embed templates/*.js.tmpl
+var scaffoldTemplates embed.FS
+
+// scenarios maps scenario names to their template file and description
+var scenarios = map[string]struct {
+	file        string
+	description string
+}{
+	"ingest":       {file: "ingest.js.tmpl", description: "Ingestion only, paced to a target MB/s"},
+	"query":        {file: "query.js.tmpl", description: "Query workload only, paced to a target QPS"},
+	"mixed":        {file: "mixed.js.tmpl", description: "Concurrent ingestion and query workloads"},
+	"verification": {file: "verification.js.tmpl", description: "Ingest marker traces and check they become searchable"},
+}
+
+// scaffoldParams holds the values substituted into scenario templates
+type scaffoldParams struct {
+	Scenario       string
+	IngestEndpoint string
+	Protocol       string
+	QueryEndpoint  string
+	Tenant         string
+	TargetMBps     float64
+	TargetQPS      float64
+	Duration       string
+	VUs            int
+	MaxVUs         int
+}
+
+// runScaffold implements the scaffold subcommand
+func runScaffold(args []string) error {
+	fs := flag.NewFlagSet("scaffold", flag.ContinueOnError)
+	params := scaffoldParams{}
+	output := ""
+
+	fs.StringVar(&params.Scenario, "scenario", "ingest", "scenario to generate: "+strings.Join(scenarioNames(), ", "))
+	fs.StringVar(&params.IngestEndpoint, "endpoint", "http://localhost:4318", "Tempo ingestion endpoint (OTLP)")
+	fs.StringVar(&params.Protocol, "protocol", "otlp-http", "ingestion protocol: "+strings.Join(tempo.Protocols, ", "))
+	fs.StringVar(&params.QueryEndpoint, "query-endpoint", "http://localhost:3200", "Tempo query endpoint")
+	fs.StringVar(&params.Tenant, "tenant", "", "tenant ID sent as X-Scope-OrgID")
+	fs.Float64Var(&params.TargetMBps, "target-mbps", 1.0, "target ingestion rate in MB/s")
+	fs.Float64Var(&params.TargetQPS, "target-qps", 10.0, "target query rate in queries per second")
+	fs.StringVar(&params.Duration, "duration", "5m", "test duration")
+	fs.IntVar(&params.VUs, "vus", 10, "pre-allocated VUs per scenario")
+	fs.StringVar(&output, "o", "", "output file (default: stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: xk6-tempo scaffold [flags]")
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Scenarios:")
+		for _, name := range scenarioNames() {
+			fmt.Fprintf(fs.Output(), "  %-13s %s\n", name, scenarios[name].description)
+		}
+		fmt.Fprintln(fs.Output())
+		fmt.Fprintln(fs.Output(), "Flags:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+
+	if err := params.validate(); err != nil {
+		return err
+	}
+	params.MaxVUs = params.VUs * 5
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return renderScaffold(w, params)
+}
+
+// validate checks the scaffold parameters
+func (p *scaffoldParams) validate() error {
+	if _, ok := scenarios[p.Scenario]; !ok {
+		return fmt.Errorf("unknown scenario %q (use one of: %s)", p.Scenario, strings.Join(scenarioNames(), ", "))
+	}
+	if !slices.Contains(tempo.Protocols, p.Protocol) {
+		return fmt.Errorf("unsupported protocol: %s (use one of: %s)", p.Protocol, strings.Join(tempo.Protocols, ", "))
+	}
+	if p.TargetMBps <= 0 {
+		return fmt.Errorf("target-mbps must be > 0, got %f", p.TargetMBps)
+	}
+	if p.TargetQPS <= 0 {
+		return fmt.Errorf("target-qps must be > 0, got %f", p.TargetQPS)
+	}
+	if p.VUs <= 0 {
+		return fmt.Errorf("vus must be > 0, got %d", p.VUs)
+	}
+	return nil
+}
+
+// renderScaffold renders the selected scenario template to w
+func renderScaffold(w io.Writer, params scaffoldParams) error {
+	tmpl, err := template.ParseFS(scaffoldTemplates, "templates/"+scenarios[params.Scenario].file)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl.Execute(w, params)
+}
+
+// scenarioNames returns the sorted list of scenario names
+func scenarioNames() []string {
+	names := make([]string, 0, len(scenarios))
+	for name := range scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rvargasp/xk6-tempo/pkg/generator"
+	"github.com/rvargasp/xk6-tempo/pkg/tempo"
+	"go.k6.io/k6/js/common"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// tsFunc describes a module-level export. Module functions take loosely typed maps
+// on the Go side, so their TypeScript signatures are declared here by hand.
+type tsFunc struct {
+	name    string
+	params  string
+	returns string
+}
+
+// moduleFunctions lists every named export of k6/x/tempo
+var moduleFunctions = []tsFunc{
+	{name: "IngestClient", params: "config: IngestConfig", returns: "IngestClient"},
+	{name: "QueryClient", params: "config: QueryConfig", returns: "QueryClient"},
+	{name: "generateTrace", params: "config?: Config", returns: "Traces"},
+	{name: "generateBatch", params: "config: BatchConfig", returns: "Traces[]"},
+	{name: "generateBatchWithMetadata", params: "config: BatchConfig", returns: "BatchResult"},
+	{name: "generateBatchParallel", params: "config: BatchConfig, workers?: number", returns: "Traces[]"},
+	{name: "createBatchIterator", params: "config?: Config", returns: "BatchIterator"},
+	{name: "createHugeTraceIterator", params: "config: Config, spanCount: number", returns: "HugeTraceIterator"},
+	{name: "registerWorkflow", params: "name: string, steps: WorkflowStep[]", returns: "void"},
+	{name: "registerAttributePack", params: "name: string, pack: AttributePackSpec", returns: "void"},
+	{name: "loadTopology", params: "path: string", returns: "TraceTreeConfig"},
+	{name: "loadTraces", params: "path: string, config?: CorpusConfig", returns: "TraceCorpus"},
+	{name: "pregenerateTraces", params: "name: string, config?: CorpusConfig & { templates?: number; traceConfig?: Config }", returns: "TraceCorpus"},
+	{name: "createRateLimiter", params: "config: RateLimitConfig", returns: "ByteRateLimiter"},
+	{name: "createQueryWorkload", params: "queryClient: QueryClient, workloadConfig: QueryWorkloadConfig, queries: Record<string, QueryDefinition>", returns: "QueryWorkload"},
+	{name: "estimateTraceSize", params: "config?: Config", returns: "number"},
+	{name: "calculateThroughput", params: "config: Config, targetBytesPerSec: number, numVUs: number", returns: "ThroughputConfig"},
+	{name: "compareTraces", params: "expected: Traces, actual: Traces", returns: "IntegrityDiff"},
+	{name: "validateTraceStructure", params: "trace: Traces", returns: "StructureReport"},
+	{name: "exportTraceJSON", params: "trace: Traces, path: string", returns: "void"},
+	{name: "compareWithGolden", params: "fetchedTrace: Traces, goldenPath: string", returns: "IntegrityDiff"},
+	{name: "auditDataLoss", params: "queryClient: QueryClient, config?: DataLossAuditConfig", returns: "DataLossReport"},
+	{name: "validateMetricsGenerator", params: "traceTree: TraceTreeConfig, config: MetricsGeneratorValidationConfig", returns: "MetricsGeneratorReport"},
+	{name: "createKnownAnswerSuite", params: "ingestClient: IngestClient, queryClient: QueryClient, config?: KnownAnswerConfig", returns: "KnownAnswerSuite"},
+	{name: "createRetentionValidator", params: "ingestClient: IngestClient, queryClient: QueryClient, config?: RetentionConfig", returns: "RetentionValidator"},
+	{name: "createVerifier", params: "ingestClient: IngestClient, queryClient: QueryClient, config?: VerifierConfig", returns: "Verifier"},
+	{name: "traceql", params: "", returns: "TraceQLBuilder"},
+	{name: "createQueryReplay", params: "queryClient: QueryClient, config: ReplayConfig", returns: "QueryReplay"},
+	{name: "createQueryableProbe", params: "ingestClient: IngestClient, queryClient: QueryClient, config?: QueryableProbeConfig", returns: "QueryableProbe"},
+	{name: "getSummary", params: "", returns: "TestSummary"},
+}
+
+// inputTypes are config shapes passed from JS; all of their fields are optional
+var inputTypes = []reflect.Type{
+	reflect.TypeOf(tempo.IngestConfig{}),
+	reflect.TypeOf(tempo.QueryConfig{}),
+	reflect.TypeOf(tempo.QueryOptions{}),
+	reflect.TypeOf(tempo.QueryWorkloadConfig{}),
+	reflect.TypeOf(tempo.QueryDefinition{}),
+	reflect.TypeOf(generator.Config{}),
+	reflect.TypeOf(generator.BatchConfig{}),
+	reflect.TypeOf(generator.RateLimitConfig{}),
+	reflect.TypeOf(generator.WorkflowStep{}),
+	reflect.TypeOf(generator.AttributePackSpec{}),
+	reflect.TypeOf(tempo.VerifierConfig{}),
+	reflect.TypeOf(tempo.DataLossAuditConfig{}),
+	reflect.TypeOf(tempo.MetricsGeneratorValidationConfig{}),
+	reflect.TypeOf(tempo.KnownAnswerConfig{}),
+	reflect.TypeOf(tempo.RetentionConfig{}),
+	reflect.TypeOf(tempo.ReplayConfig{}),
+	reflect.TypeOf(tempo.QueryableProbeConfig{}),
+	reflect.TypeOf(tempo.CorpusConfig{}),
+}
+
+// outputTypes are values returned to JS
+var outputTypes = []reflect.Type{
+	reflect.TypeOf(generator.BatchResult{}),
+	reflect.TypeOf(generator.ThroughputConfig{}),
+	reflect.TypeOf(tempo.SearchResponse{}),
+	reflect.TypeOf(tempo.MetricsInstantResponse{}),
+	reflect.TypeOf(tempo.PaginatedSearchResponse{}),
+	reflect.TypeOf(tempo.BuildInfo{}),
+	reflect.TypeOf(tempo.Trace{}),
+	reflect.TypeOf(tempo.IntegrityDiff{}),
+	reflect.TypeOf(tempo.StructureReport{}),
+	reflect.TypeOf(tempo.DataLossReport{}),
+	reflect.TypeOf(tempo.MetricsGeneratorReport{}),
+	reflect.TypeOf(tempo.RetentionReport{}),
+	reflect.TypeOf(tempo.PushResult{}),
+	reflect.TypeOf(tempo.StreamResult{}),
+	reflect.TypeOf(tempo.HugeTraceResult{}),
+	reflect.TypeOf(generator.HugeTracePart{}),
+	reflect.TypeOf(tempo.RetentionCohort{}),
+	reflect.TypeOf(tempo.ReplayResult{}),
+	reflect.TypeOf(tempo.KnownTraceFetch{}),
+	reflect.TypeOf(tempo.QueryableResult{}),
+	reflect.TypeOf(tempo.AdaptiveQPSReport{}),
+	reflect.TypeOf(tempo.TestSummary{}),
+}
+
+// objectTypes are Go objects whose exported methods are callable from JS
+var objectTypes = []reflect.Type{
+	reflect.TypeOf(&tempo.IngestClient{}),
+	reflect.TypeOf(&tempo.QueryClient{}),
+	reflect.TypeOf(&tempo.QueryWorkload{}),
+	reflect.TypeOf(&generator.ByteRateLimiter{}),
+	reflect.TypeOf(&generator.BatchIterator{}),
+	reflect.TypeOf(&generator.HugeTraceIterator{}),
+	reflect.TypeOf(&tempo.Verifier{}),
+	reflect.TypeOf(&tempo.KnownAnswerSuite{}),
+	reflect.TypeOf(&tempo.RetentionValidator{}),
+	reflect.TypeOf(&tempo.TraceQLBuilder{}),
+	reflect.TypeOf(&tempo.QueryReplay{}),
+	reflect.TypeOf(&tempo.QueryableProbe{}),
+	reflect.TypeOf(&tempo.TraceCorpus{}),
+}
+
+// paramNames gives readable names to method parameters, keyed by "Type.Method"
+var paramNames = map[string][]string{
+	"IngestClient.Push":                   {"trace"},
+	"IngestClient.PushBatch":              {"traces"},
+	"IngestClient.PushBatchWithRateLimit": {"traces", "limiter"},
+	"IngestClient.PushStream":             {"generatorConfig", "totalBytes"},
+	"IngestClient.PushHugeTrace":          {"generatorConfig", "spanCount"},
+	"QueryClient.Search":                  {"query", "options"},
+	"QueryClient.MetricsQueryInstant":     {"query", "options"},
+	"QueryClient.StreamingSearch":         {"query", "options"},
+	"QueryClient.SearchPaginated":         {"query", "options", "maxPages"},
+	"QueryClient.GetTrace":                {"traceID"},
+	"QueryClient.GetTraceOTLP":            {"traceID"},
+	"ByteRateLimiter.SetRate":             {"targetMBps"},
+	"BatchIterator.NextChunk":             {"maxBytes"},
+	"HugeTraceIterator.NextPart":          {"maxSpans"},
+	"Verifier.Verify":                     {"trace"},
+	"QueryableProbe.Measure":              {"trace"},
+	"KnownAnswerSuite.Run":                {"runId"},
+	"RetentionValidator.Check":            {"cohort"},
+	"TraceQLBuilder.Span":                 {"name", "op", "value"},
+	"TraceQLBuilder.Resource":             {"name", "op", "value"},
+	"TraceQLBuilder.Attr":                 {"name", "op", "value"},
+	"TraceQLBuilder.Duration":             {"op", "value"},
+	"TraceQLBuilder.Name":                 {"op", "value"},
+	"TraceQLBuilder.Status":               {"value"},
+	"TraceQLBuilder.Kind":                 {"value"},
+}
+
+// opaqueTypes are Go values that JS only passes around
+var opaqueTypes = map[reflect.Type]string{
+	reflect.TypeOf(ptrace.Traces{}): "Traces",
+}
+
+var (
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	httpRespType = reflect.TypeOf(&http.Response{})
+)
+
+// runTypes implements the types subcommand
+func runTypes(args []string) error {
+	fs := flag.NewFlagSet("types", flag.ContinueOnError)
+	output := fs.String("o", "", "output file (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+
+	var buf bytes.Buffer
+	newTSWriter(&buf).write()
+
+	if *output == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(*output, buf.Bytes(), 0o644)
+}
+
+// tsWriter renders the declaration file
+type tsWriter struct {
+	w        io.Writer
+	names    map[reflect.Type]string
+	optional map[reflect.Type]bool
+	queue    []reflect.Type
+}
+
+func newTSWriter(w io.Writer) *tsWriter {
+	return &tsWriter{
+		w:        w,
+		names:    make(map[reflect.Type]string),
+		optional: make(map[reflect.Type]bool),
+	}
+}
+
+// write emits the full module declaration
+func (tw *tsWriter) write() {
+	fmt.Fprintln(tw.w, "// Code generated by `xk6-tempo types`. DO NOT EDIT.")
+	fmt.Fprintln(tw.w)
+	fmt.Fprintln(tw.w, `declare module "k6/x/tempo" {`)
+	fmt.Fprintln(tw.w, "  /** Opaque OTLP trace data produced by the generators. */")
+	fmt.Fprintln(tw.w, "  export interface Traces {}")
+
+	for _, t := range inputTypes {
+		tw.enqueue(t, true)
+	}
+	for _, t := range outputTypes {
+		tw.enqueue(t, false)
+	}
+	for _, t := range objectTypes {
+		tw.names[t] = t.Elem().Name()
+	}
+	for _, t := range objectTypes {
+		tw.writeObject(t)
+	}
+	for len(tw.queue) > 0 {
+		t := tw.queue[0]
+		tw.queue = tw.queue[1:]
+		tw.writeStruct(t)
+	}
+
+	fmt.Fprintln(tw.w)
+	for _, fn := range moduleFunctions {
+		fmt.Fprintf(tw.w, "  export function %s(%s): %s;\n", fn.name, fn.params, fn.returns)
+	}
+
+	fmt.Fprintln(tw.w)
+	fmt.Fprintln(tw.w, "  const tempo: {")
+	for _, fn := range moduleFunctions {
+		fmt.Fprintf(tw.w, "    %s: typeof %s;\n", fn.name, fn.name)
+	}
+	fmt.Fprintln(tw.w, "  };")
+	fmt.Fprintln(tw.w, "  export default tempo;")
+	fmt.Fprintln(tw.w, "}")
+}
+
+// enqueue registers a named struct type for emission
+func (tw *tsWriter) enqueue(t reflect.Type, optional bool) string {
+	if name, ok := tw.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	for _, existing := range tw.names {
+		if existing == name {
+			name = strings.Title(pkgName(t)) + name
+			break
+		}
+	}
+	tw.names[t] = name
+	tw.optional[t] = optional
+	tw.queue = append(tw.queue, t)
+	return name
+}
+
+// writeStruct emits an interface for a struct type
+func (tw *tsWriter) writeStruct(t reflect.Type) {
+	fmt.Fprintln(tw.w)
+	fmt.Fprintf(tw.w, "  export interface %s {\n", tw.names[t])
+	tw.writeFields(t, tw.optional[t], "    ")
+	fmt.Fprintln(tw.w, "  }")
+}
+
+// writeFields emits the fields of a struct using k6's JS field naming
+func (tw *tsWriter) writeFields(t reflect.Type, optional bool, indent string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := common.FieldName(t, f)
+		if name == "" {
+			continue
+		}
+		marker := ""
+		if optional {
+			marker = "?"
+		}
+		fmt.Fprintf(tw.w, "%s%s%s: %s;\n", indent, name, marker, tw.tsType(f.Type, optional))
+	}
+}
+
+// writeObject emits an interface with the JS-callable methods of a Go object
+func (tw *tsWriter) writeObject(t reflect.Type) {
+	name := tw.names[t]
+	methods := make([]string, 0, t.NumMethod())
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		sig, ok := tw.methodSignature(name, m)
+		if ok {
+			methods = append(methods, sig)
+		}
+	}
+	sort.Strings(methods)
+
+	fmt.Fprintln(tw.w)
+	fmt.Fprintf(tw.w, "  export interface %s {\n", name)
+	for _, sig := range methods {
+		fmt.Fprintf(tw.w, "    %s;\n", sig)
+	}
+	fmt.Fprintln(tw.w, "  }")
+}
+
+// methodSignature renders a method, skipping ones that need Go-only arguments
+func (tw *tsWriter) methodSignature(owner string, m reflect.Method) (string, bool) {
+	mt := m.Type
+	names := paramNames[owner+"."+m.Name]
+
+	params := make([]string, 0, mt.NumIn()-1)
+	for i := 1; i < mt.NumIn(); i++ {
+		in := mt.In(i)
+		if in == contextType || in == httpRespType {
+			return "", false
+		}
+		paramName := fmt.Sprintf("arg%d", i-1)
+		if i-1 < len(names) {
+			paramName = names[i-1]
+		}
+		params = append(params, paramName+": "+tw.tsType(in, true))
+	}
+
+	results := make([]reflect.Type, 0, mt.NumOut())
+	for i := 0; i < mt.NumOut(); i++ {
+		out := mt.Out(i)
+		if out == httpRespType {
+			return "", false
+		}
+		if out != errorType {
+			results = append(results, out)
+		}
+	}
+
+	returns := "void"
+	if len(results) == 1 {
+		returns = tw.tsType(results[0], false)
+	} else if len(results) > 1 {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s(%s): %s", common.MethodName(mt, m), strings.Join(params, ", "), returns), true
+}
+
+// tsType maps a Go type to its TypeScript representation
+func (tw *tsWriter) tsType(t reflect.Type, optional bool) string {
+	if name, ok := opaqueTypes[t]; ok {
+		return name
+	}
+	if name, ok := tw.names[t]; ok {
+		return name
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return tw.tsType(t.Elem(), optional)
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		elem := tw.tsType(t.Elem(), optional)
+		if strings.ContainsAny(elem, " |{") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<" + tw.tsType(t.Key(), optional) + ", " + tw.tsType(t.Elem(), optional) + ">"
+	case reflect.Interface:
+		return "any"
+	case reflect.Struct:
+		if t.Name() == "" {
+			var buf bytes.Buffer
+			inner := &tsWriter{w: &buf, names: tw.names, optional: tw.optional, queue: tw.queue}
+			fmt.Fprintln(&buf, "{")
+			inner.writeFields(t, optional, "      ")
+			fmt.Fprint(&buf, "    }")
+			tw.queue = inner.queue
+			return buf.String()
+		}
+		return tw.enqueue(t, optional)
+	default:
+		return "unknown"
+	}
+}
+
+// pkgName returns the last element of a type's package path
+func pkgName(t reflect.Type) string {
+	path := t.PkgPath()
+	return path[strings.LastIndex(path, "/")+1:]
+}
@@ -0,0 +1,47 @@
+import tempo from 'k6/x/tempo';
+import { check } from 'k6';
+
+// Known-answer TraceQL test: ingest a seeded corpus once, then repeatedly run a catalog
+// of queries whose expected result counts are known, failing on any mismatch
+export const options = {
+  scenarios: {
+    correctness: {
+      executor: 'constant-arrival-rate',
+      rate: 2,
+      duration: '5m',
+      preAllocatedVUs: 5,
+    },
+  },
+  thresholds: {
+    'tempo_known_answer_checks': ['rate==1'],
+  },
+};
+
+const ingestClient = tempo.IngestClient({
+  endpoint: __ENV.TEMPO_ENDPOINT || 'http://localhost:4318',
+  protocol: 'otlp-http',
+  tenant: __ENV.TEMPO_TENANT || '',
+});
+
+const queryClient = tempo.QueryClient({
+  endpoint: __ENV.TEMPO_QUERY_ENDPOINT || 'http://localhost:3200',
+  tenant: __ENV.TEMPO_TENANT || '',
+});
+
+const suite = tempo.createKnownAnswerSuite(ingestClient, queryClient, {
+  traces: 100,
+  seed: 42,
+  timeout: '90s',
+});
+
+export function setup() {
+  // Every run gets a fresh run ID so results from earlier runs never interfere
+  return { runId: suite.ingest() };
+}
+
+export default function(data) {
+  const report = suite.run(data.runId);
+  check(report, {
+    'all known-answer queries passed': (r) => r.failed === 0,
+  });
+}
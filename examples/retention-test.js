@@ -0,0 +1,65 @@
+import tempo from 'k6/x/tempo';
+import { check } from 'k6';
+import { TRACE_PROFILES } from './trace-profiles.js';
+
+// Retention test: ingest a cohort of marker traces at the start, keep ingestion load running,
+// and check that the markers stay retrievable and searchable as they move from the WAL
+// to backend blocks and through compaction
+const AGES = (__ENV.RETENTION_AGES || '1m,15m,45m,2h').split(',');
+
+export const options = {
+  scenarios: {
+    load: {
+      executor: 'constant-arrival-rate',
+      exec: 'ingest',
+      rate: parseInt(__ENV.RATE || '50'),
+      duration: __ENV.DURATION || '2h5m',
+      preAllocatedVUs: 10,
+      maxVUs: 50,
+    },
+    retention: {
+      executor: 'per-vu-iterations',
+      exec: 'retention',
+      vus: 1,
+      iterations: 1,
+      maxDuration: __ENV.DURATION || '2h5m',
+    },
+  },
+  thresholds: {
+    'tempo_retention_retrieval_rate': ['rate==1'],
+    'tempo_retention_search_rate': ['rate==1'],
+  },
+};
+
+const ingestClient = tempo.IngestClient({
+  endpoint: __ENV.TEMPO_ENDPOINT || 'http://localhost:4318',
+  protocol: 'otlp-http',
+  tenant: __ENV.TEMPO_TENANT || '',
+});
+
+const queryClient = tempo.QueryClient({
+  endpoint: __ENV.TEMPO_QUERY_ENDPOINT || 'http://localhost:3200',
+  tenant: __ENV.TEMPO_TENANT || '',
+});
+
+const validator = tempo.createRetentionValidator(ingestClient, queryClient, {
+  traces: 20,
+  ages: AGES,
+});
+
+export function ingest() {
+  ingestClient.push(tempo.generateTrace({
+    useTraceTree: true,
+    traceTree: TRACE_PROFILES[__ENV.TRACE_PROFILE || 'medium'],
+  }));
+}
+
+export function retention() {
+  const report = validator.run();
+  for (const c of report.checks) {
+    check(c, {
+      [`${c.age}: all markers retrievable`]: (r) => r.retrieved === r.checked,
+      [`${c.age}: all markers searchable`]: (r) => r.searchable === r.checked,
+    });
+  }
+}
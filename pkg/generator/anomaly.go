@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// defaultAnomalyOffsetMs is how far anomalous children cross their parent's bounds by default
+const defaultAnomalyOffsetMs = 50
+
+// injectTimestampAnomalies makes children start before or end after their parent at the configured
+// rates, then shifts every span of a skewed service by its clock offset
+func injectTimestampAnomalies(spans []ptrace.Span, config Config, rng *rand.Rand) {
+	if config.EarlyChildRate > 0 || config.LateChildRate > 0 {
+		offsetMs := config.AnomalyOffsetMs
+		if offsetMs <= 0 {
+			offsetMs = defaultAnomalyOffsetMs
+		}
+
+		parents := make(map[pcommon.SpanID]ptrace.Span, len(spans))
+		for _, span := range spans {
+			parents[span.SpanID()] = span
+		}
+
+		for _, span := range spans {
+			parent, ok := parents[span.ParentSpanID()]
+			if !ok || span.ParentSpanID().IsEmpty() {
+				continue
+			}
+			// Cross the parent's bounds by 1 to offsetMs
+			if rng.Float64() < config.EarlyChildRate {
+				offset := pcommon.Timestamp((1 + rng.Intn(offsetMs)) * int(time.Millisecond))
+				if parent.StartTimestamp() > offset {
+					span.SetStartTimestamp(parent.StartTimestamp() - offset)
+				}
+			}
+			if rng.Float64() < config.LateChildRate {
+				offset := pcommon.Timestamp((1 + rng.Intn(offsetMs)) * int(time.Millisecond))
+				span.SetEndTimestamp(parent.EndTimestamp() + offset)
+			}
+		}
+	}
+
+	if len(config.ClockSkewMs) == 0 {
+		return
+	}
+	for _, span := range spans {
+		skewMs, ok := config.ClockSkewMs[spanServiceName(span)]
+		if !ok || skewMs == 0 {
+			continue
+		}
+		span.SetStartTimestamp(skewTimestamp(span.StartTimestamp(), skewMs))
+		span.SetEndTimestamp(skewTimestamp(span.EndTimestamp(), skewMs))
+		for i := 0; i < span.Events().Len(); i++ {
+			event := span.Events().At(i)
+			event.SetTimestamp(skewTimestamp(event.Timestamp(), skewMs))
+		}
+	}
+}
+
+// skewTimestamp shifts a timestamp by skewMs, which may be negative
+func skewTimestamp(ts pcommon.Timestamp, skewMs int) pcommon.Timestamp {
+	return pcommon.Timestamp(int64(ts) + int64(skewMs)*int64(time.Millisecond))
+}
+
+// spanServiceName returns the service.name attribute of a span
+func spanServiceName(span ptrace.Span) string {
+	if value, ok := span.Attributes().Get("service.name"); ok {
+		return value.Str()
+	}
+	return ""
+}
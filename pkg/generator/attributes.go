@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// Attribute value types for custom attributes
+const (
+	AttributeTypeString      = "string"
+	AttributeTypeInt         = "int"
+	AttributeTypeDouble      = "double"
+	AttributeTypeBool        = "bool"
+	AttributeTypeStringArray = "stringArray"
+	AttributeTypeKVList      = "kvlist"
+)
+
+// attributeTypes lists the supported attribute value types in selection order
+var attributeTypes = []string{
+	AttributeTypeString,
+	AttributeTypeInt,
+	AttributeTypeDouble,
+	AttributeTypeBool,
+	AttributeTypeStringArray,
+	AttributeTypeKVList,
+}
+
+// isAttributeType reports whether t is a supported attribute value type
+func isAttributeType(t string) bool {
+	for _, attrType := range attributeTypes {
+		if attrType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// selectAttributeType selects an attribute value type based on weighted distribution,
+// defaulting to string when no weights are configured
+func selectAttributeType(weights map[string]float64, rng *rand.Rand) string {
+	totalWeight := 0.0
+	for _, attrType := range attributeTypes {
+		totalWeight += weights[attrType]
+	}
+	if totalWeight <= 0 {
+		return AttributeTypeString
+	}
+
+	r := rng.Float64() * totalWeight
+	currentWeight := 0.0
+	for _, attrType := range attributeTypes {
+		currentWeight += weights[attrType]
+		if r < currentWeight {
+			return attrType
+		}
+	}
+	return AttributeTypeString
+}
+
+// attributeKeys are the keys of the first custom attributes, so spans do not format them again
+var attributeKeys = func() []string {
+	keys := make([]string, 64)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("attribute.%d", i)
+	}
+	return keys
+}()
+
+// attributeKey returns the key of the i-th custom attribute
+func attributeKey(i int) string {
+	if i < len(attributeKeys) {
+		return attributeKeys[i]
+	}
+	return fmt.Sprintf("attribute.%d", i)
+}
+
+// maxKeyDrawAttempts bounds the draws of a custom attribute key from the key pool before the
+// first unused key is taken, as skewed pools rarely yield their last keys
+const maxKeyDrawAttempts = 32
+
+// putCustomAttributes sets the custom attributes of a span: attribute.0 to attribute.<count-1>,
+// or with a key pool, count distinct keys drawn from attribute.0 to attribute.<pool-1>
+func putCustomAttributes(attrs pcommon.Map, config Config, rng *rand.Rand) {
+	if config.AttributeKeyPool <= 0 {
+		for i := 0; i < config.AttributeCount; i++ {
+			attrType := selectAttributeType(config.AttributeTypeWeights, rng)
+			putTypedAttribute(attrs, attributeKey(i), attrType, config.AttributeValueSize, config.textValueRatio(), rng)
+		}
+		return
+	}
+
+	cm := GetCardinalityManager()
+	count := min(config.AttributeCount, config.AttributeKeyPool)
+	for i := 0; i < count; i++ {
+		key := attributeKey(cm.pick(config.AttributeKeyPool, config.AttributeKeySkew, rng))
+		for attempt := 1; hasKey(attrs, key); attempt++ {
+			if attempt < maxKeyDrawAttempts {
+				key = attributeKey(cm.pick(config.AttributeKeyPool, config.AttributeKeySkew, rng))
+				continue
+			}
+			for j := 0; hasKey(attrs, key); j++ {
+				key = attributeKey(j)
+			}
+		}
+		attrType := selectAttributeType(config.AttributeTypeWeights, rng)
+		putTypedAttribute(attrs, key, attrType, config.AttributeValueSize, config.textValueRatio(), rng)
+	}
+}
+
+// hasKey reports whether attrs holds key
+func hasKey(attrs pcommon.Map, key string) bool {
+	_, ok := attrs.Get(key)
+	return ok
+}
+
+// putTypedAttribute sets a random attribute value of the given type; size and textRatio apply to
+// string values, including array elements and kvlist values
+func putTypedAttribute(attrs pcommon.Map, key, attrType string, size int, textRatio float64, rng *rand.Rand) {
+	switch attrType {
+	case AttributeTypeInt:
+		attrs.PutInt(key, rng.Int63n(1000000))
+	case AttributeTypeDouble:
+		attrs.PutDouble(key, rng.Float64()*1000)
+	case AttributeTypeBool:
+		attrs.PutBool(key, rng.Intn(2) == 0)
+	case AttributeTypeStringArray:
+		values := attrs.PutEmptySlice(key)
+		n := 1 + rng.Intn(4)
+		values.EnsureCapacity(n)
+		for i := 0; i < n; i++ {
+			values.AppendEmpty().SetStr(generateAttributeValue(size, textRatio, rng))
+		}
+	case AttributeTypeKVList:
+		values := attrs.PutEmptyMap(key)
+		n := 1 + rng.Intn(3)
+		values.EnsureCapacity(n)
+		for i := 0; i < n; i++ {
+			values.PutStr(fmt.Sprintf("key.%d", i), generateAttributeValue(size, textRatio, rng))
+		}
+	default:
+		putNonEmptyStr(attrs, key, generateAttributeValue(size, textRatio, rng))
+	}
+}
+
+// putNonEmptyStr sets a string attribute unless the value is empty
+func putNonEmptyStr(attrs pcommon.Map, key, value string) {
+	if value != "" {
+		attrs.PutStr(key, value)
+	}
+}
+
+// putStringAttributes sets string attributes, in key order so seeded traces are reproducible
+func putStringAttributes(attrs pcommon.Map, values map[string]string) {
+	keys := sortedKeys(values)
+	defer releaseKeys(keys)
+	attrs.EnsureCapacity(attrs.Len() + len(*keys))
+	for _, key := range *keys {
+		attrs.PutStr(key, values[key])
+	}
+}
+
+// putResourceArrayAttributes sets the configured array-valued resource attributes
+func putResourceArrayAttributes(attrs pcommon.Map, config Config) {
+	if len(config.ResourceArrayAttributes) == 0 {
+		return
+	}
+	for _, key := range slices.Sorted(maps.Keys(config.ResourceArrayAttributes)) {
+		values := config.ResourceArrayAttributes[key]
+		slice := attrs.PutEmptySlice(key)
+		slice.EnsureCapacity(len(values))
+		for _, value := range values {
+			slice.AppendEmpty().SetStr(value)
+		}
+	}
+}
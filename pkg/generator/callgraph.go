@@ -0,0 +1,303 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// CallGraphConfig is a service topology defined as a weighted call graph. Each trace is a random
+// walk from an entry service along the edges, so topologies of hundreds of services need no
+// hand-written trace tree. Walks may revisit services; spanDepth bounds the calls per path and
+// spansPerTrace the spans per trace.
+type CallGraphConfig struct {
+	Nodes []CallGraphNode `js:"nodes"`
+	Edges []CallGraphEdge `js:"edges"`
+}
+
+// CallGraphNode is a service of a call graph
+type CallGraphNode struct {
+	Service     string   `js:"service"`
+	Operations  []string `js:"operations"`  // Server span names, one picked per call (default: generated)
+	EntryWeight float64  `js:"entryWeight"` // Weight as the root service of a trace (default: 0; all 0 = first node)
+}
+
+// CallGraphEdge is a weighted call from one service to another. Every call is a client span
+// in the caller and a server span in the callee.
+type CallGraphEdge struct {
+	From      string         `js:"from"`
+	To        string         `js:"to"`
+	Weight    float64        `js:"weight"`    // Relative weight among the edges of From (default: 0, meaning 1)
+	Latency   DurationConfig `js:"latency"`   // Own latency of the callee, without its calls (default: durationBaseMs)
+	ErrorRate float64        `js:"errorRate"` // Probability the call fails (default: 0, range: 0.0-1.0)
+}
+
+// Validate checks that the graph has nodes, service names are unique and edges connect known services
+func (g *CallGraphConfig) Validate() error {
+	if len(g.Nodes) == 0 {
+		return fmt.Errorf("topology must have at least one node")
+	}
+	services := make(map[string]bool, len(g.Nodes))
+	for i, node := range g.Nodes {
+		if node.Service == "" {
+			return fmt.Errorf("topology node %d: service is required", i)
+		}
+		if services[node.Service] {
+			return fmt.Errorf("topology node %d: duplicate service %q", i, node.Service)
+		}
+		if node.EntryWeight < 0 {
+			return fmt.Errorf("topology node %q: entryWeight must be >= 0, got %f", node.Service, node.EntryWeight)
+		}
+		services[node.Service] = true
+	}
+	for i, edge := range g.Edges {
+		if !services[edge.From] {
+			return fmt.Errorf("topology edge %d: unknown service %q", i, edge.From)
+		}
+		if !services[edge.To] {
+			return fmt.Errorf("topology edge %d: unknown service %q", i, edge.To)
+		}
+		if edge.Weight < 0 {
+			return fmt.Errorf("topology edge %d: weight must be >= 0, got %f", i, edge.Weight)
+		}
+		if edge.ErrorRate < 0.0 || edge.ErrorRate > 1.0 {
+			return fmt.Errorf("topology edge %d: errorRate must be in range [0.0, 1.0], got %f", i, edge.ErrorRate)
+		}
+		if !IsDistribution(edge.Latency.Distribution) {
+			return fmt.Errorf("topology edge %d: unknown latency distribution %q", i, edge.Latency.Distribution)
+		}
+	}
+	return nil
+}
+
+// callGraphWalk is a call of a random walk; the root call has no edge
+type callGraphWalk struct {
+	node      *CallGraphNode
+	edge      *CallGraphEdge
+	operation string
+	depth     int
+	calls     []*callGraphWalk
+	latency   time.Duration // Own latency
+	duration  time.Duration // Own latency plus the calls
+	failed    bool
+}
+
+// Network overhead a client span adds around the server span of a call
+const maxCallOverhead = 2 * time.Millisecond
+
+// walkCallGraph draws the calls of a trace: from the root, every call makes calculateMaxChildren
+// calls along edges picked by weight, breadth first, until spanDepth or the span budget of
+// spansPerTrace (one root span, two spans per call) is reached
+func walkCallGraph(graph *CallGraphConfig, config Config, rng *rand.Rand) *callGraphWalk {
+	nodes := make(map[string]*CallGraphNode, len(graph.Nodes))
+	for i := range graph.Nodes {
+		nodes[graph.Nodes[i].Service] = &graph.Nodes[i]
+	}
+	edges := make(map[string][]*CallGraphEdge)
+	for i := range graph.Edges {
+		edge := &graph.Edges[i]
+		if nodes[edge.To] != nil {
+			edges[edge.From] = append(edges[edge.From], edge)
+		}
+	}
+
+	root := newCallGraphWalk(selectEntryNode(graph.Nodes, rng), nil, 0, rng)
+	spans := 1
+	queue := []*callGraphWalk{root}
+	for len(queue) > 0 && spans+2 <= config.SpansPerTrace {
+		call := queue[0]
+		queue = queue[1:]
+		out := edges[call.node.Service]
+		if len(out) == 0 || call.depth >= config.SpanDepth {
+			continue
+		}
+
+		for n := calculateMaxChildren(call.depth, config, rng); n > 0 && spans+2 <= config.SpansPerTrace; n-- {
+			edge := selectCallGraphEdge(out, rng)
+			child := newCallGraphWalk(nodes[edge.To], edge, call.depth+1, rng)
+			call.calls = append(call.calls, child)
+			queue = append(queue, child)
+			spans += 2
+		}
+	}
+	return root
+}
+
+// newCallGraphWalk returns a call of node with one of its operations
+func newCallGraphWalk(node *CallGraphNode, edge *CallGraphEdge, depth int, rng *rand.Rand) *callGraphWalk {
+	call := &callGraphWalk{node: node, edge: edge, depth: depth}
+	if len(node.Operations) > 0 {
+		call.operation = node.Operations[rng.Intn(len(node.Operations))]
+	} else {
+		call.operation = generateOperationName(node.Service, rng)
+	}
+	return call
+}
+
+// selectEntryNode picks the root service by entry weight, or the first node without weights
+func selectEntryNode(nodes []CallGraphNode, rng *rand.Rand) *CallGraphNode {
+	total := 0.0
+	for _, node := range nodes {
+		total += node.EntryWeight
+	}
+	if total <= 0 {
+		return &nodes[0]
+	}
+
+	r := rng.Float64() * total
+	for i := range nodes {
+		r -= nodes[i].EntryWeight
+		if r < 0 {
+			return &nodes[i]
+		}
+	}
+	return &nodes[len(nodes)-1]
+}
+
+// selectCallGraphEdge picks an edge by weight; edges without weight count as 1
+func selectCallGraphEdge(edges []*CallGraphEdge, rng *rand.Rand) *CallGraphEdge {
+	weight := func(edge *CallGraphEdge) float64 {
+		if edge.Weight > 0 {
+			return edge.Weight
+		}
+		return 1
+	}
+
+	total := 0.0
+	for _, edge := range edges {
+		total += weight(edge)
+	}
+	r := rng.Float64() * total
+	for _, edge := range edges {
+		r -= weight(edge)
+		if r < 0 {
+			return edge
+		}
+	}
+	return edges[len(edges)-1]
+}
+
+// timeCallGraphWalk samples the latency and outcome of every call, bottom up, so each server
+// span lasts its own latency plus its calls, which run one after the other
+func timeCallGraphWalk(call *callGraphWalk, config Config, rng *rand.Rand) {
+	latency := config.durationConfig()
+	if call.edge != nil {
+		if call.edge.Latency.BaseMs > 0 {
+			latency = call.edge.Latency
+		}
+		call.failed = rng.Float64() < call.edge.ErrorRate
+	}
+	call.latency = calculateDurationFromConfig(latency, rng)
+	call.duration = call.latency
+
+	for _, child := range call.calls {
+		timeCallGraphWalk(child, config, rng)
+		call.duration += child.duration + 2*maxCallOverhead
+	}
+}
+
+// generateTopologyTrace generates a trace as a random walk over config.Topology.
+// Each service gets its own ResourceSpans, like workflow traces.
+func generateTopologyTrace(traceID pcommon.TraceID, config Config, rng *rand.Rand, tagCtx *TagContext) ptrace.Traces {
+	root := walkCallGraph(config.Topology, config, rng)
+	timeCallGraphWalk(root, config, rng)
+
+	t := &topologyTrace{
+		traceID:  traceID,
+		config:   config,
+		rng:      rng,
+		tagCtx:   tagCtx,
+		spansMap: make(map[int]*spanInfo),
+		services: make(map[int]string),
+	}
+	traceStartTime := traceStartTime(config, rng)
+	t.addServerSpan(root, nil, 0, traceStartTime)
+	return serviceTraces(t.spansMap, t.services, config, rng)
+}
+
+// topologyTrace collects the spans of a call graph walk
+type topologyTrace struct {
+	traceID  pcommon.TraceID
+	config   Config
+	rng      *rand.Rand
+	tagCtx   *TagContext
+	spansMap map[int]*spanInfo
+	services map[int]string // Service of each span
+}
+
+// addServerSpan adds the server span of a call and, recursively, the spans of its calls
+func (t *topologyTrace) addServerSpan(call *callGraphWalk, parent *spanInfo, depth int, start time.Time) {
+	server := t.addSpan(call, call.node.Service, parent, depth, ptrace.SpanKindServer, start, start.Add(call.duration))
+
+	// The caller's own latency is spread before its calls, which run one after the other
+	gap := int64(call.latency)/int64(len(call.calls)+1) + 1
+	cursor := start
+	for _, child := range call.calls {
+		cursor = cursor.Add(time.Duration(t.rng.Int63n(gap)))
+		clientEnd := cursor.Add(child.duration + 2*maxCallOverhead)
+		client := t.addSpan(child, call.node.Service, server, depth+1, ptrace.SpanKindClient, cursor, clientEnd)
+		t.addServerSpan(child, client, depth+2, cursor.Add(maxCallOverhead))
+		cursor = clientEnd
+	}
+}
+
+// addSpan builds a span of a call in service with the attributes of its kind and records it
+func (t *topologyTrace) addSpan(call *callGraphWalk, service string, parent *spanInfo, depth int, kind ptrace.SpanKind, start, end time.Time) *spanInfo {
+	spanConfig := t.config
+	spanConfig.SpanKindWeights = map[string]float64{spanKindName(kind): 1}
+	spanConfig.ErrorRate = 0
+	if call.failed {
+		spanConfig.ErrorRate = 1
+	}
+
+	parentSpanID := pcommon.NewSpanIDEmpty()
+	if parent != nil {
+		parentSpanID = parent.span.SpanID()
+	}
+	index := len(t.spansMap)
+	span := buildSpanWithContext(t.traceID, parentSpanID, index, depth, service, spanConfig, start, t.rng, nil, t.tagCtx, call.operation)
+	retimeSpan(span, start, end)
+
+	info := &spanInfo{span: span, index: index, depth: depth, children: make([]int, 0)}
+	t.spansMap[index] = info
+	t.services[index] = service
+	if parent != nil {
+		parent.children = append(parent.children, index)
+	}
+	return info
+}
+
+// retimeSpan moves a span to start and end, scaling its event timestamps along
+func retimeSpan(span ptrace.Span, start, end time.Time) {
+	oldStart, oldEnd := span.StartTimestamp(), span.EndTimestamp()
+	newStart, newEnd := pcommon.NewTimestampFromTime(start), pcommon.NewTimestampFromTime(end)
+	for i := 0; i < span.Events().Len(); i++ {
+		event := span.Events().At(i)
+		offset := 0.0
+		if oldEnd > oldStart {
+			offset = float64(event.Timestamp()-oldStart) / float64(oldEnd-oldStart)
+		}
+		event.SetTimestamp(newStart + pcommon.Timestamp(offset*float64(newEnd-newStart)))
+	}
+	span.SetStartTimestamp(newStart)
+	span.SetEndTimestamp(newEnd)
+}
+
+// spanKindName returns the configuration name of a span kind
+func spanKindName(kind ptrace.SpanKind) string {
+	switch kind {
+	case ptrace.SpanKindClient:
+		return "client"
+	case ptrace.SpanKindInternal:
+		return "internal"
+	case ptrace.SpanKindProducer:
+		return "producer"
+	case ptrace.SpanKindConsumer:
+		return "consumer"
+	default:
+		return "server"
+	}
+}
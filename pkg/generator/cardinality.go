@@ -2,7 +2,10 @@ package generator
 
 import (
 	"fmt"
+	"hash/fnv"
+	"math"
 	"math/rand"
+	"sort"
 	"sync"
 )
 
@@ -20,7 +23,15 @@ const (
 type CardinalityManager struct {
 	mu          sync.RWMutex
 	valuePools  map[string][]string
-	cardinality map[string]int // Current cardinality per attribute
+	cardinality map[string]int      // Current cardinality per attribute
+	samplePools map[string][]string // Default-size pools for SampleValue, kept apart so they never resize valuePools
+	zipfCDFs    sync.Map            // zipfKey to the cumulative distribution of skewed pool picks
+}
+
+// zipfKey identifies a Zipf distribution over a pool
+type zipfKey struct {
+	size int
+	skew float64
 }
 
 var globalCardinalityManager *CardinalityManager
@@ -32,54 +43,55 @@ func GetCardinalityManager() *CardinalityManager {
 		globalCardinalityManager = &CardinalityManager{
 			valuePools:  make(map[string][]string),
 			cardinality: make(map[string]int),
+			samplePools: make(map[string][]string),
 		}
 	})
 	return globalCardinalityManager
 }
 
+// defaultCardinalities are the default cardinality tiers of well-known attributes
+var defaultCardinalities = map[string]int{
+	// Low cardinality (5-10 values)
+	"region":                 8,
+	"datacenter":             6,
+	"environment":            3,
+	"http.method":            5,
+	"deployment.environment": 3,
+	"canary":                 2,
+	"user_tier":              4,
+	"priority":               3,
+	"version":                4,
+
+	// Medium cardinality (50-100 values)
+	"http.status_code":  10,
+	"error_type":        15,
+	"availability_zone": 50,
+	"cluster":           75,
+	"tenant_id":         50,
+	"org_id":            50,
+	"git_commit":        100,
+	"feature_flags":     20,
+
+	// High cardinality (1000-10000 values)
+	"customer_id":  5000,
+	"pod_name":     2000,
+	"k8s.pod.name": 2000,
+	"host.name":    1000,
+
+	// Very high (unique per trace/span) - return 0 to indicate unique
+	"trace_id":       0,
+	"span_id":        0,
+	"order_id":       0,
+	"request_id":     0,
+	"correlation_id": 0,
+	"payment_id":     0,
+	"shipment_id":    0,
+	"session_id":     0,
+}
+
 // DefaultCardinality returns the default cardinality for an attribute
 func DefaultCardinality(attrName string) int {
-	// Define default cardinality tiers
-	defaults := map[string]int{
-		// Low cardinality (5-10 values)
-		"region":                 8,
-		"datacenter":             6,
-		"environment":            3,
-		"http.method":            5,
-		"deployment.environment": 3,
-		"canary":                 2,
-		"user_tier":              4,
-		"priority":               3,
-		"version":                4,
-
-		// Medium cardinality (50-100 values)
-		"http.status_code":  10,
-		"error_type":        15,
-		"availability_zone": 50,
-		"cluster":           75,
-		"tenant_id":         50,
-		"org_id":            50,
-		"git_commit":        100,
-		"feature_flags":     20,
-
-		// High cardinality (1000-10000 values)
-		"customer_id":  5000,
-		"pod_name":     2000,
-		"k8s.pod.name": 2000,
-		"host.name":    1000,
-
-		// Very high (unique per trace/span) - return 0 to indicate unique
-		"trace_id":       0,
-		"span_id":        0,
-		"order_id":       0,
-		"request_id":     0,
-		"correlation_id": 0,
-		"payment_id":     0,
-		"shipment_id":    0,
-		"session_id":     0,
-	}
-
-	if val, ok := defaults[attrName]; ok {
+	if val, ok := defaultCardinalities[attrName]; ok {
 		return val
 	}
 
@@ -88,7 +100,7 @@ func DefaultCardinality(attrName string) int {
 }
 
 // GetValue returns a value for an attribute with appropriate cardinality
-func (cm *CardinalityManager) GetValue(attrName string, rng *rand.Rand, cardConfig map[string]int) string {
+func (cm *CardinalityManager) GetValue(attrName string, rng *rand.Rand, cardConfig map[string]int, cardSkew map[string]float64) string {
 	// Check user override first
 	cardinality := 0
 	if val, ok := cardConfig[attrName]; ok {
@@ -103,14 +115,31 @@ func (cm *CardinalityManager) GetValue(attrName string, rng *rand.Rand, cardConf
 		return cm.generateUniqueValue(attrName, rng)
 	}
 
+	// Return random value from pool
+	pool := cm.ensurePool(attrName, cardinality)
+	return pool[cm.pick(len(pool), cardSkew[attrName], rng)]
+}
+
+// GetPoolValue returns one of the first size values of an attribute's pool, so the attribute
+// takes exactly size distinct values even when other configurations grew the pool further.
+// size 0 means a unique value.
+func (cm *CardinalityManager) GetPoolValue(attrName string, size int, skew float64, rng *rand.Rand) string {
+	if size <= 0 {
+		return cm.generateUniqueValue(attrName, rng)
+	}
+	pool := cm.ensurePool(attrName, size)
+	return pool[cm.pick(size, skew, rng)]
+}
+
+// ensurePool returns the value pool of an attribute, generating or growing it to at least size values
+func (cm *CardinalityManager) ensurePool(attrName string, size int) []string {
 	// Try with read lock first
 	cm.mu.RLock()
 	pool, exists := cm.valuePools[attrName]
-	poolLen := len(pool)
 	cm.mu.RUnlock()
 
-	if exists && poolLen >= cardinality {
-		return pool[rng.Intn(poolLen)]
+	if exists && len(pool) >= size {
+		return pool
 	}
 
 	// Need to generate/update pool, switch to write lock
@@ -119,17 +148,83 @@ func (cm *CardinalityManager) GetValue(attrName string, rng *rand.Rand, cardConf
 
 	// Double check
 	pool, exists = cm.valuePools[attrName]
-	if !exists || len(pool) < cardinality {
+	if !exists || len(pool) < size {
 		// Generate pool
-		pool = cm.generateValuePool(attrName, cardinality, rng)
+		pool = cm.generateValuePool(attrName, size, poolRand(attrName))
 		cm.valuePools[attrName] = pool
 		cm.cardinality[attrName] = len(pool)
 	}
+	return pool
+}
+
+// pick returns a random pool index. With skew > 0 indices follow a Zipf distribution with
+// exponent skew, so the first values of the pool dominate, like a few customers or tenants
+// do in real data; otherwise all indices are equally likely.
+func (cm *CardinalityManager) pick(size int, skew float64, rng *rand.Rand) int {
+	if skew <= 0 || size <= 1 {
+		return rng.Intn(size)
+	}
+
+	key := zipfKey{size: size, skew: skew}
+	cdf, ok := cm.zipfCDFs.Load(key)
+	if !ok {
+		weights := make([]float64, size)
+		total := 0.0
+		for i := range weights {
+			total += math.Pow(float64(i+1), -skew)
+			weights[i] = total
+		}
+		for i := range weights {
+			weights[i] /= total
+		}
+		cdf, _ = cm.zipfCDFs.LoadOrStore(key, weights)
+	}
+
+	weights := cdf.([]float64)
+	i := sort.SearchFloat64s(weights, rng.Float64())
+	if i >= size {
+		i = size - 1
+	}
+	return i
+}
+
+// SampleValue returns a value an ingested span may carry for an attribute, for use in queries.
+// It samples the attribute's pool when generators have created one, and otherwise a pool of
+// the default cardinality. Unlike GetValue it never creates or resizes generator pools.
+func (cm *CardinalityManager) SampleValue(attrName string, rng *rand.Rand) string {
+	cm.mu.RLock()
+	pool, exists := cm.valuePools[attrName]
+	if !exists {
+		pool, exists = cm.samplePools[attrName]
+	}
+	cm.mu.RUnlock()
+
+	if !exists {
+		cardinality := DefaultCardinality(attrName)
+		if cardinality == 0 {
+			return cm.generateUniqueValue(attrName, rng)
+		}
+
+		cm.mu.Lock()
+		pool, exists = cm.samplePools[attrName]
+		if !exists {
+			pool = cm.generateValuePool(attrName, cardinality, poolRand(attrName))
+			cm.samplePools[attrName] = pool
+		}
+		cm.mu.Unlock()
+	}
 
-	// Return random value from pool
 	return pool[rng.Intn(len(pool))]
 }
 
+// poolRand returns the source of an attribute's value pool. It is seeded with the attribute name,
+// so pools do not depend on which trace creates them, and a larger pool extends a smaller one.
+func poolRand(attrName string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(attrName))
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
 // generateValuePool creates a pool of values for an attribute
 func (cm *CardinalityManager) generateValuePool(attrName string, size int, rng *rand.Rand) []string {
 	pool := make([]string, 0, size)
@@ -284,4 +379,5 @@ func (cm *CardinalityManager) ResetPools() {
 
 	cm.valuePools = make(map[string][]string)
 	cm.cardinality = make(map[string]int)
+	cm.samplePools = make(map[string][]string)
 }
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Malformations of chaos traces, which Tempo is expected to reject, truncate or discard
+const (
+	ChaosOversizeAttribute = "oversizeAttribute" // A span attribute value of chaosAttributeBytes bytes
+	ChaosInvalidUTF8       = "invalidUtf8"       // A span name and attribute value that are not valid UTF-8
+	ChaosZeroTraceID       = "zeroTraceId"       // All spans have the all-zero trace ID
+	ChaosDuplicateSpanID   = "duplicateSpanId"   // A span reuses the span ID of another span of the trace (all-zero in single-span traces)
+	ChaosAbsurdTimestamp   = "absurdTimestamp"   // A span ends before it starts, starts at the Unix epoch or a century from now
+)
+
+// ChaosAttribute is the resource attribute naming the malformation of a chaos trace
+const ChaosAttribute = "k6.chaos"
+
+// defaultChaosAttributeBytes is the size of oversize attribute values when chaosAttributeBytes is unset
+const defaultChaosAttributeBytes = 64 * 1024
+
+// chaosKinds lists the supported malformations in selection order
+var chaosKinds = []string{
+	ChaosOversizeAttribute,
+	ChaosInvalidUTF8,
+	ChaosZeroTraceID,
+	ChaosDuplicateSpanID,
+	ChaosAbsurdTimestamp,
+}
+
+// isChaosKind reports whether kind is a supported malformation
+func isChaosKind(kind string) bool {
+	for _, k := range chaosKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// injectChaos malforms a trace with probability config.ChaosRate and returns the malformation,
+// or "" when the trace is left intact
+func injectChaos(spans []ptrace.Span, config Config, rng *rand.Rand) string {
+	if config.ChaosRate <= 0 || len(spans) == 0 || rng.Float64() >= config.ChaosRate {
+		return ""
+	}
+
+	kinds := config.ChaosKinds
+	if len(kinds) == 0 {
+		kinds = chaosKinds
+	}
+	kind := kinds[rng.Intn(len(kinds))]
+	span := spans[rng.Intn(len(spans))]
+
+	switch kind {
+	case ChaosOversizeAttribute:
+		size := config.ChaosAttributeBytes
+		if size <= 0 {
+			size = defaultChaosAttributeBytes
+		}
+		span.Attributes().PutStr("chaos.oversize", strings.Repeat("x", size))
+	case ChaosInvalidUTF8:
+		span.SetName(span.Name() + "\xff\xfe")
+		span.Attributes().PutStr("chaos.invalid_utf8", "value-\xc3\x28-\xa0\xa1")
+	case ChaosZeroTraceID:
+		for _, s := range spans {
+			s.SetTraceID(pcommon.NewTraceIDEmpty())
+		}
+	case ChaosDuplicateSpanID:
+		if len(spans) < 2 {
+			// Nothing to duplicate, so use the invalid all-zero span ID instead
+			span.SetSpanID(pcommon.NewSpanIDEmpty())
+			break
+		}
+		other := spans[rng.Intn(len(spans))]
+		for other == span {
+			other = spans[rng.Intn(len(spans))]
+		}
+		span.SetSpanID(other.SpanID())
+	case ChaosAbsurdTimestamp:
+		start, end := span.StartTimestamp(), span.EndTimestamp()
+		switch rng.Intn(3) {
+		case 0:
+			span.SetStartTimestamp(end)
+			span.SetEndTimestamp(start)
+		case 1:
+			span.SetStartTimestamp(0)
+			span.SetEndTimestamp(end - start)
+		default:
+			century := pcommon.Timestamp(100 * 365 * 24 * time.Hour)
+			span.SetStartTimestamp(start + century)
+			span.SetEndTimestamp(end + century)
+		}
+	}
+	return kind
+}
+
+// ChaosSpans returns the number of spans of chaos traces in traces, per malformation
+func ChaosSpans(traces ptrace.Traces) map[string]int {
+	var counts map[string]int
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+		kind, ok := rs.Resource().Attributes().Get(ChaosAttribute)
+		if !ok {
+			continue
+		}
+		spans := 0
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans += rs.ScopeSpans().At(j).Spans().Len()
+		}
+		if counts == nil {
+			counts = make(map[string]int)
+		}
+		counts[kind.AsString()] += spans
+	}
+	return counts
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"strings"
 )
 
 const (
@@ -21,12 +22,72 @@ type Config struct {
 	EventCount         int               `js:"eventCount"`         // Number of events/logs per span (default: 0, must be >= 0)
 	ResourceAttributes map[string]string `js:"resourceAttributes"` // Resource-level attributes (default: empty map, auto-generated if empty)
 
+	// Attribute value entropy
+	AttributeValueMode string  `js:"attributeValueMode"` // Filler values: "random" (hex, incompressible), "text" (templated natural text, compressible) or "mixed" (default: "random")
+	TextValueRatio     float64 `js:"textValueRatio"`     // Share of text filler values in "mixed" mode (default: 0.5, range: 0.0-1.0)
+
+	// Attribute key cardinality
+	AttributeKeyPool int     `js:"attributeKeyPool"` // Distinct custom attribute keys across all spans, each span drawing attributeCount of them (default: 0, attribute.0 to attribute.<attributeCount-1> on every span; must be >= attributeCount)
+	AttributeKeySkew float64 `js:"attributeKeySkew"` // Zipf exponent of key draws from the pool; keys early in the pool dominate (default: 0, uniform; must be >= 0)
+
+	// Attribute value types
+	AttributeTypeWeights    map[string]float64  `js:"attributeTypeWeights"`    // Value type mix of custom attributes, e.g., {"string": 0.5, "int": 0.2, "double": 0.1, "bool": 0.1, "stringArray": 0.05, "kvlist": 0.05} (default: empty map, all strings)
+	ResourceArrayAttributes map[string][]string `js:"resourceArrayAttributes"` // Array-valued resource attributes (default: empty map)
+
+	// Instrumentation scope
+	InstrumentationScopes []InstrumentationScope `js:"instrumentationScopes"` // Pool of instrumentation scopes, one picked per service (default: empty, built-in pool)
+
+	// Schema URLs
+	ResourceSchemaURL string `js:"resourceSchemaUrl"` // schema_url of resources, e.g. "https://opentelemetry.io/schemas/1.26.0" (default: empty)
+	ScopeSchemaURL    string `js:"scopeSchemaUrl"`    // schema_url of instrumentation scopes (default: empty)
+
+	// Span links
+	LinkCount int    `js:"linkCount"` // Number of links per span (default: 0, must be >= 0)
+	LinkMode  string `js:"linkMode"`  // "intra-trace", "cross-trace" or "random" (default: "random")
+
 	// Duration/timing configuration
 	DurationBaseMs     int `js:"durationBaseMs"`     // Base duration in milliseconds (default: 50, must be > 0)
 	DurationVarianceMs int `js:"durationVarianceMs"` // Standard deviation for duration in milliseconds (default: 30, must be >= 0)
 
+	// Latency distribution (durationBaseMs is the normal mean, lognormal median, pareto minimum or bimodal hit mean)
+	DurationDistribution string  `js:"durationDistribution"` // "normal", "lognormal", "pareto" or "bimodal" (default: "normal")
+	DurationSigma        float64 `js:"durationSigma"`        // Lognormal shape (default: 0.5, must be >= 0)
+	DurationParetoAlpha  float64 `js:"durationParetoAlpha"`  // Pareto tail index (default: 1.5, must be >= 0)
+	DurationHitRate      float64 `js:"durationHitRate"`      // Bimodal share of fast (cache hit) spans (default: 0.8, range: 0.0-1.0)
+	DurationMissMs       int     `js:"durationMissMs"`       // Bimodal mean of slow (cache miss) spans (default: 0, meaning 10x durationBaseMs)
+
+	// Timestamp anomalies
+	ClockSkewMs     map[string]int `js:"clockSkewMs"`     // Clock offset per service in milliseconds, may be negative (default: empty map)
+	EarlyChildRate  float64        `js:"earlyChildRate"`  // Probability a child span starts before its parent (default: 0, range: 0.0-1.0)
+	LateChildRate   float64        `js:"lateChildRate"`   // Probability a child span ends after its parent (default: 0, range: 0.0-1.0)
+	AnomalyOffsetMs int            `js:"anomalyOffsetMs"` // Max milliseconds anomalous children cross their parent's bounds by (default: 50, must be > 0)
+
+	// Broken traces
+	OrphanSpanRate float64 `js:"orphanSpanRate"` // Probability a non-root span references a parent that is not in the trace (default: 0, range: 0.0-1.0)
+	DropRootRate   float64 `js:"dropRootRate"`   // Probability a trace is emitted without its root span (default: 0, range: 0.0-1.0)
+
+	// Chaos (malformed payloads for negative testing)
+	ChaosRate           float64  `js:"chaosRate"`           // Probability a trace is malformed (default: 0, range: 0.0-1.0)
+	ChaosKinds          []string `js:"chaosKinds"`          // Malformations to pick from: "oversizeAttribute", "invalidUtf8", "zeroTraceId", "duplicateSpanId", "absurdTimestamp" (default: all)
+	ChaosAttributeBytes int      `js:"chaosAttributeBytes"` // Size of oversize attribute values in bytes (default: 65536, must be > 0)
+
+	// Messaging (asynchronous producer/consumer flows)
+	MessagingRate   float64 `js:"messagingRate"`   // Probability a trace publishes a message consumed in a trace of its own (default: 0, range: 0.0-1.0)
+	MessagingSystem string  `js:"messagingSystem"` // messaging.system of producer and consumer spans (default: "kafka")
+	QueueDelayMinMs int     `js:"queueDelayMinMs"` // Min time messages wait in the queue (default: 5, must be >= 0)
+	QueueDelayMaxMs int     `js:"queueDelayMaxMs"` // Max time messages wait in the queue (default: 500, must be >= queueDelayMinMs)
+
+	// Trace timing
+	StartWindowMs  int     `js:"startWindowMs"`  // Window in milliseconds before baseTimeMs or now that trace start times are spread over, in whole seconds; 0 starts all traces at the base time (default: 3600000, must be 0 or >= 1000)
+	LongTraceRate  float64 `js:"longTraceRate"`  // Probability a trace is stretched to last minutes to hours, like a batch job or saga (default: 0, range: 0.0-1.0)
+	LongTraceMinMs int     `js:"longTraceMinMs"` // Min duration of long traces (default: 60000, must be > 0)
+	LongTraceMaxMs int     `js:"longTraceMaxMs"` // Max duration of long traces, drawn log-uniformly from the min (default: 3600000, must be >= longTraceMinMs)
+
 	// Error injection
-	ErrorRate float64 `js:"errorRate"` // Probability of error status (default: 0.02, range: 0.0-1.0)
+	ErrorRate            float64 `js:"errorRate"`            // Probability of error status (default: 0.02, range: 0.0-1.0)
+	ErrorPropagationRate float64 `js:"errorPropagationRate"` // Probability the parent of an error span fails too, repeated up to the root (default: 0, range: 0.0-1.0)
+	ExceptionEvents      bool    `js:"exceptionEvents"`      // Add an "exception" event to error spans (default: false)
+	StacktraceFrames     int     `js:"stacktraceFrames"`     // Frames in exception.stacktrace (default: 20, must be >= 0)
 
 	// Span kind distribution (weights are normalized internally if they don't sum to 1.0)
 	SpanKindWeights map[string]float64 `js:"spanKindWeights"` // Distribution weights, e.g., {"server": 0.35, "client": 0.35, "internal": 0.20, "producer": 0.05, "consumer": 0.05}
@@ -36,7 +97,10 @@ type Config struct {
 	FanOutVariance float64 `js:"fanOutVariance"` // Variance in fan-out (default: 0.5, range: 0.0-1.0)
 
 	// Semantic attributes
-	UseSemanticAttributes bool `js:"useSemanticAttributes"` // Use OpenTelemetry semantic conventions (default: true)
+	UseSemanticAttributes bool                `js:"useSemanticAttributes"` // Use OpenTelemetry semantic conventions (default: true)
+	RealisticValues       bool                `js:"realisticValues"`       // Realistic URLs, client IPs, user agents, emails and SQL in semantic attributes (default: false)
+	SQLStatementLength    int                 `js:"sqlStatementLength"`    // Min length of realistic SQL statements (default: 0, natural length; must be >= 0)
+	SemanticPacks         map[string][]string `js:"semanticPacks"`         // Attribute packs per service, "*" for all others (default: http, plus db, cache or rpc for built-in services)
 
 	// Workflow-based generation (mutually exclusive with tree-based generation)
 	UseWorkflows              bool               `js:"useWorkflows"`              // Enable workflow-based trace generation (default: false)
@@ -44,13 +108,31 @@ type Config struct {
 	BusinessAttributesDensity float64            `js:"businessAttributesDensity"` // How many business attrs per span (default: 0.8, range: 0.0-1.0)
 
 	// Cardinality and tags
-	CardinalityConfig map[string]int `js:"cardinalityConfig"` // Override cardinality per attribute (default: empty map, optional)
-	EnableTags        bool           `js:"enableTags"`        // Enable additional tag generation (default: false)
-	TagDensity        float64        `js:"tagDensity"`        // Probability of adding tags (default: 0.9, range: 0.0-1.0)
+	CardinalityConfig map[string]int     `js:"cardinalityConfig"` // Override cardinality per attribute (default: empty map, optional)
+	CardinalitySkew   map[string]float64 `js:"cardinalitySkew"`   // Zipf exponent per attribute; values early in the pool dominate, e.g. {"customer_id": 1.1} (default: empty map, uniform; must be >= 0)
+	EnableTags        bool               `js:"enableTags"`        // Enable additional tag generation (default: false)
+	TagDensity        float64            `js:"tagDensity"`        // Probability of adding tags (default: 0.9, range: 0.0-1.0)
 
 	// Tree-based generation (mutually exclusive with workflow-based generation)
 	UseTraceTree    bool             `js:"useTraceTree"` // Enable tree-based trace generation (default: false)
 	TraceTreeConfig *TraceTreeConfig `js:"traceTree"`    // Tree configuration (default: nil, required if UseTraceTree is true)
+
+	// Topology-based generation (mutually exclusive with workflow- and tree-based generation)
+	Topology *CallGraphConfig `js:"topology"` // Weighted call graph traces are random walks over (default: nil, disabled)
+
+	// Mixed workloads
+	Profiles []TraceProfile `js:"profiles"` // Configurations picked per trace by weight, replacing the other fields except seed and baseTimeMs (default: empty, disabled)
+
+	// Trace IDs
+	TraceIDFormat string `js:"traceIdFormat"` // "random" (128 random bits), "64bit" (zeros in the high half) or "timestamp" (Unix seconds in the first 4 bytes) (default: "random")
+
+	// Sampling
+	SamplingProbability float64 `js:"samplingProbability"` // Head sampling probability recorded on spans as an "ot=p:<p>;r:<r>" tracestate and sampling.priority on roots, rounded to a power of two (default: 0, no sampling data; range: 0.0-1.0)
+	UnsampledRate       float64 `js:"unsampledRate"`       // Fraction of traces recorded as not sampled, with sampling.priority 0, when samplingProbability is set (default: 0, range: 0.0-1.0)
+
+	// Reproducibility
+	Seed       int64 `js:"seed"`       // Seed of the trace, including IDs (default: 0, random)
+	BaseTimeMs int64 `js:"baseTimeMs"` // Unix time in milliseconds timestamps are derived from (default: 0, the current time; must be >= 0)
 }
 
 // DefaultConfig returns a config with sensible defaults.
@@ -66,12 +148,69 @@ func DefaultConfig() Config {
 		EventCount:         0,
 		ResourceAttributes: make(map[string]string),
 
+		// Attribute value entropy
+		AttributeValueMode: ValueModeRandom,
+		TextValueRatio:     defaultTextValueRatio,
+
+		// Attribute key cardinality
+		AttributeKeyPool: 0,
+		AttributeKeySkew: 0,
+
+		// Attribute value types
+		AttributeTypeWeights:    make(map[string]float64),
+		ResourceArrayAttributes: make(map[string][]string),
+
+		// Schema URLs
+		ResourceSchemaURL: "",
+		ScopeSchemaURL:    "",
+
+		// Span links
+		LinkCount: 0,
+		LinkMode:  LinkModeRandom,
+
 		// Duration/timing configuration
 		DurationBaseMs:     50,
 		DurationVarianceMs: 30,
 
+		// Latency distribution
+		DurationDistribution: DistributionNormal,
+		DurationSigma:        defaultLatencySigma,
+		DurationParetoAlpha:  defaultLatencyAlpha,
+		DurationHitRate:      defaultLatencyHitRate,
+		DurationMissMs:       0,
+
+		// Timestamp anomalies
+		ClockSkewMs:     make(map[string]int),
+		EarlyChildRate:  0,
+		LateChildRate:   0,
+		AnomalyOffsetMs: defaultAnomalyOffsetMs,
+
+		// Broken traces
+		OrphanSpanRate: 0,
+		DropRootRate:   0,
+
+		// Chaos
+		ChaosRate:           0,
+		ChaosKinds:          nil,
+		ChaosAttributeBytes: defaultChaosAttributeBytes,
+
+		// Messaging
+		MessagingRate:   0,
+		MessagingSystem: defaultMessagingSystem,
+		QueueDelayMinMs: defaultQueueDelayMinMs,
+		QueueDelayMaxMs: defaultQueueDelayMaxMs,
+
+		// Trace timing
+		StartWindowMs:  defaultStartWindowMs,
+		LongTraceRate:  0,
+		LongTraceMinMs: defaultLongTraceMinMs,
+		LongTraceMaxMs: defaultLongTraceMaxMs,
+
 		// Error injection
-		ErrorRate: 0.02,
+		ErrorRate:            0.02,
+		ErrorPropagationRate: 0,
+		ExceptionEvents:      false,
+		StacktraceFrames:     20,
 
 		// Span kind distribution
 		SpanKindWeights: map[string]float64{
@@ -88,6 +227,8 @@ func DefaultConfig() Config {
 
 		// Semantic attributes
 		UseSemanticAttributes: true,
+		RealisticValues:       false,
+		SQLStatementLength:    0,
 
 		// Workflow-based generation
 		UseWorkflows:              false,
@@ -96,12 +237,30 @@ func DefaultConfig() Config {
 
 		// Cardinality and tags
 		CardinalityConfig: make(map[string]int),
+		CardinalitySkew:   make(map[string]float64),
 		EnableTags:        false,
 		TagDensity:        0.9,
 
 		// Tree-based generation
 		UseTraceTree:    false,
 		TraceTreeConfig: nil,
+
+		// Topology-based generation
+		Topology: nil,
+
+		// Mixed workloads
+		Profiles: nil,
+
+		// Trace IDs
+		TraceIDFormat: TraceIDFormatRandom,
+
+		// Sampling
+		SamplingProbability: 0,
+		UnsampledRate:       0,
+
+		// Reproducibility
+		Seed:       0,
+		BaseTimeMs: 0,
 	}
 }
 
@@ -132,6 +291,47 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("eventCount must be >= 0, got %d", c.EventCount)
 	}
 
+	// Attribute value entropy validation
+	switch c.AttributeValueMode {
+	case "", ValueModeRandom, ValueModeText, ValueModeMixed:
+	default:
+		return fmt.Errorf("attributeValueMode must be %q, %q or %q, got %q", ValueModeRandom, ValueModeText, ValueModeMixed, c.AttributeValueMode)
+	}
+	if c.TextValueRatio < 0.0 || c.TextValueRatio > 1.0 {
+		return fmt.Errorf("textValueRatio must be in range [0.0, 1.0], got %f", c.TextValueRatio)
+	}
+
+	// Attribute key cardinality validation
+	if c.AttributeKeyPool < 0 {
+		return fmt.Errorf("attributeKeyPool must be >= 0, got %d", c.AttributeKeyPool)
+	}
+	if c.AttributeKeyPool > 0 && c.AttributeKeyPool < c.AttributeCount {
+		return fmt.Errorf("attributeKeyPool must be >= attributeCount (%d), got %d", c.AttributeCount, c.AttributeKeyPool)
+	}
+	if c.AttributeKeySkew < 0 {
+		return fmt.Errorf("attributeKeySkew must be >= 0, got %f", c.AttributeKeySkew)
+	}
+
+	// Attribute value type validation
+	for attrType, weight := range c.AttributeTypeWeights {
+		if !isAttributeType(attrType) {
+			return fmt.Errorf("attributeTypeWeights: unknown attribute type %q", attrType)
+		}
+		if weight < 0 {
+			return fmt.Errorf("attributeTypeWeights: weight of %q must be >= 0, got %f", attrType, weight)
+		}
+	}
+
+	// Span link validation
+	if c.LinkCount < 0 {
+		return fmt.Errorf("linkCount must be >= 0, got %d", c.LinkCount)
+	}
+	switch c.LinkMode {
+	case "", LinkModeIntraTrace, LinkModeCrossTrace, LinkModeRandom:
+	default:
+		return fmt.Errorf("linkMode must be %q, %q or %q, got %q", LinkModeIntraTrace, LinkModeCrossTrace, LinkModeRandom, c.LinkMode)
+	}
+
 	// Duration/timing validation
 	if c.DurationBaseMs <= 0 {
 		return fmt.Errorf("durationBaseMs must be > 0, got %d", c.DurationBaseMs)
@@ -139,11 +339,89 @@ func (c *Config) Validate() error {
 	if c.DurationVarianceMs < 0 {
 		return fmt.Errorf("durationVarianceMs must be >= 0, got %d", c.DurationVarianceMs)
 	}
+	if !IsDistribution(c.DurationDistribution) {
+		return fmt.Errorf("durationDistribution must be %q, %q, %q or %q, got %q", DistributionNormal, DistributionLogNormal, DistributionPareto, DistributionBimodal, c.DurationDistribution)
+	}
+	if c.DurationSigma < 0 {
+		return fmt.Errorf("durationSigma must be >= 0, got %f", c.DurationSigma)
+	}
+	if c.DurationParetoAlpha < 0 {
+		return fmt.Errorf("durationParetoAlpha must be >= 0, got %f", c.DurationParetoAlpha)
+	}
+	if c.DurationHitRate < 0.0 || c.DurationHitRate > 1.0 {
+		return fmt.Errorf("durationHitRate must be in range [0.0, 1.0], got %f", c.DurationHitRate)
+	}
+	if c.DurationMissMs < 0 {
+		return fmt.Errorf("durationMissMs must be >= 0, got %d", c.DurationMissMs)
+	}
+
+	// Timestamp anomaly validation
+	if c.EarlyChildRate < 0.0 || c.EarlyChildRate > 1.0 {
+		return fmt.Errorf("earlyChildRate must be in range [0.0, 1.0], got %f", c.EarlyChildRate)
+	}
+	if c.LateChildRate < 0.0 || c.LateChildRate > 1.0 {
+		return fmt.Errorf("lateChildRate must be in range [0.0, 1.0], got %f", c.LateChildRate)
+	}
+	if c.AnomalyOffsetMs <= 0 {
+		return fmt.Errorf("anomalyOffsetMs must be > 0, got %d", c.AnomalyOffsetMs)
+	}
+
+	// Broken trace validation
+	if c.OrphanSpanRate < 0.0 || c.OrphanSpanRate > 1.0 {
+		return fmt.Errorf("orphanSpanRate must be in range [0.0, 1.0], got %f", c.OrphanSpanRate)
+	}
+	if c.DropRootRate < 0.0 || c.DropRootRate > 1.0 {
+		return fmt.Errorf("dropRootRate must be in range [0.0, 1.0], got %f", c.DropRootRate)
+	}
+
+	// Chaos validation
+	if c.ChaosRate < 0.0 || c.ChaosRate > 1.0 {
+		return fmt.Errorf("chaosRate must be in range [0.0, 1.0], got %f", c.ChaosRate)
+	}
+	for _, kind := range c.ChaosKinds {
+		if !isChaosKind(kind) {
+			return fmt.Errorf("unknown chaos kind %q (use one of: %s)", kind, strings.Join(chaosKinds, ", "))
+		}
+	}
+	if c.ChaosAttributeBytes <= 0 {
+		return fmt.Errorf("chaosAttributeBytes must be > 0, got %d", c.ChaosAttributeBytes)
+	}
+
+	// Messaging validation
+	if c.MessagingRate < 0.0 || c.MessagingRate > 1.0 {
+		return fmt.Errorf("messagingRate must be in range [0.0, 1.0], got %f", c.MessagingRate)
+	}
+	if c.QueueDelayMinMs < 0 {
+		return fmt.Errorf("queueDelayMinMs must be >= 0, got %d", c.QueueDelayMinMs)
+	}
+	if c.QueueDelayMaxMs < c.QueueDelayMinMs {
+		return fmt.Errorf("queueDelayMaxMs must be >= queueDelayMinMs (%d), got %d", c.QueueDelayMinMs, c.QueueDelayMaxMs)
+	}
+
+	// Trace timing validation
+	if err := c.ValidateStartWindow(); err != nil {
+		return err
+	}
+	if c.LongTraceRate < 0.0 || c.LongTraceRate > 1.0 {
+		return fmt.Errorf("longTraceRate must be in range [0.0, 1.0], got %f", c.LongTraceRate)
+	}
+	if c.LongTraceMinMs <= 0 {
+		return fmt.Errorf("longTraceMinMs must be > 0, got %d", c.LongTraceMinMs)
+	}
+	if c.LongTraceMaxMs < c.LongTraceMinMs {
+		return fmt.Errorf("longTraceMaxMs must be >= longTraceMinMs (%d), got %d", c.LongTraceMinMs, c.LongTraceMaxMs)
+	}
 
 	// Error rate validation
 	if c.ErrorRate < 0.0 || c.ErrorRate > 1.0 {
 		return fmt.Errorf("errorRate must be in range [0.0, 1.0], got %f", c.ErrorRate)
 	}
+	if c.ErrorPropagationRate < 0.0 || c.ErrorPropagationRate > 1.0 {
+		return fmt.Errorf("errorPropagationRate must be in range [0.0, 1.0], got %f", c.ErrorPropagationRate)
+	}
+	if c.StacktraceFrames < 0 {
+		return fmt.Errorf("stacktraceFrames must be >= 0, got %d", c.StacktraceFrames)
+	}
 
 	// Trace shape variance validation
 	if c.MaxFanOut <= 0 {
@@ -158,6 +436,25 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("businessAttributesDensity must be in range [0.0, 1.0], got %f", c.BusinessAttributesDensity)
 	}
 
+	// Semantic attribute validation
+	if c.SQLStatementLength < 0 {
+		return fmt.Errorf("sqlStatementLength must be >= 0, got %d", c.SQLStatementLength)
+	}
+	for service, packs := range c.SemanticPacks {
+		for _, pack := range packs {
+			if !IsAttributePack(pack) {
+				return fmt.Errorf("semanticPacks[%s]: unknown attribute pack %q", service, pack)
+			}
+		}
+	}
+
+	// Cardinality skew validation
+	for attr, skew := range c.CardinalitySkew {
+		if skew < 0 {
+			return fmt.Errorf("cardinalitySkew[%s] must be >= 0, got %f", attr, skew)
+		}
+	}
+
 	// Tag density validation
 	if c.TagDensity < 0.0 || c.TagDensity > 1.0 {
 		return fmt.Errorf("tagDensity must be in range [0.0, 1.0], got %f", c.TagDensity)
@@ -172,14 +469,71 @@ func (c *Config) Validate() error {
 	if c.UseTraceTree && c.TraceTreeConfig == nil {
 		return fmt.Errorf("traceTreeConfig is required when useTraceTree is true")
 	}
+	if c.TraceTreeConfig != nil {
+		if err := c.TraceTreeConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid traceTree: %w", err)
+		}
+	}
+
+	// Topology-based generation validation
+	if c.Topology != nil {
+		if c.UseWorkflows || c.UseTraceTree {
+			return fmt.Errorf("topology is mutually exclusive with useWorkflows and useTraceTree")
+		}
+		if err := c.Topology.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// Mixed workload validation
+	if err := c.ValidateProfiles(); err != nil {
+		return fmt.Errorf("invalid profiles: %w", err)
+	}
+
+	// Trace ID validation
+	switch c.TraceIDFormat {
+	case "", TraceIDFormatRandom, TraceIDFormat64Bit, TraceIDFormatTimestamp:
+	default:
+		return fmt.Errorf("traceIdFormat must be %q, %q or %q, got %q", TraceIDFormatRandom, TraceIDFormat64Bit, TraceIDFormatTimestamp, c.TraceIDFormat)
+	}
+
+	// Sampling validation
+	if c.SamplingProbability < 0.0 || c.SamplingProbability > 1.0 {
+		return fmt.Errorf("samplingProbability must be in range [0.0, 1.0], got %f", c.SamplingProbability)
+	}
+	if c.UnsampledRate < 0.0 || c.UnsampledRate > 1.0 {
+		return fmt.Errorf("unsampledRate must be in range [0.0, 1.0], got %f", c.UnsampledRate)
+	}
+
+	// Reproducibility validation
+	if c.BaseTimeMs < 0 {
+		return fmt.Errorf("baseTimeMs must be >= 0, got %d", c.BaseTimeMs)
+	}
 
 	return nil
 }
 
 // BatchConfig represents configuration for generating batches
 type BatchConfig struct {
-	TargetSizeBytes int    `js:"targetSizeBytes"` // Target batch size in bytes
-	TraceConfig     Config `js:"traceConfig"`     // Configuration for individual traces
+	TargetSizeBytes int     `js:"targetSizeBytes"` // Target batch size in bytes, as OTLP protobuf
+	SizeTolerance   float64 `js:"sizeTolerance"`   // Share of targetSizeBytes a batch may exceed it by (default: 0)
+	TargetSpans     int     `js:"targetSpans"`     // Target number of spans in the batch
+	TargetTraces    int     `js:"targetTraces"`    // Number of traces in the batch
+	TraceConfig     Config  `js:"traceConfig"`     // Configuration for individual traces
+}
+
+// Validate checks that the batch has a target
+func (c BatchConfig) Validate() error {
+	if c.TargetSizeBytes < 0 || c.TargetSpans < 0 || c.TargetTraces < 0 {
+		return fmt.Errorf("batch targets must be >= 0")
+	}
+	if c.SizeTolerance < 0 {
+		return fmt.Errorf("sizeTolerance must be >= 0, got %f", c.SizeTolerance)
+	}
+	if c.TargetSizeBytes == 0 && c.TargetSpans == 0 && c.TargetTraces == 0 {
+		return fmt.Errorf("one of targetSizeBytes, targetSpans or targetTraces is required")
+	}
+	return nil
 }
 
 // RateLimitConfig represents configuration for MB/s rate limiting
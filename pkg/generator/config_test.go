@@ -0,0 +1,24 @@
+package generator
+
+import "testing"
+
+func TestConfigValidateStartWindow(t *testing.T) {
+	tests := []struct {
+		startWindowMs int
+		valid         bool
+	}{
+		{startWindowMs: 0, valid: true},
+		{startWindowMs: 1000, valid: true},
+		{startWindowMs: 3600000, valid: true},
+		{startWindowMs: -1, valid: false},
+		{startWindowMs: 1, valid: false},
+		{startWindowMs: 999, valid: false},
+	}
+	for _, tt := range tests {
+		config := DefaultConfig()
+		config.StartWindowMs = tt.startWindowMs
+		if err := config.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate() with startWindowMs %d: error = %v, want valid %v", tt.startWindowMs, err, tt.valid)
+		}
+	}
+}
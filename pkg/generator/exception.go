@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// exceptionTypes maps error messages to the exception types reported for them
+var exceptionTypes = map[string]string{
+	"connection timeout":         "java.net.SocketTimeoutException",
+	"database connection failed": "java.sql.SQLTransientConnectionException",
+	"invalid request":            "java.lang.IllegalArgumentException",
+	"authentication failed":      "org.springframework.security.authentication.BadCredentialsException",
+	"rate limit exceeded":        "io.github.resilience4j.ratelimiter.RequestNotPermitted",
+	"service unavailable":        "org.springframework.web.client.HttpServerErrorException",
+	"internal server error":      "java.lang.IllegalStateException",
+	"not found":                  "java.util.NoSuchElementException",
+	"permission denied":          "java.nio.file.AccessDeniedException",
+	"request timeout":            "java.util.concurrent.TimeoutException",
+}
+
+// stacktraceClasses and stacktraceMethods are the frames stack traces are built from
+var (
+	stacktraceClasses = []string{"ApiController", "RequestHandler", "OrderService", "AccountRepository", "HttpClient", "AuthInterceptor", "TaskExecutor"}
+	stacktraceMethods = []string{"handle", "process", "execute", "invoke", "call", "doFilter", "run", "apply"}
+)
+
+// addExceptionEvent adds an exception event following the OpenTelemetry semantic conventions for
+// the error status of a span
+func addExceptionEvent(span ptrace.Span, serviceName string, frames int, rng *rand.Rand) {
+	message := span.Status().Message()
+	exceptionType, ok := exceptionTypes[message]
+	if !ok {
+		exceptionType = "java.lang.RuntimeException"
+	}
+
+	event := span.Events().AppendEmpty()
+	event.SetTimestamp(span.EndTimestamp())
+	event.SetName("exception")
+	attrs := event.Attributes()
+	attrs.EnsureCapacity(3)
+	attrs.PutStr("exception.type", exceptionType)
+	attrs.PutStr("exception.message", message)
+	attrs.PutStr("exception.stacktrace", generateStacktrace(exceptionType, message, serviceName, frames, rng))
+}
+
+// generateStacktrace generates a Java-style multi-line stack trace with the given number of frames
+func generateStacktrace(exceptionType, message, serviceName string, frames int, rng *rand.Rand) string {
+	pkg := "com.example." + strings.ReplaceAll(serviceName, "-", "")
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s", exceptionType, message)
+	for i := 0; i < frames; i++ {
+		class := stacktraceClasses[rng.Intn(len(stacktraceClasses))]
+		method := stacktraceMethods[rng.Intn(len(stacktraceMethods))]
+		fmt.Fprintf(&sb, "\n\tat %s.%s.%s(%s.java:%d)", pkg, class, method, class, 20+rng.Intn(480))
+	}
+	return sb.String()
+}
@@ -0,0 +1,204 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Root span stretching of huge traces: spans last about hugeTraceShrink of their parent, so the
+// root span is stretched by its inverse per level, up to hugeTraceMaxStretchLevels levels, for
+// leaf spans to last about durationBaseMs
+const (
+	hugeTraceShrink           = 0.65
+	hugeTraceMaxStretchLevels = 16
+)
+
+// HugeTraceIterator generates a single trace of tens or hundreds of thousands of spans in parts
+// sharing its trace ID, so it can be exported across several requests without being held in memory
+// at once. The spans form a complete tree, generated level by level so parents come first, whose
+// fan-out is the smallest one of at least maxFanOut fitting the spans within spanDepth levels.
+// Spans get the attributes, events, tags, errors, services and sampling data of the config, and
+// long traces the long duration; trace shapes (workflows, trees, topologies) and trace-level anomalies do not apply.
+type HugeTraceIterator struct {
+	config    Config
+	rng       *rand.Rand
+	traceID   pcommon.TraceID
+	tagCtx    *TagContext
+	fanOut    int
+	spanCount int
+	spans     []hugeSpan             // Generated spans, so the spans of later parts can reference their parents
+	services  []ptrace.ResourceSpans // Resource and scope of each service, copied into the parts
+	long      time.Duration          // Duration of the root span of long traces
+	longStart time.Time              // Start of the root span of long traces
+	sampling  *samplingDecision      // Sampling decision of the trace, when sampling data is enabled
+}
+
+// hugeSpan is what the children of a span of a huge trace need from it
+type hugeSpan struct {
+	id         pcommon.SpanID
+	start, end pcommon.Timestamp
+	depth      int
+}
+
+// HugeTracePart is a part of a huge trace, holding the next spans in generation order
+type HugeTracePart struct {
+	Trace     ptrace.Traces `js:"trace"`     // The spans of the part, to push with push
+	TraceID   string        `js:"traceId"`   // ID of the trace, shared by all parts
+	SpanCount int           `js:"spanCount"` // Spans of the part
+	SizeBytes int           `js:"sizeBytes"` // OTLP protobuf size of the part
+	Remaining int           `js:"remaining"` // Spans of the trace left for the next parts
+}
+
+// NewHugeTraceIterator creates an iterator generating a trace of spanCount spans with the given
+// configuration, or one of its profiles; seeded iterators generate the same trace
+func NewHugeTraceIterator(config Config, spanCount int) (*HugeTraceIterator, error) {
+	if spanCount <= 0 {
+		return nil, fmt.Errorf("spanCount must be > 0, got %d", spanCount)
+	}
+	if len(config.Profiles) > 0 {
+		config = selectProfile(config)
+	}
+
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	it := &HugeTraceIterator{
+		config:    config,
+		rng:       rng,
+		traceID:   newTraceID(config, idRand(config, rng)),
+		tagCtx:    GenerateTagContext(config, rng),
+		fanOut:    hugeTraceFanOut(spanCount, config.MaxFanOut, config.SpanDepth),
+		spanCount: spanCount,
+		spans:     make([]hugeSpan, 0, spanCount),
+	}
+	if config.LongTraceRate > 0 {
+		longRNG := longTraceRand(config)
+		if duration, ok := longTraceDuration(config, longRNG); ok {
+			it.long = duration
+			it.longStart = longTraceStart(config, duration, longRNG)
+		}
+	}
+	if config.SamplingProbability > 0 {
+		decision := newSamplingDecision(config, samplingRand(config))
+		it.sampling = &decision
+	}
+
+	for i := 0; i < max(config.Services, 1); i++ {
+		serviceName := generateServiceName(i)
+		rs := ptrace.NewResourceSpans()
+		resourceAttrs := generateResourceAttributes(serviceName, rng)
+		resourceAttrs["service.name"] = serviceName
+		putStringAttributes(rs.Resource().Attributes(), resourceAttrs)
+		putResourceArrayAttributes(rs.Resource().Attributes(), config)
+		setInstrumentationScope(rs.ScopeSpans().AppendEmpty().Scope(), config.InstrumentationScopes, rng)
+		setResourceSchemaURLs(rs, config)
+		it.services = append(it.services, rs)
+	}
+	return it, nil
+}
+
+// NextPart generates the next part of the trace, up to maxSpans spans, grouped by service. Once
+// all spans were generated, parts hold no spans.
+func (it *HugeTraceIterator) NextPart(maxSpans int) (*HugeTracePart, error) {
+	if maxSpans <= 0 {
+		return nil, fmt.Errorf("maxSpans must be > 0, got %d", maxSpans)
+	}
+
+	trace := ptrace.NewTraces()
+	serviceSpans := make(map[int]ptrace.SpanSlice, len(it.services))
+	n := min(maxSpans, it.Remaining())
+	for i := 0; i < n; i++ {
+		service := len(it.spans) % len(it.services)
+		spans, ok := serviceSpans[service]
+		if !ok {
+			rs := trace.ResourceSpans().AppendEmpty()
+			it.services[service].CopyTo(rs)
+			spans = rs.ScopeSpans().At(0).Spans()
+			serviceSpans[service] = spans
+		}
+		span := spans.AppendEmpty()
+		it.nextSpan(service).MoveTo(span)
+		if it.sampling != nil {
+			it.sampling.apply(span)
+		}
+	}
+
+	return &HugeTracePart{
+		Trace:     trace,
+		TraceID:   it.traceID.String(),
+		SpanCount: n,
+		SizeBytes: estimateTraceSize(trace),
+		Remaining: it.Remaining(),
+	}, nil
+}
+
+// Remaining returns the number of spans of the trace not generated yet
+func (it *HugeTraceIterator) Remaining() int {
+	return it.spanCount - len(it.spans)
+}
+
+// nextSpan generates the next span of the tree on the given service. Children start within the
+// first tenth of their parent and last half to nine tenths of the rest of it.
+func (it *HugeTraceIterator) nextSpan(service int) ptrace.Span {
+	index := len(it.spans)
+	serviceName := generateServiceName(service)
+
+	if index == 0 {
+		start := traceStartTime(it.config, it.rng)
+		span := buildSpanWithContext(it.traceID, pcommon.NewSpanIDEmpty(), 0, 0, serviceName, it.config, start, it.rng, nil, it.tagCtx, "")
+		levels := min(hugeTraceLevels(it.spanCount, it.fanOut), hugeTraceMaxStretchLevels)
+		duration := time.Duration(float64(span.EndTimestamp()-span.StartTimestamp()) * math.Pow(1/hugeTraceShrink, float64(levels)))
+		if it.long > 0 {
+			start, duration = it.longStart, it.long
+		}
+		retimeSpan(span, start, start.Add(duration))
+		it.spans = append(it.spans, hugeSpan{id: span.SpanID(), start: span.StartTimestamp(), end: span.EndTimestamp()})
+		return span
+	}
+
+	parent := it.spans[(index-1)/it.fanOut]
+	parentDuration := float64(parent.end - parent.start)
+	start := parent.start + pcommon.Timestamp(it.rng.Float64()*0.1*parentDuration)
+	end := start + pcommon.Timestamp((0.5+0.4*it.rng.Float64())*float64(parent.end-start))
+
+	span := buildSpanWithContext(it.traceID, parent.id, index, parent.depth+1, serviceName, it.config, start.AsTime(), it.rng, nil, it.tagCtx, "")
+	retimeSpan(span, start.AsTime(), end.AsTime())
+	it.spans = append(it.spans, hugeSpan{id: span.SpanID(), start: start, end: end, depth: parent.depth + 1})
+	return span
+}
+
+// hugeTraceFanOut returns the smallest fan-out of at least maxFanOut with which a complete tree
+// of spanCount spans is at most spanDepth levels deep below its root
+func hugeTraceFanOut(spanCount, maxFanOut, spanDepth int) int {
+	fanOut := max(maxFanOut, 1)
+	if spanDepth <= 0 {
+		return fanOut
+	}
+	for hugeTraceLevels(spanCount, fanOut) > spanDepth {
+		fanOut++
+	}
+	return fanOut
+}
+
+// hugeTraceLevels returns the number of levels below its root of a complete tree of spanCount
+// spans with the given fan-out
+func hugeTraceLevels(spanCount, fanOut int) int {
+	if fanOut <= 1 {
+		return spanCount - 1
+	}
+	levels, capacity, width := 0, 1, 1
+	for capacity < spanCount {
+		width *= fanOut
+		capacity += width
+		levels++
+	}
+	return levels
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	cryptoRand "crypto/rand"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// KnownAnswerServices are the services known-answer traces are spread across
+var KnownAnswerServices = []string{"ka-frontend", "ka-checkout", "ka-inventory"}
+
+// KnownAnswerSpec describes the controlled properties of one known-answer trace
+type KnownAnswerSpec struct {
+	Index    int
+	Service  string
+	Bucket   int           // Value of the ka.bucket span attribute (0-9)
+	Duration time.Duration // Root span duration, always a whole number of milliseconds
+	Error    bool          // Root span has error status
+}
+
+// KnownAnswerSpecs returns count trace specs derived deterministically from seed
+func KnownAnswerSpecs(count int, seed int64) []KnownAnswerSpec {
+	rng := rand.New(rand.NewSource(seed))
+	specs := make([]KnownAnswerSpec, count)
+	for i := range specs {
+		specs[i] = KnownAnswerSpec{
+			Index:    i,
+			Service:  KnownAnswerServices[rng.Intn(len(KnownAnswerServices))],
+			Bucket:   rng.Intn(10),
+			Duration: time.Duration(20+rng.Intn(200)) * time.Millisecond,
+			Error:    rng.Float64() < 0.2,
+		}
+	}
+	return specs
+}
+
+// GenerateKnownAnswerTraces builds one trace per spec, all tagged with runID.
+// Each trace has a root span carrying the controlled properties and a child span half as long.
+func GenerateKnownAnswerTraces(runID string, specs []KnownAnswerSpec) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	now := time.Now()
+
+	for _, spec := range specs {
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", spec.Service)
+		rs.Resource().Attributes().PutStr(RunIDAttribute, runID)
+
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+
+		var traceID [16]byte
+		cryptoRand.Read(traceID[:])
+		start := now.Add(-spec.Duration)
+
+		root := spans.AppendEmpty()
+		root.SetTraceID(pcommon.TraceID(traceID))
+		root.SetSpanID(newMarkerSpanID())
+		root.SetName("ka-request")
+		root.SetKind(ptrace.SpanKindServer)
+		root.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		root.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(spec.Duration)))
+		root.Attributes().PutInt("ka.index", int64(spec.Index))
+		root.Attributes().PutInt("ka.bucket", int64(spec.Bucket))
+		if spec.Error {
+			root.Status().SetCode(ptrace.StatusCodeError)
+			root.Status().SetMessage("known-answer error")
+		} else {
+			root.Status().SetCode(ptrace.StatusCodeOk)
+		}
+
+		child := spans.AppendEmpty()
+		child.SetTraceID(root.TraceID())
+		child.SetSpanID(newMarkerSpanID())
+		child.SetParentSpanID(root.SpanID())
+		child.SetName("ka-step")
+		child.SetKind(ptrace.SpanKindInternal)
+		child.SetStartTimestamp(root.StartTimestamp())
+		child.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(spec.Duration / 2)))
+	}
+
+	return traces
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Latency distributions for span durations
+const (
+	DistributionNormal    = "normal"    // Mean baseMs, standard deviation varianceMs
+	DistributionLogNormal = "lognormal" // Median baseMs, shape sigma
+	DistributionPareto    = "pareto"    // Minimum baseMs, tail index alpha
+	DistributionBimodal   = "bimodal"   // Cache hits around baseMs, misses around missMs
+)
+
+// Latency distribution defaults
+const (
+	defaultLatencySigma   = 0.5
+	defaultLatencyAlpha   = 1.5
+	defaultLatencyHitRate = 0.8
+	defaultMissFactor     = 10   // Default missMs as a multiple of baseMs
+	maxParetoFactor       = 1000 // Pareto samples are capped at this multiple of baseMs
+)
+
+// IsDistribution reports whether d is a supported latency distribution ("" means normal)
+func IsDistribution(d string) bool {
+	switch d {
+	case "", DistributionNormal, DistributionLogNormal, DistributionPareto, DistributionBimodal:
+		return true
+	}
+	return false
+}
+
+// sampleDurationMs draws a duration in milliseconds from the distribution of dur, with base and
+// variance already defaulted
+func sampleDurationMs(dur DurationConfig, base, variance float64, rng *rand.Rand) float64 {
+	switch dur.Distribution {
+	case DistributionLogNormal:
+		sigma := dur.Sigma
+		if sigma <= 0 {
+			sigma = defaultLatencySigma
+		}
+		return base * math.Exp(sigma*rng.NormFloat64())
+	case DistributionPareto:
+		alpha := dur.Alpha
+		if alpha <= 0 {
+			alpha = defaultLatencyAlpha
+		}
+		// 1-Float64() is in (0, 1], so the sample is >= base
+		return math.Min(base*math.Pow(1-rng.Float64(), -1/alpha), base*maxParetoFactor)
+	case DistributionBimodal:
+		hitRate := dur.HitRate
+		if hitRate <= 0 {
+			hitRate = defaultLatencyHitRate
+		}
+		if rng.Float64() < hitRate {
+			return base + rng.NormFloat64()*variance
+		}
+		miss := float64(dur.MissMs)
+		if miss <= 0 {
+			miss = base * defaultMissFactor
+		}
+		// Misses keep the relative spread of hits
+		return miss + rng.NormFloat64()*variance*miss/base
+	default:
+		return base + rng.NormFloat64()*variance
+	}
+}
+
+// durationConfig returns the span duration settings of a generator config
+func (c Config) durationConfig() DurationConfig {
+	return DurationConfig{
+		BaseMs:       c.DurationBaseMs,
+		VarianceMs:   c.DurationVarianceMs,
+		Distribution: c.DurationDistribution,
+		Sigma:        c.DurationSigma,
+		Alpha:        c.DurationParetoAlpha,
+		HitRate:      c.DurationHitRate,
+		MissMs:       c.DurationMissMs,
+	}
+}
+
+// calculateDurationFromConfig calculates a duration from a duration config
+func calculateDurationFromConfig(dur DurationConfig, rng *rand.Rand) time.Duration {
+	base := float64(dur.BaseMs)
+	if base <= 0 {
+		base = 50 // default
+	}
+	variance := float64(dur.VarianceMs)
+	if variance < 0 {
+		variance = 30 // default
+	}
+
+	duration := sampleDurationMs(dur, base, variance, rng)
+	if duration < 1 {
+		duration = 1
+	}
+	return time.Duration(duration) * time.Millisecond
+}
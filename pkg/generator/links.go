@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"math/rand"
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Span link modes
+const (
+	LinkModeIntraTrace = "intra-trace" // Links point to other spans of the same trace
+	LinkModeCrossTrace = "cross-trace" // Links point to spans of previously generated traces
+	LinkModeRandom     = "random"      // Each link is intra- or cross-trace at random
+)
+
+// linkReasons are the values of the link.reason attribute
+var linkReasons = []string{"follows_from", "retry", "batch", "fan_in", "async_callback"}
+
+// linkTargetCapacity bounds the spans kept for cross-trace links
+const linkTargetCapacity = 1024
+
+// linkTarget identifies a span that links may point to
+type linkTarget struct {
+	traceID pcommon.TraceID
+	spanID  pcommon.SpanID
+}
+
+// linkTargets keeps spans of recently generated traces, so cross-trace links point to traces that
+// were actually generated and, most likely, ingested
+var linkTargets = &linkTargetRing{}
+
+// linkTargetRing is a fixed-size ring of link targets shared by all VUs
+type linkTargetRing struct {
+	mu      sync.Mutex
+	targets []linkTarget
+	next    int
+}
+
+// add records a span as a link target, replacing the oldest one when the ring is full
+func (r *linkTargetRing) add(target linkTarget) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.targets) < linkTargetCapacity {
+		r.targets = append(r.targets, target)
+		return
+	}
+	r.targets[r.next] = target
+	r.next = (r.next + 1) % linkTargetCapacity
+}
+
+// random returns a recorded link target; ok is false while none was recorded
+func (r *linkTargetRing) random(rng *rand.Rand) (target linkTarget, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.targets) == 0 {
+		return linkTarget{}, false
+	}
+	return r.targets[rng.Intn(len(r.targets))], true
+}
+
+// addSpanLinks adds config.LinkCount links with attributes to every span of a trace, then records
+// a span of the trace as a target for cross-trace links of later traces
+func addSpanLinks(spans []ptrace.Span, config Config, rng *rand.Rand) {
+	if config.LinkCount <= 0 || len(spans) == 0 {
+		return
+	}
+
+	for _, span := range spans {
+		links := span.Links()
+		links.EnsureCapacity(config.LinkCount)
+		for i := 0; i < config.LinkCount; i++ {
+			mode := config.LinkMode
+			if mode != LinkModeIntraTrace && mode != LinkModeCrossTrace {
+				mode = LinkModeIntraTrace
+				if rng.Float64() < DensityMediumLow {
+					mode = LinkModeCrossTrace
+				}
+			}
+
+			var target linkTarget
+			if mode == LinkModeIntraTrace && len(spans) > 1 {
+				// Link to any other span of the trace
+				other := spans[rng.Intn(len(spans)-1)]
+				if other == span {
+					other = spans[len(spans)-1]
+				}
+				target = linkTarget{traceID: other.TraceID(), spanID: other.SpanID()}
+			} else {
+				mode = LinkModeCrossTrace
+				var ok bool
+				if target, ok = linkTargets.random(rng); !ok {
+					// No trace generated before this one; link to an unknown trace
+					ids := idRand(config, rng)
+					target = linkTarget{traceID: generateTraceID(ids), spanID: generateSpanID(ids)}
+				}
+			}
+
+			link := links.AppendEmpty()
+			link.SetTraceID(target.traceID)
+			link.SetSpanID(target.spanID)
+			link.Attributes().PutStr("link.type", mode)
+			link.Attributes().PutStr("link.reason", linkReasons[rng.Intn(len(linkReasons))])
+		}
+	}
+
+	target := spans[rng.Intn(len(spans))]
+	linkTargets.add(linkTarget{traceID: target.TraceID(), spanID: target.SpanID()})
+}
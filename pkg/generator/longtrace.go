@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Long-duration trace and start window defaults
+const (
+	defaultLongTraceMinMs = 60 * 1000
+	defaultLongTraceMaxMs = 60 * 60 * 1000
+	defaultStartWindowMs  = 60 * 60 * 1000
+)
+
+// longTraceSeed separates the random stream of long traces from the one of the trace
+const longTraceSeed = 0x6c6f6e67
+
+// longTraceRand returns the source of a trace's stretching; seeded traces get a source derived
+// from their seed, so enabling long traces leaves the rest of the trace unchanged
+func longTraceRand(config Config) *rand.Rand {
+	if config.Seed != 0 {
+		return rand.New(rand.NewSource(DeriveSeed(config.Seed, longTraceSeed)))
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// traceStartTime draws the start of a trace within the start window before the base time, in
+// whole seconds
+func traceStartTime(config Config, rng *rand.Rand) time.Time {
+	windowSeconds := startWindow(config) / time.Second
+	if windowSeconds < 1 {
+		return baseTime(config)
+	}
+	return baseTime(config).Add(-time.Duration(rng.Intn(int(windowSeconds))) * time.Second)
+}
+
+// ValidateStartWindow checks startWindowMs. Start times are drawn in whole seconds, so a window
+// under a second would not spread them and is rejected.
+func (c *Config) ValidateStartWindow() error {
+	if c.StartWindowMs < 0 {
+		return fmt.Errorf("startWindowMs must be >= 0, got %d", c.StartWindowMs)
+	}
+	if c.StartWindowMs > 0 && c.StartWindowMs < 1000 {
+		return fmt.Errorf("startWindowMs must be 0 or at least 1000, got %d", c.StartWindowMs)
+	}
+	return nil
+}
+
+// startWindow returns the window trace start times are spread over; 0 means no spread
+func startWindow(config Config) time.Duration {
+	return time.Duration(max(config.StartWindowMs, 0)) * time.Millisecond
+}
+
+// longTraceDuration decides with probability config.LongTraceRate whether a trace is long, and
+// draws its duration log-uniformly between the configured bounds, so minutes and hours are
+// equally common
+func longTraceDuration(config Config, rng *rand.Rand) (time.Duration, bool) {
+	if config.LongTraceRate <= 0 || rng.Float64() >= config.LongTraceRate {
+		return 0, false
+	}
+	minMs, maxMs := config.LongTraceMinMs, config.LongTraceMaxMs
+	if minMs <= 0 {
+		minMs = defaultLongTraceMinMs
+	}
+	if maxMs < minMs {
+		maxMs = minMs
+	}
+	ms := float64(minMs) * math.Pow(float64(maxMs)/float64(minMs), rng.Float64())
+	return time.Duration(ms * float64(time.Millisecond)), true
+}
+
+// longTraceStart returns the start of a long trace of the given duration: the trace ends within
+// the start window before the base time, so it is complete by then
+func longTraceStart(config Config, duration time.Duration, rng *rand.Rand) time.Time {
+	end := baseTime(config).Add(-time.Duration(rng.Int63n(int64(startWindow(config)) + 1)))
+	return end.Add(-duration)
+}
+
+// stretchTrace makes a trace long with probability config.LongTraceRate, like a batch job or a
+// saga: the timestamps of its spans, events included, are scaled to the drawn duration and moved
+// to its start. Chaos traces are left alone, as their timestamps may be absurd.
+func stretchTrace(traces ptrace.Traces, config Config, rng *rand.Rand) {
+	duration, ok := longTraceDuration(config, rng)
+	if !ok || len(ChaosSpans(traces)) > 0 {
+		return
+	}
+
+	var spans []ptrace.Span
+	var first, last pcommon.Timestamp
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		scopeSpans := traces.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			for k := 0; k < scopeSpans.At(j).Spans().Len(); k++ {
+				span := scopeSpans.At(j).Spans().At(k)
+				if len(spans) == 0 || span.StartTimestamp() < first {
+					first = span.StartTimestamp()
+				}
+				if span.EndTimestamp() > last {
+					last = span.EndTimestamp()
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+	if last <= first {
+		return
+	}
+
+	start := pcommon.NewTimestampFromTime(longTraceStart(config, duration, rng))
+	scale := float64(duration) / float64(last-first)
+	stretch := func(t pcommon.Timestamp) time.Time {
+		return (start + pcommon.Timestamp(float64(t-first)*scale)).AsTime()
+	}
+	for _, span := range spans {
+		retimeSpan(span, stretch(span.StartTimestamp()), stretch(span.EndTimestamp()))
+	}
+}
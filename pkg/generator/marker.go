@@ -0,0 +1,60 @@
+package generator
+
+import (
+	cryptoRand "crypto/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// RunIDAttribute is the resource attribute that scopes marker traces to a run
+const RunIDAttribute = "k6.run_id"
+
+// MarkerServiceName is the service name of retention marker traces
+const MarkerServiceName = "k6-marker"
+
+// GenerateMarkerTraces builds count small two-span traces tagged with runID
+func GenerateMarkerTraces(runID string, count int) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	now := time.Now()
+
+	for i := 0; i < count; i++ {
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", MarkerServiceName)
+		rs.Resource().Attributes().PutStr(RunIDAttribute, runID)
+
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+
+		var traceID [16]byte
+		cryptoRand.Read(traceID[:])
+		start := now.Add(-100 * time.Millisecond)
+
+		root := spans.AppendEmpty()
+		root.SetTraceID(pcommon.TraceID(traceID))
+		root.SetSpanID(newMarkerSpanID())
+		root.SetName("marker")
+		root.SetKind(ptrace.SpanKindServer)
+		root.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		root.SetEndTimestamp(pcommon.NewTimestampFromTime(now))
+		root.Attributes().PutInt("marker.index", int64(i))
+
+		child := spans.AppendEmpty()
+		child.SetTraceID(root.TraceID())
+		child.SetSpanID(newMarkerSpanID())
+		child.SetParentSpanID(root.SpanID())
+		child.SetName("marker-step")
+		child.SetKind(ptrace.SpanKindInternal)
+		child.SetStartTimestamp(root.StartTimestamp())
+		child.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(50 * time.Millisecond)))
+	}
+
+	return traces
+}
+
+// newMarkerSpanID returns a random span ID
+func newMarkerSpanID() pcommon.SpanID {
+	var id [8]byte
+	cryptoRand.Read(id[:])
+	return pcommon.SpanID(id)
+}
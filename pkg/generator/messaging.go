@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Messaging defaults
+const (
+	defaultMessagingSystem = "kafka"
+	defaultQueueDelayMinMs = 5
+	defaultQueueDelayMaxMs = 500
+	maxPublishDuration     = 5 * time.Millisecond
+)
+
+// messagingSeed separates the random stream of messaging flows from the one of the trace
+const messagingSeed = 0x6d657373
+
+// messagingDestination is a queue or topic and the service consuming it
+type messagingDestination struct {
+	name     string
+	consumer string
+}
+
+// messagingDestinations are the destinations messages are published to
+var messagingDestinations = []messagingDestination{
+	{name: "orders", consumer: "fulfillment"},
+	{name: "payments", consumer: "ledger"},
+	{name: "shipments", consumer: "notification"},
+	{name: "inventory-updates", consumer: "search-indexer"},
+	{name: "user-events", consumer: "analytics"},
+	{name: "audit-log", consumer: "audit-archiver"},
+}
+
+// messagingRand returns the source of a trace's messaging flow; seeded traces get a source
+// derived from their seed, so enabling messaging leaves the rest of the trace unchanged
+func messagingRand(config Config) *rand.Rand {
+	if config.Seed != 0 {
+		return rand.New(rand.NewSource(DeriveSeed(config.Seed, messagingSeed)))
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// addMessagingFlow publishes a message from a trace with probability config.MessagingRate: a
+// producer span is added under a random span of the trace, and a consumer span in a trace of its
+// own starts after the queue delay, linked to the producer span. Both carry messaging.* attributes.
+func addMessagingFlow(traces ptrace.Traces, config Config, rng *rand.Rand) {
+	if config.MessagingRate <= 0 || rng.Float64() >= config.MessagingRate {
+		return
+	}
+
+	// Pick the span publishing the message
+	type candidate struct {
+		scope   ptrace.ScopeSpans
+		span    ptrace.Span
+		service string
+	}
+	var candidates []candidate
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+		service := ""
+		if v, ok := rs.Resource().Attributes().Get("service.name"); ok {
+			service = v.AsString()
+		}
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				candidates = append(candidates, candidate{scope: rs.ScopeSpans().At(j), span: spans.At(k), service: service})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	parent := candidates[rng.Intn(len(candidates))]
+
+	system := config.MessagingSystem
+	if system == "" {
+		system = defaultMessagingSystem
+	}
+	destination := messagingDestinations[rng.Intn(len(messagingDestinations))]
+	messageID := fakeUUID(rng)
+	idRNG := idRand(config, rng)
+
+	// Producer span, within its parent
+	parentStart := parent.span.StartTimestamp().AsTime()
+	parentEnd := parent.span.EndTimestamp().AsTime()
+	publishStart := parentStart.Add(time.Duration(rng.Float64() * 0.5 * float64(parentEnd.Sub(parentStart))))
+	publishEnd := publishStart.Add(time.Duration(rng.Int63n(int64(maxPublishDuration))) + time.Microsecond)
+	if publishEnd.After(parentEnd) && parentEnd.After(publishStart) {
+		publishEnd = parentEnd
+	}
+
+	producerConfig := config
+	producerConfig.SpanKindWeights = map[string]float64{"producer": 1}
+	producerConfig.ErrorRate = 0
+	producer := buildSpanWithContext(parent.span.TraceID(), parent.span.SpanID(), 0, 0, parent.service, producerConfig, publishStart, rng, nil, nil, destination.name+" publish")
+	retimeSpan(producer, publishStart, publishEnd)
+	putMessagingAttributes(producer.Attributes(), system, destination, "publish", messageID)
+
+	// Consumer span, in a trace of its own
+	delayMs := queueDelayMs(config, rng)
+	consumeStart := publishEnd.Add(time.Duration(delayMs * float64(time.Millisecond)))
+
+	consumerConfig := config
+	consumerConfig.SpanKindWeights = map[string]float64{"consumer": 1}
+	consumer := buildSpanWithContext(newTraceID(config, idRNG), pcommon.NewSpanIDEmpty(), 0, 0, destination.consumer, consumerConfig, consumeStart, rng, nil, nil, destination.name+" process")
+	putMessagingAttributes(consumer.Attributes(), system, destination, "process", messageID)
+	link := consumer.Links().AppendEmpty()
+	link.SetTraceID(producer.TraceID())
+	link.SetSpanID(producer.SpanID())
+	producer.MoveTo(parent.scope.Spans().AppendEmpty())
+
+	rs := traces.ResourceSpans().AppendEmpty()
+	resourceAttrs := generateResourceAttributes(destination.consumer, rng)
+	resourceAttrs["service.name"] = destination.consumer
+	putStringAttributes(rs.Resource().Attributes(), resourceAttrs)
+	putResourceArrayAttributes(rs.Resource().Attributes(), config)
+	scopeSpans := rs.ScopeSpans().AppendEmpty()
+	setInstrumentationScope(scopeSpans.Scope(), config.InstrumentationScopes, rng)
+	consumer.MoveTo(scopeSpans.Spans().AppendEmpty())
+}
+
+// queueDelayMs draws the time a message waits in the queue, uniformly between the configured bounds
+func queueDelayMs(config Config, rng *rand.Rand) float64 {
+	minMs, maxMs := config.QueueDelayMinMs, config.QueueDelayMaxMs
+	if maxMs < minMs {
+		maxMs = minMs
+	}
+	return float64(minMs) + rng.Float64()*float64(maxMs-minMs)
+}
+
+// putMessagingAttributes sets the messaging semantic convention attributes of a producer
+// ("publish") or consumer ("process") span
+func putMessagingAttributes(attrs pcommon.Map, system string, destination messagingDestination, operation string, messageID string) {
+	operationType := "send"
+	if operation == "process" {
+		operationType = "process"
+	}
+	attrs.PutStr("messaging.system", system)
+	attrs.PutStr("messaging.destination.name", destination.name)
+	attrs.PutStr("messaging.operation.name", operation)
+	attrs.PutStr("messaging.operation.type", operationType)
+	attrs.PutStr("messaging.message.id", messageID)
+	if operation == "process" {
+		attrs.PutStr("messaging.consumer.group.name", destination.consumer)
+	}
+}
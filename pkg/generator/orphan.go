@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"encoding/binary"
+	"math/rand"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// breakTraceStructure drops the root span of a trace with probability config.DropRootRate, then
+// re-parents each remaining non-root span with probability config.OrphanSpanRate to a span ID
+// that is not part of the trace. The root span is at index 0 of spansMap.
+func breakTraceStructure(spansMap map[int]*spanInfo, config Config, rng *rand.Rand) {
+	if config.DropRootRate > 0 && len(spansMap) > 1 && rng.Float64() < config.DropRootRate {
+		delete(spansMap, 0)
+	}
+
+	if config.OrphanSpanRate <= 0 {
+		return
+	}
+	spanIDs := make(map[pcommon.SpanID]bool, len(spansMap))
+	for _, info := range spansMap {
+		spanIDs[info.span.SpanID()] = true
+	}
+	for _, info := range spansMap {
+		if info.span.ParentSpanID().IsEmpty() || rng.Float64() >= config.OrphanSpanRate {
+			continue
+		}
+		info.span.SetParentSpanID(missingSpanID(spanIDs, rng))
+	}
+}
+
+// missingSpanID returns a random non-zero span ID that is not in spanIDs
+func missingSpanID(spanIDs map[pcommon.SpanID]bool, rng *rand.Rand) pcommon.SpanID {
+	var id pcommon.SpanID
+	for {
+		binary.BigEndian.PutUint64(id[:], rng.Uint64())
+		if !id.IsEmpty() && !spanIDs[id] {
+			return id
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package generator
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// AttributePack sets the semantic convention attributes of a span of a service in attrs; values
+// enables realistic values and may be nil
+type AttributePack func(attrs pcommon.Map, kind ptrace.SpanKind, serviceName string, values *ValueOptions, rng *rand.Rand)
+
+// Built-in attribute packs
+const (
+	PackHTTP      = "http"
+	PackDB        = "db"
+	PackCache     = "cache"
+	PackRPC       = "rpc"
+	PackMessaging = "messaging"
+	PackGenAI     = "gen_ai"
+	PackFaaS      = "faas"
+)
+
+// AllServices is the semanticPacks key of services without packs of their own
+const AllServices = "*"
+
+// attributePacksMu guards attributePacks, which RegisterAttributePack extends at runtime
+var attributePacksMu sync.RWMutex
+
+// attributePacks holds the built-in and registered attribute packs by name
+var attributePacks = map[string]AttributePack{
+	PackHTTP:      httpPack,
+	PackDB:        dbPack,
+	PackCache:     cachePack,
+	PackRPC:       rpcPack,
+	PackMessaging: messagingPack,
+	PackGenAI:     genAIPack,
+	PackFaaS:      faasPack,
+}
+
+// RegisterAttributePack adds an attribute pack, or replaces the pack with the same name, built-in
+// packs included, so services can select it through semanticPacks
+func RegisterAttributePack(name string, pack AttributePack) error {
+	if name == "" {
+		return fmt.Errorf("attribute pack name is required")
+	}
+	if pack == nil {
+		return fmt.Errorf("attribute pack %q is nil", name)
+	}
+
+	attributePacksMu.Lock()
+	defer attributePacksMu.Unlock()
+	attributePacks[name] = pack
+	return nil
+}
+
+// IsAttributePack reports whether an attribute pack is built in or registered
+func IsAttributePack(name string) bool {
+	attributePacksMu.RLock()
+	defer attributePacksMu.RUnlock()
+	_, ok := attributePacks[name]
+	return ok
+}
+
+// getAttributePack returns an attribute pack by name
+func getAttributePack(name string) (AttributePack, bool) {
+	attributePacksMu.RLock()
+	defer attributePacksMu.RUnlock()
+	pack, ok := attributePacks[name]
+	return pack, ok
+}
+
+// servicePacks returns the attribute packs of a service: its own entry of packs, the AllServices
+// entry, or by default http plus db, cache or rpc for the built-in database, cache, backend and
+// gateway services
+func servicePacks(serviceName string, packs map[string][]string) []string {
+	if names, ok := packs[serviceName]; ok {
+		return names
+	}
+	if names, ok := packs[AllServices]; ok {
+		return names
+	}
+
+	switch serviceName {
+	case "database":
+		return []string{PackHTTP, PackDB}
+	case "cache":
+		return []string{PackHTTP, PackCache}
+	case "backend", "gateway":
+		return []string{PackHTTP, PackRPC}
+	default:
+		return []string{PackHTTP}
+	}
+}
+
+// AttributePackSpec is a custom attribute pack of value pools: spans of the kinds it applies to
+// take a random value of every pool
+type AttributePackSpec struct {
+	Attributes map[string][]string `js:"attributes"` // Attribute name to the values to pick from
+	SpanKinds  []string            `js:"spanKinds"`  // "server", "client", "internal", "producer" or "consumer" (default: all)
+}
+
+// Pack returns the attribute pack of the spec
+func (s AttributePackSpec) Pack() (AttributePack, error) {
+	if len(s.Attributes) == 0 {
+		return nil, fmt.Errorf("attribute pack must have at least one attribute")
+	}
+	for key, pool := range s.Attributes {
+		if len(pool) == 0 {
+			return nil, fmt.Errorf("attribute %q has no values", key)
+		}
+	}
+	kinds := make(map[ptrace.SpanKind]bool, len(s.SpanKinds))
+	for _, kind := range s.SpanKinds {
+		switch kind {
+		case "server", "client", "internal", "producer", "consumer":
+			kinds[parseSpanKind(kind)] = true
+		default:
+			return nil, fmt.Errorf("unknown spanKind %q", kind)
+		}
+	}
+
+	keys := slices.Sorted(maps.Keys(s.Attributes))
+	pools := maps.Clone(s.Attributes)
+	return func(attrs pcommon.Map, kind ptrace.SpanKind, _ string, _ *ValueOptions, rng *rand.Rand) {
+		if len(kinds) > 0 && !kinds[kind] {
+			return
+		}
+		for _, key := range keys {
+			pool := pools[key]
+			attrs.PutStr(key, pool[rng.Intn(len(pool))])
+		}
+	}, nil
+}
+
+// httpPack generates HTTP attributes of server and client spans; with values set, URLs are
+// realistic and server spans carry client addresses and user agents
+func httpPack(attrs pcommon.Map, kind ptrace.SpanKind, serviceName string, values *ValueOptions, rng *rand.Rand) {
+	if kind != ptrace.SpanKindServer && kind != ptrace.SpanKindClient {
+		return
+	}
+
+	// HTTP attributes
+	methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
+	method := methods[rng.Intn(len(methods))]
+	attrs.PutStr("http.method", method)
+
+	statusCodes := []int{200, 201, 204, 400, 401, 403, 404, 500, 502, 503}
+	statusCode := statusCodes[rng.Intn(len(statusCodes))]
+	attrs.PutInt("http.status_code", int64(statusCode))
+
+	// URL based on service
+	var url string
+	switch serviceName {
+	case "frontend":
+		urls := []string{"/api/users", "/api/orders", "/api/products", "/health", "/static/app.js"}
+		url = urls[rng.Intn(len(urls))]
+	case "backend":
+		urls := []string{"/v1/process", "/v1/validate", "/v1/webhook"}
+		url = urls[rng.Intn(len(urls))]
+	default:
+		url = "/api/" + serviceName
+	}
+	if values != nil {
+		url = FakeURL("", rng)
+	}
+	attrs.PutStr("http.url", url)
+
+	attrs.PutStr("http.scheme", "https")
+
+	if values != nil && kind == ptrace.SpanKindServer {
+		attrs.PutStr("client.address", FakeIP(rng))
+		attrs.PutStr("user_agent.original", FakeUserAgent(rng))
+		if serviceName == "auth" || serviceName == "frontend" {
+			attrs.PutStr("user.email", FakeEmail(rng))
+		}
+	}
+}
+
+// dbPack generates database attributes; with values set, statements are realistic SQL
+func dbPack(attrs pcommon.Map, _ ptrace.SpanKind, _ string, values *ValueOptions, rng *rand.Rand) {
+	dbSystems := []string{"postgresql", "mysql", "mongodb", "redis"}
+	dbSystem := dbSystems[rng.Intn(len(dbSystems))]
+	attrs.PutStr("db.system", dbSystem)
+
+	statements := []string{
+		"SELECT * FROM users WHERE id = ?",
+		"INSERT INTO orders (user_id, total) VALUES (?, ?)",
+		"UPDATE products SET stock = ? WHERE id = ?",
+		"DELETE FROM sessions WHERE expires_at < ?",
+	}
+	statement := statements[rng.Intn(len(statements))]
+	if values != nil {
+		statement = FakeSQL(values.SQLLength, rng)
+	}
+	attrs.PutStr("db.statement", statement)
+}
+
+// cachePack generates Redis cache attributes
+func cachePack(attrs pcommon.Map, _ ptrace.SpanKind, _ string, _ *ValueOptions, rng *rand.Rand) {
+	attrs.PutStr("db.system", "redis")
+
+	operations := []string{"GET", "SET", "MGET", "MSET", "DEL"}
+	operation := operations[rng.Intn(len(operations))]
+	attrs.PutStr("db.operation", operation)
+}
+
+// rpcPack generates RPC attributes
+func rpcPack(attrs pcommon.Map, _ ptrace.SpanKind, serviceName string, _ *ValueOptions, rng *rand.Rand) {
+	attrs.PutStr("rpc.service", serviceName+".Service")
+
+	methods := []string{"Process", "Validate", "Handle", "Execute"}
+	method := methods[rng.Intn(len(methods))]
+	attrs.PutStr("rpc.method", method)
+}
+
+// messagingPack generates messaging attributes: producer and client spans publish a message,
+// other spans process one
+func messagingPack(attrs pcommon.Map, kind ptrace.SpanKind, _ string, _ *ValueOptions, rng *rand.Rand) {
+	operation := "process"
+	if kind == ptrace.SpanKindProducer || kind == ptrace.SpanKindClient {
+		operation = "publish"
+	}
+	destination := messagingDestinations[rng.Intn(len(messagingDestinations))]
+	putMessagingAttributes(attrs, defaultMessagingSystem, destination, operation, fakeUUID(rng))
+}
+
+// genAIModel is a generative AI model and the system serving it
+type genAIModel struct {
+	system    string
+	name      string
+	embedding bool
+}
+
+// genAIModels are the models of gen_ai spans
+var genAIModels = []genAIModel{
+	{system: "openai", name: "gpt-4o"},
+	{system: "openai", name: "gpt-4o-mini"},
+	{system: "openai", name: "text-embedding-3-small", embedding: true},
+	{system: "aws.bedrock", name: "meta.llama3-70b-instruct-v1:0"},
+	{system: "vertex_ai", name: "gemini-1.5-flash"},
+	{system: "mistral_ai", name: "mistral-large-latest"},
+}
+
+// genAIPack generates generative AI client attributes: the model, request parameters and token usage
+func genAIPack(attrs pcommon.Map, _ ptrace.SpanKind, _ string, _ *ValueOptions, rng *rand.Rand) {
+	model := genAIModels[rng.Intn(len(genAIModels))]
+	inputTokens := 20 + rng.Intn(4000)
+
+	if model.embedding {
+		attrs.PutStr("gen_ai.system", model.system)
+		attrs.PutStr("gen_ai.operation.name", "embeddings")
+		attrs.PutStr("gen_ai.request.model", model.name)
+		attrs.PutStr("gen_ai.response.model", model.name)
+		attrs.PutInt("gen_ai.usage.input_tokens", int64(inputTokens))
+		return
+	}
+
+	maxTokens := []int{256, 512, 1024, 2048, 4096}[rng.Intn(5)]
+	outputTokens := 1 + rng.Intn(maxTokens)
+	finishReason := "stop"
+	if rng.Float64() < DensityVeryLow {
+		outputTokens, finishReason = maxTokens, "length"
+	}
+	attrs.PutStr("gen_ai.system", model.system)
+	attrs.PutStr("gen_ai.operation.name", "chat")
+	attrs.PutStr("gen_ai.request.model", model.name)
+	attrs.PutStr("gen_ai.response.model", model.name)
+	attrs.PutInt("gen_ai.request.max_tokens", int64(maxTokens))
+	attrs.PutDouble("gen_ai.request.temperature", float64(rng.Intn(11))/10)
+	attrs.PutInt("gen_ai.usage.input_tokens", int64(inputTokens))
+	attrs.PutInt("gen_ai.usage.output_tokens", int64(outputTokens))
+	attrs.PutStr("gen_ai.response.id", "chatcmpl-"+randomString(24, rng))
+	attrs.PutEmptySlice("gen_ai.response.finish_reasons").AppendEmpty().SetStr(finishReason)
+}
+
+// FaaS providers and the regions of invoked functions
+var faasRegions = map[string][]string{
+	"aws":   {"us-east-1", "eu-west-1", "ap-southeast-2"},
+	"gcp":   {"us-central1", "europe-west1"},
+	"azure": {"eastus", "westeurope"},
+}
+
+// faasPack generates function-as-a-service attributes: client spans invoke a function, other
+// spans are invocations with a trigger matching their kind
+func faasPack(attrs pcommon.Map, kind ptrace.SpanKind, serviceName string, _ *ValueOptions, rng *rand.Rand) {
+	if kind == ptrace.SpanKindClient {
+		providers := slices.Sorted(maps.Keys(faasRegions))
+		provider := providers[rng.Intn(len(providers))]
+		regions := faasRegions[provider]
+		attrs.PutStr("faas.invoked_name", serviceName+"-fn")
+		attrs.PutStr("faas.invoked_provider", provider)
+		attrs.PutStr("faas.invoked_region", regions[rng.Intn(len(regions))])
+		return
+	}
+
+	trigger := "other"
+	switch kind {
+	case ptrace.SpanKindServer:
+		trigger = "http"
+	case ptrace.SpanKindConsumer:
+		trigger = "pubsub"
+	case ptrace.SpanKindInternal:
+		trigger = []string{"timer", "datasource"}[rng.Intn(2)]
+	}
+	attrs.PutStr("faas.trigger", trigger)
+	attrs.PutStr("faas.invocation_id", fakeUUID(rng))
+	attrs.PutBool("faas.coldstart", rng.Float64() < DensityVeryLow)
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"runtime"
+	"sync"
+)
+
+// tracePool generates the traces of an iterator with a pool of workers. Traces are handed out in
+// stream order, so seeded batches are the same as when generated by a single goroutine.
+type tracePool struct {
+	pending chan chan sizedTrace // Traces being generated, in stream order
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// traceJob is a trace to generate and where to deliver it
+type traceJob struct {
+	index  int
+	result chan sizedTrace
+}
+
+// newTracePool starts workers generating the traces of it, from the next index on. At most two
+// traces per worker are generated ahead of the ones handed out.
+func newTracePool(it *BatchIterator, workers int) *tracePool {
+	p := &tracePool{
+		pending: make(chan chan sizedTrace, 2*workers),
+		done:    make(chan struct{}),
+	}
+	jobs := make(chan traceJob, workers)
+
+	p.wg.Add(1 + workers)
+	go func() {
+		defer p.wg.Done()
+		defer close(jobs)
+		for index := it.generated; ; index++ {
+			result := make(chan sizedTrace, 1)
+			select {
+			case p.pending <- result:
+			case <-p.done:
+				return
+			}
+			select {
+			case jobs <- traceJob{index: index, result: result}:
+			case <-p.done:
+				return
+			}
+		}
+	}()
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer p.wg.Done()
+			for job := range jobs {
+				job.result <- it.generate(job.index)
+			}
+		}()
+	}
+	return p
+}
+
+// next returns the next trace of the stream
+func (p *tracePool) next() sizedTrace {
+	return <-<-p.pending
+}
+
+// stop stops the workers, dropping the traces generated ahead, and waits for them to exit
+func (p *tracePool) stop() {
+	close(p.done)
+	p.wg.Wait()
+}
+
+// GenerateBatchParallel generates a batch like GenerateBatchResult, with traces generated by a
+// pool of goroutines; workers <= 0 uses one per CPU. Seeded batches are the same as with
+// GenerateBatchResult.
+func GenerateBatchParallel(config BatchConfig, workers int) BatchResult {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	it := NewBatchIterator(config.TraceConfig)
+	if workers > 1 {
+		it.pool = newTracePool(it, workers)
+		defer it.pool.stop()
+	}
+	return it.next(config)
+}
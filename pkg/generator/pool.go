@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"slices"
+	"sync"
+)
+
+// Scratch buffers are reused across spans through pools: generation would otherwise allocate
+// short-lived slices for every span and attribute, keeping the load generator busy with GC.
+var (
+	keyBuffers  = sync.Pool{New: func() any { return new([]string) }}
+	byteBuffers = sync.Pool{New: func() any { return new([]byte) }}
+)
+
+// sortedKeys returns the keys of m in order, in a pooled slice to hand back with releaseKeys
+func sortedKeys[V any](m map[string]V) *[]string {
+	keys := keyBuffers.Get().(*[]string)
+	*keys = (*keys)[:0]
+	for key := range m {
+		*keys = append(*keys, key)
+	}
+	slices.Sort(*keys)
+	return keys
+}
+
+// releaseKeys returns a slice obtained from sortedKeys to the pool
+func releaseKeys(keys *[]string) {
+	clear(*keys)
+	keyBuffers.Put(keys)
+}
+
+// randomHex returns the hex encoding of n random bytes, drawn like randomBytes
+func randomHex(n int, rng *rand.Rand) string {
+	buf := byteBuffers.Get().(*[]byte)
+	defer byteBuffers.Put(buf)
+
+	size := n + hex.EncodedLen(n)
+	if cap(*buf) < size {
+		*buf = make([]byte, size)
+	}
+	raw, encoded := (*buf)[:n], (*buf)[n:size]
+	fillRandom(raw, rng)
+	hex.Encode(encoded, raw)
+	return string(encoded)
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"encoding/hex"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func BenchmarkSortedKeys(b *testing.B) {
+	m := map[string]float64{"server": 0.4, "client": 0.3, "internal": 0.2, "producer": 0.05, "consumer": 0.05}
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			keys := sortedKeys(m)
+			releaseKeys(keys)
+		}
+	})
+	b.Run("unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = slices.Sorted(maps.Keys(m))
+		}
+	})
+}
+
+func BenchmarkRandomHex(b *testing.B) {
+	const size = 32
+
+	b.Run("pooled", func(b *testing.B) {
+		rng := rand.New(rand.NewSource(1))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = randomHex(size, rng)
+		}
+	})
+	b.Run("unpooled", func(b *testing.B) {
+		rng := rand.New(rand.NewSource(1))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			raw := make([]byte, size)
+			fillRandom(raw, rng)
+			_ = hex.EncodeToString(raw)
+		}
+	})
+}
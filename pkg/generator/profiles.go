@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// TraceProfile is a trace configuration making up a share of a mixed workload, such as mostly
+// small traces with a few huge ones
+type TraceProfile struct {
+	Weight float64 `js:"weight"` // Relative weight among the profiles (must be >= 0)
+	Config Config  `js:"config"` // Configuration of the profile's traces; seed is taken from the outer config, and so is baseTimeMs when unset
+}
+
+// profileSeed separates the profile draw from the random stream of the trace
+const profileSeed = 0x70726f66
+
+// ValidateProfiles checks the weights and configurations of the profiles of a mixed workload
+func (c *Config) ValidateProfiles() error {
+	if len(c.Profiles) == 0 {
+		return nil
+	}
+	total := 0.0
+	for i := range c.Profiles {
+		profile := &c.Profiles[i]
+		if profile.Weight < 0 {
+			return fmt.Errorf("profile %d: weight must be >= 0, got %f", i, profile.Weight)
+		}
+		if len(profile.Config.Profiles) > 0 {
+			return fmt.Errorf("profile %d: profiles cannot be nested", i)
+		}
+		if err := profile.Config.Validate(); err != nil {
+			return fmt.Errorf("profile %d: %w", i, err)
+		}
+		total += profile.Weight
+	}
+	if total <= 0 {
+		return fmt.Errorf("profiles must have a total weight > 0")
+	}
+	return nil
+}
+
+// selectProfile returns the configuration of the next trace of a mixed workload: a profile picked
+// by weight, with the seed and, unless the profile sets its own, the base time of config. Seeded
+// configs draw the profile from their seed, so mixed workloads are reproducible too.
+func selectProfile(config Config) Config {
+	var r float64
+	if config.Seed != 0 {
+		r = float64(uint64(DeriveSeed(config.Seed, profileSeed))>>11) / (1 << 53)
+	} else {
+		r = rand.Float64()
+	}
+
+	total := 0.0
+	for _, profile := range config.Profiles {
+		total += profile.Weight
+	}
+	r *= total
+
+	selected := config.Profiles[len(config.Profiles)-1].Config
+	for _, profile := range config.Profiles {
+		r -= profile.Weight
+		if r < 0 {
+			selected = profile.Config
+			break
+		}
+	}
+
+	selected.Seed = config.Seed
+	if selected.BaseTimeMs == 0 {
+		selected.BaseTimeMs = config.BaseTimeMs
+	}
+	return selected
+}
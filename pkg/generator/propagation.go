@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"math/rand"
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// propagatedErrorMessage is the status message of spans failing because a child failed
+const propagatedErrorMessage = "child span failed"
+
+// propagateErrors bubbles error status up a span tree: the parent of an error span fails too
+// with probability config.ErrorPropagationRate, and so on towards the root, so root-level
+// status=error queries find traces failing deeper down. Children come after their parents in
+// index order, so spans are visited from the last one.
+func propagateErrors(spansMap map[int]*spanInfo, config Config, rng *rand.Rand) {
+	if config.ErrorPropagationRate <= 0 {
+		return
+	}
+
+	byID := make(map[pcommon.SpanID]*spanInfo, len(spansMap))
+	indices := make([]int, 0, len(spansMap))
+	for index, info := range spansMap {
+		byID[info.span.SpanID()] = info
+		indices = append(indices, index)
+	}
+	slices.Sort(indices)
+
+	for i := len(indices) - 1; i >= 0; i-- {
+		span := spansMap[indices[i]].span
+		if span.Status().Code() != ptrace.StatusCodeError || span.ParentSpanID().IsEmpty() {
+			continue
+		}
+		parent, ok := byID[span.ParentSpanID()]
+		if !ok || rng.Float64() >= config.ErrorPropagationRate {
+			continue
+		}
+		parent.span.Status().SetCode(ptrace.StatusCodeError)
+		if parent.span.Status().Message() == "" {
+			parent.span.Status().SetMessage(propagatedErrorMessage)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// maxSamplingValue is the largest p- and r-value of the OpenTelemetry probability sampling tracestate
+const maxSamplingValue = 62
+
+// samplingSeed separates the random stream of sampling decisions from the one of the trace
+const samplingSeed = 0x73616d70
+
+// samplingDecision is the head sampling decision of a trace, carried by all its spans
+type samplingDecision struct {
+	sampled    bool
+	traceState string
+}
+
+// samplingRand returns the source of a trace's sampling decisions; seeded traces get a source
+// derived from their seed, so enabling sampling data leaves the rest of the trace unchanged
+func samplingRand(config Config) *rand.Rand {
+	if config.Seed != 0 {
+		return rand.New(rand.NewSource(DeriveSeed(config.Seed, samplingSeed)))
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+// samplingPValue returns the p-value of a sampling probability: the negated base-2 logarithm of
+// the nearest power of two
+func samplingPValue(probability float64) int {
+	p := int(math.Round(-math.Log2(probability)))
+	return min(max(p, 0), maxSamplingValue)
+}
+
+// newSamplingDecision draws the sampling decision of a trace. The r-value is drawn like the
+// leading zeros of a random number, as consistent probability samplers do; sampled traces have
+// an r-value of at least the p-value, the config.UnsampledRate others a smaller one when possible.
+func newSamplingDecision(config Config, rng *rand.Rand) samplingDecision {
+	p := samplingPValue(config.SamplingProbability)
+	sampled := rng.Float64() >= config.UnsampledRate
+
+	r := 0
+	for r < maxSamplingValue && rng.Intn(2) == 0 {
+		r++
+	}
+	if sampled {
+		r = min(r+p, maxSamplingValue)
+	} else if p > 0 {
+		r %= p
+	}
+
+	return samplingDecision{
+		sampled:    sampled,
+		traceState: fmt.Sprintf("ot=p:%d;r:%d", p, r),
+	}
+}
+
+// apply sets the decision on a span: the tracestate, plus the sampling.priority attribute on root
+// spans. The span flags carrying the W3C sampled bit are not set, as the OTLP model of the pinned
+// pdata version predates them.
+func (d samplingDecision) apply(span ptrace.Span) {
+	span.TraceState().FromRaw(d.traceState)
+	if span.ParentSpanID().IsEmpty() {
+		priority := int64(0)
+		if d.sampled {
+			priority = 1
+		}
+		span.Attributes().PutInt("sampling.priority", priority)
+	}
+}
+
+// setSampling draws a sampling decision per trace ID of traces, as messaging flows may hold
+// several, and sets it on their spans
+func setSampling(traces ptrace.Traces, config Config, rng *rand.Rand) {
+	decisions := make(map[pcommon.TraceID]samplingDecision)
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		scopeSpans := traces.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			for k := 0; k < scopeSpans.At(j).Spans().Len(); k++ {
+				span := scopeSpans.At(j).Spans().At(k)
+				decision, ok := decisions[span.TraceID()]
+				if !ok {
+					decision = newSamplingDecision(config, rng)
+					decisions[span.TraceID()] = decision
+				}
+				decision.apply(span)
+			}
+		}
+	}
+}
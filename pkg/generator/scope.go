@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"math/rand"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// InstrumentationScope describes the instrumentation library that produced a service's spans
+type InstrumentationScope struct {
+	Name       string            `js:"name"`       // Instrumentation library name
+	Version    string            `js:"version"`    // Instrumentation library version
+	Attributes map[string]string `js:"attributes"` // Scope attributes (optional)
+}
+
+// defaultInstrumentationScopes is the scope pool used when none is configured
+var defaultInstrumentationScopes = []InstrumentationScope{
+	{Name: "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp", Version: "0.53.0"},
+	{Name: "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc", Version: "0.53.0"},
+	{Name: "io.opentelemetry.spring-webmvc-6.0", Version: "2.6.0-alpha"},
+	{Name: "io.opentelemetry.jdbc", Version: "2.6.0-alpha"},
+	{Name: "io.opentelemetry.kafka-clients-2.6", Version: "2.6.0-alpha"},
+	{Name: "@opentelemetry/instrumentation-http", Version: "0.52.1"},
+	{Name: "opentelemetry.instrumentation.flask", Version: "0.47b0"},
+	{Name: "OpenTelemetry.Instrumentation.AspNetCore", Version: "1.9.0"},
+}
+
+// setInstrumentationScope populates a scope with an entry of the configured pool, or of the
+// default pool when none is configured
+func setInstrumentationScope(scope pcommon.InstrumentationScope, pool []InstrumentationScope, rng *rand.Rand) {
+	if len(pool) == 0 {
+		pool = defaultInstrumentationScopes
+	}
+
+	selected := pool[rng.Intn(len(pool))]
+	scope.SetName(selected.Name)
+	scope.SetVersion(selected.Version)
+	keys := sortedKeys(selected.Attributes)
+	defer releaseKeys(keys)
+	for _, key := range *keys {
+		scope.Attributes().PutStr(key, selected.Attributes[key])
+	}
+}
+
+// setSchemaURLs sets the configured schema URLs on the resources and scopes of traces
+func setSchemaURLs(traces ptrace.Traces, config Config) {
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		setResourceSchemaURLs(traces.ResourceSpans().At(i), config)
+	}
+}
+
+// setResourceSchemaURLs sets the configured schema URLs on a resource and its scopes
+func setResourceSchemaURLs(rs ptrace.ResourceSpans, config Config) {
+	rs.SetSchemaUrl(config.ResourceSchemaURL)
+	for i := 0; i < rs.ScopeSpans().Len(); i++ {
+		rs.ScopeSpans().At(i).SetSchemaUrl(config.ScopeSchemaURL)
+	}
+}
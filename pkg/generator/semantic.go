@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"math/rand"
 
-	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
-	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 // Operation name templates per service type
@@ -115,162 +115,25 @@ func generateOperationName(serviceName string, rng *rand.Rand) string {
 	return templates[rng.Intn(len(templates))]
 }
 
-// generateSemanticAttributes generates OTel semantic convention attributes
-func generateSemanticAttributes(kind tracev1.Span_SpanKind, serviceName string, rng *rand.Rand) []*commonv1.KeyValue {
-	attrs := make([]*commonv1.KeyValue, 0)
-
-	switch kind {
-	case tracev1.Span_SPAN_KIND_SERVER, tracev1.Span_SPAN_KIND_CLIENT:
-		// HTTP attributes
-		methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
-		method := methods[rng.Intn(len(methods))]
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "http.method",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: method,
-				},
-			},
-		})
-
-		statusCodes := []int{200, 201, 204, 400, 401, 403, 404, 500, 502, 503}
-		statusCode := statusCodes[rng.Intn(len(statusCodes))]
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "http.status_code",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_IntValue{
-					IntValue: int64(statusCode),
-				},
-			},
-		})
-
-		// URL based on service
-		var url string
-		switch serviceName {
-		case "frontend":
-			urls := []string{"/api/users", "/api/orders", "/api/products", "/health", "/static/app.js"}
-			url = urls[rng.Intn(len(urls))]
-		case "backend":
-			urls := []string{"/v1/process", "/v1/validate", "/v1/webhook"}
-			url = urls[rng.Intn(len(urls))]
-		default:
-			url = "/api/" + serviceName
-		}
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "http.url",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: url,
-				},
-			},
-		})
-
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "http.scheme",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: "https",
-				},
-			},
-		})
-
-	case tracev1.Span_SPAN_KIND_INTERNAL:
+// generateSemanticAttributes sets OTel semantic convention attributes with the attribute packs of
+// the service (see servicePacks); with values set, packs generate realistic values
+func generateSemanticAttributes(attrs pcommon.Map, kind ptrace.SpanKind, serviceName string, packs map[string][]string, values *ValueOptions, rng *rand.Rand) {
+	if kind == ptrace.SpanKindInternal {
 		// Internal service attributes
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "service.operation",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: "internal-process",
-				},
-			},
-		})
+		attrs.PutStr("service.operation", "internal-process")
 	}
 
-	// Database attributes
-	if serviceName == "database" {
-		dbSystems := []string{"postgresql", "mysql", "mongodb", "redis"}
-		dbSystem := dbSystems[rng.Intn(len(dbSystems))]
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "db.system",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: dbSystem,
-				},
-			},
-		})
-
-		statements := []string{
-			"SELECT * FROM users WHERE id = ?",
-			"INSERT INTO orders (user_id, total) VALUES (?, ?)",
-			"UPDATE products SET stock = ? WHERE id = ?",
-			"DELETE FROM sessions WHERE expires_at < ?",
+	for _, name := range servicePacks(serviceName, packs) {
+		if pack, ok := getAttributePack(name); ok {
+			pack(attrs, kind, serviceName, values, rng)
 		}
-		statement := statements[rng.Intn(len(statements))]
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "db.statement",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: statement,
-				},
-			},
-		})
-	}
-
-	// Cache attributes
-	if serviceName == "cache" {
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "db.system",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: "redis",
-				},
-			},
-		})
-
-		operations := []string{"GET", "SET", "MGET", "MSET", "DEL"}
-		operation := operations[rng.Intn(len(operations))]
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "db.operation",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: operation,
-				},
-			},
-		})
 	}
-
-	// RPC attributes for backend services
-	if serviceName == "backend" || serviceName == "gateway" {
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "rpc.service",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: serviceName + ".Service",
-				},
-			},
-		})
-
-		methods := []string{"Process", "Validate", "Handle", "Execute"}
-		method := methods[rng.Intn(len(methods))]
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "rpc.method",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: method,
-				},
-			},
-		})
-	}
-
-	return attrs
 }
 
-// generateBusinessAttributes generates business domain attributes based on workflow context
-func generateBusinessAttributes(ctx *WorkflowContext, serviceName string, config Config, rng *rand.Rand) []*commonv1.KeyValue {
-	attrs := make([]*commonv1.KeyValue, 0)
-
+// generateBusinessAttributes sets business domain attributes based on workflow context
+func generateBusinessAttributes(attrs pcommon.Map, ctx *WorkflowContext, serviceName string, config Config, rng *rand.Rand) {
 	if ctx == nil {
-		return attrs
+		return
 	}
 
 	density := config.BusinessAttributesDensity
@@ -283,294 +146,117 @@ func generateBusinessAttributes(ctx *WorkflowContext, serviceName string, config
 
 	// Add user_id to most services
 	if rng.Float64() < density && ctx.UserID != "" {
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: "user.id",
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: ctx.UserID,
-				},
-			},
-		})
+		attrs.PutStr("user.id", ctx.UserID)
 	}
 
 	// Service-specific business attributes
 	switch serviceName {
 	case "auth":
 		if rng.Float64() < density && ctx.SessionID != "" {
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "session.id",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: ctx.SessionID,
-					},
-				},
-			})
+			attrs.PutStr("session.id", ctx.SessionID)
 		}
 		if rng.Float64() < density*0.6 {
 			authMethods := []string{"password", "oauth", "jwt", "saml"}
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "auth.method",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: authMethods[rng.Intn(len(authMethods))],
-					},
-				},
-			})
+			attrs.PutStr("auth.method", authMethods[rng.Intn(len(authMethods))])
 		}
 		if rng.Float64() < density*0.5 {
 			tokenTypes := []string{"bearer", "api_key", "session"}
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "auth.token_type",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: tokenTypes[rng.Intn(len(tokenTypes))],
-					},
-				},
-			})
+			attrs.PutStr("auth.token_type", tokenTypes[rng.Intn(len(tokenTypes))])
 		}
 
 	case "payment":
 		if rng.Float64() < density && ctx.PaymentID != "" {
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "payment.id",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: ctx.PaymentID,
-					},
-				},
-			})
+			attrs.PutStr("payment.id", ctx.PaymentID)
 		}
 		if rng.Float64() < density {
 			amount := float64(rng.Intn(10000)+100) / 100.0 // $1.00 to $100.00
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "payment.amount",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_DoubleValue{
-						DoubleValue: amount,
-					},
-				},
-			})
+			attrs.PutDouble("payment.amount", amount)
 		}
 		if rng.Float64() < density {
 			currencies := []string{"USD", "EUR", "GBP", "JPY"}
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "payment.currency",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: currencies[rng.Intn(len(currencies))],
-					},
-				},
-			})
+			attrs.PutStr("payment.currency", currencies[rng.Intn(len(currencies))])
 		}
 		if rng.Float64() < density*0.8 {
 			methods := []string{"credit_card", "debit_card", "paypal", "bank_transfer"}
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "payment.method",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: methods[rng.Intn(len(methods))],
-					},
-				},
-			})
+			attrs.PutStr("payment.method", methods[rng.Intn(len(methods))])
 		}
 		if rng.Float64() < density*0.7 {
 			statuses := []string{"pending", "completed", "failed", "refunded"}
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "payment.status",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: statuses[rng.Intn(len(statuses))],
-					},
-				},
-			})
+			attrs.PutStr("payment.status", statuses[rng.Intn(len(statuses))])
 		}
 
 	case "database":
 		if rng.Float64() < density*0.8 {
 			tables := []string{"users", "orders", "products", "sessions", "payments", "shipments"}
 			table := tables[rng.Intn(len(tables))]
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "db.table",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: table,
-					},
-				},
-			})
+			attrs.PutStr("db.table", table)
 		}
 		if rng.Float64() < density*0.6 {
 			queryTypes := []string{"SELECT", "INSERT", "UPDATE", "DELETE"}
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "db.query_type",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: queryTypes[rng.Intn(len(queryTypes))],
-					},
-				},
-			})
+			attrs.PutStr("db.query_type", queryTypes[rng.Intn(len(queryTypes))])
 		}
 		if rng.Float64() < density*0.5 {
 			rowsAffected := rng.Intn(1000) + 1
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "db.rows_affected",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_IntValue{
-						IntValue: int64(rowsAffected),
-					},
-				},
-			})
+			attrs.PutInt("db.rows_affected", int64(rowsAffected))
 		}
 		if rng.Float64() < density*0.4 {
 			cacheHit := rng.Float64() < 0.3 // 30% cache hit rate
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "db.cache_hit",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_BoolValue{
-						BoolValue: cacheHit,
-					},
-				},
-			})
+			attrs.PutBool("db.cache_hit", cacheHit)
 		}
 
 	case "cache":
 		if rng.Float64() < density {
 			cacheKey := fmt.Sprintf("cache:%s:%d", serviceName, rng.Intn(10000))
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "cache.key",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: cacheKey,
-					},
-				},
-			})
+			attrs.PutStr("cache.key", cacheKey)
 		}
 		if rng.Float64() < density*0.8 {
 			cacheHit := rng.Float64() < 0.7 // 70% cache hit rate
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "cache.hit",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_BoolValue{
-						BoolValue: cacheHit,
-					},
-				},
-			})
+			attrs.PutBool("cache.hit", cacheHit)
 		}
 		if rng.Float64() < density*0.5 {
 			ttl := rng.Intn(3600) + 60 // 60 to 3660 seconds
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "cache.ttl",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_IntValue{
-						IntValue: int64(ttl),
-					},
-				},
-			})
+			attrs.PutInt("cache.ttl", int64(ttl))
 		}
 
 	case "shipping":
 		if rng.Float64() < density && ctx.ShipmentID != "" {
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "shipment.id",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: ctx.ShipmentID,
-					},
-				},
-			})
+			attrs.PutStr("shipment.id", ctx.ShipmentID)
 		}
 		if rng.Float64() < density*0.8 {
 			carriers := []string{"UPS", "FedEx", "DHL", "USPS"}
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "shipment.carrier",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: carriers[rng.Intn(len(carriers))],
-					},
-				},
-			})
+			attrs.PutStr("shipment.carrier", carriers[rng.Intn(len(carriers))])
 		}
 		if rng.Float64() < density*0.6 {
 			trackingNumber := fmt.Sprintf("TRK%012d", rng.Intn(1000000000000))
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "shipment.tracking_number",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: trackingNumber,
-					},
-				},
-			})
+			attrs.PutStr("shipment.tracking_number", trackingNumber)
 		}
 		if rng.Float64() < density*0.5 {
 			destinations := []string{"US", "CA", "UK", "DE", "FR", "JP"}
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "shipment.destination",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: destinations[rng.Intn(len(destinations))],
-					},
-				},
-			})
+			attrs.PutStr("shipment.destination", destinations[rng.Intn(len(destinations))])
 		}
 
 	case "analytics":
 		if rng.Float64() < density {
 			events := []string{"page_view", "click", "purchase", "search", "login", "logout"}
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "analytics.event_name",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: events[rng.Intn(len(events))],
-					},
-				},
-			})
+			attrs.PutStr("analytics.event_name", events[rng.Intn(len(events))])
 		}
 		if rng.Float64() < density*0.6 {
 			pageViews := rng.Intn(10) + 1
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "analytics.page_views",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_IntValue{
-						IntValue: int64(pageViews),
-					},
-				},
-			})
+			attrs.PutInt("analytics.page_views", int64(pageViews))
 		}
 		if rng.Float64() < density*0.5 {
 			sessionDuration := rng.Intn(3600) + 60 // 60 to 3660 seconds
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "analytics.session_duration_seconds",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_IntValue{
-						IntValue: int64(sessionDuration),
-					},
-				},
-			})
+			attrs.PutInt("analytics.session_duration_seconds", int64(sessionDuration))
 		}
 
 	case "frontend", "backend":
 		if rng.Float64() < density && ctx.OrderID != "" {
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "order.id",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: ctx.OrderID,
-					},
-				},
-			})
+			attrs.PutStr("order.id", ctx.OrderID)
 		}
 		if rng.Float64() < density && ctx.ProductID != "" {
-			attrs = append(attrs, &commonv1.KeyValue{
-				Key: "product.id",
-				Value: &commonv1.AnyValue{
-					Value: &commonv1.AnyValue_StringValue{
-						StringValue: ctx.ProductID,
-					},
-				},
-			})
+			attrs.PutStr("product.id", ctx.ProductID)
 		}
 	}
-
-	return attrs
 }
 
 // generateResourceAttributes generates realistic resource attributes
@@ -2,56 +2,108 @@ package generator
 
 import (
 	cryptoRand "crypto/rand"
-	"encoding/hex"
+	"encoding/binary"
 	"fmt"
 	"math/rand"
 	"time"
 
-	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
-	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
-// generateSpanID generates a random span ID
-func generateSpanID() []byte {
-	id := make([]byte, 8)
-	cryptoRand.Read(id)
+// generateSpanID generates a random span ID from idRNG, or from crypto/rand when idRNG is nil
+func generateSpanID(idRNG *rand.Rand) pcommon.SpanID {
+	var id pcommon.SpanID
+	fillRandom(id[:], idRNG)
 	return id
 }
 
-// generateAttributeValue generates a random attribute value of specified size
-func generateAttributeValue(size int) string {
-	if size <= 0 {
-		return ""
+// generateTraceID generates a random trace ID from idRNG, or from crypto/rand when idRNG is nil
+func generateTraceID(idRNG *rand.Rand) pcommon.TraceID {
+	var id pcommon.TraceID
+	fillRandom(id[:], idRNG)
+	return id
+}
+
+// Trace ID formats
+const (
+	TraceIDFormatRandom    = "random"    // 128 random bits
+	TraceIDFormat64Bit     = "64bit"     // 64 random bits in the low half and zeros in the high half, as sent by 64-bit Jaeger and Zipkin clients
+	TraceIDFormatTimestamp = "timestamp" // Unix time in seconds in the first 4 bytes and 96 random bits, like AWS X-Ray trace IDs
+)
+
+// newTraceID generates the ID of a trace in the configured format; timestamps are the base time
+func newTraceID(config Config, idRNG *rand.Rand) pcommon.TraceID {
+	var id pcommon.TraceID
+	switch config.TraceIDFormat {
+	case TraceIDFormat64Bit:
+		fillRandom(id[8:], idRNG)
+	case TraceIDFormatTimestamp:
+		binary.BigEndian.PutUint32(id[:4], uint32(baseTime(config).Unix()))
+		fillRandom(id[4:], idRNG)
+	default:
+		fillRandom(id[:], idRNG)
 	}
-	bytes := make([]byte, size)
-	cryptoRand.Read(bytes)
-	return hex.EncodeToString(bytes)
+	return id
 }
 
-// calculateDuration calculates span duration with variance
-func calculateDuration(config Config, rng *rand.Rand) time.Duration {
-	base := float64(config.DurationBaseMs)
-	if base <= 0 {
-		base = 50
+// randomBytes returns n random bytes from rng, or from crypto/rand when rng is nil
+func randomBytes(n int, rng *rand.Rand) []byte {
+	b := make([]byte, n)
+	fillRandom(b, rng)
+	return b
+}
+
+// fillRandom fills b with random bytes from rng, or from crypto/rand when rng is nil
+func fillRandom(b []byte, rng *rand.Rand) {
+	if rng == nil {
+		cryptoRand.Read(b)
+		return
 	}
-	variance := float64(config.DurationVarianceMs)
-	if variance < 0 {
-		variance = 30
+	for i := range b {
+		b[i] = byte(rng.Intn(256))
 	}
+}
 
-	// Normal distribution: base + (random * variance)
-	duration := base + rng.NormFloat64()*variance
-	if duration < 1 {
-		duration = 1
+// idRand returns the source of trace and span IDs: rng for seeded configs, so IDs are
+// reproducible, and nil otherwise, so traces seeded at the same instant never share IDs
+func idRand(config Config, rng *rand.Rand) *rand.Rand {
+	if config.Seed != 0 {
+		return rng
 	}
-	return time.Duration(duration) * time.Millisecond
+	return nil
+}
+
+// baseTime returns the time trace timestamps are derived from
+func baseTime(config Config) time.Time {
+	if config.BaseTimeMs > 0 {
+		return time.UnixMilli(config.BaseTimeMs)
+	}
+	return time.Now()
+}
+
+// generateAttributeValue generates a filler attribute value of specified size: random hex, or with
+// probability textRatio natural text of the same length
+func generateAttributeValue(size int, textRatio float64, rng *rand.Rand) string {
+	if size <= 0 {
+		return ""
+	}
+	if textRatio >= 1 || (textRatio > 0 && rng.Float64() < textRatio) {
+		return generateTextValue(2*size, rng)
+	}
+	return randomHex(size, rng)
+}
+
+// calculateDuration calculates span duration with variance
+func calculateDuration(config Config, rng *rand.Rand) time.Duration {
+	return calculateDurationFromConfig(config.durationConfig(), rng)
 }
 
 // selectSpanKind selects a span kind based on weighted distribution
-func selectSpanKind(config Config, serviceName string, rng *rand.Rand) tracev1.Span_SpanKind {
+func selectSpanKind(config Config, serviceName string, rng *rand.Rand) ptrace.SpanKind {
 	if len(config.SpanKindWeights) == 0 {
 		// Default to server if no weights configured
-		return tracev1.Span_SPAN_KIND_SERVER
+		return ptrace.SpanKindServer
 	}
 
 	// Normalize weights
@@ -61,38 +113,40 @@ func selectSpanKind(config Config, serviceName string, rng *rand.Rand) tracev1.S
 	}
 
 	if totalWeight == 0 {
-		return tracev1.Span_SPAN_KIND_SERVER
+		return ptrace.SpanKindServer
 	}
 
-	// Weighted random selection
+	// Weighted random selection, in key order so seeded traces are reproducible
 	r := rng.Float64() * totalWeight
 	currentWeight := 0.0
 
-	for kindStr, weight := range config.SpanKindWeights {
-		currentWeight += weight
+	kinds := sortedKeys(config.SpanKindWeights)
+	defer releaseKeys(kinds)
+	for _, kindStr := range *kinds {
+		currentWeight += config.SpanKindWeights[kindStr]
 		if r <= currentWeight {
 			switch kindStr {
 			case "server":
-				return tracev1.Span_SPAN_KIND_SERVER
+				return ptrace.SpanKindServer
 			case "client":
-				return tracev1.Span_SPAN_KIND_CLIENT
+				return ptrace.SpanKindClient
 			case "internal":
-				return tracev1.Span_SPAN_KIND_INTERNAL
+				return ptrace.SpanKindInternal
 			case "producer":
-				return tracev1.Span_SPAN_KIND_PRODUCER
+				return ptrace.SpanKindProducer
 			case "consumer":
-				return tracev1.Span_SPAN_KIND_CONSUMER
+				return ptrace.SpanKindConsumer
 			default:
-				return tracev1.Span_SPAN_KIND_SERVER
+				return ptrace.SpanKindServer
 			}
 		}
 	}
 
-	return tracev1.Span_SPAN_KIND_SERVER
+	return ptrace.SpanKindServer
 }
 
-// generateStatus generates span status with error injection
-func generateStatus(config Config, rng *rand.Rand) *tracev1.Status {
+// generateStatus generates the status code and message of a span with error injection
+func generateStatus(config Config, rng *rand.Rand) (ptrace.StatusCode, string) {
 	errorRate := config.ErrorRate
 	if errorRate < 0 {
 		errorRate = 0
@@ -103,22 +157,17 @@ func generateStatus(config Config, rng *rand.Rand) *tracev1.Status {
 
 	if rng.Float64() < errorRate {
 		// Generate error
-		message := errorMessages[rng.Intn(len(errorMessages))]
-		return &tracev1.Status{
-			Code:    tracev1.Status_STATUS_CODE_ERROR,
-			Message: message,
-		}
+		return ptrace.StatusCodeError, errorMessages[rng.Intn(len(errorMessages))]
 	}
 
-	return &tracev1.Status{
-		Code: tracev1.Status_STATUS_CODE_OK,
-	}
+	return ptrace.StatusCodeOk, ""
 }
 
-// buildSpanWithContext creates a span with workflow context and tag context
+// buildSpanWithContext creates a standalone span with workflow context and tag context; an empty
+// parentSpanID makes it a root span
 func buildSpanWithContext(
-	traceID []byte,
-	parentSpanID []byte,
+	traceID pcommon.TraceID,
+	parentSpanID pcommon.SpanID,
 	spanIndex int,
 	depth int,
 	serviceName string,
@@ -128,8 +177,8 @@ func buildSpanWithContext(
 	workflowCtx *WorkflowContext,
 	tagCtx *TagContext,
 	operationName string,
-) *tracev1.Span {
-	spanID := generateSpanID()
+) ptrace.Span {
+	spanID := generateSpanID(idRand(config, rng))
 
 	// Generate realistic operation name
 	var spanName string
@@ -150,96 +199,62 @@ func buildSpanWithContext(
 	kind := selectSpanKind(config, serviceName, rng)
 
 	// Generate status (with error injection)
-	status := generateStatus(config, rng)
-
-	span := &tracev1.Span{
-		TraceId:           traceID,
-		SpanId:            spanID,
-		ParentSpanId:      parentSpanID,
-		Name:              spanName,
-		Kind:              kind,
-		StartTimeUnixNano: uint64(startTime.UnixNano()),
-		EndTimeUnixNano:   uint64(endTime.UnixNano()),
-		Status:            status,
-	}
-
-	// Add attributes
-	attrs := make([]*commonv1.KeyValue, 0)
+	statusCode, statusMessage := generateStatus(config, rng)
+
+	span := ptrace.NewSpan()
+	span.SetTraceID(traceID)
+	span.SetSpanID(spanID)
+	span.SetParentSpanID(parentSpanID)
+	span.SetName(spanName)
+	span.SetKind(kind)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(endTime))
+	span.Status().SetCode(statusCode)
+	span.Status().SetMessage(statusMessage)
+
+	// Add attributes; most spans get a handful of semantic attributes besides the custom ones
+	attrs := span.Attributes()
+	attrs.EnsureCapacity(8 + config.AttributeCount)
 
 	// Standard attributes
-	attrs = append(attrs, &commonv1.KeyValue{
-		Key: "service.name",
-		Value: &commonv1.AnyValue{
-			Value: &commonv1.AnyValue_StringValue{
-				StringValue: serviceName,
-			},
-		},
-	})
-
-	attrs = append(attrs, &commonv1.KeyValue{
-		Key: "span.depth",
-		Value: &commonv1.AnyValue{
-			Value: &commonv1.AnyValue_IntValue{
-				IntValue: int64(depth),
-			},
-		},
-	})
+	attrs.PutStr("service.name", serviceName)
+
+	attrs.PutInt("span.depth", int64(depth))
 
 	// Add semantic attributes if enabled
 	if config.UseSemanticAttributes {
-		semanticAttrs := generateSemanticAttributes(kind, serviceName, rng)
-		attrs = append(attrs, semanticAttrs...)
+		generateSemanticAttributes(attrs, kind, serviceName, config.SemanticPacks, config.valueOptions(), rng)
 	}
 
 	// Add business attributes if workflow context is provided
 	if workflowCtx != nil {
-		businessAttrs := generateBusinessAttributes(workflowCtx, serviceName, config, rng)
-		attrs = append(attrs, businessAttrs...)
+		generateBusinessAttributes(attrs, workflowCtx, serviceName, config, rng)
 	}
 
 	// Add tags if enabled
 	if tagCtx != nil {
-		tags := GenerateTags(tagCtx, config, rng)
-		attrs = append(attrs, tags...)
+		GenerateTags(attrs, tagCtx, config, rng)
 	}
 
 	// Generate custom attributes
-	for i := 0; i < config.AttributeCount; i++ {
-		key := fmt.Sprintf("attribute.%d", i)
-		value := generateAttributeValue(config.AttributeValueSize)
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: key,
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: value,
-				},
-			},
-		})
-	}
-
-	span.Attributes = attrs
+	putCustomAttributes(attrs, config, rng)
 
 	// Add events if configured
 	if config.EventCount > 0 {
-		events := make([]*tracev1.Span_Event, 0, config.EventCount)
+		events := span.Events()
+		events.EnsureCapacity(config.EventCount)
 		for i := 0; i < config.EventCount; i++ {
 			eventTime := startTime.Add(time.Duration(i) * duration / time.Duration(config.EventCount))
-			events = append(events, &tracev1.Span_Event{
-				TimeUnixNano: uint64(eventTime.UnixNano()),
-				Name:         fmt.Sprintf("event-%d", i),
-				Attributes: []*commonv1.KeyValue{
-					{
-						Key: "event.type",
-						Value: &commonv1.AnyValue{
-							Value: &commonv1.AnyValue_StringValue{
-								StringValue: "log",
-							},
-						},
-					},
-				},
-			})
+			event := events.AppendEmpty()
+			event.SetTimestamp(pcommon.NewTimestampFromTime(eventTime))
+			event.SetName(fmt.Sprintf("event-%d", i))
+			event.Attributes().PutStr("event.type", "log")
 		}
-		span.Events = events
+	}
+
+	// Report errors as exception events if configured
+	if config.ExceptionEvents && statusCode == ptrace.StatusCodeError {
+		addExceptionEvent(span, serviceName, config.StacktraceFrames, rng)
 	}
 
 	return span
@@ -0,0 +1,96 @@
+package generator
+
+// ServiceEdge identifies calls from one service to another
+type ServiceEdge struct {
+	Client string
+	Server string
+}
+
+// ExpectedTopology holds the expected per-trace span and call counts of a trace tree
+type ExpectedTopology struct {
+	SpansPerService map[string]float64      // Expected spans per trace for each service
+	CallsPerEdge    map[ServiceEdge]float64 // Expected service-graph requests per trace for each edge
+}
+
+// ExpectedTreeTopology computes the expected number of spans per service and service-graph
+// requests per edge for a single trace generated from config.
+// Service-graph edges follow Tempo's metrics-generator: a client (or producer) span whose
+// child is a server (or consumer) span of a different service.
+func ExpectedTreeTopology(config *TraceTreeConfig) ExpectedTopology {
+	topology := ExpectedTopology{
+		SpansPerService: make(map[string]float64),
+		CallsPerEdge:    make(map[ServiceEdge]float64),
+	}
+	if config == nil || config.Root == nil {
+		return topology
+	}
+
+	if root, refPath := config.expandRef(config.Root, nil); root != nil {
+		expectNode(config, root, refPath, 1.0, &topology)
+	}
+	return topology
+}
+
+// expectNode accumulates the expected counts of node, which appears multiplicity times per trace.
+// Subtree refs expand as in generation, along refPath.
+func expectNode(config *TraceTreeConfig, node *TraceTreeNode, refPath map[string]int, multiplicity float64, topology *ExpectedTopology) {
+	topology.SpansPerService[node.Service] += multiplicity
+
+	probabilities := edgeProbabilities(node.Children)
+	for i, edge := range node.Children {
+		child, childRefPath := config.expandRef(edge.Node, refPath)
+		if child == nil || probabilities[i] == 0 {
+			continue
+		}
+
+		childMultiplicity := multiplicity * probabilities[i] * expectedCount(edge.Count)
+		if isCallerKind(node.SpanKind) && isCalleeKind(child.SpanKind) && node.Service != child.Service {
+			topology.CallsPerEdge[ServiceEdge{Client: node.Service, Server: child.Service}] += childMultiplicity
+		}
+		expectNode(config, child, childRefPath, childMultiplicity, topology)
+	}
+}
+
+// edgeProbabilities returns the selection probability of each edge, matching NormalizeWeights
+// without modifying the configuration
+func edgeProbabilities(edges []TraceTreeEdge) []float64 {
+	probabilities := make([]float64, len(edges))
+
+	total := 0.0
+	for _, e := range edges {
+		if e.Weight > 0 {
+			total += e.Weight
+		}
+	}
+
+	for i, e := range edges {
+		switch {
+		case total == 0:
+			probabilities[i] = 1.0 / float64(len(edges))
+		case e.Weight > 0:
+			probabilities[i] = e.Weight / total
+		}
+	}
+	return probabilities
+}
+
+// expectedCount returns the mean number of repetitions for an edge
+func expectedCount(count CountConfig) float64 {
+	if count.Max <= 0 {
+		return 1
+	}
+	if count.Min < count.Max {
+		return float64(count.Min+count.Max) / 2
+	}
+	return float64(count.Min)
+}
+
+// isCallerKind reports whether a span kind starts a service-graph edge
+func isCallerKind(kind string) bool {
+	return kind == "client" || kind == "producer"
+}
+
+// isCalleeKind reports whether a span kind ends a service-graph edge (unknown kinds generate server spans)
+func isCalleeKind(kind string) bool {
+	return kind != "client" && kind != "producer" && kind != "internal"
+}
@@ -0,0 +1,257 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// TopologyFile is a declarative service topology, kept as a versioned YAML or JSON file and
+// compiled into a trace tree by LoadTopology
+type TopologyFile struct {
+	Seed           int64                      `json:"seed" yaml:"seed"`
+	Defaults       *TopologyDefaults          `json:"defaults" yaml:"defaults"`
+	Context        TopologyContext            `json:"context" yaml:"context"`
+	AttributePools map[string][]string        `json:"attributePools" yaml:"attributePools"` // Named value pools referenced by attributes
+	Services       map[string]TopologyService `json:"services" yaml:"services"`
+	Root           TopologyRef                `json:"root" yaml:"root"`   // Operation that starts every trace
+	Edges          []TopologyEdge             `json:"edges" yaml:"edges"` // Calls between operations
+}
+
+// TopologyDefaults mirrors TreeDefaults; unset fields keep the trace tree defaults
+type TopologyDefaults struct {
+	UseSemanticAttributes *bool    `json:"useSemanticAttributes" yaml:"useSemanticAttributes"`
+	EnableTags            *bool    `json:"enableTags" yaml:"enableTags"`
+	TagDensity            *float64 `json:"tagDensity" yaml:"tagDensity"`
+}
+
+// TopologyContext mirrors TreeContext
+type TopologyContext struct {
+	Propagate   []string           `json:"propagate" yaml:"propagate"`
+	Cardinality map[string]int     `json:"cardinality" yaml:"cardinality"`
+	Skew        map[string]float64 `json:"skew" yaml:"skew"`
+}
+
+// TopologyService declares a service and its operations
+type TopologyService struct {
+	Tags       map[string]string            `json:"tags" yaml:"tags"`             // Static attributes of all operations
+	Attributes map[string]string            `json:"attributes" yaml:"attributes"` // Attribute name to pool name, for all operations
+	Operations map[string]TopologyOperation `json:"operations" yaml:"operations"`
+}
+
+// TopologyOperation declares an operation of a service
+type TopologyOperation struct {
+	SpanKind        string            `json:"spanKind" yaml:"spanKind"` // Default: "server"
+	Latency         TopologyLatency   `json:"latency" yaml:"latency"`
+	ErrorRate       float64           `json:"errorRate" yaml:"errorRate"`
+	ErrorPropagates bool              `json:"errorPropagates" yaml:"errorPropagates"`
+	Tags            map[string]string `json:"tags" yaml:"tags"`
+	Attributes      map[string]string `json:"attributes" yaml:"attributes"` // Attribute name to pool name
+}
+
+// TopologyRef references an operation of a service
+type TopologyRef struct {
+	Service   string `json:"service" yaml:"service"`
+	Operation string `json:"operation" yaml:"operation"`
+}
+
+// String returns the reference as service/operation
+func (r TopologyRef) String() string {
+	return r.Service + "/" + r.Operation
+}
+
+// TopologyEdge declares a call from one operation to another
+type TopologyEdge struct {
+	From     TopologyRef   `json:"from" yaml:"from"`
+	To       TopologyRef   `json:"to" yaml:"to"`
+	Weight   float64       `json:"weight" yaml:"weight"` // 0 = equiprobable
+	Parallel bool          `json:"parallel" yaml:"parallel"`
+	Count    TopologyCount `json:"count" yaml:"count"`
+}
+
+// TopologyLatency mirrors DurationConfig
+type TopologyLatency struct {
+	BaseMs       int     `json:"baseMs" yaml:"baseMs"`
+	VarianceMs   int     `json:"varianceMs" yaml:"varianceMs"`
+	Distribution string  `json:"distribution" yaml:"distribution"`
+	Sigma        float64 `json:"sigma" yaml:"sigma"`
+	Alpha        float64 `json:"alpha" yaml:"alpha"`
+	HitRate      float64 `json:"hitRate" yaml:"hitRate"`
+	MissMs       int     `json:"missMs" yaml:"missMs"`
+}
+
+// TopologyCount mirrors CountConfig
+type TopologyCount struct {
+	Min int `json:"min" yaml:"min"`
+	Max int `json:"max" yaml:"max"`
+}
+
+// LoadTopology reads a topology file (.json, otherwise YAML) and compiles it into a trace tree
+func LoadTopology(path string) (*TraceTreeConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology file: %w", err)
+	}
+
+	var topology TopologyFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &topology)
+	} else {
+		err = yaml.Unmarshal(data, &topology)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse topology file %s: %w", path, err)
+	}
+
+	return topology.TraceTree()
+}
+
+// TraceTree compiles the topology into a trace tree rooted at its root operation.
+// Operations reachable through several paths are expanded once per path; cycles are rejected.
+func (t *TopologyFile) TraceTree() (*TraceTreeConfig, error) {
+	if t.Root.Service == "" || t.Root.Operation == "" {
+		return nil, fmt.Errorf("topology root service and operation are required")
+	}
+
+	// Index edges by caller, validating both ends
+	edges := make(map[TopologyRef][]TopologyEdge)
+	for i, edge := range t.Edges {
+		for _, ref := range []TopologyRef{edge.From, edge.To} {
+			if _, err := t.operation(ref); err != nil {
+				return nil, fmt.Errorf("edge %d: %w", i, err)
+			}
+		}
+		edges[edge.From] = append(edges[edge.From], edge)
+	}
+
+	root, err := t.node(t.Root, edges, make(map[TopologyRef]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	config := &TraceTreeConfig{
+		Seed: t.Seed,
+		Context: TreeContext{
+			Propagate:   t.Context.Propagate,
+			Cardinality: t.Context.Cardinality,
+			Skew:        t.Context.Skew,
+		},
+		Defaults: TreeDefaults{
+			UseSemanticAttributes: true,
+			EnableTags:            true,
+			TagDensity:            0.9,
+		},
+		Root: root,
+	}
+	if t.Defaults != nil {
+		if t.Defaults.UseSemanticAttributes != nil {
+			config.Defaults.UseSemanticAttributes = *t.Defaults.UseSemanticAttributes
+		}
+		if t.Defaults.EnableTags != nil {
+			config.Defaults.EnableTags = *t.Defaults.EnableTags
+		}
+		if t.Defaults.TagDensity != nil {
+			config.Defaults.TagDensity = *t.Defaults.TagDensity
+		}
+	}
+	return config, nil
+}
+
+// operation returns the operation a reference points to
+func (t *TopologyFile) operation(ref TopologyRef) (TopologyOperation, error) {
+	service, ok := t.Services[ref.Service]
+	if !ok {
+		return TopologyOperation{}, fmt.Errorf("unknown service %q", ref.Service)
+	}
+	op, ok := service.Operations[ref.Operation]
+	if !ok {
+		return TopologyOperation{}, fmt.Errorf("unknown operation %q of service %q", ref.Operation, ref.Service)
+	}
+	return op, nil
+}
+
+// node builds the tree node of an operation and, recursively, of the operations it calls;
+// visiting holds the operations on the current path
+func (t *TopologyFile) node(ref TopologyRef, edges map[TopologyRef][]TopologyEdge, visiting map[TopologyRef]bool) (*TraceTreeNode, error) {
+	if visiting[ref] {
+		return nil, fmt.Errorf("topology has a cycle through %s", ref)
+	}
+	visiting[ref] = true
+	defer delete(visiting, ref)
+
+	op, err := t.operation(ref)
+	if err != nil {
+		return nil, err
+	}
+	service := t.Services[ref.Service]
+
+	spanKind := op.SpanKind
+	if spanKind == "" {
+		spanKind = "server"
+	}
+	switch spanKind {
+	case "server", "client", "internal", "producer", "consumer":
+	default:
+		return nil, fmt.Errorf("%s: unknown spanKind %q", ref, spanKind)
+	}
+	if !IsDistribution(op.Latency.Distribution) {
+		return nil, fmt.Errorf("%s: unknown latency distribution %q", ref, op.Latency.Distribution)
+	}
+
+	node := &TraceTreeNode{
+		Service:   ref.Service,
+		Operation: ref.Operation,
+		SpanKind:  spanKind,
+		Duration: DurationConfig{
+			BaseMs:       op.Latency.BaseMs,
+			VarianceMs:   op.Latency.VarianceMs,
+			Distribution: op.Latency.Distribution,
+			Sigma:        op.Latency.Sigma,
+			Alpha:        op.Latency.Alpha,
+			HitRate:      op.Latency.HitRate,
+			MissMs:       op.Latency.MissMs,
+		},
+		ErrorRate:       op.ErrorRate,
+		ErrorPropagates: op.ErrorPropagates,
+	}
+
+	// Operation tags and attributes override the service ones
+	for _, tags := range []map[string]string{service.Tags, op.Tags} {
+		for key, value := range tags {
+			if node.Tags == nil {
+				node.Tags = make(map[string]string)
+			}
+			node.Tags[key] = value
+		}
+	}
+	for _, attributes := range []map[string]string{service.Attributes, op.Attributes} {
+		for key, poolName := range attributes {
+			pool, ok := t.AttributePools[poolName]
+			if !ok || len(pool) == 0 {
+				return nil, fmt.Errorf("%s: attribute %q references unknown or empty pool %q", ref, key, poolName)
+			}
+			if node.AttributePools == nil {
+				node.AttributePools = make(map[string][]string)
+			}
+			node.AttributePools[key] = pool
+		}
+	}
+
+	for _, edge := range edges[ref] {
+		child, err := t.node(edge.To, edges, visiting)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, TraceTreeEdge{
+			Weight:   edge.Weight,
+			Parallel: edge.Parallel,
+			Count:    CountConfig{Min: edge.Count.Min, Max: edge.Count.Max},
+			Node:     child,
+		})
+	}
+	return node, nil
+}
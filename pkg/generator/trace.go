@@ -1,26 +1,48 @@
 package generator
 
 import (
-	cryptoRand "crypto/rand"
+	"fmt"
+	"maps"
 	"math/rand"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
-	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
 )
 
 // spanInfo holds information about a span for tree building
 type spanInfo struct {
-	span        *tracev1.Span
+	span        ptrace.Span // Standalone until moved into the trace
 	index       int
 	depth       int
 	children    []int // indices of child spans
 	maxChildren int
 }
 
-// GenerateTrace generates a single trace based on the configuration
+// GenerateTrace generates a single trace based on the configuration, or on one of its profiles
 func GenerateTrace(config Config) ptrace.Traces {
+	if len(config.Profiles) > 0 {
+		config = selectProfile(config)
+	}
+	traces := generateTrace(config)
+	if config.LongTraceRate > 0 {
+		stretchTrace(traces, config, longTraceRand(config))
+	}
+	if config.MessagingRate > 0 {
+		addMessagingFlow(traces, config, messagingRand(config))
+	}
+	if config.ResourceSchemaURL != "" || config.ScopeSchemaURL != "" {
+		setSchemaURLs(traces, config)
+	}
+	if config.SamplingProbability > 0 {
+		setSampling(traces, config, samplingRand(config))
+	}
+	return traces
+}
+
+// generateTrace generates a trace with the tree-, topology-, workflow-based or legacy generator
+func generateTrace(config Config) ptrace.Traces {
 	// Use tree-based generation if enabled
 	if config.UseTraceTree && config.TraceTreeConfig != nil {
 		return GenerateTraceFromTree(*config.TraceTreeConfig)
@@ -32,8 +54,14 @@ func GenerateTrace(config Config) ptrace.Traces {
 	// Set resource attributes
 	resource := resourceSpans.Resource()
 
+	// Seeded traces are reproducible, IDs and timestamps included when baseTimeMs is set
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
 	// Generate resource attributes if not provided
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	resourceAttrs := config.ResourceAttributes
 	if len(resourceAttrs) == 0 {
 		// Generate default resource attributes
@@ -42,13 +70,11 @@ func GenerateTrace(config Config) ptrace.Traces {
 		resourceAttrs["service.name"] = serviceName
 	}
 
-	for key, value := range resourceAttrs {
-		resource.Attributes().PutStr(key, value)
-	}
+	putStringAttributes(resource.Attributes(), resourceAttrs)
+	putResourceArrayAttributes(resource.Attributes(), config)
 
 	// Generate trace ID
-	traceID := make([]byte, 16)
-	cryptoRand.Read(traceID)
+	traceID := newTraceID(config, idRand(config, rng))
 
 	// Generate tag context (consistent across all spans in trace)
 	tagCtx := GenerateTagContext(config, rng)
@@ -58,14 +84,18 @@ func GenerateTrace(config Config) ptrace.Traces {
 	var workflowName string
 	if config.UseWorkflows {
 		workflowName = SelectWorkflow(config.WorkflowWeights, rng)
-		workflowCtx = GenerateWorkflowContext(workflowName, rng, config.CardinalityConfig)
+		workflowCtx = GenerateWorkflowContext(workflowName, rng, config.CardinalityConfig, config.CardinalitySkew)
 	}
 
 	// Generate spans
 	scopeSpans := resourceSpans.ScopeSpans().AppendEmpty()
+	setInstrumentationScope(scopeSpans.Scope(), config.InstrumentationScopes, rng)
 	spans := scopeSpans.Spans()
 
-	// Use workflow-based generation if enabled, otherwise use legacy tree-based
+	// Use topology- or workflow-based generation if enabled, otherwise use legacy tree-based
+	if config.Topology != nil && len(config.Topology.Nodes) > 0 {
+		return generateTopologyTrace(traceID, config, rng, tagCtx)
+	}
 	if config.UseWorkflows && workflowCtx != nil {
 		return generateWorkflowTrace(traces, traceID, config, rng, workflowCtx, tagCtx, workflowName)
 	}
@@ -75,12 +105,12 @@ func GenerateTrace(config Config) ptrace.Traces {
 	serviceIndex := 0
 
 	// Trace start time (all spans relative to this)
-	traceStartTime := time.Now().Add(-time.Duration(rng.Intn(3600)) * time.Second)
+	traceStartTime := traceStartTime(config, rng)
 
 	// Generate root span
 	rootSpan := buildSpanWithContext(
 		traceID,
-		nil, // no parent
+		pcommon.NewSpanIDEmpty(), // no parent
 		0,
 		0,
 		generateServiceName(serviceIndex),
@@ -129,8 +159,8 @@ func GenerateTrace(config Config) ptrace.Traces {
 
 		// Calculate child timing (must fit within parent)
 		parentSpan := parentInfo.span
-		parentStart := time.Unix(0, int64(parentSpan.StartTimeUnixNano))
-		parentEnd := time.Unix(0, int64(parentSpan.EndTimeUnixNano))
+		parentStart := parentSpan.StartTimestamp().AsTime()
+		parentEnd := parentSpan.EndTimestamp().AsTime()
 		parentDuration := parentEnd.Sub(parentStart)
 
 		// Child starts after some delay within parent
@@ -155,7 +185,7 @@ func GenerateTrace(config Config) ptrace.Traces {
 
 		childSpan := buildSpanWithContext(
 			traceID,
-			parentSpan.SpanId,
+			parentSpan.SpanID(),
 			spansGenerated,
 			parentInfo.depth+1,
 			generateServiceName(serviceIndex),
@@ -168,9 +198,8 @@ func GenerateTrace(config Config) ptrace.Traces {
 		)
 
 		// Ensure child ends before parent
-		childEnd := time.Unix(0, int64(childSpan.EndTimeUnixNano))
-		if childEnd.After(parentEnd) {
-			childSpan.EndTimeUnixNano = parentSpan.EndTimeUnixNano - uint64(time.Millisecond.Nanoseconds())
+		if childSpan.EndTimestamp() > parentSpan.EndTimestamp() {
+			childSpan.SetEndTimestamp(parentSpan.EndTimestamp() - pcommon.Timestamp(time.Millisecond))
 		}
 
 		childInfo := &spanInfo{
@@ -186,10 +215,19 @@ func GenerateTrace(config Config) ptrace.Traces {
 		spansGenerated++
 	}
 
-	// Convert to ptrace.Span and add to scope spans
-	for _, spanInfo := range spansMap {
-		span := spans.AppendEmpty()
-		spanProtoToPtrace(spanInfo.span, span)
+	// Propagate errors, break the trace structure, inject anomalies and link spans, then move the
+	// spans into the scope
+	propagateErrors(spansMap, config, rng)
+	breakTraceStructure(spansMap, config, rng)
+	traceSpans := orderedSpans(spansMap)
+	injectTimestampAnomalies(traceSpans, config, rng)
+	addSpanLinks(traceSpans, config, rng)
+	if kind := injectChaos(traceSpans, config, rng); kind != "" {
+		resource.Attributes().PutStr(ChaosAttribute, kind)
+	}
+	spans.EnsureCapacity(len(traceSpans))
+	for _, span := range traceSpans {
+		span.MoveTo(spans.AppendEmpty())
 	}
 
 	return traces
@@ -238,7 +276,8 @@ func calculateMaxChildren(depth int, config Config, rng *rand.Rand) int {
 func selectParentWithFanOut(spansMap map[int]*spanInfo, config Config, rng *rand.Rand) *spanInfo {
 	// Collect available parents (those that can still have children)
 	available := make([]*spanInfo, 0)
-	for _, info := range spansMap {
+	for i := 0; i < len(spansMap); i++ {
+		info := spansMap[i]
 		if len(info.children) < info.maxChildren && info.depth < config.SpanDepth {
 			available = append(available, info)
 		}
@@ -274,7 +313,8 @@ func selectParentWithFanOut(spansMap map[int]*spanInfo, config Config, rng *rand
 
 // findAvailableParent finds any parent that can still have children
 func findAvailableParent(spansMap map[int]*spanInfo, config Config) *spanInfo {
-	for _, info := range spansMap {
+	for i := 0; i < len(spansMap); i++ {
+		info := spansMap[i]
 		if len(info.children) < info.maxChildren && info.depth < config.SpanDepth {
 			return info
 		}
@@ -282,51 +322,157 @@ func findAvailableParent(spansMap map[int]*spanInfo, config Config) *spanInfo {
 	return nil
 }
 
-// GenerateBatch generates a batch of traces targeting a specific size in bytes
+// BatchResult is a generated batch and what it holds
+type BatchResult struct {
+	Traces     []ptrace.Traces `js:"traces"`     // The batch, to push with pushBatch
+	TraceCount int             `js:"traceCount"` // Generated traces
+	SpanCount  int             `js:"spanCount"`  // Spans of all traces
+	SizeBytes  int             `js:"sizeBytes"`  // OTLP protobuf size of all traces
+}
+
+// GenerateBatch generates a batch of traces targeting a size in bytes, a number of spans or a
+// number of traces
 func GenerateBatch(config BatchConfig) []ptrace.Traces {
-	traces := make([]ptrace.Traces, 0)
-	currentSize := 0
+	return GenerateBatchResult(config).Traces
+}
 
-	// Estimate size per trace
-	sampleTrace := GenerateTrace(config.TraceConfig)
-	sampleSize := estimateTraceSize(sampleTrace)
+// GenerateBatchResult generates a batch like GenerateBatch and reports what it holds. Traces are
+// added until the batch reaches the size or span target, as long as the next trace keeps it within
+// the target plus SizeTolerance, or until the trace target is reached; with several targets the
+// batch stops at the first one. A batch holds at least one trace.
+func GenerateBatchResult(config BatchConfig) BatchResult {
+	return NewBatchIterator(config.TraceConfig).next(config)
+}
 
-	if sampleSize == 0 {
-		// Fallback: generate at least one trace
-		traces = append(traces, GenerateTrace(config.TraceConfig))
-		return traces
-	}
+// BatchIterator generates a stream of traces in chunks, so large volumes can be generated and
+// sent without holding them in memory at once
+type BatchIterator struct {
+	config    Config
+	generated int         // Traces generated so far, used to derive the seed of the next one
+	pending   *sizedTrace // Trace that did not fit in the previous chunk
+	pool      *tracePool  // Generates the traces in parallel when set
+}
+
+// NewBatchIterator creates an iterator generating traces with the given configuration; seeded
+// iterators generate the same stream of traces
+func NewBatchIterator(config Config) *BatchIterator {
+	return &BatchIterator{config: config}
+}
 
-	// Calculate how many traces we need
-	targetCount := config.TargetSizeBytes / sampleSize
-	if targetCount == 0 {
-		targetCount = 1
+// NextChunk generates the next chunk of traces, up to maxBytes of OTLP protobuf. A chunk holds
+// at least one trace; the trace that does not fit starts the next chunk.
+func (it *BatchIterator) NextChunk(maxBytes int) (*BatchResult, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("maxBytes must be > 0, got %d", maxBytes)
 	}
+	result := it.next(BatchConfig{TargetSizeBytes: maxBytes})
+	return &result, nil
+}
+
+// next generates a batch of traces following the targets of config; its trace config is ignored
+func (it *BatchIterator) next(config BatchConfig) BatchResult {
+	result := BatchResult{Traces: make([]ptrace.Traces, 0)}
+	maxSize := config.TargetSizeBytes + int(config.SizeTolerance*float64(config.TargetSizeBytes))
+
+	// Generate traces until we reach a target
+	for config.TargetTraces == 0 || result.TraceCount < config.TargetTraces {
+		if config.TargetSizeBytes > 0 && result.SizeBytes >= config.TargetSizeBytes {
+			break
+		}
+		if config.TargetSpans > 0 && result.SpanCount >= config.TargetSpans {
+			break
+		}
 
-	// Generate traces until we reach target size
-	for currentSize < config.TargetSizeBytes {
-		trace := GenerateTrace(config.TraceConfig)
-		traceSize := estimateTraceSize(trace)
+		trace := it.nextTrace()
 
-		if currentSize+traceSize > config.TargetSizeBytes && len(traces) > 0 {
-			// Adding this trace would exceed target, stop
+		exceedsSize := config.TargetSizeBytes > 0 && result.SizeBytes+trace.size > maxSize
+		exceedsSpans := config.TargetSpans > 0 && result.SpanCount+trace.traces.SpanCount() > config.TargetSpans
+		if (exceedsSize || exceedsSpans) && result.TraceCount > 0 {
+			// Adding this trace would exceed target, keep it for the next batch
+			it.pending = &trace
 			break
 		}
 
-		traces = append(traces, trace)
-		currentSize += traceSize
+		result.add(trace.traces, trace.size)
 
-		// Safety limit
-		if len(traces) > 10000 {
+		// Safety limit, unless the number of traces is the target
+		if config.TargetTraces == 0 && result.TraceCount > 10000 {
 			break
 		}
 	}
 
-	return traces
+	return result
+}
+
+// nextTrace returns the trace left over from the previous batch or generates one
+func (it *BatchIterator) nextTrace() sizedTrace {
+	if it.pending != nil {
+		trace := *it.pending
+		it.pending = nil
+		return trace
+	}
+
+	if it.pool != nil {
+		return it.pool.next()
+	}
+	it.generated++
+	return it.generate(it.generated - 1)
+}
+
+// generate generates the trace at index of the stream; seeded traces get a seed each
+func (it *BatchIterator) generate(index int) sizedTrace {
+	traceConfig := it.config
+	if it.config.Seed != 0 {
+		traceConfig.Seed = DeriveSeed(it.config.Seed, int64(index))
+	}
+	trace := GenerateTrace(traceConfig)
+	return sizedTrace{traces: trace, size: estimateTraceSize(trace)}
+}
+
+// sizedTrace is a generated trace and its OTLP protobuf size
+type sizedTrace struct {
+	traces ptrace.Traces
+	size   int
+}
+
+// add appends a trace of size bytes to the batch
+func (r *BatchResult) add(trace ptrace.Traces, size int) {
+	r.Traces = append(r.Traces, trace)
+	r.TraceCount++
+	r.SpanCount += trace.SpanCount()
+	r.SizeBytes += size
 }
 
 // Helper functions
 
+// DeriveSeed mixes a seed with parts such as a VU ID and an iteration into a new non-zero seed,
+// so every part gets its own reproducible stream
+func DeriveSeed(seed int64, parts ...int64) int64 {
+	x := uint64(seed)
+	for _, part := range parts {
+		// splitmix64 finalizer
+		x += uint64(part) + 0x9e3779b97f4a7c15
+		x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+		x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+		x ^= x >> 31
+	}
+	if x == 0 {
+		return 1
+	}
+	return int64(x)
+}
+
+// orderedSpans returns the spans of a span tree in index order; indices of dropped spans are skipped
+func orderedSpans(spansMap map[int]*spanInfo) []ptrace.Span {
+	spans := make([]ptrace.Span, 0, len(spansMap))
+	for i := 0; len(spans) < len(spansMap); i++ {
+		if info, ok := spansMap[i]; ok {
+			spans = append(spans, info.span)
+		}
+	}
+	return spans
+}
+
 func calculateDepth(spanIndex, totalSpans int) int {
 	if spanIndex == 0 {
 		return 0
@@ -340,99 +486,11 @@ func calculateDepth(spanIndex, totalSpans int) int {
 	return depth
 }
 
-func spanProtoToPtrace(proto *tracev1.Span, ptraceSpan ptrace.Span) {
-	// Convert []byte to TraceID/SpanID
-	var traceID pcommon.TraceID
-	copy(traceID[:], proto.TraceId)
-	ptraceSpan.SetTraceID(traceID)
-
-	var spanID pcommon.SpanID
-	copy(spanID[:], proto.SpanId)
-	ptraceSpan.SetSpanID(spanID)
-
-	if len(proto.ParentSpanId) > 0 {
-		var parentSpanID pcommon.SpanID
-		copy(parentSpanID[:], proto.ParentSpanId)
-		ptraceSpan.SetParentSpanID(parentSpanID)
-	}
-	ptraceSpan.SetName(proto.Name)
-	ptraceSpan.SetKind(ptrace.SpanKind(proto.Kind))
-	ptraceSpan.SetStartTimestamp(pcommon.Timestamp(proto.StartTimeUnixNano))
-	ptraceSpan.SetEndTimestamp(pcommon.Timestamp(proto.EndTimeUnixNano))
-
-	// Set status
-	if proto.Status != nil {
-		ptraceSpan.Status().SetCode(ptrace.StatusCode(proto.Status.Code))
-		if proto.Status.Message != "" {
-			ptraceSpan.Status().SetMessage(proto.Status.Message)
-		}
-	}
-
-	// Set attributes
-	for _, attr := range proto.Attributes {
-		if strVal := attr.Value.GetStringValue(); strVal != "" {
-			ptraceSpan.Attributes().PutStr(attr.Key, strVal)
-		} else if intVal := attr.Value.GetIntValue(); intVal != 0 {
-			ptraceSpan.Attributes().PutInt(attr.Key, intVal)
-		} else if boolVal := attr.Value.GetBoolValue(); boolVal {
-			ptraceSpan.Attributes().PutBool(attr.Key, boolVal)
-		} else if doubleVal := attr.Value.GetDoubleValue(); doubleVal != 0 {
-			ptraceSpan.Attributes().PutDouble(attr.Key, doubleVal)
-		}
-	}
-
-	// Set events
-	for _, event := range proto.Events {
-		eventPtrace := ptraceSpan.Events().AppendEmpty()
-		eventPtrace.SetName(event.Name)
-		eventPtrace.SetTimestamp(pcommon.Timestamp(event.TimeUnixNano))
-		for _, attr := range event.Attributes {
-			if strVal := attr.Value.GetStringValue(); strVal != "" {
-				eventPtrace.Attributes().PutStr(attr.Key, strVal)
-			}
-		}
-	}
-}
-
+// estimateTraceSize returns the OTLP protobuf size of a trace in bytes, the size the ingest client
+// measures and sends
 func estimateTraceSize(trace ptrace.Traces) int {
-	size := 0
-	if trace.ResourceSpans().Len() == 0 {
-		return 0
-	}
-
-	// Iterate over all ResourceSpans (one per service in workflow mode)
-	for rsIdx := 0; rsIdx < trace.ResourceSpans().Len(); rsIdx++ {
-		resourceSpans := trace.ResourceSpans().At(rsIdx)
-
-		// Add resource overhead
-		size += 50
-		resourceSpans.Resource().Attributes().Range(func(key string, value pcommon.Value) bool {
-			size += len(key) + len(value.AsString())
-			return true
-		})
-
-		for ssIdx := 0; ssIdx < resourceSpans.ScopeSpans().Len(); ssIdx++ {
-			scopeSpans := resourceSpans.ScopeSpans().At(ssIdx)
-			spans := scopeSpans.Spans()
-
-			for i := 0; i < spans.Len(); i++ {
-				span := spans.At(i)
-				// Rough estimate: count attributes, events, and basic span data
-				size += 100 // Base span overhead
-				attrs := span.Attributes()
-				attrs.Range(func(key string, value pcommon.Value) bool {
-					size += len(key) + len(value.AsString())
-					return true
-				})
-				events := span.Events()
-				for j := 0; j < events.Len(); j++ {
-					event := events.At(j)
-					size += len(event.Name()) + 50
-				}
-			}
-		}
-	}
-	return size
+	marshaler := &ptrace.ProtoMarshaler{}
+	return marshaler.TracesSize(trace)
 }
 
 // EstimateTraceSizeFromConfig estimates the average size of a trace in bytes based on configuration
@@ -453,17 +511,11 @@ func EstimateTraceSizeFromConfig(config Config) int {
 	return avgSize
 }
 
-// spanWithService holds span info along with its service name for grouping
-type spanWithService struct {
-	span        *tracev1.Span
-	serviceName string
-}
-
 // generateWorkflowTrace generates a trace following a workflow's service call chain
 // Each service gets its own ResourceSpans with proper service.name resource attribute
 func generateWorkflowTrace(
 	_ ptrace.Traces, // Ignored - we create a fresh traces object
-	traceID []byte,
+	traceID pcommon.TraceID,
 	config Config,
 	rng *rand.Rand,
 	workflowCtx *WorkflowContext,
@@ -481,7 +533,7 @@ func generateWorkflowTrace(
 	}
 
 	// Trace start time
-	traceStartTime := time.Now().Add(-time.Duration(rng.Intn(3600)) * time.Second)
+	traceStartTime := traceStartTime(config, rng)
 
 	// Build spans following workflow steps, tracking service for each span
 	spansMap := make(map[int]*spanInfo)
@@ -498,7 +550,7 @@ func generateWorkflowTrace(
 
 	rootSpan := buildSpanWithContext(
 		traceID,
-		nil,
+		pcommon.NewSpanIDEmpty(),
 		0,
 		0,
 		rootStep.Service,
@@ -511,13 +563,7 @@ func generateWorkflowTrace(
 	)
 
 	// Set span kind based on workflow step
-	if rootStep.SpanKind == "client" {
-		rootSpan.Kind = tracev1.Span_SPAN_KIND_CLIENT
-	} else if rootStep.SpanKind == "internal" {
-		rootSpan.Kind = tracev1.Span_SPAN_KIND_INTERNAL
-	} else {
-		rootSpan.Kind = tracev1.Span_SPAN_KIND_SERVER
-	}
+	rootSpan.SetKind(parseSpanKind(rootStep.SpanKind))
 
 	spansMap[0] = &spanInfo{
 		span:        rootSpan,
@@ -548,8 +594,8 @@ func generateWorkflowTrace(
 
 		// Calculate timing
 		parentSpan := parentInfo.span
-		parentStart := time.Unix(0, int64(parentSpan.StartTimeUnixNano))
-		parentEnd := time.Unix(0, int64(parentSpan.EndTimeUnixNano))
+		parentStart := parentSpan.StartTimestamp().AsTime()
+		parentEnd := parentSpan.EndTimestamp().AsTime()
 		parentDuration := parentEnd.Sub(parentStart)
 
 		delay := time.Duration(rng.Float64() * 0.3 * float64(parentDuration))
@@ -573,7 +619,7 @@ func generateWorkflowTrace(
 
 		childSpan := buildSpanWithContext(
 			traceID,
-			parentSpan.SpanId,
+			parentSpan.SpanID(),
 			spanIndex,
 			parentInfo.depth+1,
 			step.Service,
@@ -586,19 +632,11 @@ func generateWorkflowTrace(
 		)
 
 		// Set span kind based on workflow step
-		switch step.SpanKind {
-		case "client":
-			childSpan.Kind = tracev1.Span_SPAN_KIND_CLIENT
-		case "internal":
-			childSpan.Kind = tracev1.Span_SPAN_KIND_INTERNAL
-		default:
-			childSpan.Kind = tracev1.Span_SPAN_KIND_SERVER
-		}
+		childSpan.SetKind(parseSpanKind(step.SpanKind))
 
 		// Ensure child ends before parent
-		childEnd := time.Unix(0, int64(childSpan.EndTimeUnixNano))
-		if childEnd.After(parentEnd) {
-			childSpan.EndTimeUnixNano = parentSpan.EndTimeUnixNano - uint64(time.Millisecond.Nanoseconds())
+		if childSpan.EndTimestamp() > parentSpan.EndTimestamp() {
+			childSpan.SetEndTimestamp(parentSpan.EndTimestamp() - pcommon.Timestamp(time.Millisecond))
 		}
 
 		childInfo := &spanInfo{
@@ -624,33 +662,50 @@ func generateWorkflowTrace(
 		spanIndex++
 	}
 
-	// Group spans by service
-	serviceSpans := make(map[string][]*tracev1.Span)
-	for idx, info := range spansMap {
+	return serviceTraces(spansMap, spanServices, config, rng)
+}
+
+// serviceTraces propagates errors, breaks the structure of a span tree, injects anomalies and
+// links spans, then returns its spans with one ResourceSpans per service, given the service of
+// each span
+func serviceTraces(spansMap map[int]*spanInfo, spanServices map[int]string, config Config, rng *rand.Rand) ptrace.Traces {
+	traces := ptrace.NewTraces()
+
+	propagateErrors(spansMap, config, rng)
+	breakTraceStructure(spansMap, config, rng)
+	traceSpans := orderedSpans(spansMap)
+	injectTimestampAnomalies(traceSpans, config, rng)
+	addSpanLinks(traceSpans, config, rng)
+	chaosKind := injectChaos(traceSpans, config, rng)
+
+	// Group spans by service, in span order
+	serviceSpans := make(map[string][]ptrace.Span)
+	for _, idx := range slices.Sorted(maps.Keys(spansMap)) {
 		serviceName := spanServices[idx]
-		if serviceSpans[serviceName] == nil {
-			serviceSpans[serviceName] = make([]*tracev1.Span, 0)
-		}
-		serviceSpans[serviceName] = append(serviceSpans[serviceName], info.span)
+		serviceSpans[serviceName] = append(serviceSpans[serviceName], spansMap[idx].span)
 	}
 
-	// Create ResourceSpans for each service
-	for serviceName, spans := range serviceSpans {
+	// Create ResourceSpans for each service, in name order so seeded traces are reproducible
+	for _, serviceName := range slices.Sorted(maps.Keys(serviceSpans)) {
+		spans := serviceSpans[serviceName]
 		rs := traces.ResourceSpans().AppendEmpty()
 		resource := rs.Resource()
 
 		// Set resource attributes for this service
 		resourceAttrs := generateResourceAttributes(serviceName, rng)
 		resourceAttrs["service.name"] = serviceName
-		for key, value := range resourceAttrs {
-			resource.Attributes().PutStr(key, value)
+		putStringAttributes(resource.Attributes(), resourceAttrs)
+		putResourceArrayAttributes(resource.Attributes(), config)
+		if chaosKind != "" {
+			resource.Attributes().PutStr(ChaosAttribute, chaosKind)
 		}
 
-		// Add spans to this service's scope
+		// Move spans into this service's scope
 		scopeSpans := rs.ScopeSpans().AppendEmpty()
-		for _, protoSpan := range spans {
-			span := scopeSpans.Spans().AppendEmpty()
-			spanProtoToPtrace(protoSpan, span)
+		setInstrumentationScope(scopeSpans.Scope(), config.InstrumentationScopes, rng)
+		scopeSpans.Spans().EnsureCapacity(len(spans))
+		for _, span := range spans {
+			span.MoveTo(scopeSpans.Spans().AppendEmpty())
 		}
 	}
 
@@ -0,0 +1,32 @@
+package generator
+
+import "testing"
+
+// benchmarkBaseTimeMs fixes timestamps so seeded benchmark traces are identical across runs
+const benchmarkBaseTimeMs = 1700000000000
+
+func BenchmarkGenerateTrace(b *testing.B) {
+	config := DefaultConfig()
+	config.Seed = 1
+	config.BaseTimeMs = benchmarkBaseTimeMs
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if GenerateTrace(config).SpanCount() == 0 {
+			b.Fatal("generated an empty trace")
+		}
+	}
+}
+
+func BenchmarkGenerateBatch(b *testing.B) {
+	config := BatchConfig{TargetSizeBytes: 1024 * 1024, TraceConfig: DefaultConfig()}
+	config.TraceConfig.Seed = 1
+	config.TraceConfig.BaseTimeMs = benchmarkBaseTimeMs
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if len(GenerateBatch(config)) == 0 {
+			b.Fatal("generated an empty batch")
+		}
+	}
+}
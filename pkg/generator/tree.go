@@ -1,20 +1,27 @@
 package generator
 
 import (
-	cryptoRand "crypto/rand"
+	"fmt"
+	"maps"
 	"math/rand"
+	"slices"
+	"sort"
 	"time"
 
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
-	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
-	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
 )
 
 // DurationConfig configures duration for a node
 type DurationConfig struct {
-	BaseMs     int `js:"baseMs"`
-	VarianceMs int `js:"varianceMs"`
+	BaseMs       int     `js:"baseMs"`
+	VarianceMs   int     `js:"varianceMs"`
+	Distribution string  `js:"distribution"` // "normal", "lognormal", "pareto" or "bimodal" (default: "normal")
+	Sigma        float64 `js:"sigma"`        // Lognormal shape (default: 0.5)
+	Alpha        float64 `js:"alpha"`        // Pareto tail index (default: 1.5)
+	HitRate      float64 `js:"hitRate"`      // Bimodal share of fast (cache hit) spans (default: 0.8)
+	MissMs       int     `js:"missMs"`       // Bimodal mean of slow (cache miss) spans (default: 10x baseMs)
 }
 
 // CountConfig configures repetitions for a child
@@ -25,14 +32,99 @@ type CountConfig struct {
 
 // TraceTreeNode represents a tree node
 type TraceTreeNode struct {
-	Service         string            `js:"service"`
-	Operation       string            `js:"operation"`
-	SpanKind        string            `js:"spanKind"`
-	Tags            map[string]string `js:"tags"`
-	Duration        DurationConfig    `js:"duration"`
-	ErrorRate       float64           `js:"errorRate"`
-	ErrorPropagates bool              `js:"errorPropagates"`
-	Children        []TraceTreeEdge   `js:"children"`
+	Ref             string                  `js:"ref"` // Name of a subtree in TraceTreeConfig.Subtrees this node stands for; other fields are ignored
+	Service         string                  `js:"service"`
+	Operation       string                  `js:"operation"`
+	SpanKind        string                  `js:"spanKind"`
+	Tags            map[string]string       `js:"tags"`
+	TagValues       map[string]TagValueSpec `js:"tagValues"`      // Tags taking a generated value per span
+	AttributePools  map[string][]string     `js:"attributePools"` // Attributes taking a random value of their pool per span
+	Duration        DurationConfig          `js:"duration"`
+	ErrorRate       float64                 `js:"errorRate"`
+	ErrorPropagates bool                    `js:"errorPropagates"`
+	Events          []TraceTreeEvent        `js:"events"` // Events of every span of the node
+	Links           []TraceTreeLink         `js:"links"`  // Links of every span of the node
+	Children        []TraceTreeEdge         `js:"children"`
+}
+
+// TraceTreeEvent is an event (log) of the spans of a node
+type TraceTreeEvent struct {
+	Name      string            `js:"name"`
+	Attrs     map[string]string `js:"attrs"`
+	OffsetPct float64           `js:"offsetPct"` // Time of the event as a percentage of the span duration (default: 0, range: 0-100)
+}
+
+// TraceTreeLink is a link of the spans of a node
+type TraceTreeLink struct {
+	Operation string            `js:"operation"` // Links to an earlier span of the trace with this operation (default: "", a span of a previous trace)
+	Attrs     map[string]string `js:"attrs"`
+}
+
+// TagValueSpec generates the value of a tree node tag per span: one of oneOf, by weight, or
+// otherwise a value of a cardinality pool
+type TagValueSpec struct {
+	Pool        string    `js:"pool"`        // Cardinality pool, e.g. "customer_id" (default: the tag name)
+	Cardinality int       `js:"cardinality"` // Distinct values (default: context.cardinality of the pool, or its built-in cardinality)
+	OneOf       []string  `js:"oneOf"`       // Values to pick from instead of a pool
+	Weights     []float64 `js:"weights"`     // Weights of the oneOf values (default: equal)
+}
+
+// validate checks that weights match the values and cardinality is not negative
+func (s TagValueSpec) validate() error {
+	if s.Cardinality < 0 {
+		return fmt.Errorf("cardinality must be >= 0, got %d", s.Cardinality)
+	}
+	if len(s.Weights) == 0 {
+		return nil
+	}
+	if len(s.Weights) != len(s.OneOf) {
+		return fmt.Errorf("weights must have one weight per oneOf value, got %d weights for %d values", len(s.Weights), len(s.OneOf))
+	}
+	total := 0.0
+	for _, weight := range s.Weights {
+		if weight < 0 {
+			return fmt.Errorf("weights must be >= 0, got %f", weight)
+		}
+		total += weight
+	}
+	if total <= 0 {
+		return fmt.Errorf("weights must not all be 0")
+	}
+	return nil
+}
+
+// value generates a value of tag key, taking pool cardinality and skew from ctx
+func (s TagValueSpec) value(key string, ctx TreeContext, rng *rand.Rand) string {
+	if len(s.OneOf) > 0 {
+		if len(s.Weights) != len(s.OneOf) {
+			return s.OneOf[rng.Intn(len(s.OneOf))]
+		}
+		total := 0.0
+		for _, weight := range s.Weights {
+			total += weight
+		}
+		r := rng.Float64() * total
+		for i, weight := range s.Weights {
+			r -= weight
+			if r < 0 {
+				return s.OneOf[i]
+			}
+		}
+		return s.OneOf[len(s.OneOf)-1]
+	}
+
+	pool := s.Pool
+	if pool == "" {
+		pool = key
+	}
+	size := s.Cardinality
+	if size == 0 {
+		var ok bool
+		if size, ok = ctx.Cardinality[pool]; !ok {
+			size = DefaultCardinality(pool)
+		}
+	}
+	return GetCardinalityManager().GetPoolValue(pool, size, ctx.Skew[pool], rng)
 }
 
 // TraceTreeEdge represents an edge with weight and configuration
@@ -45,8 +137,9 @@ type TraceTreeEdge struct {
 
 // TreeContext holds context propagated through the trace
 type TreeContext struct {
-	Propagate   []string       `js:"propagate"`
-	Cardinality map[string]int `js:"cardinality"`
+	Propagate   []string           `js:"propagate"`
+	Cardinality map[string]int     `js:"cardinality"`
+	Skew        map[string]float64 `js:"skew"` // Zipf exponent per attribute, see Config.CardinalitySkew
 }
 
 // TreeDefaults holds default configuration settings
@@ -58,10 +151,81 @@ type TreeDefaults struct {
 
 // TraceTreeConfig holds complete tree configuration
 type TraceTreeConfig struct {
-	Seed     int64          `js:"seed"` // Seed for reproducibility (0 = random)
-	Context  TreeContext    `js:"context"`
-	Defaults TreeDefaults   `js:"defaults"`
-	Root     *TraceTreeNode `js:"root"`
+	Seed        int64                     `js:"seed"` // Seed for reproducibility (0 = random)
+	Context     TreeContext               `js:"context"`
+	Defaults    TreeDefaults              `js:"defaults"`
+	Root        *TraceTreeNode            `js:"root"`
+	Subtrees    map[string]*TraceTreeNode `js:"subtrees"`    // Named subtrees referenced by nodes with ref
+	MaxRefDepth int                       `js:"maxRefDepth"` // Max expansions of a subtree on a path from the root (default: 3)
+}
+
+// defaultMaxRefDepth bounds subtree recursion when TraceTreeConfig.MaxRefDepth is not set
+const defaultMaxRefDepth = 3
+
+// Validate checks that every ref names a subtree
+func (c *TraceTreeConfig) Validate() error {
+	if c.MaxRefDepth < 0 {
+		return fmt.Errorf("maxRefDepth must be >= 0, got %d", c.MaxRefDepth)
+	}
+	if err := c.validateNode(c.Root); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Subtrees)) {
+		if c.Subtrees[name] == nil {
+			return fmt.Errorf("subtree %q is empty", name)
+		}
+		if err := c.validateNode(c.Subtrees[name]); err != nil {
+			return fmt.Errorf("subtree %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateNode checks the refs and tag values of node and its descendants
+func (c *TraceTreeConfig) validateNode(node *TraceTreeNode) error {
+	if node == nil {
+		return nil
+	}
+	if node.Ref != "" {
+		if _, ok := c.Subtrees[node.Ref]; !ok {
+			return fmt.Errorf("unknown subtree %q", node.Ref)
+		}
+		return nil
+	}
+	for key, spec := range node.TagValues {
+		if err := spec.validate(); err != nil {
+			return fmt.Errorf("tagValues[%s]: %w", key, err)
+		}
+	}
+	for _, edge := range node.Children {
+		if err := c.validateNode(edge.Node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandRef resolves a node standing for a subtree, also through subtrees that are refs
+// themselves. path counts the expansions of each subtree from the root to the node; the subtree
+// is nil once it was expanded maxRefDepth times on the path, or when it is unknown.
+func (c *TraceTreeConfig) expandRef(node *TraceTreeNode, path map[string]int) (*TraceTreeNode, map[string]int) {
+	if node == nil || node.Ref == "" {
+		return node, path
+	}
+
+	maxDepth := c.MaxRefDepth
+	if maxDepth == 0 {
+		maxDepth = defaultMaxRefDepth
+	}
+	if path[node.Ref] >= maxDepth {
+		return nil, path
+	}
+
+	// Copy the counts, so sibling subtrees do not see each other's expansions
+	next := make(map[string]int, len(path)+1)
+	maps.Copy(next, path)
+	next[node.Ref]++
+	return c.expandRef(c.Subtrees[node.Ref], next)
 }
 
 // NormalizeWeights normalizes edge weights to sum to 1
@@ -147,17 +311,12 @@ func GenerateTraceFromTree(config TraceTreeConfig) ptrace.Traces {
 	// Create trace context
 	traceCtx := NewTreeTraceContext(config.Context, rng)
 
-	// Generate trace ID (use RNG for reproducibility if seed provided)
-	traceID := make([]byte, 16)
+	// Generate trace ID (use RNG for reproducibility if seed provided, random otherwise)
+	var idRNG *rand.Rand
 	if config.Seed != 0 {
-		// Use RNG for reproducibility
-		for i := 0; i < 16; i++ {
-			traceID[i] = byte(rng.Intn(256))
-		}
-	} else {
-		// Random if no seed
-		cryptoRand.Read(traceID)
+		idRNG = rng
 	}
+	traceID := generateTraceID(idRNG)
 
 	// Create traces structure
 	traces := ptrace.NewTraces()
@@ -166,9 +325,11 @@ func GenerateTraceFromTree(config TraceTreeConfig) ptrace.Traces {
 	traceStartTime := time.Now().Add(-time.Duration(rng.Intn(3600)) * time.Second)
 
 	// Generate spans from tree
-	spansByService := make(map[string][]*tracev1.Span)
+	spansByService := make(map[string][]ptrace.Span)
+	root, refPath := config.expandRef(config.Root, nil)
 	generateSpansFromNode(
-		config.Root,
+		root,
+		refPath,
 		nil, // no parent
 		traceID,
 		traceStartTime,
@@ -186,34 +347,34 @@ func GenerateTraceFromTree(config TraceTreeConfig) ptrace.Traces {
 		// Resource attributes for the service
 		resourceAttrs := generateResourceAttributes(serviceName, rng)
 		resourceAttrs["service.name"] = serviceName
-		for key, value := range resourceAttrs {
-			resource.Attributes().PutStr(key, value)
-		}
+		putStringAttributes(resource.Attributes(), resourceAttrs)
 
-		// Add spans to scope
+		// Move spans into the scope
 		scopeSpans := rs.ScopeSpans().AppendEmpty()
-		for _, protoSpan := range spans {
-			span := scopeSpans.Spans().AppendEmpty()
-			spanProtoToPtrace(protoSpan, span)
+		setInstrumentationScope(scopeSpans.Scope(), nil, rng)
+		scopeSpans.Spans().EnsureCapacity(len(spans))
+		for _, span := range spans {
+			span.MoveTo(scopeSpans.Spans().AppendEmpty())
 		}
 	}
 
 	return traces
 }
 
-// generateSpansFromNode recursively generates spans from a node
+// generateSpansFromNode recursively generates spans from a node; ok is false when node is nil
 func generateSpansFromNode(
 	node *TraceTreeNode,
-	parentSpan *tracev1.Span,
-	traceID []byte,
+	refPath map[string]int,
+	parentSpan *ptrace.Span,
+	traceID pcommon.TraceID,
 	parentStartTime time.Time,
 	rng *rand.Rand,
 	config TraceTreeConfig,
 	traceCtx *TreeTraceContext,
-	spansByService map[string][]*tracev1.Span,
-) *tracev1.Span {
+	spansByService map[string][]ptrace.Span,
+) (span ptrace.Span, ok bool) {
 	if node == nil {
-		return nil
+		return ptrace.Span{}, false
 	}
 
 	// Calculate duration
@@ -226,8 +387,8 @@ func generateSpansFromNode(
 		startTime = parentStartTime
 	} else {
 		// Child: must start after parent and end before parent
-		parentStart := time.Unix(0, int64(parentSpan.StartTimeUnixNano))
-		parentEnd := time.Unix(0, int64(parentSpan.EndTimeUnixNano))
+		parentStart := parentSpan.StartTimestamp().AsTime()
+		parentEnd := parentSpan.EndTimestamp().AsTime()
 		parentDuration := parentEnd.Sub(parentStart)
 
 		// Random delay within parent (up to 30% of parent time)
@@ -250,80 +411,71 @@ func generateSpansFromNode(
 	spanKind := parseSpanKind(node.SpanKind)
 
 	// Determine if there's an error
-	hasError := rng.Float64() < node.ErrorRate
-	status := &tracev1.Status{
-		Code: tracev1.Status_STATUS_CODE_OK,
-	}
-	if hasError {
-		status.Code = tracev1.Status_STATUS_CODE_ERROR
-		status.Message = getRandomErrorMessage(rng)
+	statusCode, statusMessage := ptrace.StatusCodeOk, ""
+	if rng.Float64() < node.ErrorRate {
+		statusCode, statusMessage = ptrace.StatusCodeError, getRandomErrorMessage(rng)
 	}
 
 	// Create span ID (use RNG for reproducibility)
-	spanID := make([]byte, 8)
-	for i := 0; i < 8; i++ {
-		spanID[i] = byte(rng.Intn(256))
-	}
+	var spanID pcommon.SpanID
+	fillRandom(spanID[:], rng)
 
-	var parentSpanID []byte
+	span = ptrace.NewSpan()
+	span.SetTraceID(traceID)
+	span.SetSpanID(spanID)
 	if parentSpan != nil {
-		parentSpanID = parentSpan.SpanId
-	}
-
-	span := &tracev1.Span{
-		TraceId:           traceID,
-		SpanId:            spanID,
-		ParentSpanId:      parentSpanID,
-		Name:              node.Operation,
-		Kind:              spanKind,
-		StartTimeUnixNano: uint64(startTime.UnixNano()),
-		EndTimeUnixNano:   uint64(endTime.UnixNano()),
-		Status:            status,
+		span.SetParentSpanID(parentSpan.SpanID())
 	}
+	span.SetName(node.Operation)
+	span.SetKind(spanKind)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(endTime))
+	span.Status().SetCode(statusCode)
+	span.Status().SetMessage(statusMessage)
 
 	// Add attributes
-	attrs := make([]*commonv1.KeyValue, 0)
+	attrs := span.Attributes()
 
 	// Service name
-	attrs = append(attrs, &commonv1.KeyValue{
-		Key: "service.name",
-		Value: &commonv1.AnyValue{
-			Value: &commonv1.AnyValue_StringValue{
-				StringValue: node.Service,
-			},
-		},
-	})
+	attrs.PutStr("service.name", node.Service)
 
 	// Node tags
 	for key, value := range node.Tags {
-		attrs = append(attrs, &commonv1.KeyValue{
-			Key: key,
-			Value: &commonv1.AnyValue{
-				Value: &commonv1.AnyValue_StringValue{
-					StringValue: value,
-				},
-			},
-		})
+		putNonEmptyStr(attrs, key, value)
+	}
+
+	// Generated tag values, in key order so seeded trees stay reproducible
+	for _, key := range slices.Sorted(maps.Keys(node.TagValues)) {
+		putNonEmptyStr(attrs, key, node.TagValues[key].value(key, config.Context, rng))
+	}
+
+	// Pooled attributes, in key order so seeded trees stay reproducible
+	poolKeys := make([]string, 0, len(node.AttributePools))
+	for key := range node.AttributePools {
+		poolKeys = append(poolKeys, key)
+	}
+	sort.Strings(poolKeys)
+	for _, key := range poolKeys {
+		if pool := node.AttributePools[key]; len(pool) > 0 {
+			putNonEmptyStr(attrs, key, pool[rng.Intn(len(pool))])
+		}
 	}
 
 	// Semantic attributes if enabled
 	if config.Defaults.UseSemanticAttributes {
-		semanticAttrs := generateSemanticAttributes(spanKind, node.Service, rng)
-		attrs = append(attrs, semanticAttrs...)
+		generateSemanticAttributes(attrs, spanKind, node.Service, nil, nil, rng)
 	}
 
 	// Infrastructure tags if enabled
 	if config.Defaults.EnableTags {
-		tagAttrs := traceCtx.GetPropagatedTags(config.Defaults.TagDensity, rng)
-		attrs = append(attrs, tagAttrs...)
+		traceCtx.PutPropagatedTags(attrs, config.Defaults.TagDensity, rng)
 	}
 
-	span.Attributes = attrs
+	// Events and links, before the span is added so it never links to itself
+	addTreeNodeEvents(span.Events(), node.Events, startTime, endTime)
+	addTreeNodeLinks(span.Links(), node.Links, spansByService, rng)
 
 	// Add span to service collection
-	if spansByService[node.Service] == nil {
-		spansByService[node.Service] = make([]*tracev1.Span, 0)
-	}
 	spansByService[node.Service] = append(spansByService[node.Service], span)
 
 	// Process children
@@ -341,9 +493,11 @@ func generateSpansFromNode(
 		// Process sequential first
 		currentTime := startTime
 		for _, childEdge := range sequential {
-			childSpan := generateSpansFromNode(
-				childEdge.Node,
-				span,
+			child, childRefPath := config.expandRef(childEdge.Node, refPath)
+			childSpan, ok := generateSpansFromNode(
+				child,
+				childRefPath,
+				&span,
 				traceID,
 				currentTime,
 				rng,
@@ -351,9 +505,9 @@ func generateSpansFromNode(
 				traceCtx,
 				spansByService,
 			)
-			if childSpan != nil {
+			if ok {
 				// Update time for next sequential child
-				childEnd := time.Unix(0, int64(childSpan.EndTimeUnixNano))
+				childEnd := childSpan.EndTimestamp().AsTime()
 				if childEnd.After(currentTime) {
 					currentTime = childEnd
 				}
@@ -368,9 +522,11 @@ func generateSpansFromNode(
 				delay := time.Duration(rng.Float64() * 0.2 * float64(availableTime))
 				parallelStart := currentTime.Add(delay)
 
-				childSpan := generateSpansFromNode(
-					childEdge.Node,
-					span,
+				child, childRefPath := config.expandRef(childEdge.Node, refPath)
+				childSpan, ok := generateSpansFromNode(
+					child,
+					childRefPath,
+					&span,
 					traceID,
 					parallelStart,
 					rng,
@@ -380,54 +536,87 @@ func generateSpansFromNode(
 				)
 
 				// If child fails and errorPropagates is active, mark parent as error
-				if childSpan != nil && childSpan.Status != nil &&
-					childSpan.Status.Code == tracev1.Status_STATUS_CODE_ERROR &&
-					childEdge.Node.ErrorPropagates {
-					span.Status.Code = tracev1.Status_STATUS_CODE_ERROR
-					if span.Status.Message == "" {
-						span.Status.Message = "child span failed"
+				if ok && childSpan.Status().Code() == ptrace.StatusCodeError && child.ErrorPropagates {
+					span.Status().SetCode(ptrace.StatusCodeError)
+					if span.Status().Message() == "" {
+						span.Status().SetMessage(propagatedErrorMessage)
 					}
 				}
 			}
 		}
 	}
 
-	return span
+	return span, true
 }
 
-// calculateDurationFromConfig calculates duration from configuration
-func calculateDurationFromConfig(dur DurationConfig, rng *rand.Rand) time.Duration {
-	base := float64(dur.BaseMs)
-	if base <= 0 {
-		base = 50 // default
+// addTreeNodeEvents adds the events of a span from start to end
+func addTreeNodeEvents(events ptrace.SpanEventSlice, defs []TraceTreeEvent, start, end time.Time) {
+	if len(defs) == 0 {
+		return
 	}
-	variance := float64(dur.VarianceMs)
-	if variance < 0 {
-		variance = 30 // default
+	events.EnsureCapacity(len(defs))
+	for _, def := range defs {
+		offset := time.Duration(def.OffsetPct / 100 * float64(end.Sub(start)))
+		event := events.AppendEmpty()
+		event.SetTimestamp(pcommon.NewTimestampFromTime(start.Add(offset)))
+		event.SetName(def.Name)
+		putStringAttributes(event.Attributes(), def.Attrs)
 	}
+}
 
-	duration := base + rng.NormFloat64()*variance
-	if duration < 1 {
-		duration = 1
+// addTreeNodeLinks adds the links of a span. Links with an operation point to a random earlier
+// span of the trace with that operation and are dropped when there is none; other links point to
+// a span of a previously generated trace, or of an unknown trace when none was generated yet.
+func addTreeNodeLinks(links ptrace.SpanLinkSlice, defs []TraceTreeLink, spansByService map[string][]ptrace.Span, rng *rand.Rand) {
+	if len(defs) == 0 {
+		return
+	}
+	links.EnsureCapacity(len(defs))
+	for _, def := range defs {
+		var target linkTarget
+		if def.Operation != "" {
+			// Services in order so seeded trees stay reproducible
+			var candidates []ptrace.Span
+			for _, service := range slices.Sorted(maps.Keys(spansByService)) {
+				for _, span := range spansByService[service] {
+					if span.Name() == def.Operation {
+						candidates = append(candidates, span)
+					}
+				}
+			}
+			if len(candidates) == 0 {
+				continue
+			}
+			other := candidates[rng.Intn(len(candidates))]
+			target = linkTarget{traceID: other.TraceID(), spanID: other.SpanID()}
+		} else {
+			var ok bool
+			if target, ok = linkTargets.random(rng); !ok {
+				target = linkTarget{traceID: generateTraceID(rng), spanID: generateSpanID(rng)}
+			}
+		}
+		link := links.AppendEmpty()
+		link.SetTraceID(target.traceID)
+		link.SetSpanID(target.spanID)
+		putStringAttributes(link.Attributes(), def.Attrs)
 	}
-	return time.Duration(duration) * time.Millisecond
 }
 
 // parseSpanKind converts string to SpanKind
-func parseSpanKind(kindStr string) tracev1.Span_SpanKind {
+func parseSpanKind(kindStr string) ptrace.SpanKind {
 	switch kindStr {
 	case "server":
-		return tracev1.Span_SPAN_KIND_SERVER
+		return ptrace.SpanKindServer
 	case "client":
-		return tracev1.Span_SPAN_KIND_CLIENT
+		return ptrace.SpanKindClient
 	case "internal":
-		return tracev1.Span_SPAN_KIND_INTERNAL
+		return ptrace.SpanKindInternal
 	case "producer":
-		return tracev1.Span_SPAN_KIND_PRODUCER
+		return ptrace.SpanKindProducer
 	case "consumer":
-		return tracev1.Span_SPAN_KIND_CONSUMER
+		return ptrace.SpanKindConsumer
 	default:
-		return tracev1.Span_SPAN_KIND_SERVER
+		return ptrace.SpanKindServer
 	}
 }
 
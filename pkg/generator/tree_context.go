@@ -3,7 +3,7 @@ package generator
 import (
 	"math/rand"
 
-	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 )
 
 // TreeTraceContext maintains consistent IDs during trace generation
@@ -39,55 +39,53 @@ func NewTreeTraceContext(config TreeContext, rng *rand.Rand) *TreeTraceContext {
 	for _, propKey := range config.Propagate {
 		switch propKey {
 		case "user_id":
-			ctx.UserID = cm.GetValue("customer_id", rng, config.Cardinality)
+			ctx.UserID = cm.GetValue("customer_id", rng, config.Cardinality, config.Skew)
 		case "order_id":
-			ctx.OrderID = cm.GetValue("order_id", rng, config.Cardinality)
+			ctx.OrderID = cm.GetValue("order_id", rng, config.Cardinality, config.Skew)
 		case "correlation_id":
-			ctx.CorrelationID = cm.GetValue("correlation_id", rng, config.Cardinality)
+			ctx.CorrelationID = cm.GetValue("correlation_id", rng, config.Cardinality, config.Skew)
 		case "session_id":
-			ctx.SessionID = cm.GetValue("session_id", rng, config.Cardinality)
+			ctx.SessionID = cm.GetValue("session_id", rng, config.Cardinality, config.Skew)
 		case "tenant_id":
-			ctx.TenantID = cm.GetValue("tenant_id", rng, config.Cardinality)
+			ctx.TenantID = cm.GetValue("tenant_id", rng, config.Cardinality, config.Skew)
 		case "region":
-			ctx.Region = cm.GetValue("region", rng, config.Cardinality)
+			ctx.Region = cm.GetValue("region", rng, config.Cardinality, config.Skew)
 		case "datacenter":
-			ctx.Datacenter = cm.GetValue("datacenter", rng, config.Cardinality)
+			ctx.Datacenter = cm.GetValue("datacenter", rng, config.Cardinality, config.Skew)
 		case "availability_zone":
-			ctx.AvailabilityZone = cm.GetValue("availability_zone", rng, config.Cardinality)
+			ctx.AvailabilityZone = cm.GetValue("availability_zone", rng, config.Cardinality, config.Skew)
 		case "cluster":
-			ctx.Cluster = cm.GetValue("cluster", rng, config.Cardinality)
+			ctx.Cluster = cm.GetValue("cluster", rng, config.Cardinality, config.Skew)
 		case "org_id":
-			ctx.OrgID = cm.GetValue("org_id", rng, config.Cardinality)
+			ctx.OrgID = cm.GetValue("org_id", rng, config.Cardinality, config.Skew)
 		case "customer_id":
-			ctx.CustomerID = cm.GetValue("customer_id", rng, config.Cardinality)
+			ctx.CustomerID = cm.GetValue("customer_id", rng, config.Cardinality, config.Skew)
 		case "version":
-			ctx.Version = cm.GetValue("version", rng, config.Cardinality)
+			ctx.Version = cm.GetValue("version", rng, config.Cardinality, config.Skew)
 		case "git_commit":
-			ctx.GitCommit = cm.GetValue("git_commit", rng, config.Cardinality)
+			ctx.GitCommit = cm.GetValue("git_commit", rng, config.Cardinality, config.Skew)
 		case "canary":
-			ctx.Canary = cm.GetValue("canary", rng, config.Cardinality)
+			ctx.Canary = cm.GetValue("canary", rng, config.Cardinality, config.Skew)
 		case "user_tier":
-			ctx.UserTier = cm.GetValue("user_tier", rng, config.Cardinality)
+			ctx.UserTier = cm.GetValue("user_tier", rng, config.Cardinality, config.Skew)
 		case "priority":
-			ctx.Priority = cm.GetValue("priority", rng, config.Cardinality)
+			ctx.Priority = cm.GetValue("priority", rng, config.Cardinality, config.Skew)
 		case "request_id":
-			ctx.RequestID = cm.GetValue("request_id", rng, config.Cardinality)
+			ctx.RequestID = cm.GetValue("request_id", rng, config.Cardinality, config.Skew)
 		case "payment_id":
-			ctx.PaymentID = cm.GetValue("payment_id", rng, config.Cardinality)
+			ctx.PaymentID = cm.GetValue("payment_id", rng, config.Cardinality, config.Skew)
 		case "shipment_id":
-			ctx.ShipmentID = cm.GetValue("shipment_id", rng, config.Cardinality)
+			ctx.ShipmentID = cm.GetValue("shipment_id", rng, config.Cardinality, config.Skew)
 		case "product_id":
-			ctx.ProductID = cm.GetValue("product_id", rng, config.Cardinality)
+			ctx.ProductID = cm.GetValue("product_id", rng, config.Cardinality, config.Skew)
 		}
 	}
 
 	return ctx
 }
 
-// GetPropagatedTags returns propagated tags as attributes
-func (ctx *TreeTraceContext) GetPropagatedTags(tagDensity float64, rng *rand.Rand) []*commonv1.KeyValue {
-	tags := make([]*commonv1.KeyValue, 0)
-
+// PutPropagatedTags sets the propagated tags as attributes in attrs
+func (ctx *TreeTraceContext) PutPropagatedTags(attrs pcommon.Map, tagDensity float64, rng *rand.Rand) {
 	if tagDensity <= 0 {
 		tagDensity = 0.9
 	}
@@ -97,88 +95,86 @@ func (ctx *TreeTraceContext) GetPropagatedTags(tagDensity float64, rng *rand.Ran
 
 	// Infrastructure tags
 	if ctx.Region != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("infrastructure.region", ctx.Region))
+		attrs.PutStr("infrastructure.region", ctx.Region)
 	}
 
 	if ctx.Datacenter != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("infrastructure.datacenter", ctx.Datacenter))
+		attrs.PutStr("infrastructure.datacenter", ctx.Datacenter)
 	}
 
 	if ctx.AvailabilityZone != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("infrastructure.availability_zone", ctx.AvailabilityZone))
+		attrs.PutStr("infrastructure.availability_zone", ctx.AvailabilityZone)
 	}
 
 	if ctx.Cluster != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("infrastructure.cluster", ctx.Cluster))
+		attrs.PutStr("infrastructure.cluster", ctx.Cluster)
 	}
 
 	// Tenant tags
 	if ctx.TenantID != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("tenant.id", ctx.TenantID))
+		attrs.PutStr("tenant.id", ctx.TenantID)
 	}
 
 	if ctx.CustomerID != "" && rng.Float64() < tagDensity*DensityMediumHigh {
-		tags = append(tags, newStringKeyValue("tenant.customer_id", ctx.CustomerID))
+		attrs.PutStr("tenant.customer_id", ctx.CustomerID)
 	}
 
 	if ctx.OrgID != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("tenant.org_id", ctx.OrgID))
+		attrs.PutStr("tenant.org_id", ctx.OrgID)
 	}
 
 	// Deployment tags
 	if ctx.Version != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("deployment.version", ctx.Version))
+		attrs.PutStr("deployment.version", ctx.Version)
 	}
 
 	if ctx.GitCommit != "" && rng.Float64() < tagDensity*DensityHigh {
-		tags = append(tags, newStringKeyValue("deployment.git_commit", ctx.GitCommit))
+		attrs.PutStr("deployment.git_commit", ctx.GitCommit)
 	}
 
 	if ctx.Canary != "" && rng.Float64() < tagDensity*DensityVeryLow {
-		tags = append(tags, newStringKeyValue("deployment.canary", ctx.Canary))
+		attrs.PutStr("deployment.canary", ctx.Canary)
 	}
 
 	// Request context tags
 	if ctx.RequestID != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("request.id", ctx.RequestID))
+		attrs.PutStr("request.id", ctx.RequestID)
 	}
 
 	if ctx.CorrelationID != "" && rng.Float64() < tagDensity*DensityHigh {
-		tags = append(tags, newStringKeyValue("request.correlation_id", ctx.CorrelationID))
+		attrs.PutStr("request.correlation_id", ctx.CorrelationID)
 	}
 
 	if ctx.UserTier != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("request.user_tier", ctx.UserTier))
+		attrs.PutStr("request.user_tier", ctx.UserTier)
 	}
 
 	if ctx.Priority != "" && rng.Float64() < tagDensity*DensityMediumLow {
-		tags = append(tags, newStringKeyValue("request.priority", ctx.Priority))
+		attrs.PutStr("request.priority", ctx.Priority)
 	}
 
 	// Business context tags
 	if ctx.UserID != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("user.id", ctx.UserID))
+		attrs.PutStr("user.id", ctx.UserID)
 	}
 
 	if ctx.OrderID != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("order.id", ctx.OrderID))
+		attrs.PutStr("order.id", ctx.OrderID)
 	}
 
 	if ctx.SessionID != "" && rng.Float64() < tagDensity*DensityHigh {
-		tags = append(tags, newStringKeyValue("session.id", ctx.SessionID))
+		attrs.PutStr("session.id", ctx.SessionID)
 	}
 
 	if ctx.PaymentID != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("payment.id", ctx.PaymentID))
+		attrs.PutStr("payment.id", ctx.PaymentID)
 	}
 
 	if ctx.ShipmentID != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("shipment.id", ctx.ShipmentID))
+		attrs.PutStr("shipment.id", ctx.ShipmentID)
 	}
 
 	if ctx.ProductID != "" && rng.Float64() < tagDensity {
-		tags = append(tags, newStringKeyValue("product.id", ctx.ProductID))
+		attrs.PutStr("product.id", ctx.ProductID)
 	}
-
-	return tags
 }
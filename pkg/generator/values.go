@@ -0,0 +1,275 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// ValueOptions controls realistic attribute values
+type ValueOptions struct {
+	SQLLength int // Min length of SQL statements; 0 keeps their natural length
+}
+
+// valueOptions returns the realistic value settings of a generator config, or nil when
+// realistic values are disabled
+func (c Config) valueOptions() *ValueOptions {
+	if !c.RealisticValues {
+		return nil
+	}
+	return &ValueOptions{SQLLength: c.SQLStatementLength}
+}
+
+// Filler attribute value modes
+const (
+	ValueModeRandom = "random" // Random hex, which compresses poorly
+	ValueModeText   = "text"   // Templated natural text, which compresses well
+	ValueModeMixed  = "mixed"  // Text with probability TextValueRatio, random hex otherwise
+)
+
+// defaultTextValueRatio is the default share of text filler values in mixed mode
+const defaultTextValueRatio = 0.5
+
+// textValueRatio returns the probability a filler value of a generator config is text
+func (c Config) textValueRatio() float64 {
+	switch c.AttributeValueMode {
+	case ValueModeText:
+		return 1
+	case ValueModeMixed:
+		return c.TextValueRatio
+	default:
+		return 0
+	}
+}
+
+// Sentence templates of text filler values; %s are filled with words of textWords
+var (
+	textTemplates = []string{
+		"request %s completed for %s in %s with status %s",
+		"user %s updated the %s settings of %s",
+		"retrying %s after %s timeout on %s",
+		"cache %s for %s key in %s",
+		"processed %s items of %s batch from %s queue",
+		"connection to %s %s established after %s attempts",
+	}
+	textWords = []string{
+		"checkout", "order", "payment", "inventory", "account", "session", "profile", "catalog",
+		"primary", "replica", "upstream", "downstream", "default", "pending", "ok", "failed",
+	}
+)
+
+// generateTextValue returns templated natural text of exactly length bytes. Its small vocabulary
+// makes it compress about as well as real log lines and messages do.
+func generateTextValue(length int, rng *rand.Rand) string {
+	var sb strings.Builder
+	sb.Grow(length + 64)
+	for sb.Len() < length {
+		if sb.Len() > 0 {
+			sb.WriteString(". ")
+		}
+		template := textTemplates[rng.Intn(len(textTemplates))]
+		words := make([]interface{}, strings.Count(template, "%s"))
+		for i := range words {
+			words[i] = textWords[rng.Intn(len(textWords))]
+		}
+		fmt.Fprintf(&sb, template, words...)
+	}
+	return sb.String()[:length]
+}
+
+// realisticValueKeys maps attribute keys to the generator of their realistic values
+var realisticValueKeys = map[string]func(rng *rand.Rand, opts ValueOptions) string{
+	"http.url":             func(rng *rand.Rand, _ ValueOptions) string { return FakeURL("", rng) },
+	"url.full":             func(rng *rand.Rand, _ ValueOptions) string { return FakeURL("", rng) },
+	"http.target":          func(rng *rand.Rand, _ ValueOptions) string { return FakeURLPath(rng) },
+	"url.path":             func(rng *rand.Rand, _ ValueOptions) string { return FakeURLPath(rng) },
+	"client.address":       func(rng *rand.Rand, _ ValueOptions) string { return FakeIP(rng) },
+	"http.client_ip":       func(rng *rand.Rand, _ ValueOptions) string { return FakeIP(rng) },
+	"net.peer.ip":          func(rng *rand.Rand, _ ValueOptions) string { return FakeIP(rng) },
+	"network.peer.address": func(rng *rand.Rand, _ ValueOptions) string { return FakeIP(rng) },
+	"user_agent.original":  func(rng *rand.Rand, _ ValueOptions) string { return FakeUserAgent(rng) },
+	"http.user_agent":      func(rng *rand.Rand, _ ValueOptions) string { return FakeUserAgent(rng) },
+	"user.email":           func(rng *rand.Rand, _ ValueOptions) string { return FakeEmail(rng) },
+	"enduser.email":        func(rng *rand.Rand, _ ValueOptions) string { return FakeEmail(rng) },
+	"db.statement":         func(rng *rand.Rand, opts ValueOptions) string { return FakeSQL(opts.SQLLength, rng) },
+	"db.query.text":        func(rng *rand.Rand, opts ValueOptions) string { return FakeSQL(opts.SQLLength, rng) },
+}
+
+// RealisticValue returns a realistic value for a common attribute key, such as http.url,
+// client.address, user_agent.original, user.email or db.statement. ok is false for other keys.
+func RealisticValue(key string, rng *rand.Rand, opts ValueOptions) (value string, ok bool) {
+	generate, ok := realisticValueKeys[key]
+	if !ok {
+		return "", false
+	}
+	return generate(rng, opts), true
+}
+
+// URL parts
+var (
+	urlHosts = []string{"api.shop.example.com", "www.shop.example.com", "checkout.example.com", "m.example.com", "partner-api.example.net"}
+	urlPaths = []string{
+		"/api/v1/users/{id}",
+		"/api/v1/users/{id}/orders",
+		"/api/v1/orders/{id}",
+		"/api/v1/orders/{id}/items/{id}",
+		"/api/v2/products/{sku}",
+		"/api/v2/products/{sku}/reviews",
+		"/api/v1/carts/{uuid}",
+		"/api/v1/search",
+		"/api/v1/payments/{uuid}/capture",
+		"/static/js/app.{hash}.js",
+		"/health",
+	}
+	urlQueryWords = []string{"shoes", "laptop", "coffee", "headphones", "jacket", "desk", "lamp", "backpack"}
+)
+
+// FakeURL returns an https URL with path parameters and, sometimes, a query string; host defaults
+// to a random shop host
+func FakeURL(host string, rng *rand.Rand) string {
+	if host == "" {
+		host = urlHosts[rng.Intn(len(urlHosts))]
+	}
+	return "https://" + host + FakeURLPath(rng)
+}
+
+// FakeURLPath returns a URL path with filled-in parameters and, sometimes, a query string
+func FakeURLPath(rng *rand.Rand) string {
+	path := urlPaths[rng.Intn(len(urlPaths))]
+	for strings.Contains(path, "{") {
+		switch {
+		case strings.Contains(path, "{id}"):
+			path = strings.Replace(path, "{id}", fmt.Sprintf("%d", 1000+rng.Intn(9000000)), 1)
+		case strings.Contains(path, "{sku}"):
+			path = strings.Replace(path, "{sku}", fmt.Sprintf("SKU-%c%c%05d", 'A'+rune(rng.Intn(26)), 'A'+rune(rng.Intn(26)), rng.Intn(100000)), 1)
+		case strings.Contains(path, "{uuid}"):
+			path = strings.Replace(path, "{uuid}", fakeUUID(rng), 1)
+		case strings.Contains(path, "{hash}"):
+			path = strings.Replace(path, "{hash}", randomHexString(8, rng), 1)
+		}
+	}
+
+	if strings.HasSuffix(path, "/search") || rng.Float64() < DensityVeryLow {
+		path += fmt.Sprintf("?q=%s&page=%d&limit=%d", urlQueryWords[rng.Intn(len(urlQueryWords))], 1+rng.Intn(20), []int{10, 20, 50}[rng.Intn(3)])
+	}
+	return path
+}
+
+// fakeUUID returns a random version 4 UUID
+func fakeUUID(rng *rand.Rand) string {
+	b := randomBytes(16, rng)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// FakeIP returns a client IP: mostly public IPv4, some private IPv4 behind proxies, some IPv6
+func FakeIP(rng *rand.Rand) string {
+	switch r := rng.Float64(); {
+	case r < 0.1:
+		return fmt.Sprintf("2001:db8:%x:%x::%x", rng.Intn(0x10000), rng.Intn(0x10000), 1+rng.Intn(0xffff))
+	case r < 0.3:
+		return fmt.Sprintf("10.%d.%d.%d", rng.Intn(256), rng.Intn(256), 1+rng.Intn(254))
+	default:
+		// Public ranges only: skip 0, 10, 127 and multicast and above
+		first := 1 + rng.Intn(223)
+		for first == 10 || first == 127 {
+			first = 1 + rng.Intn(223)
+		}
+		return fmt.Sprintf("%d.%d.%d.%d", first, rng.Intn(256), rng.Intn(256), 1+rng.Intn(254))
+	}
+}
+
+// User agent templates; %d are filled with version numbers
+var userAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%d.0.%d.%d Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%d.0.%d.%d Safari/537.36",
+	"Mozilla/5.0 (X11; Linux x86_64; rv:%d.0) Gecko/20100101 Firefox/%d.%d",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 17_%d like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.%d Mobile/15E148 Safari/%d.1",
+	"Mozilla/5.0 (Linux; Android 14; Pixel %d) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%d.0.%d.0 Mobile Safari/537.36",
+	"okhttp/4.%d.%d",
+	"Go-http-client/%d.%d",
+	"python-requests/2.%d.%d",
+	"curl/8.%d.%d",
+}
+
+// FakeUserAgent returns a browser, mobile or HTTP library user agent
+func FakeUserAgent(rng *rand.Rand) string {
+	template := userAgents[rng.Intn(len(userAgents))]
+	versions := make([]interface{}, strings.Count(template, "%d"))
+	for i := range versions {
+		versions[i] = 1 + rng.Intn(130)
+	}
+	return fmt.Sprintf(template, versions...)
+}
+
+// Email parts
+var (
+	firstNames   = []string{"james", "maria", "wei", "olga", "ahmed", "sofia", "lucas", "aiko", "noah", "fatima", "liam", "elena"}
+	lastNames    = []string{"smith", "garcia", "chen", "ivanova", "hassan", "rossi", "silva", "tanaka", "miller", "khan", "murphy", "novak"}
+	emailDomains = []string{"gmail.com", "outlook.com", "yahoo.com", "icloud.com", "proton.me", "example.com", "corp.example.org"}
+)
+
+// FakeEmail returns a personal email address
+func FakeEmail(rng *rand.Rand) string {
+	first := firstNames[rng.Intn(len(firstNames))]
+	last := lastNames[rng.Intn(len(lastNames))]
+	var local string
+	switch rng.Intn(4) {
+	case 0:
+		local = first + "." + last
+	case 1:
+		local = first[:1] + last
+	case 2:
+		local = first + "_" + last + fmt.Sprintf("%d", rng.Intn(100))
+	default:
+		local = first + last + fmt.Sprintf("%d", 1950+rng.Intn(60))
+	}
+	return local + "@" + emailDomains[rng.Intn(len(emailDomains))]
+}
+
+// SQL schema used for statements
+var (
+	sqlTables  = []string{"users", "orders", "order_items", "products", "payments", "shipments", "sessions", "inventory"}
+	sqlColumns = []string{"id", "user_id", "order_id", "product_id", "status", "created_at", "updated_at", "total_cents", "currency", "quantity", "email", "region", "deleted_at"}
+)
+
+// FakeSQL returns a parameterized SELECT, INSERT, UPDATE or DELETE statement. Statements are
+// extended with further conditions until they are at least minLength bytes long.
+func FakeSQL(minLength int, rng *rand.Rand) string {
+	table := sqlTables[rng.Intn(len(sqlTables))]
+	column := func() string { return sqlColumns[rng.Intn(len(sqlColumns))] }
+
+	var sb strings.Builder
+	switch r := rng.Float64(); {
+	case r < 0.6:
+		alias := table[:1]
+		fmt.Fprintf(&sb, "SELECT %s.%s, %s.%s, %s.%s FROM %s %s", alias, column(), alias, column(), alias, column(), table, alias)
+		if rng.Float64() < DensityMediumLow {
+			joined := sqlTables[rng.Intn(len(sqlTables))]
+			fmt.Fprintf(&sb, " JOIN %s j ON j.%s = %s.id", joined, column(), alias)
+		}
+		fmt.Fprintf(&sb, " WHERE %s.%s = $1", alias, column())
+	case r < 0.75:
+		fmt.Fprintf(&sb, "INSERT INTO %s (%s, %s, %s) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING", table, column(), column(), column())
+		return padSQL(&sb, minLength, rng, false)
+	case r < 0.9:
+		fmt.Fprintf(&sb, "UPDATE %s SET %s = $1, updated_at = now() WHERE id = $2", table, column())
+	default:
+		fmt.Fprintf(&sb, "DELETE FROM %s WHERE %s < $1", table, column())
+	}
+	return padSQL(&sb, minLength, rng, true)
+}
+
+// padSQL appends conditions, or for statements without a WHERE clause a comment, until the
+// statement is at least minLength bytes long
+func padSQL(sb *strings.Builder, minLength int, rng *rand.Rand, where bool) string {
+	for param := strings.Count(sb.String(), "$") + 1; sb.Len() < minLength; param++ {
+		if where {
+			fmt.Fprintf(sb, " AND %s = $%d", sqlColumns[rng.Intn(len(sqlColumns))], param)
+		} else {
+			fmt.Fprintf(sb, " /* %s */", sqlColumns[rng.Intn(len(sqlColumns))])
+		}
+	}
+	return sb.String()
+}
@@ -2,16 +2,19 @@ package generator
 
 import (
 	"fmt"
+	"maps"
 	"math/rand"
+	"slices"
+	"sync"
 )
 
 // WorkflowStep represents a single step in a workflow
 type WorkflowStep struct {
-	Service     string // Service name
-	Operation   string // Operation name
-	SpanKind    string // "server", "client", "internal"
-	DurationMs  int    // Base duration in ms
-	CanParallel bool   // Can this step have parallel children?
+	Service     string `js:"service"`     // Service name
+	Operation   string `js:"operation"`   // Operation name
+	SpanKind    string `js:"spanKind"`    // "server", "client", "internal", "producer", "consumer"
+	DurationMs  int    `js:"durationMs"`  // Base duration in ms
+	CanParallel bool   `js:"canParallel"` // Can this step have parallel children?
 }
 
 // Workflow defines a business workflow with service call chain
@@ -35,6 +38,9 @@ type WorkflowContext struct {
 	CorrelationID string
 }
 
+// workflowsMu guards workflows, which RegisterWorkflow extends at runtime
+var workflowsMu sync.RWMutex
+
 // Define available workflows
 var workflows = map[string]Workflow{
 	"place_order": {
@@ -122,14 +128,51 @@ var workflows = map[string]Workflow{
 	},
 }
 
+// RegisterWorkflow adds a workflow, or replaces the workflow with the same name, so it can be
+// selected through workflowWeights or uniformly along with the built-in workflows
+func RegisterWorkflow(name string, steps []WorkflowStep) error {
+	if name == "" {
+		return fmt.Errorf("workflow name is required")
+	}
+	if len(steps) == 0 {
+		return fmt.Errorf("workflow %q must have at least one step", name)
+	}
+	for i, step := range steps {
+		if step.Service == "" {
+			return fmt.Errorf("workflow %q step %d: service is required", name, i)
+		}
+		if step.Operation == "" {
+			return fmt.Errorf("workflow %q step %d: operation is required", name, i)
+		}
+		switch step.SpanKind {
+		case "", "server", "client", "internal", "producer", "consumer":
+		default:
+			return fmt.Errorf("workflow %q step %d: unknown spanKind %q", name, i, step.SpanKind)
+		}
+		if step.DurationMs <= 0 {
+			return fmt.Errorf("workflow %q step %d: durationMs must be > 0, got %d", name, i, step.DurationMs)
+		}
+	}
+
+	workflowsMu.Lock()
+	defer workflowsMu.Unlock()
+
+	workflows[name] = Workflow{
+		Name:        name,
+		Description: "Custom workflow",
+		Steps:       append([]WorkflowStep(nil), steps...),
+	}
+	return nil
+}
+
 // SelectWorkflow selects a workflow based on weights
 func SelectWorkflow(weights map[string]float64, rng *rand.Rand) string {
+	workflowsMu.RLock()
+	defer workflowsMu.RUnlock()
+
 	if len(weights) == 0 {
-		// Default uniform distribution
-		workflowNames := make([]string, 0, len(workflows))
-		for name := range workflows {
-			workflowNames = append(workflowNames, name)
-		}
+		// Default uniform distribution, in name order so seeded traces are reproducible
+		workflowNames := slices.Sorted(maps.Keys(workflows))
 		return workflowNames[rng.Intn(len(workflowNames))]
 	}
 
@@ -141,10 +184,7 @@ func SelectWorkflow(weights map[string]float64, rng *rand.Rand) string {
 
 	if totalWeight == 0 {
 		// Fallback to uniform
-		workflowNames := make([]string, 0, len(workflows))
-		for name := range workflows {
-			workflowNames = append(workflowNames, name)
-		}
+		workflowNames := slices.Sorted(maps.Keys(workflows))
 		return workflowNames[rng.Intn(len(workflowNames))]
 	}
 
@@ -152,8 +192,8 @@ func SelectWorkflow(weights map[string]float64, rng *rand.Rand) string {
 	r := rng.Float64() * totalWeight
 	currentWeight := 0.0
 
-	for workflowName, weight := range weights {
-		currentWeight += weight
+	for _, workflowName := range slices.Sorted(maps.Keys(weights)) {
+		currentWeight += weights[workflowName]
 		if r <= currentWeight {
 			// Verify workflow exists
 			if _, exists := workflows[workflowName]; exists {
@@ -163,8 +203,8 @@ func SelectWorkflow(weights map[string]float64, rng *rand.Rand) string {
 	}
 
 	// Fallback to first workflow
-	for name := range workflows {
-		return name
+	if len(workflows) > 0 {
+		return slices.Sorted(maps.Keys(workflows))[0]
 	}
 
 	return "place_order" // Ultimate fallback
@@ -172,35 +212,38 @@ func SelectWorkflow(weights map[string]float64, rng *rand.Rand) string {
 
 // GetWorkflow returns a workflow by name
 func GetWorkflow(name string) (Workflow, bool) {
+	workflowsMu.RLock()
+	defer workflowsMu.RUnlock()
+
 	wf, ok := workflows[name]
 	return wf, ok
 }
 
 // GenerateWorkflowContext creates a new workflow context with business IDs
-func GenerateWorkflowContext(workflowName string, rng *rand.Rand, cardConfig map[string]int) *WorkflowContext {
+func GenerateWorkflowContext(workflowName string, rng *rand.Rand, cardConfig map[string]int, cardSkew map[string]float64) *WorkflowContext {
 	cm := GetCardinalityManager()
 
 	ctx := &WorkflowContext{
 		WorkflowName:  workflowName,
-		UserID:        cm.GetValue("customer_id", rng, cardConfig), // Reuse customer_id pool
-		SessionID:     cm.GetValue("session_id", rng, cardConfig),
-		RequestID:     cm.GetValue("request_id", rng, cardConfig),
-		CorrelationID: cm.GetValue("correlation_id", rng, cardConfig),
+		UserID:        cm.GetValue("customer_id", rng, cardConfig, cardSkew), // Reuse customer_id pool
+		SessionID:     cm.GetValue("session_id", rng, cardConfig, cardSkew),
+		RequestID:     cm.GetValue("request_id", rng, cardConfig, cardSkew),
+		CorrelationID: cm.GetValue("correlation_id", rng, cardConfig, cardSkew),
 	}
 
 	// Generate workflow-specific IDs
 	switch workflowName {
 	case "place_order", "process_refund":
-		ctx.OrderID = cm.GetValue("order_id", rng, cardConfig)
-		ctx.PaymentID = cm.GetValue("payment_id", rng, cardConfig)
+		ctx.OrderID = cm.GetValue("order_id", rng, cardConfig, cardSkew)
+		ctx.PaymentID = cm.GetValue("payment_id", rng, cardConfig, cardSkew)
 		if workflowName == "place_order" {
 			ctx.ProductID = fmt.Sprintf("product-%06d", rng.Intn(10000)+1)
-			ctx.ShipmentID = cm.GetValue("shipment_id", rng, cardConfig)
+			ctx.ShipmentID = cm.GetValue("shipment_id", rng, cardConfig, cardSkew)
 		}
 	case "browse_products", "search_products":
 		ctx.ProductID = fmt.Sprintf("product-%06d", rng.Intn(10000)+1)
 	case "user_registration":
-		ctx.UserID = cm.GetValue("customer_id", rng, cardConfig) // New user
+		ctx.UserID = cm.GetValue("customer_id", rng, cardConfig, cardSkew) // New user
 	}
 
 	return ctx
@@ -208,7 +251,9 @@ func GenerateWorkflowContext(workflowName string, rng *rand.Rand, cardConfig map
 
 // GetWorkflowOperationName returns the operation name for a workflow step
 func GetWorkflowOperationName(workflowName string, stepIndex int) string {
+	workflowsMu.RLock()
 	wf, ok := workflows[workflowName]
+	workflowsMu.RUnlock()
 	if !ok || stepIndex >= len(wf.Steps) {
 		return "unknown-operation"
 	}
@@ -217,7 +262,9 @@ func GetWorkflowOperationName(workflowName string, stepIndex int) string {
 
 // GetWorkflowService returns the service name for a workflow step
 func GetWorkflowService(workflowName string, stepIndex int) string {
+	workflowsMu.RLock()
 	wf, ok := workflows[workflowName]
+	workflowsMu.RUnlock()
 	if !ok || stepIndex >= len(wf.Steps) {
 		return "frontend"
 	}
@@ -226,7 +273,9 @@ func GetWorkflowService(workflowName string, stepIndex int) string {
 
 // GetWorkflowSpanKind returns the span kind for a workflow step
 func GetWorkflowSpanKind(workflowName string, stepIndex int) string {
+	workflowsMu.RLock()
 	wf, ok := workflows[workflowName]
+	workflowsMu.RUnlock()
 	if !ok || stepIndex >= len(wf.Steps) {
 		return "server"
 	}
@@ -235,7 +284,9 @@ func GetWorkflowSpanKind(workflowName string, stepIndex int) string {
 
 // GetWorkflowStepDuration returns the base duration for a workflow step
 func GetWorkflowStepDuration(workflowName string, stepIndex int) int {
+	workflowsMu.RLock()
 	wf, ok := workflows[workflowName]
+	workflowsMu.RUnlock()
 	if !ok || stepIndex >= len(wf.Steps) {
 		return 50
 	}
@@ -244,7 +295,9 @@ func GetWorkflowStepDuration(workflowName string, stepIndex int) int {
 
 // GetWorkflowSteps returns all steps for a workflow
 func GetWorkflowSteps(workflowName string) []WorkflowStep {
+	workflowsMu.RLock()
 	wf, ok := workflows[workflowName]
+	workflowsMu.RUnlock()
 	if !ok {
 		return []WorkflowStep{}
 	}
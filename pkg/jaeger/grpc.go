@@ -0,0 +1,122 @@
+package jaeger
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// postSpansMethod is the full gRPC method name of jaeger.api_v2.CollectorService.PostSpans
+const postSpansMethod = "/jaeger.api_v2.CollectorService/PostSpans"
+
+// GRPCExporter exports traces to a Jaeger gRPC collector endpoint
+type GRPCExporter struct {
+	conns    *otlp.GRPCConns
+	endpoint string
+	tenant   string
+	md       metadata.MD
+	timeout  time.Duration // Deadline of every export
+}
+
+// NewGRPCExporter creates a new Jaeger gRPC exporter
+func NewGRPCExporter(endpoint string, tenant string, timeout time.Duration, opts otlp.Options) (*GRPCExporter, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("endpoint must not be empty")
+	}
+	creds := opts.GRPCCredentials(endpoint)
+	endpoint = otlp.GRPCTarget(endpoint, "14250")
+
+	// Create gRPC connection
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{})),
+	}, opts.GRPCDialOptions()...)
+	conns, err := opts.DialGRPC(endpoint, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC connection: %w", err)
+	}
+
+	return &GRPCExporter{
+		conns:    conns,
+		endpoint: endpoint,
+		tenant:   tenant,
+		md:       opts.GRPCMetadata(tenant),
+		timeout:  timeout,
+	}, nil
+}
+
+// ExportTraces exports traces to Tempo's Jaeger receiver. Jaeger has no partial success,
+// so the result only carries the payload size.
+func (e *GRPCExporter) ExportTraces(ctx context.Context, traces ptrace.Traces) (otlp.ExportResult, error) {
+	// Add tenant and custom headers if configured
+	md := e.md
+	if tenant, ok := otlp.TenantFromContext(ctx); ok {
+		md = md.Copy()
+		md.Set("X-Scope-OrgID", tenant)
+	}
+	if md != nil {
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	ctx, cancel := otlp.CallContext(ctx, e.timeout)
+	defer cancel()
+
+	req := EncodePostSpansRequest(traces)
+	var resp []byte
+	var payloadBytes int64
+	if err := e.conns.Next().Invoke(otlp.WithPayloadSize(ctx, &payloadBytes), postSpansMethod, &req, &resp); err != nil {
+		return otlp.ExportResult{PayloadBytes: atomic.LoadInt64(&payloadBytes)}, fmt.Errorf("failed to export traces: %w", err)
+	}
+
+	return otlp.ExportResult{PayloadBytes: atomic.LoadInt64(&payloadBytes)}, nil
+}
+
+// ExportBatch exports multiple traces in a batch
+func (e *GRPCExporter) ExportBatch(ctx context.Context, traces []ptrace.Traces) (otlp.ExportResult, error) {
+	// Combine all traces into a single request
+	combined := ptrace.NewTraces()
+	for _, trace := range traces {
+		// Merge resource spans
+		trace.ResourceSpans().MoveAndAppendTo(combined.ResourceSpans())
+	}
+
+	return e.ExportTraces(ctx, combined)
+}
+
+// Shutdown closes the exporter
+func (e *GRPCExporter) Shutdown(ctx context.Context) error {
+	return e.conns.Close()
+}
+
+// rawCodec passes pre-encoded protobuf messages through gRPC unchanged
+type rawCodec struct{}
+
+// Marshal returns the encoded message
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	return *b, nil
+}
+
+// Unmarshal stores the encoded message
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// Name returns "proto" so requests carry the standard application/grpc+proto content type
+func (rawCodec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,14 @@
+package jaeger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+)
+
+func TestNewGRPCExporterEmptyEndpoint(t *testing.T) {
+	if _, err := NewGRPCExporter("", "", time.Second, otlp.Options{}); err == nil {
+		t.Error("NewGRPCExporter() with an empty endpoint succeeded, want an error")
+	}
+}
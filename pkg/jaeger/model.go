@@ -0,0 +1,270 @@
+package jaeger
+
+import (
+	"math"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the jaeger.api_v2 model (model.proto and collector.proto)
+const (
+	postSpansBatch = 1
+
+	batchSpans   = 1
+	batchProcess = 2
+
+	spanTraceID       = 1
+	spanSpanID        = 2
+	spanOperationName = 3
+	spanReferences    = 4
+	spanStartTime     = 6
+	spanDuration      = 7
+	spanTags          = 8
+	spanLogs          = 9
+	spanProcess       = 10
+
+	refTraceID = 1
+	refSpanID  = 2
+	refType    = 3
+
+	processServiceName = 1
+	processTags        = 2
+
+	logTimestamp = 1
+	logFields    = 2
+
+	kvKey     = 1
+	kvType    = 2
+	kvStr     = 3
+	kvBool    = 4
+	kvInt64   = 5
+	kvFloat64 = 6
+	kvBinary  = 7
+
+	timestampSeconds = 1
+	timestampNanos   = 2
+)
+
+// Jaeger enum values
+const (
+	valueTypeString  = 0
+	valueTypeBool    = 1
+	valueTypeInt64   = 2
+	valueTypeFloat64 = 3
+	valueTypeBinary  = 4
+
+	refTypeChildOf     = 0
+	refTypeFollowsFrom = 1
+)
+
+// EncodePostSpansRequest converts OTLP traces to a serialized jaeger.api_v2.PostSpansRequest.
+// Every span carries the process of its resource, so a single batch can hold many services.
+// The mapping follows the OpenTelemetry Jaeger translator: span kind, status and instrumentation
+// scope become tags, the parent becomes a CHILD_OF reference, links become FOLLOWS_FROM
+// references and events become logs.
+func EncodePostSpansRequest(traces ptrace.Traces) []byte {
+	var batch []byte
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+		process := encodeProcess(rs.Resource())
+
+		// The batch-level process is required; use the first resource
+		if i == 0 {
+			batch = protowire.AppendTag(batch, batchProcess, protowire.BytesType)
+			batch = protowire.AppendBytes(batch, process)
+		}
+
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				batch = protowire.AppendTag(batch, batchSpans, protowire.BytesType)
+				batch = protowire.AppendBytes(batch, encodeSpan(ss.Spans().At(k), ss.Scope(), process))
+			}
+		}
+	}
+
+	var req []byte
+	req = protowire.AppendTag(req, postSpansBatch, protowire.BytesType)
+	req = protowire.AppendBytes(req, batch)
+	return req
+}
+
+// encodeSpan encodes a jaeger.api_v2.Span
+func encodeSpan(s ptrace.Span, scope pcommon.InstrumentationScope, process []byte) []byte {
+	var b []byte
+	b = appendBytesField(b, spanTraceID, traceIDBytes(s.TraceID()))
+	b = appendBytesField(b, spanSpanID, spanIDBytes(s.SpanID()))
+	b = appendBytesField(b, spanOperationName, []byte(s.Name()))
+
+	if !s.ParentSpanID().IsEmpty() {
+		b = appendBytesField(b, spanReferences, encodeRef(s.TraceID(), s.ParentSpanID(), refTypeChildOf))
+	}
+	for i := 0; i < s.Links().Len(); i++ {
+		link := s.Links().At(i)
+		b = appendBytesField(b, spanReferences, encodeRef(link.TraceID(), link.SpanID(), refTypeFollowsFrom))
+	}
+
+	start := s.StartTimestamp().AsTime()
+	b = appendBytesField(b, spanStartTime, encodeTimestamp(start.Unix(), int32(start.Nanosecond())))
+	duration := time.Duration(s.EndTimestamp() - s.StartTimestamp())
+	b = appendBytesField(b, spanDuration, encodeTimestamp(int64(duration/time.Second), int32(duration%time.Second)))
+
+	s.Attributes().Range(func(key string, value pcommon.Value) bool {
+		b = appendBytesField(b, spanTags, encodeKeyValue(key, value))
+		return true
+	})
+	if kind := spanKindTag(s.Kind()); kind != "" {
+		b = appendBytesField(b, spanTags, encodeKeyValue("span.kind", pcommon.NewValueStr(kind)))
+	}
+	switch s.Status().Code() {
+	case ptrace.StatusCodeError:
+		b = appendBytesField(b, spanTags, encodeKeyValue("otel.status_code", pcommon.NewValueStr("ERROR")))
+		b = appendBytesField(b, spanTags, encodeKeyValue("error", pcommon.NewValueBool(true)))
+		if msg := s.Status().Message(); msg != "" {
+			b = appendBytesField(b, spanTags, encodeKeyValue("otel.status_description", pcommon.NewValueStr(msg)))
+		}
+	case ptrace.StatusCodeOk:
+		b = appendBytesField(b, spanTags, encodeKeyValue("otel.status_code", pcommon.NewValueStr("OK")))
+	}
+	if name := scope.Name(); name != "" {
+		b = appendBytesField(b, spanTags, encodeKeyValue("otel.scope.name", pcommon.NewValueStr(name)))
+	}
+	if version := scope.Version(); version != "" {
+		b = appendBytesField(b, spanTags, encodeKeyValue("otel.scope.version", pcommon.NewValueStr(version)))
+	}
+
+	for i := 0; i < s.Events().Len(); i++ {
+		b = appendBytesField(b, spanLogs, encodeLog(s.Events().At(i)))
+	}
+
+	b = appendBytesField(b, spanProcess, process)
+	return b
+}
+
+// encodeProcess encodes a jaeger.api_v2.Process from a resource
+func encodeProcess(resource pcommon.Resource) []byte {
+	serviceName := "unknown_service"
+	if v, ok := resource.Attributes().Get("service.name"); ok {
+		serviceName = v.AsString()
+	}
+
+	var b []byte
+	b = appendBytesField(b, processServiceName, []byte(serviceName))
+	resource.Attributes().Range(func(key string, value pcommon.Value) bool {
+		if key != "service.name" {
+			b = appendBytesField(b, processTags, encodeKeyValue(key, value))
+		}
+		return true
+	})
+	return b
+}
+
+// encodeRef encodes a jaeger.api_v2.SpanRef
+func encodeRef(traceID pcommon.TraceID, spanID pcommon.SpanID, kind uint64) []byte {
+	var b []byte
+	b = appendBytesField(b, refTraceID, traceIDBytes(traceID))
+	b = appendBytesField(b, refSpanID, spanIDBytes(spanID))
+	if kind != refTypeChildOf {
+		b = protowire.AppendTag(b, refType, protowire.VarintType)
+		b = protowire.AppendVarint(b, kind)
+	}
+	return b
+}
+
+// encodeLog encodes a span event as a jaeger.api_v2.Log
+func encodeLog(event ptrace.SpanEvent) []byte {
+	ts := event.Timestamp().AsTime()
+
+	var b []byte
+	b = appendBytesField(b, logTimestamp, encodeTimestamp(ts.Unix(), int32(ts.Nanosecond())))
+	if event.Name() != "" {
+		b = appendBytesField(b, logFields, encodeKeyValue("event", pcommon.NewValueStr(event.Name())))
+	}
+	event.Attributes().Range(func(key string, value pcommon.Value) bool {
+		b = appendBytesField(b, logFields, encodeKeyValue(key, value))
+		return true
+	})
+	return b
+}
+
+// encodeKeyValue encodes a jaeger.api_v2.KeyValue. Maps and slices are sent as their JSON string form.
+func encodeKeyValue(key string, value pcommon.Value) []byte {
+	var b []byte
+	b = appendBytesField(b, kvKey, []byte(key))
+
+	switch value.Type() {
+	case pcommon.ValueTypeBool:
+		b = appendVarintField(b, kvType, valueTypeBool)
+		if value.Bool() {
+			b = appendVarintField(b, kvBool, 1)
+		}
+	case pcommon.ValueTypeInt:
+		b = appendVarintField(b, kvType, valueTypeInt64)
+		b = appendVarintField(b, kvInt64, uint64(value.Int()))
+	case pcommon.ValueTypeDouble:
+		b = appendVarintField(b, kvType, valueTypeFloat64)
+		b = protowire.AppendTag(b, kvFloat64, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(value.Double()))
+	case pcommon.ValueTypeBytes:
+		b = appendVarintField(b, kvType, valueTypeBinary)
+		b = appendBytesField(b, kvBinary, value.Bytes().AsRaw())
+	default:
+		// STRING is the zero enum value and is omitted
+		b = appendBytesField(b, kvStr, []byte(value.AsString()))
+	}
+	return b
+}
+
+// encodeTimestamp encodes a google.protobuf.Timestamp or Duration
+func encodeTimestamp(seconds int64, nanos int32) []byte {
+	var b []byte
+	if seconds != 0 {
+		b = appendVarintField(b, timestampSeconds, uint64(seconds))
+	}
+	if nanos != 0 {
+		b = appendVarintField(b, timestampNanos, uint64(nanos))
+	}
+	return b
+}
+
+// spanKindTag returns the Jaeger span.kind tag value; internal spans have no tag
+func spanKindTag(kind ptrace.SpanKind) string {
+	switch kind {
+	case ptrace.SpanKindClient:
+		return "client"
+	case ptrace.SpanKindServer:
+		return "server"
+	case ptrace.SpanKindProducer:
+		return "producer"
+	case ptrace.SpanKindConsumer:
+		return "consumer"
+	default:
+		return ""
+	}
+}
+
+// traceIDBytes returns the Jaeger wire form of a trace ID. Jaeger encodes the high and low
+// halves big-endian, which is the same byte order as OTLP.
+func traceIDBytes(id pcommon.TraceID) []byte {
+	return id[:]
+}
+
+// spanIDBytes returns the Jaeger wire form of a span ID
+func spanIDBytes(id pcommon.SpanID) []byte {
+	return id[:]
+}
+
+// appendBytesField appends a length-delimited field
+func appendBytesField(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// appendVarintField appends a varint field
+func appendVarintField(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
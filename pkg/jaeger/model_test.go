@@ -0,0 +1,198 @@
+package jaeger
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	testTraceID  = pcommon.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}
+	testRootID   = pcommon.SpanID{0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01}
+	testChildID  = pcommon.SpanID{0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02, 0x02}
+	testLinkID   = pcommon.SpanID{0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03}
+	testStart    = time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	testDuration = 1500 * time.Millisecond
+)
+
+// testTraces returns a trace of two services: a server root span, and a client child span with
+// a link, an event and an error status
+func testTraces() ptrace.Traces {
+	traces := ptrace.NewTraces()
+
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "frontend")
+	rs.Resource().Attributes().PutStr("host.name", "host-1")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("xk6-tempo")
+	ss.Scope().SetVersion("1.0.0")
+	root := ss.Spans().AppendEmpty()
+	root.SetTraceID(testTraceID)
+	root.SetSpanID(testRootID)
+	root.SetName("GET /")
+	root.SetKind(ptrace.SpanKindServer)
+	root.SetStartTimestamp(pcommon.NewTimestampFromTime(testStart))
+	root.SetEndTimestamp(pcommon.NewTimestampFromTime(testStart.Add(testDuration)))
+	root.Attributes().PutStr("http.method", "GET")
+	root.Attributes().PutInt("http.status_code", 200)
+	root.Attributes().PutDouble("ratio", 0.5)
+	root.Attributes().PutBool("cached", false)
+	root.Attributes().PutEmptyBytes("payload").FromRaw([]byte{0xca, 0xfe})
+	root.Status().SetCode(ptrace.StatusCodeOk)
+
+	rs = traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "backend")
+	child := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	child.SetTraceID(testTraceID)
+	child.SetSpanID(testChildID)
+	child.SetParentSpanID(testRootID)
+	child.SetName("query")
+	child.SetKind(ptrace.SpanKindClient)
+	child.SetStartTimestamp(pcommon.NewTimestampFromTime(testStart))
+	child.SetEndTimestamp(pcommon.NewTimestampFromTime(testStart.Add(time.Millisecond)))
+	child.Status().SetCode(ptrace.StatusCodeError)
+	child.Status().SetMessage("timeout")
+	link := child.Links().AppendEmpty()
+	link.SetTraceID(testTraceID)
+	link.SetSpanID(testLinkID)
+	event := child.Events().AppendEmpty()
+	event.SetName("retry")
+	event.SetTimestamp(pcommon.NewTimestampFromTime(testStart))
+	event.Attributes().PutInt("attempt", 2)
+	return traces
+}
+
+// decodePostSpansRequest decodes b with the upstream Jaeger proto types
+func decodePostSpansRequest(t *testing.T, b []byte) *api_v2.PostSpansRequest {
+	t.Helper()
+	req := &api_v2.PostSpansRequest{}
+	if err := req.Unmarshal(b); err != nil {
+		t.Fatalf("decoding PostSpansRequest: %v", err)
+	}
+	return req
+}
+
+// tagMap indexes tags by key
+func tagMap(tags []model.KeyValue) map[string]model.KeyValue {
+	m := make(map[string]model.KeyValue, len(tags))
+	for _, tag := range tags {
+		m[tag.Key] = tag
+	}
+	return m
+}
+
+func TestEncodePostSpansRequest(t *testing.T) {
+	req := decodePostSpansRequest(t, EncodePostSpansRequest(testTraces()))
+
+	if req.Batch.Process == nil || req.Batch.Process.ServiceName != "frontend" {
+		t.Fatalf("batch process = %v, want service frontend", req.Batch.Process)
+	}
+	if len(req.Batch.Spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(req.Batch.Spans))
+	}
+	wantTraceID := model.NewTraceID(0x0102030405060708, 0x090a0b0c0d0e0f10)
+
+	root := req.Batch.Spans[0]
+	if root.TraceID != wantTraceID {
+		t.Errorf("root trace ID = %v, want %v", root.TraceID, wantTraceID)
+	}
+	if root.SpanID != model.NewSpanID(0x0101010101010101) {
+		t.Errorf("root span ID = %v", root.SpanID)
+	}
+	if root.OperationName != "GET /" {
+		t.Errorf("root operation = %q, want %q", root.OperationName, "GET /")
+	}
+	if len(root.References) != 0 {
+		t.Errorf("root has %d references, want 0", len(root.References))
+	}
+	if !root.StartTime.Equal(testStart) {
+		t.Errorf("root start = %v, want %v", root.StartTime, testStart)
+	}
+	if root.Duration != testDuration {
+		t.Errorf("root duration = %v, want %v", root.Duration, testDuration)
+	}
+	if root.Process == nil || root.Process.ServiceName != "frontend" {
+		t.Errorf("root process = %v, want service frontend", root.Process)
+	} else if host := tagMap(root.Process.Tags)["host.name"]; host.VStr != "host-1" {
+		t.Errorf("root process host.name = %q, want %q", host.VStr, "host-1")
+	}
+
+	tags := tagMap(root.Tags)
+	wantTags := []struct {
+		key   string
+		check func(model.KeyValue) bool
+	}{
+		{"http.method", func(kv model.KeyValue) bool { return kv.VType == model.StringType && kv.VStr == "GET" }},
+		{"http.status_code", func(kv model.KeyValue) bool { return kv.VType == model.Int64Type && kv.VInt64 == 200 }},
+		{"ratio", func(kv model.KeyValue) bool { return kv.VType == model.Float64Type && kv.VFloat64 == 0.5 }},
+		{"cached", func(kv model.KeyValue) bool { return kv.VType == model.BoolType && !kv.VBool }},
+		{"payload", func(kv model.KeyValue) bool {
+			return kv.VType == model.BinaryType && string(kv.VBinary) == "\xca\xfe"
+		}},
+		{"span.kind", func(kv model.KeyValue) bool { return kv.VStr == "server" }},
+		{"otel.status_code", func(kv model.KeyValue) bool { return kv.VStr == "OK" }},
+		{"otel.scope.name", func(kv model.KeyValue) bool { return kv.VStr == "xk6-tempo" }},
+		{"otel.scope.version", func(kv model.KeyValue) bool { return kv.VStr == "1.0.0" }},
+	}
+	for _, want := range wantTags {
+		kv, ok := tags[want.key]
+		if !ok {
+			t.Errorf("root tag %q missing", want.key)
+			continue
+		}
+		if !want.check(kv) {
+			t.Errorf("root tag %q = %v", want.key, kv)
+		}
+	}
+
+	child := req.Batch.Spans[1]
+	if child.Process == nil || child.Process.ServiceName != "backend" {
+		t.Errorf("child process = %v, want service backend", child.Process)
+	}
+	if len(child.References) != 2 {
+		t.Fatalf("child has %d references, want 2", len(child.References))
+	}
+	if ref := child.References[0]; ref.RefType != model.ChildOf || ref.SpanID != model.NewSpanID(0x0101010101010101) || ref.TraceID != wantTraceID {
+		t.Errorf("child parent reference = %v", ref)
+	}
+	if ref := child.References[1]; ref.RefType != model.FollowsFrom || ref.SpanID != model.NewSpanID(0x0303030303030303) {
+		t.Errorf("child link reference = %v", ref)
+	}
+
+	tags = tagMap(child.Tags)
+	if kv := tags["error"]; kv.VType != model.BoolType || !kv.VBool {
+		t.Errorf("child error tag = %v, want true", kv)
+	}
+	if kv := tags["otel.status_description"]; kv.VStr != "timeout" {
+		t.Errorf("child status description = %q, want %q", kv.VStr, "timeout")
+	}
+	if kv := tags["span.kind"]; kv.VStr != "client" {
+		t.Errorf("child span.kind = %q, want %q", kv.VStr, "client")
+	}
+
+	if len(child.Logs) != 1 {
+		t.Fatalf("child has %d logs, want 1", len(child.Logs))
+	}
+	log := child.Logs[0]
+	if !log.Timestamp.Equal(testStart) {
+		t.Errorf("log timestamp = %v, want %v", log.Timestamp, testStart)
+	}
+	fields := tagMap(log.Fields)
+	if fields["event"].VStr != "retry" {
+		t.Errorf("log event = %q, want %q", fields["event"].VStr, "retry")
+	}
+	if fields["attempt"].VInt64 != 2 {
+		t.Errorf("log attempt = %d, want 2", fields["attempt"].VInt64)
+	}
+}
+
+func TestEncodePostSpansRequestEmpty(t *testing.T) {
+	req := decodePostSpansRequest(t, EncodePostSpansRequest(ptrace.NewTraces()))
+	if len(req.Batch.Spans) != 0 {
+		t.Errorf("got %d spans, want 0", len(req.Batch.Spans))
+	}
+}
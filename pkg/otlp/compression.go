@@ -0,0 +1,133 @@
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+	grpcgzip "google.golang.org/grpc/encoding/gzip"
+)
+
+// Payload compressions
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{})
+}
+
+// validateCompression normalizes compression and rejects unknown values
+func validateCompression(compression string) (string, error) {
+	switch compression {
+	case "", CompressionNone:
+		return CompressionNone, nil
+	case CompressionGzip, CompressionZstd:
+		return compression, nil
+	default:
+		return "", fmt.Errorf("unsupported compression: %s (use '%s', '%s' or '%s')", compression, CompressionNone, CompressionGzip, CompressionZstd)
+	}
+}
+
+// grpcCompressorName returns the registered gRPC compressor for compression, or "" for none
+func grpcCompressorName(compression string) string {
+	switch compression {
+	case CompressionGzip:
+		return grpcgzip.Name
+	case CompressionZstd:
+		return CompressionZstd
+	default:
+		return ""
+	}
+}
+
+var (
+	gzipWriters = sync.Pool{
+		New: func() interface{} { return gzip.NewWriter(io.Discard) },
+	}
+
+	zstdEncoderOnce sync.Once
+	zstdEncoder     *zstd.Encoder
+)
+
+// compressPayload compresses an HTTP request body
+func compressPayload(data []byte, compression string) ([]byte, error) {
+	switch compression {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzipWriters.Get().(*gzip.Writer)
+		defer gzipWriters.Put(w)
+		w.Reset(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to gzip payload: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("failed to gzip payload: %w", err)
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		return sharedZstdEncoder().EncodeAll(data, make([]byte, 0, len(data)/2)), nil
+	default:
+		return data, nil
+	}
+}
+
+// sharedZstdEncoder returns a process-wide encoder; EncodeAll is safe for concurrent use
+func sharedZstdEncoder() *zstd.Encoder {
+	zstdEncoderOnce.Do(func() {
+		zstdEncoder, _ = zstd.NewWriter(nil)
+	})
+	return zstdEncoder
+}
+
+// zstdCompressor implements the gRPC "zstd" compressor
+type zstdCompressor struct {
+	encoders sync.Pool
+}
+
+// zstdWriter returns its encoder to the pool on Close
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+// Compress returns a writer that compresses into w
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if enc, ok := c.encoders.Get().(*zstd.Encoder); ok {
+		enc.Reset(w)
+		return &zstdWriter{Encoder: enc, pool: &c.encoders}, nil
+	}
+
+	enc, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdWriter{Encoder: enc, pool: &c.encoders}, nil
+}
+
+// Close flushes the frame and returns the encoder to the pool
+func (w *zstdWriter) Close() error {
+	err := w.Encoder.Close()
+	w.pool.Put(w.Encoder)
+	return err
+}
+
+// Decompress returns a reader that decompresses r
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
+
+// Name returns the compressor name negotiated via grpc-encoding
+func (c *zstdCompressor) Name() string {
+	return CompressionZstd
+}
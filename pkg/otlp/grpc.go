@@ -3,65 +3,74 @@ package otlp
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 )
 
 // GRPCExporter exports traces via OTLP gRPC
 type GRPCExporter struct {
-	client   ptraceotlp.GRPCClient
+	conns    *GRPCConns
 	endpoint string
 	tenant   string
+	md       metadata.MD
+	timeout  time.Duration // Deadline of every export attempt
+	callOpts []grpc.CallOption
+	retry    RetryConfig
+	onRetry  func(error)
 }
 
 // NewGRPCExporter creates a new gRPC exporter
-func NewGRPCExporter(endpoint string, tenant string, timeout time.Duration) (*GRPCExporter, error) {
-	// Ensure endpoint doesn't have http:// prefix for gRPC
-	if len(endpoint) > 7 && endpoint[:7] == "http://" {
-		endpoint = endpoint[7:]
+func NewGRPCExporter(endpoint string, tenant string, timeout time.Duration, opts Options) (*GRPCExporter, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("endpoint must not be empty")
 	}
-	if len(endpoint) > 8 && endpoint[:8] == "https://" {
-		endpoint = endpoint[8:]
+	creds := opts.GRPCCredentials(endpoint)
+	endpoint = GRPCTarget(endpoint, "4317")
+
+	compression, err := validateCompression(opts.Compression)
+	if err != nil {
+		return nil, err
 	}
-	// Default port if not specified
-	if endpoint[len(endpoint)-1] == ':' || !containsPort(endpoint) {
-		if endpoint[len(endpoint)-1] != ':' {
-			endpoint += ":"
-		}
-		endpoint += "4317"
+	var callOpts []grpc.CallOption
+	if name := grpcCompressorName(compression); name != "" {
+		callOpts = append(callOpts, grpc.UseCompressor(name))
 	}
 
 	// Create gRPC connection
-	conn, err := grpc.NewClient(
-		endpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithTimeout(timeout),
-	)
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+	}, opts.GRPCDialOptions()...)
+	conns, err := opts.DialGRPC(endpoint, dialOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC connection: %w", err)
 	}
 
-	client := ptraceotlp.NewGRPCClient(conn)
-
 	return &GRPCExporter{
-		client:   client,
+		conns:    conns,
 		endpoint: endpoint,
 		tenant:   tenant,
+		md:       opts.GRPCMetadata(tenant),
+		timeout:  timeout,
+		callOpts: callOpts,
+		retry:    opts.Retry,
+		onRetry:  opts.OnRetry,
 	}, nil
 }
 
-// ExportTraces exports traces to Tempo via gRPC
-func (e *GRPCExporter) ExportTraces(ctx context.Context, traces ptrace.Traces) error {
-	// Add tenant header if configured
-	if e.tenant != "" {
-		md := metadata.New(map[string]string{
-			"X-Scope-OrgID": e.tenant,
-		})
+// ExportTraces exports traces to Tempo via gRPC and returns the partial success reported by Tempo
+func (e *GRPCExporter) ExportTraces(ctx context.Context, traces ptrace.Traces) (ExportResult, error) {
+	// Add tenant and custom headers if configured
+	md := e.md
+	if tenant, ok := TenantFromContext(ctx); ok {
+		md = md.Copy()
+		md.Set("X-Scope-OrgID", tenant)
+	}
+	if md != nil {
 		ctx = metadata.NewOutgoingContext(ctx, md)
 	}
 
@@ -69,16 +78,27 @@ func (e *GRPCExporter) ExportTraces(ctx context.Context, traces ptrace.Traces) e
 	req := ptraceotlp.NewExportRequestFromTraces(traces)
 
 	// Send request
-	_, err := e.client.Export(ctx, req)
+	var resp ptraceotlp.ExportResponse
+	var payloadBytes int64
+	err := e.retry.do(ctx, e.onRetry, e.retry.retryableGRPC, func() error {
+		callCtx, cancel := CallContext(ctx, e.timeout)
+		defer cancel()
+
+		var exportErr error
+		resp, exportErr = ptraceotlp.NewGRPCClient(e.conns.Next()).Export(WithPayloadSize(callCtx, &payloadBytes), req, e.callOpts...)
+		return exportErr
+	})
 	if err != nil {
-		return fmt.Errorf("failed to export traces: %w", err)
+		return ExportResult{PayloadBytes: atomic.LoadInt64(&payloadBytes)}, fmt.Errorf("failed to export traces: %w", err)
 	}
 
-	return nil
+	result := resultFromResponse(resp)
+	result.PayloadBytes = atomic.LoadInt64(&payloadBytes)
+	return result, nil
 }
 
 // ExportBatch exports multiple traces in a batch
-func (e *GRPCExporter) ExportBatch(ctx context.Context, traces []ptrace.Traces) error {
+func (e *GRPCExporter) ExportBatch(ctx context.Context, traces []ptrace.Traces) (ExportResult, error) {
 	// Combine all traces into a single request
 	combined := ptrace.NewTraces()
 	for _, trace := range traces {
@@ -91,8 +111,30 @@ func (e *GRPCExporter) ExportBatch(ctx context.Context, traces []ptrace.Traces)
 
 // Shutdown closes the exporter
 func (e *GRPCExporter) Shutdown(ctx context.Context) error {
-	// gRPC client cleanup handled by connection
-	return nil
+	return e.conns.Close()
+}
+
+// GRPCTarget strips any http:// or https:// prefix from endpoint and appends defaultPort
+// when no port is specified. unix: endpoints are passed to gRPC unchanged.
+func GRPCTarget(endpoint string, defaultPort string) string {
+	if _, ok := UnixSocketPath(endpoint); ok {
+		return endpoint
+	}
+	// Ensure endpoint doesn't have http:// prefix for gRPC
+	if len(endpoint) > 7 && endpoint[:7] == "http://" {
+		endpoint = endpoint[7:]
+	}
+	if len(endpoint) > 8 && endpoint[:8] == "https://" {
+		endpoint = endpoint[8:]
+	}
+	// Default port if not specified
+	if endpoint[len(endpoint)-1] == ':' || !containsPort(endpoint) {
+		if endpoint[len(endpoint)-1] != ':' {
+			endpoint += ":"
+		}
+		endpoint += defaultPort
+	}
+	return endpoint
 }
 
 func containsPort(endpoint string) bool {
@@ -0,0 +1,12 @@
+package otlp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewGRPCExporterEmptyEndpoint(t *testing.T) {
+	if _, err := NewGRPCExporter("", "", time.Second, Options{}); err == nil {
+		t.Error("NewGRPCExporter() with an empty endpoint succeeded, want an error")
+	}
+}
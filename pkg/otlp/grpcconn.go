@@ -0,0 +1,198 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC load-balancing policies
+const (
+	LoadBalancingPickFirst  = "pick_first"
+	LoadBalancingRoundRobin = "round_robin"
+)
+
+// GRPCConfig configures the connections of gRPC exporters; the zero value keeps the gRPC defaults
+type GRPCConfig struct {
+	KeepaliveTime    time.Duration // Ping interval on idle connections; 0 disables keepalive pings
+	KeepaliveTimeout time.Duration // Wait for a ping ack before closing the connection; 0 keeps the gRPC default
+	MaxMessageSize   int           // Max send and receive message size in bytes; 0 keeps the gRPC defaults
+	NumConnections   int           // Connections opened per exporter and used in turn; 0 means 1
+	LoadBalancing    string        // LoadBalancingPickFirst (default) or LoadBalancingRoundRobin
+	DialTimeout      time.Duration // When set, DialGRPC waits this long for every connection to be ready; 0 connects lazily
+}
+
+// Validate checks the gRPC settings
+func (c GRPCConfig) Validate() error {
+	switch c.LoadBalancing {
+	case "", LoadBalancingPickFirst, LoadBalancingRoundRobin:
+	default:
+		return fmt.Errorf("unsupported loadBalancing: %s (use '%s' or '%s')", c.LoadBalancing, LoadBalancingPickFirst, LoadBalancingRoundRobin)
+	}
+	if c.KeepaliveTime < 0 || c.KeepaliveTimeout < 0 || c.DialTimeout < 0 {
+		return fmt.Errorf("durations must not be negative")
+	}
+	if c.MaxMessageSize < 0 {
+		return fmt.Errorf("maxMessageSize must not be negative, got %d", c.MaxMessageSize)
+	}
+	if c.NumConnections < 0 {
+		return fmt.Errorf("numConnections must not be negative, got %d", c.NumConnections)
+	}
+	return nil
+}
+
+// dialOptions returns the dial options applying the connection settings
+func (c GRPCConfig) dialOptions() []grpc.DialOption {
+	dialOpts := []grpc.DialOption{grpc.WithStatsHandler(payloadSizeHandler{})}
+	if c.KeepaliveTime > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    c.KeepaliveTime,
+			Timeout: c.KeepaliveTimeout,
+		}))
+	}
+	if c.MaxMessageSize > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(
+			grpc.MaxCallSendMsgSize(c.MaxMessageSize),
+			grpc.MaxCallRecvMsgSize(c.MaxMessageSize),
+		))
+	}
+	if c.LoadBalancing != "" {
+		dialOpts = append(dialOpts, grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig": [{"%s": {}}]}`, c.LoadBalancing)))
+	}
+	return dialOpts
+}
+
+// GRPCConns is a fixed set of client connections to one target, used in turn so that
+// requests are spread over several HTTP/2 connections
+type GRPCConns struct {
+	conns []*grpc.ClientConn
+	next  uint64
+}
+
+// DialGRPC opens o.GRPC.NumConnections connections to target. Connections are established on
+// first use unless o.GRPC.DialTimeout is set.
+func (o Options) DialGRPC(target string, dialOpts ...grpc.DialOption) (*GRPCConns, error) {
+	n := o.GRPC.NumConnections
+	if n <= 0 {
+		n = 1
+	}
+
+	pool := &GRPCConns{}
+	for i := 0; i < n; i++ {
+		conn, err := grpc.NewClient(target, dialOpts...)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.conns = append(pool.conns, conn)
+
+		if o.GRPC.DialTimeout > 0 {
+			if err := waitReady(conn, o.GRPC.DialTimeout); err != nil {
+				pool.Close()
+				return nil, fmt.Errorf("%s: %w", target, err)
+			}
+		}
+	}
+	return pool, nil
+}
+
+// Next returns the connection for the next request
+func (p *GRPCConns) Next() *grpc.ClientConn {
+	if len(p.conns) == 1 {
+		return p.conns[0]
+	}
+	return p.conns[(atomic.AddUint64(&p.next, 1)-1)%uint64(len(p.conns))]
+}
+
+// Close closes all connections
+func (p *GRPCConns) Close() error {
+	var errs []error
+	for _, conn := range p.conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitReady connects conn and waits until it is ready or timeout expires
+func waitReady(conn *grpc.ClientConn, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn.Connect()
+	for state := conn.GetState(); state != connectivity.Ready; state = conn.GetState() {
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("not connected within %s (state: %s)", timeout, state)
+		}
+	}
+	return nil
+}
+
+// CallContext returns the context of one RPC, bounded by timeout unless timeout is 0
+func CallContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// IsTimeout reports whether an export failed because its deadline expired: a gRPC
+// DEADLINE_EXCEEDED status, an expired context or an HTTP client timeout
+func IsTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// payloadSizeKey is the context key of the counter receiving the wire size of an RPC's request
+type payloadSizeKey struct{}
+
+// WithPayloadSize returns ctx for an RPC whose request size on the wire, after compression,
+// is stored in size
+func WithPayloadSize(ctx context.Context, size *int64) context.Context {
+	return context.WithValue(ctx, payloadSizeKey{}, size)
+}
+
+// payloadSizeHandler is a gRPC stats handler storing the wire size of outgoing messages in
+// the counter set by WithPayloadSize
+type payloadSizeHandler struct{}
+
+// TagRPC implements stats.Handler
+func (payloadSizeHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+// HandleRPC implements stats.Handler
+func (payloadSizeHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	out, ok := s.(*stats.OutPayload)
+	if !ok {
+		return
+	}
+	if size, ok := ctx.Value(payloadSizeKey{}).(*int64); ok {
+		atomic.StoreInt64(size, int64(out.WireLength))
+	}
+}
+
+// TagConn implements stats.Handler
+func (payloadSizeHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn implements stats.Handler
+func (payloadSizeHandler) HandleConn(context.Context, stats.ConnStats) {}
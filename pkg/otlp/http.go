@@ -14,14 +14,33 @@ import (
 
 // HTTPExporter exports traces via OTLP HTTP
 type HTTPExporter struct {
-	client   *http.Client
-	endpoint string
-	tenant   string
-	headers  map[string]string
+	client      *http.Client
+	endpoint    string
+	tenant      string
+	headers     map[string]string
+	auth        func(ctx context.Context) (string, error)
+	encoding    string
+	compression string
+	retry       RetryConfig
+	onRetry     func(error)
 }
 
 // NewHTTPExporter creates a new HTTP exporter
-func NewHTTPExporter(endpoint string, tenant string, timeout time.Duration) *HTTPExporter {
+func NewHTTPExporter(endpoint string, tenant string, timeout time.Duration, opts Options) (*HTTPExporter, error) {
+	encoding := opts.Encoding
+	if encoding == "" {
+		encoding = EncodingProtobuf
+	}
+	if encoding != EncodingProtobuf && encoding != EncodingJSON {
+		return nil, fmt.Errorf("unsupported encoding: %s (use '%s' or '%s')", encoding, EncodingProtobuf, EncodingJSON)
+	}
+	compression, err := validateCompression(opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, opts = UnixHTTPEndpoint(endpoint, opts)
+
 	// Ensure endpoint ends with /v1/traces
 	if endpoint[len(endpoint)-1] != '/' {
 		endpoint += "/"
@@ -30,60 +49,108 @@ func NewHTTPExporter(endpoint string, tenant string, timeout time.Duration) *HTT
 
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-protobuf"
+	if encoding == EncodingJSON {
+		headers["Content-Type"] = "application/json"
+	}
+	if compression != CompressionNone {
+		headers["Content-Encoding"] = compression
+	}
 	if tenant != "" {
 		headers["X-Scope-OrgID"] = tenant
 	}
+	for key, value := range opts.Headers {
+		headers[key] = value
+	}
 
 	return &HTTPExporter{
-		client: &http.Client{
-			Timeout: timeout,
-		},
-		endpoint: endpoint,
-		tenant:   tenant,
-		headers:  headers,
-	}
+		client:      opts.HTTPClient(timeout),
+		endpoint:    endpoint,
+		tenant:      tenant,
+		headers:     headers,
+		auth:        opts.Authorization,
+		encoding:    encoding,
+		compression: compression,
+		retry:       opts.Retry,
+		onRetry:     opts.OnRetry,
+	}, nil
 }
 
-// ExportTraces exports traces to Tempo via HTTP
-func (e *HTTPExporter) ExportTraces(ctx context.Context, traces ptrace.Traces) error {
+// ExportTraces exports traces to Tempo via HTTP and returns the partial success reported by Tempo
+func (e *HTTPExporter) ExportTraces(ctx context.Context, traces ptrace.Traces) (ExportResult, error) {
 	// Convert ptrace.Traces to OTLP request
 	req := ptraceotlp.NewExportRequestFromTraces(traces)
 
-	// Serialize to protobuf
-	data, err := req.MarshalProto()
+	// Serialize to protobuf or OTLP/JSON
+	var data []byte
+	var err error
+	if e.encoding == EncodingJSON {
+		data, err = req.MarshalJSON()
+	} else {
+		data, err = req.MarshalProto()
+	}
 	if err != nil {
-		return fmt.Errorf("failed to marshal traces: %w", err)
+		return ExportResult{}, fmt.Errorf("failed to marshal traces: %w", err)
 	}
 
+	data, err = compressPayload(data, e.compression)
+	if err != nil {
+		return ExportResult{}, err
+	}
+
+	var result ExportResult
+	err = e.retry.do(ctx, e.onRetry, e.retry.retryableHTTP, func() error {
+		var sendErr error
+		result, sendErr = e.send(ctx, data)
+		return sendErr
+	})
+	result.PayloadBytes = int64(len(data))
+	return result, err
+}
+
+// send posts an encoded payload once
+func (e *HTTPExporter) send(ctx context.Context, data []byte) (ExportResult, error) {
 	// Create HTTP request
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.endpoint, bytes.NewReader(data))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return ExportResult{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
 	for key, value := range e.headers {
 		httpReq.Header.Set(key, value)
 	}
+	if tenant, ok := TenantFromContext(ctx); ok {
+		httpReq.Header.Set("X-Scope-OrgID", tenant)
+	}
+	if e.auth != nil {
+		authorization, err := e.auth(ctx)
+		if err != nil {
+			return ExportResult{}, fmt.Errorf("failed to get authorization: %w", err)
+		}
+		httpReq.Header.Set("Authorization", authorization)
+	}
 
 	// Send request
 	resp, err := e.client.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return ExportResult{}, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check status code
+	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(body))
+		return ExportResult{}, NewStatusError(resp, body)
 	}
 
-	return nil
+	// A 2xx means the spans were accepted, so the export must not be retried. A body that is not
+	// an OTLP response, such as a proxy's, reports no partial success.
+	result, _ := decodeHTTPResponse(body, resp.Header.Get("Content-Type"))
+	return result, nil
 }
 
 // ExportBatch exports multiple traces in a batch
-func (e *HTTPExporter) ExportBatch(ctx context.Context, traces []ptrace.Traces) error {
+func (e *HTTPExporter) ExportBatch(ctx context.Context, traces []ptrace.Traces) (ExportResult, error) {
 	// Combine all traces into a single request
 	combined := ptrace.NewTraces()
 	for _, trace := range traces {
@@ -96,6 +163,7 @@ func (e *HTTPExporter) ExportBatch(ctx context.Context, traces []ptrace.Traces)
 
 // Shutdown closes the exporter
 func (e *HTTPExporter) Shutdown(ctx context.Context) error {
-	// HTTP client doesn't need explicit shutdown
+	// The HTTP client only holds idle keep-alive connections
+	e.client.CloseIdleConnections()
 	return nil
 }
@@ -0,0 +1,46 @@
+package otlp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestHTTPExporterUndecodableSuccessBody(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html>accepted by proxy</html>"))
+	}))
+	defer server.Close()
+
+	retry := DefaultRetryConfig()
+	retry.MaxRetries = 3
+	retry.InitialBackoff = time.Millisecond
+	exporter, err := NewHTTPExporter(server.URL, "", 5*time.Second, Options{Retry: retry})
+	if err != nil {
+		t.Fatalf("NewHTTPExporter() error = %v", err)
+	}
+
+	traces := ptrace.NewTraces()
+	traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("op")
+	result, err := exporter.ExportTraces(context.Background(), traces)
+	if err != nil {
+		t.Fatalf("ExportTraces() error = %v, want success", err)
+	}
+	if result.RejectedSpans != 0 {
+		t.Errorf("RejectedSpans = %d, want 0", result.RejectedSpans)
+	}
+	if result.PayloadBytes == 0 {
+		t.Error("PayloadBytes = 0, want the request size")
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,176 @@
+package otlp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Payload encodings for the HTTP exporter
+const (
+	EncodingProtobuf = "protobuf"
+	EncodingJSON     = "json"
+)
+
+// Options holds optional exporter settings
+type Options struct {
+	Encoding    string // HTTP payload encoding: EncodingProtobuf (default) or EncodingJSON
+	Compression string // CompressionNone (default), CompressionGzip or CompressionZstd
+
+	// TLS enables TLS with the given client config. gRPC endpoints with an https:// prefix
+	// use TLS with default settings when TLS is nil.
+	TLS *tls.Config
+
+	// Headers are added to every request, as HTTP headers or gRPC metadata
+	Headers map[string]string
+
+	// Authorization, when set, returns the Authorization header for every request and takes
+	// precedence over a static Authorization header
+	Authorization func(ctx context.Context) (string, error)
+
+	// Retry configures retries of failed exports; the zero value disables them
+	Retry RetryConfig
+	// OnRetry, when set, is called with the failed attempt's error before every retry
+	OnRetry func(err error)
+
+	// GRPC configures the connections of gRPC exporters
+	GRPC GRPCConfig
+
+	// Proxy routes HTTP requests through the given proxy; when nil, HTTP_PROXY, HTTPS_PROXY
+	// and NO_PROXY from the environment apply
+	Proxy *url.URL
+
+	// UnixSocket, when set, sends HTTP requests over this Unix domain socket instead of TCP
+	UnixSocket string
+}
+
+// HTTPClient returns an HTTP client applying the TLS and proxy options
+func (o Options) HTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if o.Proxy != nil {
+		transport.Proxy = http.ProxyURL(o.Proxy)
+	}
+	if o.TLS != nil {
+		transport.TLSClientConfig = o.TLS
+	}
+	if o.UnixSocket != "" {
+		socket := o.UnixSocket
+		dialer := &net.Dialer{}
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// UnixSocketPath returns the socket path of a unix:///path/to.sock or unix:relative.sock endpoint
+func UnixSocketPath(endpoint string) (string, bool) {
+	if !strings.HasPrefix(endpoint, "unix:") {
+		return "", false
+	}
+	if path, ok := strings.CutPrefix(endpoint, "unix://"); ok {
+		return path, true
+	}
+	return strings.TrimPrefix(endpoint, "unix:"), true
+}
+
+// UnixHTTPEndpoint prepares an HTTP exporter for a unix:// endpoint: it returns the base URL
+// for requests and opts set to dial the socket. Other endpoints are returned unchanged.
+func UnixHTTPEndpoint(endpoint string, opts Options) (string, Options) {
+	socket, ok := UnixSocketPath(endpoint)
+	if !ok {
+		return endpoint, opts
+	}
+	opts.UnixSocket = socket
+	return "http://localhost", opts
+}
+
+// ParseProxyURL parses an http://, https:// or socks5:// proxy URL; it returns nil for an empty string
+func ParseProxyURL(raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	proxy, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", proxy.Scheme)
+	}
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", raw)
+	}
+	return proxy, nil
+}
+
+// GRPCMetadata returns the outgoing metadata for a gRPC exporter: the tenant header and the
+// custom headers, which take precedence, or nil when there are none
+func (o Options) GRPCMetadata(tenant string) metadata.MD {
+	md := metadata.MD{}
+	if tenant != "" {
+		md.Set("X-Scope-OrgID", tenant)
+	}
+	for key, value := range o.Headers {
+		md.Set(key, value)
+	}
+	if md.Len() == 0 {
+		return nil
+	}
+	return md
+}
+
+// GRPCDialOptions returns dial options applying the connection settings and the per-request
+// Authorization, if any
+func (o Options) GRPCDialOptions() []grpc.DialOption {
+	dialOpts := o.GRPC.dialOptions()
+	if o.Authorization != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(perRPCAuthorization(o.Authorization)))
+	}
+	return dialOpts
+}
+
+// perRPCAuthorization adds the Authorization header to every RPC
+type perRPCAuthorization func(ctx context.Context) (string, error)
+
+// GetRequestMetadata returns the authorization metadata
+func (a perRPCAuthorization) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	authorization, err := a(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": authorization}, nil
+}
+
+// RequireTransportSecurity returns false so plaintext in-cluster endpoints can be authenticated
+func (a perRPCAuthorization) RequireTransportSecurity() bool {
+	return false
+}
+
+// GRPCCredentials returns the transport credentials for a gRPC endpoint as configured by the user
+func (o Options) GRPCCredentials(endpoint string) credentials.TransportCredentials {
+	if o.TLS != nil {
+		return credentials.NewTLS(o.TLS)
+	}
+	if strings.HasPrefix(endpoint, "https://") {
+		return credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	return insecure.NewCredentials()
+}
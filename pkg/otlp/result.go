@@ -0,0 +1,43 @@
+package otlp
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+// ExportResult holds the partial success reported by the receiver and the size of the
+// request. A zero RejectedSpans means every span was accepted.
+type ExportResult struct {
+	RejectedSpans int64  // Spans the receiver dropped
+	ErrorMessage  string // Receiver's explanation, set for rejections and warnings
+	PayloadBytes  int64  // Size of the request sent, after compression
+}
+
+// resultFromResponse extracts the partial_success field of an ExportTraceServiceResponse
+func resultFromResponse(resp ptraceotlp.ExportResponse) ExportResult {
+	return ExportResult{
+		RejectedSpans: resp.PartialSuccess().RejectedSpans(),
+		ErrorMessage:  resp.PartialSuccess().ErrorMessage(),
+	}
+}
+
+// decodeHTTPResponse parses an OTLP/HTTP response body in the encoding given by contentType.
+// An empty body is a full success.
+func decodeHTTPResponse(body []byte, contentType string) (ExportResult, error) {
+	if len(body) == 0 {
+		return ExportResult{}, nil
+	}
+
+	resp := ptraceotlp.NewExportResponse()
+	var err error
+	if strings.HasPrefix(contentType, "application/json") {
+		err = resp.UnmarshalJSON(body)
+	} else {
+		err = resp.UnmarshalProto(body)
+	}
+	if err != nil {
+		return ExportResult{}, err
+	}
+	return resultFromResponse(resp), nil
+}
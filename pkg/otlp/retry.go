@@ -0,0 +1,243 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryConfig configures retries of failed exports
+type RetryConfig struct {
+	MaxRetries           int           // Retries after the first attempt; 0 disables retries
+	InitialBackoff       time.Duration // Delay before the first retry, doubled for every further retry
+	MaxBackoff           time.Duration // Upper bound for the delay
+	RetryableStatusCodes []int         // HTTP status codes that are retried
+	RetryableGRPCCodes   []codes.Code  // gRPC status codes that are retried
+}
+
+// DefaultRetryConfig returns the retryable codes of the OTLP specification with retries disabled
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:     0,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableGRPCCodes: []codes.Code{
+			codes.Unavailable,
+			codes.ResourceExhausted,
+			codes.Aborted,
+			codes.DeadlineExceeded,
+		},
+	}
+}
+
+// StatusError is returned when an HTTP export is answered with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // Delay requested by the Retry-After header; 0 when absent
+}
+
+// NewStatusError creates the error for a non-2xx response with the given body
+func NewStatusError(resp *http.Response, body []byte) *StatusError {
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+// parseRetryAfter parses a Retry-After value in seconds or as an HTTP date; it returns 0
+// when the value is missing or invalid
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, e.Body)
+}
+
+// Pushback reports whether an export failed because Tempo is overloaded: HTTP 429 or gRPC
+// RESOURCE_EXHAUSTED. wait is the delay Tempo asked for through Retry-After or gRPC retry
+// info, or 0 when it did not say.
+func Pushback(err error) (wait time.Duration, ok bool) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.RetryAfter, statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	st, isStatus := status.FromError(err)
+	if !isStatus || st.Code() != codes.ResourceExhausted {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, true
+}
+
+// Invalid reports whether an export was refused as malformed: HTTP 400 or gRPC INVALID_ARGUMENT
+func Invalid(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusBadRequest
+	}
+	return status.Code(err) == codes.InvalidArgument
+}
+
+// Error classes of failed exports
+const (
+	ErrorClassTimeout  = "timeout"  // The deadline expired, see IsTimeout
+	ErrorClass4xx      = "4xx"      // HTTP client error status
+	ErrorClass5xx      = "5xx"      // HTTP server error status
+	ErrorClassHTTP     = "http"     // Other non-2xx HTTP status
+	ErrorClassNetwork  = "network"  // Connection failure without a response
+	ErrorClassCanceled = "canceled" // The push was canceled, e.g. at the end of the test
+	ErrorClassOther    = "other"    // Anything else, such as encoding failures
+)
+
+// ErrorClass classifies why an export failed: a timeout, the class of the HTTP status, the gRPC
+// status code in upper snake case, e.g. "UNAVAILABLE", or one of the other error classes
+func ErrorClass(err error) string {
+	if IsTimeout(err) {
+		return ErrorClassTimeout
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode / 100 {
+		case 4:
+			return ErrorClass4xx
+		case 5:
+			return ErrorClass5xx
+		default:
+			return ErrorClassHTTP
+		}
+	}
+	if st, ok := status.FromError(err); ok && st.Code() != codes.OK {
+		return grpcCodeName(st.Code())
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.As(err, &netErr):
+		return ErrorClassNetwork
+	default:
+		return ErrorClassOther
+	}
+}
+
+// grpcCodeName returns the name of a gRPC status code in upper snake case, as in the gRPC
+// specification and retryableGrpcCodes
+func grpcCodeName(code codes.Code) string {
+	var b strings.Builder
+	prev := rune(0)
+	for _, r := range code.String() {
+		// Split words at lower to upper case changes, so "OK" stays "OK"
+		if unicode.IsLower(prev) && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		prev = r
+	}
+	return b.String()
+}
+
+// retryableHTTP reports whether a failed HTTP export should be retried. Errors without
+// a response (connection refused, reset, timeouts) are always retried.
+func (r RetryConfig) retryableHTTP(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return slices.Contains(r.RetryableStatusCodes, statusErr.StatusCode)
+	}
+	return true
+}
+
+// retryableGRPC reports whether a failed gRPC export should be retried
+func (r RetryConfig) retryableGRPC(err error) bool {
+	return slices.Contains(r.RetryableGRPCCodes, status.Code(err))
+}
+
+// DoHTTP calls request with the retry policy of HTTP exports: failures without a response and
+// StatusErrors with a retryable status code are retried, honoring Retry-After
+func (r RetryConfig) DoHTTP(ctx context.Context, onRetry func(error), request func() error) error {
+	return r.do(ctx, onRetry, r.retryableHTTP, request)
+}
+
+// do calls export until it succeeds, fails with an error that is not retryable or runs out
+// of retries. onRetry, when set, is called before every retry. The delay uses full jitter so
+// VUs that failed together do not retry together.
+func (r RetryConfig) do(ctx context.Context, onRetry func(error), retryable func(error) bool, export func() error) error {
+	backoff := r.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Millisecond
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := export()
+		if err == nil || attempt >= r.MaxRetries || ctx.Err() != nil || !retryable(err) {
+			return err
+		}
+		if onRetry != nil {
+			onRetry(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(r.delay(backoff, err)):
+		}
+
+		backoff *= 2
+		if r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+			backoff = r.MaxBackoff
+		}
+	}
+}
+
+// delay returns the wait before retrying err: a random delay in (0, backoff], or the delay Tempo
+// asked for when it is longer, bounded by MaxBackoff
+func (r RetryConfig) delay(backoff time.Duration, err error) time.Duration {
+	delay := time.Duration(rand.Int63n(int64(backoff))) + 1
+	// Wait at least as long as Tempo asked, within the configured bound
+	if wait, ok := Pushback(err); ok && wait > delay {
+		delay = wait
+		if r.MaxBackoff > 0 && delay > r.MaxBackoff {
+			delay = r.MaxBackoff
+		}
+	}
+	return delay
+}
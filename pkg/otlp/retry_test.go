@@ -0,0 +1,266 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// timeoutError is a net.Error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// resourceExhausted returns a gRPC RESOURCE_EXHAUSTED error asking to retry after delay
+func resourceExhausted(t *testing.T, delay time.Duration) error {
+	t.Helper()
+	st, err := status.New(codes.ResourceExhausted, "rate limited").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	if err != nil {
+		t.Fatalf("adding retry info: %v", err)
+	}
+	return st.Err()
+}
+
+func TestRetryConfigDelay(t *testing.T) {
+	const backoff = 100 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		maxBackoff time.Duration
+		err        error
+		min, max   time.Duration
+	}{
+		{
+			name:       "full jitter",
+			maxBackoff: 5 * time.Second,
+			err:        errors.New("connection reset"),
+			min:        1,
+			max:        backoff,
+		},
+		{
+			name:       "retry-after longer than the backoff",
+			maxBackoff: 5 * time.Second,
+			err:        &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Second},
+			min:        2 * time.Second,
+			max:        2 * time.Second,
+		},
+		{
+			name:       "retry-after bounded by the max backoff",
+			maxBackoff: 5 * time.Second,
+			err:        &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute},
+			min:        5 * time.Second,
+			max:        5 * time.Second,
+		},
+		{
+			name: "retry-after without a max backoff",
+			err:  &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute},
+			min:  time.Minute,
+			max:  time.Minute,
+		},
+		{
+			name:       "retry-after shorter than the backoff",
+			maxBackoff: 5 * time.Second,
+			err:        &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Millisecond},
+			min:        time.Millisecond,
+			max:        backoff,
+		},
+		{
+			name:       "retry-after of other statuses ignored",
+			maxBackoff: 5 * time.Second,
+			err:        &StatusError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 2 * time.Second},
+			min:        1,
+			max:        backoff,
+		},
+		{
+			name:       "grpc retry info",
+			maxBackoff: 5 * time.Second,
+			err:        resourceExhausted(t, 2*time.Second),
+			min:        2 * time.Second,
+			max:        2 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := RetryConfig{MaxBackoff: tt.maxBackoff}
+			for i := 0; i < 1000; i++ {
+				if delay := config.delay(backoff, tt.err); delay < tt.min || delay > tt.max {
+					t.Fatalf("delay() = %v, want within [%v, %v]", delay, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryConfigDelayJitter(t *testing.T) {
+	const backoff = 100 * time.Millisecond
+
+	// Full jitter spreads retries over the whole backoff instead of clustering at its end
+	config := RetryConfig{MaxBackoff: 5 * time.Second}
+	var low, high bool
+	for i := 0; i < 1000; i++ {
+		delay := config.delay(backoff, errors.New("connection reset"))
+		low = low || delay < backoff/4
+		high = high || delay > backoff*3/4
+	}
+	if !low || !high {
+		t.Errorf("1000 delays did not cover the backoff: below %v %t, above %v %t", backoff/4, low, backoff*3/4, high)
+	}
+}
+
+func TestRetryConfigDo(t *testing.T) {
+	errRetryable := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	errPermanent := &StatusError{StatusCode: http.StatusBadRequest}
+
+	tests := []struct {
+		name       string
+		maxRetries int
+		errs       []error // Results of the attempts; attempts past the end succeed
+		wantCalls  int
+		wantErr    error
+	}{
+		{name: "success", maxRetries: 3, wantCalls: 1},
+		{name: "retries disabled", maxRetries: 0, errs: []error{errRetryable}, wantCalls: 1, wantErr: errRetryable},
+		{name: "success after retries", maxRetries: 3, errs: []error{errRetryable, errRetryable}, wantCalls: 3},
+		{name: "retries exhausted", maxRetries: 2, errs: []error{errRetryable, errRetryable, errRetryable, errRetryable}, wantCalls: 3, wantErr: errRetryable},
+		{name: "not retryable", maxRetries: 3, errs: []error{errPermanent}, wantCalls: 1, wantErr: errPermanent},
+		{name: "network errors retried", maxRetries: 3, errs: []error{errors.New("connection refused")}, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultRetryConfig()
+			config.MaxRetries = tt.maxRetries
+			config.InitialBackoff = time.Microsecond
+			config.MaxBackoff = time.Millisecond
+
+			calls, retries := 0, 0
+			err := config.DoHTTP(context.Background(), func(error) { retries++ }, func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DoHTTP() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("got %d attempts, want %d", calls, tt.wantCalls)
+			}
+			if retries != calls-1 {
+				t.Errorf("onRetry called %d times for %d attempts", retries, calls)
+			}
+		})
+	}
+}
+
+func TestRetryConfigDoCanceled(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.MaxRetries = 3
+	config.InitialBackoff = time.Hour
+	config.MaxBackoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errRetryable := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	calls := 0
+	err := config.DoHTTP(ctx, func(error) { cancel() }, func() error {
+		calls++
+		return errRetryable
+	})
+
+	if !errors.Is(err, errRetryable) {
+		t.Errorf("DoHTTP() error = %v, want %v", err, errRetryable)
+	}
+	if calls != 1 {
+		t.Errorf("got %d attempts after cancellation, want 1", calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		min, max time.Duration
+	}{
+		{name: "missing", value: ""},
+		{name: "seconds", value: "3", min: 3 * time.Second, max: 3 * time.Second},
+		{name: "zero seconds", value: "0"},
+		{name: "negative seconds", value: "-1"},
+		{name: "invalid", value: "soon"},
+		{name: "past date", value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)},
+		{name: "future date", value: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), min: 58 * time.Second, max: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+				t.Errorf("parseRetryAfter(%q) = %v, want within [%v, %v]", tt.value, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "context deadline", err: fmt.Errorf("export: %w", context.DeadlineExceeded), want: ErrorClassTimeout},
+		{name: "grpc deadline", err: status.Error(codes.DeadlineExceeded, "deadline"), want: ErrorClassTimeout},
+		{name: "net timeout", err: &net.OpError{Op: "read", Err: timeoutError{}}, want: ErrorClassTimeout},
+		{name: "http 4xx", err: fmt.Errorf("export: %w", &StatusError{StatusCode: http.StatusTooManyRequests}), want: ErrorClass4xx},
+		{name: "http 5xx", err: &StatusError{StatusCode: http.StatusBadGateway}, want: ErrorClass5xx},
+		{name: "other http status", err: &StatusError{StatusCode: http.StatusFound}, want: ErrorClassHTTP},
+		{name: "grpc unavailable", err: status.Error(codes.Unavailable, "unavailable"), want: "UNAVAILABLE"},
+		{name: "grpc resource exhausted", err: resourceExhausted(t, time.Second), want: "RESOURCE_EXHAUSTED"},
+		{name: "canceled", err: fmt.Errorf("export: %w", context.Canceled), want: ErrorClassCanceled},
+		{name: "network", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: ErrorClassNetwork},
+		{name: "other", err: errors.New("failed to marshal"), want: ErrorClassOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorClass(tt.err); got != tt.want {
+				t.Errorf("ErrorClass(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGRPCCodeName(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want string
+	}{
+		{code: codes.OK, want: "OK"},
+		{code: codes.Canceled, want: "CANCELED"},
+		{code: codes.Unavailable, want: "UNAVAILABLE"},
+		{code: codes.ResourceExhausted, want: "RESOURCE_EXHAUSTED"},
+		{code: codes.DeadlineExceeded, want: "DEADLINE_EXCEEDED"},
+		{code: codes.InvalidArgument, want: "INVALID_ARGUMENT"},
+		{code: codes.FailedPrecondition, want: "FAILED_PRECONDITION"},
+		{code: codes.DataLoss, want: "DATA_LOSS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := grpcCodeName(tt.code); got != tt.want {
+				t.Errorf("grpcCodeName(%v) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
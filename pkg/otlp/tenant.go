@@ -0,0 +1,21 @@
+package otlp
+
+import "context"
+
+// tenantKey is the context key of a per-request tenant
+type tenantKey struct{}
+
+// WithTenant returns a context whose exports and queries use tenant instead of the client's
+// configured tenant. An empty tenant leaves ctx unchanged.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	if tenant == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the per-request tenant set with WithTenant
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok
+}
@@ -0,0 +1,182 @@
+package tempo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// adaptiveControllers holds the controllers of the workloads running queries in the process, so
+// that reports and metrics can sum the per-VU rates
+var adaptiveControllers sync.Map
+
+// AdaptiveQPSReport summarizes the search for a sustainable query rate
+type AdaptiveQPSReport struct {
+	CurrentQPS          float64 `js:"currentQPS"`          // Rate of this workload
+	SustainableQPS      float64 `js:"sustainableQPS"`      // Highest rate of this workload whose window met the objectives
+	TotalCurrentQPS     float64 `js:"totalCurrentQPS"`     // Sum over the workloads of the process that ran queries
+	TotalSustainableQPS float64 `js:"totalSustainableQPS"` // Sum over the workloads of the process that ran queries
+	Evaluations         int     `js:"evaluations"`         // Windows evaluated by this workload
+	Decreases           int     `js:"decreases"`           // Windows that missed an objective
+}
+
+// adaptiveController adjusts a workload's rate to the highest one that meets a p95 latency and an
+// error-rate objective: the rate grows by a factor while windows meet them and is cut by a
+// larger factor when one misses.
+type adaptiveController struct {
+	config AdaptiveQPSConfig
+
+	mu          sync.Mutex
+	qps         float64
+	sustainable float64
+	windowStart time.Time
+	latencies   []time.Duration
+	failures    int
+	evaluations int
+	decreases   int
+}
+
+// newAdaptiveController creates a controller starting at startQPS; it counts in the totals of the
+// process once registered
+func newAdaptiveController(config AdaptiveQPSConfig, startQPS float64) *adaptiveController {
+	return &adaptiveController{
+		config:      config,
+		qps:         startQPS,
+		windowStart: time.Now(),
+	}
+}
+
+// register adds the controller to the totals of the process
+func (a *adaptiveController) register() {
+	adaptiveControllers.Store(a, struct{}{})
+}
+
+// release removes the controller from the totals of the process
+func (a *adaptiveController) release() {
+	adaptiveControllers.Delete(a)
+}
+
+// validate checks an adaptive config after defaults were applied
+func (c AdaptiveQPSConfig) validate() error {
+	if c.TargetP95Ms <= 0 && c.MaxErrorRate <= 0 {
+		return fmt.Errorf("adaptive: set targetP95Ms or maxErrorRate")
+	}
+	if c.IntervalMs <= 0 {
+		return fmt.Errorf("adaptive: intervalMs must be > 0, got %d", c.IntervalMs)
+	}
+	if c.IncreaseFactor <= 1 {
+		return fmt.Errorf("adaptive: increaseFactor must be > 1, got %g", c.IncreaseFactor)
+	}
+	if c.DecreaseFactor <= 0 || c.DecreaseFactor >= 1 {
+		return fmt.Errorf("adaptive: decreaseFactor must be in (0, 1), got %g", c.DecreaseFactor)
+	}
+	if c.MinQPS <= 0 {
+		return fmt.Errorf("adaptive: minQPS must be > 0, got %g", c.MinQPS)
+	}
+	if c.MaxQPS > 0 && c.MaxQPS < c.MinQPS {
+		return fmt.Errorf("adaptive: maxQPS (%g) must be >= minQPS (%g)", c.MaxQPS, c.MinQPS)
+	}
+	return nil
+}
+
+// observe records the outcome of one query
+func (a *adaptiveController) observe(duration time.Duration, failed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.latencies = append(a.latencies, duration)
+	if failed {
+		a.failures++
+	}
+}
+
+// restart discards the current window and starts a new one at now
+func (a *adaptiveController) restart(now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.windowStart = now
+	a.latencies = a.latencies[:0]
+	a.failures = 0
+}
+
+// evaluate closes the window once it has lasted intervalMs and holds minSamples queries, and
+// returns the new rate. changed is false while the window is still open.
+func (a *adaptiveController) evaluate(now time.Time) (qps float64, changed bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if now.Sub(a.windowStart) < time.Duration(a.config.IntervalMs)*time.Millisecond || len(a.latencies) < a.config.MinSamples {
+		return a.qps, false
+	}
+
+	p95 := percentile(a.latencies, 0.95)
+	errorRate := float64(a.failures) / float64(len(a.latencies))
+	met := (a.config.TargetP95Ms <= 0 || float64(p95.Microseconds())/1000 <= a.config.TargetP95Ms) &&
+		(a.config.MaxErrorRate <= 0 || errorRate <= a.config.MaxErrorRate)
+
+	a.evaluations++
+	if met {
+		a.sustainable = math.Max(a.sustainable, a.qps)
+		a.qps *= a.config.IncreaseFactor
+		if a.config.MaxQPS > 0 {
+			a.qps = math.Min(a.qps, a.config.MaxQPS)
+		}
+	} else {
+		a.decreases++
+		failed := a.qps
+		a.qps = math.Max(a.qps*a.config.DecreaseFactor, a.config.MinQPS)
+		// A rate at or above one that failed is not sustainable, e.g. once the backend degraded
+		if a.sustainable >= failed {
+			a.sustainable = a.qps
+		}
+	}
+
+	a.windowStart = now
+	a.latencies = a.latencies[:0]
+	a.failures = 0
+	return a.qps, true
+}
+
+// report returns this controller's state together with the totals of all controllers
+func (a *adaptiveController) report() *AdaptiveQPSReport {
+	report := &AdaptiveQPSReport{}
+	if a != nil {
+		a.mu.Lock()
+		report.CurrentQPS = a.qps
+		report.SustainableQPS = a.sustainable
+		report.Evaluations = a.evaluations
+		report.Decreases = a.decreases
+		a.mu.Unlock()
+	}
+	report.TotalCurrentQPS, report.TotalSustainableQPS = adaptiveTotals()
+	return report
+}
+
+// adaptiveTotals sums the current and sustainable rates of all controllers
+func adaptiveTotals() (current, sustainable float64) {
+	adaptiveControllers.Range(func(key, _ interface{}) bool {
+		a := key.(*adaptiveController)
+		a.mu.Lock()
+		current += a.qps
+		sustainable += a.sustainable
+		a.mu.Unlock()
+		return true
+	})
+	return current, sustainable
+}
+
+// percentile returns the p-th percentile of durations (nearest rank); it sorts durations in place
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	rank := int(math.Ceil(p*float64(len(durations)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return durations[rank]
+}
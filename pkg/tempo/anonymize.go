@@ -0,0 +1,72 @@
+package tempo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// anonymizedHashLength is the number of hex digits kept of attribute hashes
+const anonymizedHashLength = 16
+
+// anonymizes reports whether the config scrubs replayed traces
+func (c CorpusConfig) anonymizes() bool {
+	return len(c.HashKeys) > 0 || len(c.DropKeyPrefixes) > 0 || len(c.ServiceNames) > 0
+}
+
+// anonymize scrubs the resource, scope, span, event and link attributes of a trace: keys with a
+// dropped prefix are removed, service names are rewritten, then hashed keys get a salted hash of
+// their value. Equal values hash alike, so queries and cardinality keep working on hashed keys.
+func anonymize(traces ptrace.Traces, config CorpusConfig) {
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+		scrubAttributes(rs.Resource().Attributes(), config)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			scrubAttributes(ss.Scope().Attributes(), config)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				scrubAttributes(span.Attributes(), config)
+				for e := 0; e < span.Events().Len(); e++ {
+					scrubAttributes(span.Events().At(e).Attributes(), config)
+				}
+				for l := 0; l < span.Links().Len(); l++ {
+					scrubAttributes(span.Links().At(l).Attributes(), config)
+				}
+			}
+		}
+	}
+}
+
+// scrubAttributes anonymizes one attribute map
+func scrubAttributes(attrs pcommon.Map, config CorpusConfig) {
+	if len(config.DropKeyPrefixes) > 0 {
+		attrs.RemoveIf(func(key string, _ pcommon.Value) bool {
+			for _, prefix := range config.DropKeyPrefixes {
+				if strings.HasPrefix(key, prefix) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if service, ok := attrs.Get("service.name"); ok {
+		if renamed, ok := config.ServiceNames[service.AsString()]; ok {
+			attrs.PutStr("service.name", renamed)
+		}
+	}
+	for _, key := range config.HashKeys {
+		if value, ok := attrs.Get(key); ok {
+			attrs.PutStr(key, hashValue(value.AsString(), config.HashSalt))
+		}
+	}
+}
+
+// hashValue returns a salted, truncated SHA-256 of a value
+func hashValue(value, salt string) string {
+	sum := sha256.Sum256([]byte(salt + value))
+	return hex.EncodeToString(sum[:])[:anonymizedHashLength]
+}
@@ -0,0 +1,84 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+)
+
+// DataLossReport summarizes an end-of-test data-loss audit
+type DataLossReport struct {
+	Recorded        int64    `js:"recorded"`        // Trace IDs recorded during the test
+	Checked         int      `js:"checked"`         // Trace IDs looked up
+	Found           int      `js:"found"`           // Lookups that returned the trace
+	Missing         int      `js:"missing"`         // Lookups that returned 404
+	Errors          int      `js:"errors"`          // Lookups that failed for other reasons (excluded from the ratio)
+	LossRatio       float64  `js:"lossRatio"`       // Missing / (Found + Missing)
+	MissingTraceIDs []string `js:"missingTraceIds"` // Trace IDs Tempo did not return
+}
+
+// AuditDataLoss looks up a random sample of recorded trace IDs and reports how many Tempo no longer returns
+func AuditDataLoss(ctx context.Context, client *QueryClient, registry *TraceRegistry, config DataLossAuditConfig) (*DataLossReport, error) {
+	if client == nil {
+		return nil, fmt.Errorf("query client is required")
+	}
+	if config.SampleSize <= 0 {
+		return nil, fmt.Errorf("sampleSize must be > 0, got %d", config.SampleSize)
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	sample := registry.Sample(config.SampleSize)
+	report := &DataLossReport{
+		Recorded:        registry.Recorded(),
+		Checked:         len(sample),
+		MissingTraceIDs: []string{},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	ids := make(chan TrackedTrace)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tracked := range ids {
+				// Look the trace up in the tenant it was pushed to
+				id := tracked.TraceID
+				_, resp, err := client.getTraceWithHTTP(otlp.WithTenant(ctx, tracked.Tenant), id)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					report.Found++
+				case resp != nil && resp.StatusCode == http.StatusNotFound:
+					report.Missing++
+					report.MissingTraceIDs = append(report.MissingTraceIDs, id)
+				default:
+					report.Errors++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range sample {
+		ids <- id
+	}
+	close(ids)
+	wg.Wait()
+
+	sort.Strings(report.MissingTraceIDs)
+	if report.Found+report.Missing > 0 {
+		report.LossRatio = float64(report.Missing) / float64(report.Found+report.Missing)
+	}
+
+	return report, nil
+}
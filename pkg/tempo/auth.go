@@ -1,6 +1,7 @@
 package tempo
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
 	"strings"
@@ -48,6 +49,24 @@ func ResolveBearerToken(bearerToken, bearerTokenFile string) (string, error) {
 	return "", nil
 }
 
+// ResolveAuthorization resolves the Authorization header value: HTTP basic auth when a
+// username is set, otherwise the bearer token from ResolveBearerToken. It returns an empty
+// string when no credentials are available.
+func ResolveAuthorization(username, password, bearerToken, bearerTokenFile string) (string, error) {
+	if username != "" {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	}
+
+	token, err := ResolveBearerToken(bearerToken, bearerTokenFile)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", nil
+	}
+	return "Bearer " + token, nil
+}
+
 // readTokenFromFile reads a token from a file path
 func readTokenFromFile(path string) (string, error) {
 	data, err := os.ReadFile(path)
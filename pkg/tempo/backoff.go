@@ -0,0 +1,42 @@
+package tempo
+
+import (
+	"sync"
+	"time"
+)
+
+// backoffCoordinators holds one coordinator per query endpoint, shared by the workloads of all VUs
+// with sharedBackoff
+var backoffCoordinators sync.Map
+
+// backoffCoordinator spreads overload signals across VUs: when one workload is throttled, every
+// workload sharing the coordinator pauses until the backoff has passed, like a well-behaved client
+// fleet, instead of each VU waiting for its own 429 or 5xx.
+type backoffCoordinator struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// getBackoffCoordinator returns the coordinator of an endpoint
+func getBackoffCoordinator(endpoint string) *backoffCoordinator {
+	coordinator, _ := backoffCoordinators.LoadOrStore(endpoint, &backoffCoordinator{})
+	return coordinator.(*backoffCoordinator)
+}
+
+// signal pauses all workloads for at least d from now
+func (b *backoffCoordinator) signal(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(b.until) {
+		b.until = until
+	}
+}
+
+// remaining returns how long the shared backoff still lasts
+func (b *backoffCoordinator) remaining() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return time.Until(b.until)
+}
@@ -1,45 +1,174 @@
 package tempo
 
-import "time"
+import (
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+)
 
 // IngestConfig represents the configuration for the Tempo ingestion client
 type IngestConfig struct {
-	Endpoint string `js:"endpoint"`
-	Protocol string `js:"protocol"` // "otlp-http" or "otlp-grpc"
-	Tenant   string `js:"tenant"`
-	Timeout  int    `js:"timeout"` // seconds, default 30
+	Endpoint         string            `js:"endpoint"`
+	Endpoints        []string          `js:"endpoints"`        // Several endpoints, used instead of endpoint; ingestion metrics get an endpoint tag
+	EndpointStrategy string            `js:"endpointStrategy"` // "round-robin" (default) or "failover"
+	Protocol         string            `js:"protocol"`         // "otlp-http", "otlp-grpc", "zipkin-json" or "jaeger-grpc"
+	Tenant           string            `js:"tenant"`
+	Tenants          []string          `js:"tenants"`        // Tenants rotated per push; metrics get a tenant tag
+	TenantStrategy   string            `js:"tenantStrategy"` // "round-robin" (default), "random" or "weighted"
+	TenantWeights    []float64         `js:"tenantWeights"`  // One weight per tenant for the weighted strategy
+	Timeout          int               `js:"timeout"`        // seconds, default 30
+	Encoding         string            `js:"encoding"`       // "protobuf" (default) or "json"; otlp-http only
+	Compression      string            `js:"compression"`    // "none" (default), "gzip" or "zstd"; OTLP protocols only
+	TLS              *TLSConfig        `js:"tls"`            // Client TLS; gRPC uses plaintext unless set or the endpoint starts with https://
+	Headers          map[string]string `js:"headers"`        // Added to every request as HTTP headers or gRPC metadata
+	Retry            RetryConfig       `js:"retry"`          // Retries of failed exports; OTLP protocols only
+	GRPC             *GRPCConfig       `js:"grpc"`           // Connection settings; gRPC protocols only
+	ProxyURL         string            `js:"proxyURL"`       // HTTP proxy, overriding HTTP_PROXY/HTTPS_PROXY; HTTP protocols only
+
+	// Adaptive backoff while Tempo pushes back with HTTP 429 or gRPC RESOURCE_EXHAUSTED
+	EnableBackoff bool `js:"enableBackoff"` // Pause before exports while Tempo pushes back (default: true)
+	MinBackoffMs  int  `js:"minBackoffMs"`  // First pause when Tempo gives no Retry-After (default: 200)
+	MaxBackoffMs  int  `js:"maxBackoffMs"`  // Longest pause (default: 30000)
+
+	// Background export queue
+	QueueSize       int `js:"queueSize"`       // Max buffered spans; enables asynchronous pushes (default: 0, synchronous)
+	FlushIntervalMs int `js:"flushIntervalMs"` // Interval at which queued spans are exported (default: 5000)
+	MaxBatchBytes   int `js:"maxBatchBytes"`   // Max size of a queued export request; full batches are exported right away (default: 1MiB)
+
+	// Streamed pushes
+	StreamChunkBytes    int `js:"streamChunkBytes"`    // Size of the chunks pushStream generates and sends (default: 1MiB)
+	HugeTraceChunkSpans int `js:"hugeTraceChunkSpans"` // Spans per export request of pushHugeTrace (default: 2000)
+
+	// Late-arriving spans
+	LateSpanRate     float64 `js:"lateSpanRate"`     // Share of pushed traces whose spans arrive in two pieces (default: 0, disabled)
+	LateSpanFraction float64 `js:"lateSpanFraction"` // Probability each non-root span of such a trace is sent late (default: 0.3)
+	LateDelayMinMs   int     `js:"lateDelayMinMs"`   // Min delay of late spans (default: 30000)
+	LateDelayMaxMs   int     `js:"lateDelayMaxMs"`   // Max delay of late spans (default: 120000)
+
+	// Authentication
+	BearerToken     string        `js:"bearerToken"`     // Direct bearer token string (optional override)
+	BearerTokenFile string        `js:"bearerTokenFile"` // Path to bearer token file (optional override)
+	Username        string        `js:"username"`        // Basic auth username; takes precedence over bearer tokens
+	Password        string        `js:"password"`        // Basic auth password
+	OAuth2          *OAuth2Config `js:"oauth2"`          // Client-credentials token, refreshed automatically; overrides the options above
 
 	// Test context for metric tagging
 	TestName   string  `js:"testName"`   // Test name for metric tags
 	TargetQPS  int     `js:"targetQPS"`  // Target QPS for metric tags
 	TargetMBps float64 `js:"targetMBps"` // Target MB/s for metric tags
+
+	// Trace ID tracking for end-of-test audits
+	TrackTraceIDs   bool    `js:"trackTraceIds"`   // Record pushed trace IDs in the shared registry
+	TrackSampleRate float64 `js:"trackSampleRate"` // Fraction of pushed traces to record (default: 1.0)
 }
 
 // DefaultIngestConfig returns a config with sensible defaults
 func DefaultIngestConfig() IngestConfig {
 	return IngestConfig{
-		Endpoint: "http://localhost:4318",
-		Protocol: "otlp-http",
-		Timeout:  30,
+		Endpoint:            "http://localhost:4318",
+		EndpointStrategy:    EndpointRoundRobin,
+		Protocol:            "otlp-http",
+		Timeout:             30,
+		Encoding:            otlp.EncodingProtobuf,
+		Compression:         otlp.CompressionNone,
+		Retry:               DefaultRetryConfig(),
+		EnableBackoff:       true,
+		MinBackoffMs:        200,
+		MaxBackoffMs:        30000,
+		FlushIntervalMs:     int(defaultFlushInterval / time.Millisecond),
+		MaxBatchBytes:       defaultMaxBatchBytes,
+		StreamChunkBytes:    defaultStreamChunkBytes,
+		HugeTraceChunkSpans: defaultHugeTraceChunkSpans,
+		LateSpanFraction:    defaultLateSpanFraction,
+		LateDelayMinMs:      int(defaultLateDelayMin / time.Millisecond),
+		LateDelayMaxMs:      int(defaultLateDelayMax / time.Millisecond),
+		TrackSampleRate:     1.0,
 	}
 }
 
+// RetryConfig represents retry settings for failed exports and query requests
+type RetryConfig struct {
+	MaxRetries           int      `js:"maxRetries"`           // Retries after the first attempt (default: 0, disabled)
+	InitialBackoff       string   `js:"initialBackoff"`       // Delay before the first retry, doubled for every further retry (default: "100ms")
+	MaxBackoff           string   `js:"maxBackoff"`           // Upper bound for the delay (default: "5s")
+	RetryableStatusCodes []int    `js:"retryableStatusCodes"` // HTTP status codes to retry (default: [429, 502, 503, 504])
+	RetryableGRPCCodes   []string `js:"retryableGrpcCodes"`   // gRPC codes to retry (default: ["UNAVAILABLE", "RESOURCE_EXHAUSTED", "ABORTED", "DEADLINE_EXCEEDED"])
+}
+
+// DefaultRetryConfig returns a config with sensible defaults
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:           0,
+		InitialBackoff:       "100ms",
+		MaxBackoff:           "5s",
+		RetryableStatusCodes: []int{429, 502, 503, 504},
+		RetryableGRPCCodes:   []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED", "ABORTED", "DEADLINE_EXCEEDED"},
+	}
+}
+
+// GRPCConfig represents connection settings for the gRPC protocols
+type GRPCConfig struct {
+	KeepaliveTime    string `js:"keepaliveTime"`    // Ping interval on idle connections, e.g. "30s" (default: no pings)
+	KeepaliveTimeout string `js:"keepaliveTimeout"` // Wait for a ping ack before closing the connection (default: "20s")
+	MaxMessageSize   int    `js:"maxMessageSize"`   // Max send and receive message size in bytes (default: 4MiB receive, unlimited send)
+	NumConnections   int    `js:"numConnections"`   // Connections per endpoint, used in turn (default: 1)
+	LoadBalancing    string `js:"loadBalancing"`    // "pick_first" (default) or "round_robin" over the addresses the endpoint resolves to
+	DialTimeout      string `js:"dialTimeout"`      // Wait this long for connections when the client is created, failing it otherwise (default: connect on first push)
+}
+
 // QueryConfig represents the configuration for the Tempo query client
 type QueryConfig struct {
-	Endpoint string `js:"endpoint"`
-	Tenant   string `js:"tenant"`
-	Timeout  int    `js:"timeout"` // seconds, default 30
+	Endpoint string     `js:"endpoint"`
+	Tenant   string     `js:"tenant"`
+	Timeout  int        `js:"timeout"`  // seconds, default 30
+	TLS      *TLSConfig `js:"tls"`      // Client TLS for https:// endpoints
+	ProxyURL string     `js:"proxyURL"` // HTTP proxy, overriding HTTP_PROXY/HTTPS_PROXY
+
+	// StreamingQuerier gRPC endpoint used by streamingSearch, e.g. "tempo-query-frontend:9095"
+	// (default: the host and port of endpoint, for Tempo serving gRPC streams over HTTP)
+	GRPCEndpoint string `js:"grpcEndpoint"`
+
+	// Retries of failed HTTP requests, like a Grafana data source; separate from the workload's adaptive backoff
+	Retry RetryConfig `js:"retry"`
+
+	// Tenant rotation
+	Tenants        []string  `js:"tenants"`        // Tenants rotated per request; workload metrics get a tenant tag
+	TenantStrategy string    `js:"tenantStrategy"` // "round-robin" (default), "random" or "weighted"
+	TenantWeights  []float64 `js:"tenantWeights"`  // One weight per tenant for the weighted strategy
 
 	// Authentication
-	BearerToken     string `js:"bearerToken"`     // Direct bearer token string (optional override)
-	BearerTokenFile string `js:"bearerTokenFile"` // Path to bearer token file (optional override)
+	BearerToken     string        `js:"bearerToken"`     // Direct bearer token string (optional override)
+	BearerTokenFile string        `js:"bearerTokenFile"` // Path to bearer token file (optional override)
+	OAuth2          *OAuth2Config `js:"oauth2"`          // Client-credentials token, refreshed automatically; overrides the bearer token
+
+	// Validation
+	ValidateStructure bool   `js:"validateStructure"` // Check the structure of every fetched trace
+	ValidateSchema    bool   `js:"validateSchema"`    // Check JSON responses for unknown and missing fields
+	SchemaVersion     string `js:"schemaVersion"`     // Tempo version whose response schemas are used (default: "2.7")
+
+	// Trace by ID
+	TraceAPIVersion string `js:"traceAPIVersion"` // "v1" (default, /api/traces/{id}) or "v2" (/api/v2/traces/{id}, Tempo 2.7+)
+	TraceFormat     string `js:"traceFormat"`     // "json" (default) or "protobuf", decoded as OTLP data without parsing JSON
+
+	// Test context for metric tagging
+	TestName string `js:"testName"` // Test name for metric tags
 }
 
+// Trace-by-ID API versions and response formats
+const (
+	TraceAPIVersionV1   = "v1"
+	TraceAPIVersionV2   = "v2"
+	TraceFormatJSON     = "json"
+	TraceFormatProtobuf = "protobuf"
+)
+
 // DefaultQueryConfig returns a config with sensible defaults
 func DefaultQueryConfig() QueryConfig {
 	return QueryConfig{
-		Endpoint: "http://localhost:3200",
-		Timeout:  30,
+		Endpoint:      "http://localhost:3200",
+		Timeout:       30,
+		Retry:         DefaultRetryConfig(),
+		SchemaVersion: DefaultSchemaVersion,
 	}
 }
 
@@ -50,11 +179,23 @@ type QueryWorkloadConfig struct {
 	BurstMultiplier float64 `js:"burstMultiplier"` // Burst multiplier (default: 2.0)
 	QPSMultiplier   float64 `js:"qpsMultiplier"`   // QPS multiplier for compensation (default: 1.0)
 
+	// Ramping schedule; the workload adjusts its own rate, independent of k6 stages
+	Stages []QPSStage `js:"stages"`
+
+	// Warm-up phase before the measured one, so cold-cache latencies stay out of steady-state percentiles
+	WarmupDurationMs      int     `js:"warmupDurationMs"`      // Warm-up length in ms (default: 0, no warm-up)
+	WarmupQPS             float64 `js:"warmupQPS"`             // Target QPS during warm-up (default: targetQPS)
+	SuppressWarmupMetrics bool    `js:"suppressWarmupMetrics"` // Drop warm-up metrics instead of tagging them phase:warmup
+
+	// Closed-loop rate control; raises and lowers the rate to find the highest QPS that meets the objectives
+	Adaptive *AdaptiveQPSConfig `js:"adaptive"`
+
 	// Backoff configuration
 	EnableBackoff bool `js:"enableBackoff"` // Enable adaptive backoff (default: true)
 	MinBackoffMs  int  `js:"minBackoffMs"`  // Minimum backoff in ms (default: 200)
 	MaxBackoffMs  int  `js:"maxBackoffMs"`  // Maximum backoff in ms (default: 30000)
 	BackoffJitter bool `js:"backoffJitter"` // Add jitter to backoff (default: true)
+	SharedBackoff bool `js:"sharedBackoff"` // Back off all VUs querying the same endpoint when one is throttled (default: false)
 
 	// Time buckets for query distribution
 	TimeBuckets []TimeBucketConfig `js:"timeBuckets"`
@@ -64,9 +205,48 @@ type QueryWorkloadConfig struct {
 
 	// Search and fetch workflow
 	TraceFetchProbability float64 `js:"traceFetchProbability"` // Probability of fetching trace after search (0.0-1.0, default: 0.1)
+	TraceFetchCount       int     `js:"traceFetchCount"`       // Traces fetched from the top of the results (default: 1)
+	TraceFetchConcurrency int     `js:"traceFetchConcurrency"` // Parallel trace fetches (default: 1)
+
+	// Known trace fetches: minimum age of trace IDs drawn from the registry, e.g. to read traces already flushed to blocks
+	KnownTraceMinAgeMs int `js:"knownTraceMinAgeMs"`
 
 	// Time window jitter
 	TimeWindowJitterMs int `js:"timeWindowJitterMs"` // Jitter to add to time windows in ms (default: 0)
+
+	// Query templating: ${name} placeholders take a value from these lists, or else from the generator's cardinality pools
+	TemplateValues map[string][]string `js:"templateValues"`
+}
+
+// QPSStage ramps the target QPS linearly from the previous stage's target (or targetQPS) over its duration
+type QPSStage struct {
+	DurationMs int     `js:"durationMs"` // Ramp duration in ms; 0 switches to the target at once
+	TargetQPS  float64 `js:"targetQPS"`  // Target queries per second at the end of the stage
+}
+
+// AdaptiveQPSConfig configures the search for the highest sustainable query rate. Rates are in the
+// units of targetQPS, which is the starting rate; qpsMultiplier still applies.
+type AdaptiveQPSConfig struct {
+	TargetP95Ms    float64 `js:"targetP95Ms"`    // p95 latency objective in ms; 0 ignores latency
+	MaxErrorRate   float64 `js:"maxErrorRate"`   // Error-rate objective (0.0-1.0, default: 0.01); 0 ignores errors
+	IntervalMs     int     `js:"intervalMs"`     // Evaluation window in ms (default: 10000)
+	MinSamples     int     `js:"minSamples"`     // Queries a window needs before it is evaluated (default: 20)
+	IncreaseFactor float64 `js:"increaseFactor"` // Rate multiplier after a window met the objectives (default: 1.1)
+	DecreaseFactor float64 `js:"decreaseFactor"` // Rate multiplier after a window missed one (default: 0.7)
+	MinQPS         float64 `js:"minQPS"`         // Lower bound of the rate (default: 0.1)
+	MaxQPS         float64 `js:"maxQPS"`         // Upper bound of the rate (default: 0, unbounded)
+}
+
+// DefaultAdaptiveQPSConfig returns an adaptive config with sensible defaults
+func DefaultAdaptiveQPSConfig() AdaptiveQPSConfig {
+	return AdaptiveQPSConfig{
+		MaxErrorRate:   0.01,
+		IntervalMs:     10000,
+		MinSamples:     20,
+		IncreaseFactor: 1.1,
+		DecreaseFactor: 0.7,
+		MinQPS:         0.1,
+	}
 }
 
 // TimeBucketConfig represents a time bucket for query distribution
@@ -74,22 +254,45 @@ type TimeBucketConfig struct {
 	Name     string  `js:"name"`     // Bucket name/identifier
 	AgeStart string  `js:"ageStart"` // Start age (e.g., "1h", "30m")
 	AgeEnd   string  `js:"ageEnd"`   // End age (e.g., "2h", "1h")
-	Weight   float64 `js:"weight"`   // Weight for selection (default: 1.0)
+	Weight   float64 `js:"weight"`   // Weight for selection by plan entries without a bucket (default: 1.0)
+
+	Headers map[string]string `js:"headers"` // Extra HTTP headers of queries in this bucket
 }
 
 // PlanEntry represents an entry in the execution plan
 type PlanEntry struct {
 	QueryName  string  `js:"queryName"`  // Name of the query to execute
-	BucketName string  `js:"bucketName"` // Name of the time bucket to use
+	BucketName string  `js:"bucketName"` // Name of the time bucket to use; empty picks one by bucket weight
 	Weight     float64 `js:"weight"`     // Weight for selection (default: 1.0)
+
+	// Operation to run; "search" (default) runs the query by its type. traceByID needs no query.
+	OperationType string `js:"operationType"`
 }
 
+// Plan entry operation types; query metrics are tagged with the operation
+const (
+	OperationSearch       = "search"       // The query definition, a search or an instant metrics query
+	OperationSearchTags   = "searchTags"   // Tag names via /api/v2/search/tags, filtered by the query
+	OperationTagValues    = "tagValues"    // Values of the query's tag via /api/v2/search/tag/{tag}/values
+	OperationMetricsRange = "metricsRange" // Range TraceQL metrics query via /api/metrics/query_range
+	OperationTraceByID    = "traceByID"    // Trace by ID of a trace recorded by ingest clients with trackTraceIds
+)
+
+// Query definition types
+const (
+	QueryTypeSearch         = "search"         // TraceQL search via /api/search
+	QueryTypeMetricsInstant = "metricsInstant" // Instant TraceQL metrics query via /api/metrics/query
+)
+
 // QueryDefinition represents a query definition
 type QueryDefinition struct {
 	Name    string                 `js:"name"`    // Query name/identifier
-	Query   string                 `js:"query"`   // TraceQL query string
+	Type    string                 `js:"type"`    // "search" (default) or "metricsInstant"
+	Query   string                 `js:"query"`   // TraceQL query string; ${name} placeholders are resolved on every run
 	Limit   int                    `js:"limit"`   // Result limit (default: 20)
-	Options map[string]interface{} `js:"options"` // Additional options
+	Options map[string]interface{} `js:"options"` // Extra query parameters, e.g. {"spss": 10, "mostRecent": true}
+	Headers map[string]string      `js:"headers"` // Extra HTTP headers, e.g. {"Cache-Control": "no-cache"}; they override bucket headers
+	Tag     string                 `js:"tag"`     // Tag whose values tagValues operations list, e.g. "resource.service.name"
 }
 
 // DefaultQueryWorkloadConfig returns a config with sensible defaults
@@ -103,6 +306,8 @@ func DefaultQueryWorkloadConfig() QueryWorkloadConfig {
 		MaxBackoffMs:          30000,
 		BackoffJitter:         true,
 		TraceFetchProbability: 0.1,
+		TraceFetchCount:       1,
+		TraceFetchConcurrency: 1,
 		TimeWindowJitterMs:    0,
 		TimeBuckets: []TimeBucketConfig{
 			{
@@ -145,3 +350,147 @@ func (tb *TimeBucketConfig) ParseTimeRanges(elapsed time.Duration) (start time.T
 
 	return start, end, true, nil
 }
+
+// VerifierConfig represents configuration for read-after-write verification
+type VerifierConfig struct {
+	PollInterval string `js:"pollInterval"` // Delay between trace lookups (default: "1s")
+	Timeout      string `js:"timeout"`      // Give up after this long, counted from the first lookup (default: "30s")
+	Delay        string `js:"delay"`        // Wait after the push before the first lookup (default: "0s")
+	Search       bool   `js:"search"`       // Also require a TraceQL search for the marker to return the trace (default: false)
+}
+
+// DefaultVerifierConfig returns a config with sensible defaults
+func DefaultVerifierConfig() VerifierConfig {
+	return VerifierConfig{
+		PollInterval: "1s",
+		Timeout:      "30s",
+		Delay:        "0s",
+	}
+}
+
+// QueryableProbeConfig represents configuration for time-to-queryable measurements
+type QueryableProbeConfig struct {
+	PollInterval    string `js:"pollInterval"`    // Wait before the first lookup, doubled after every miss (default: "250ms")
+	MaxPollInterval string `js:"maxPollInterval"` // Upper bound for the wait between lookups (default: "5s")
+	MaxAttempts     int    `js:"maxAttempts"`     // Lookups before the trace counts as not queryable (default: 20)
+}
+
+// DefaultQueryableProbeConfig returns a config with sensible defaults
+func DefaultQueryableProbeConfig() QueryableProbeConfig {
+	return QueryableProbeConfig{
+		PollInterval:    "250ms",
+		MaxPollInterval: "5s",
+		MaxAttempts:     20,
+	}
+}
+
+// DataLossAuditConfig represents configuration for the end-of-test data-loss audit
+type DataLossAuditConfig struct {
+	SampleSize  int `js:"sampleSize"`  // Number of recorded trace IDs to look up (default: 100)
+	Concurrency int `js:"concurrency"` // Parallel lookups (default: 4)
+}
+
+// DefaultDataLossAuditConfig returns a config with sensible defaults
+func DefaultDataLossAuditConfig() DataLossAuditConfig {
+	return DataLossAuditConfig{
+		SampleSize:  100,
+		Concurrency: 4,
+	}
+}
+
+// MetricsGeneratorValidationConfig represents configuration for validating metrics-generator output
+type MetricsGeneratorValidationConfig struct {
+	Endpoint        string `js:"endpoint"` // Prometheus-compatible API base URL (e.g. "http://mimir:8080/prometheus")
+	Tenant          string `js:"tenant"`
+	Timeout         int    `js:"timeout"` // seconds, default 30
+	BearerToken     string `js:"bearerToken"`
+	BearerTokenFile string `js:"bearerTokenFile"`
+
+	TracesPerSecond float64 `js:"tracesPerSecond"` // Trace rate pushed by the test, used to compute expected rates
+	Window          string  `js:"window"`          // Rate window (default: "5m")
+	Tolerance       float64 `js:"tolerance"`       // Allowed relative deviation (default: 0.1)
+
+	// PromQL templates; %s is replaced with the rate window
+	SpanMetricsQuery  string `js:"spanMetricsQuery"`  // Must return one series per "service" label
+	ServiceGraphQuery string `js:"serviceGraphQuery"` // Must return one series per "client"/"server" label pair
+}
+
+// DefaultMetricsGeneratorValidationConfig returns a config with sensible defaults
+func DefaultMetricsGeneratorValidationConfig() MetricsGeneratorValidationConfig {
+	return MetricsGeneratorValidationConfig{
+		Endpoint:          "http://localhost:9090",
+		Timeout:           30,
+		Window:            "5m",
+		Tolerance:         0.1,
+		SpanMetricsQuery:  "sum by (service) (rate(traces_spanmetrics_calls_total[%s]))",
+		ServiceGraphQuery: "sum by (client, server) (rate(traces_service_graph_request_total[%s]))",
+	}
+}
+
+// KnownAnswerConfig represents configuration for known-answer TraceQL checks
+type KnownAnswerConfig struct {
+	Traces       int    `js:"traces"`       // Number of traces in the corpus (default: 50)
+	Seed         int64  `js:"seed"`         // Seed for the corpus attributes (default: 1)
+	PollInterval string `js:"pollInterval"` // Delay between check rounds while results converge (default: "2s")
+	Timeout      string `js:"timeout"`      // Give up after this long; "0s" runs the catalog once (default: "60s")
+}
+
+// DefaultKnownAnswerConfig returns a config with sensible defaults
+func DefaultKnownAnswerConfig() KnownAnswerConfig {
+	return KnownAnswerConfig{
+		Traces:       50,
+		Seed:         1,
+		PollInterval: "2s",
+		Timeout:      "60s",
+	}
+}
+
+// RetentionConfig represents configuration for backend/retention validation
+type RetentionConfig struct {
+	Traces int      `js:"traces"` // Marker traces ingested per cohort (default: 20)
+	Ages   []string `js:"ages"`   // Cohort ages at which to check retrieval and search (default: ["1m", "15m", "45m", "2h"])
+}
+
+// DefaultRetentionConfig returns a config with sensible defaults.
+// The default ages cover the ingester/WAL, freshly flushed blocks and compacted blocks
+// for Tempo's default flush and compaction windows.
+func DefaultRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		Traces: 20,
+		Ages:   []string{"1m", "15m", "45m", "2h"},
+	}
+}
+
+// ReplayConfig represents configuration for replaying a recorded query log
+type ReplayConfig struct {
+	Path  string  `js:"path"`  // JSON lines file of recorded queries
+	Speed float64 `js:"speed"` // Replay speed relative to the recording; 0 replays without waiting (default: 1)
+	Loop  bool    `js:"loop"`  // Start over after the last query instead of stopping
+}
+
+// DefaultReplayConfig returns a config that replays at the recorded pace once
+func DefaultReplayConfig() ReplayConfig {
+	return ReplayConfig{
+		Speed: 1,
+	}
+}
+
+// CorpusConfig represents configuration for replaying a trace corpus
+type CorpusConfig struct {
+	Retime        bool `js:"retime"`        // Shift timestamps so each replayed trace ends at the time it is replayed (default: true)
+	RegenerateIDs bool `js:"regenerateIds"` // Give each replayed trace new trace and span IDs, so replays never collide (default: true)
+
+	// Anonymization of replayed traces
+	HashKeys        []string          `js:"hashKeys"`        // Attributes whose values are replaced by a hash (default: none)
+	HashSalt        string            `js:"hashSalt"`        // Prepended to values before hashing, so hashes cannot be matched against known values (default: "")
+	DropKeyPrefixes []string          `js:"dropKeyPrefixes"` // Attributes removed when their key starts with one of these (default: none)
+	ServiceNames    map[string]string `js:"serviceNames"`    // Service name rewrites, original to replayed name (default: none)
+}
+
+// DefaultCorpusConfig returns a config that replays traces as if they just happened
+func DefaultCorpusConfig() CorpusConfig {
+	return CorpusConfig{
+		Retime:        true,
+		RegenerateIDs: true,
+	}
+}
@@ -0,0 +1,356 @@
+package tempo
+
+import (
+	"bytes"
+	cryptoRand "crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/generator"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// traceCorpus holds the traces of a corpus and the replay position, shared by all VUs replaying it
+type traceCorpus struct {
+	traces []ptrace.Traces
+	next   atomic.Uint64
+}
+
+// traceCorpora holds the parsed corpora by path, so each file is read once per test
+var traceCorpora sync.Map
+
+// generatedCorpora holds the pre-generated corpora by name, so each is generated once per test
+var generatedCorpora sync.Map
+
+// defaultCorpusTemplates is the number of template traces of a pre-generated corpus
+const defaultCorpusTemplates = 100
+
+// TraceCorpus replays traces exported from a real system, e.g. by the OpenTelemetry Collector
+// file exporter, or pre-generated templates. Every replayed trace is a copy, anonymized, re-timed
+// and with new IDs as configured, ready to push with an IngestClient. All VUs loading the same
+// corpus share one position.
+type TraceCorpus struct {
+	corpus *traceCorpus
+	config CorpusConfig
+}
+
+// LoadTraceCorpus loads the OTLP traces in path, a file or a directory of files
+func LoadTraceCorpus(path string, config CorpusConfig) (*TraceCorpus, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+
+	corpus, ok := traceCorpora.Load(path)
+	if !ok {
+		traces, err := loadTraceFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		corpus, _ = traceCorpora.LoadOrStore(path, &traceCorpus{traces: traces})
+	}
+
+	return &TraceCorpus{
+		corpus: corpus.(*traceCorpus),
+		config: config,
+	}, nil
+}
+
+// GenerateTraceCorpus generates a corpus of template traces under name, or returns the corpus
+// generated before under that name. Replaying templates is much cheaper than generating traces,
+// which lets a load generator reach higher ingest rates.
+func GenerateTraceCorpus(name string, templates int, traceConfig generator.Config, config CorpusConfig) (*TraceCorpus, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if templates <= 0 {
+		return nil, fmt.Errorf("templates must be > 0, got %d", templates)
+	}
+
+	corpus, ok := generatedCorpora.Load(name)
+	if !ok {
+		batch := generator.GenerateBatchResult(generator.BatchConfig{TargetTraces: templates, TraceConfig: traceConfig})
+		corpus, _ = generatedCorpora.LoadOrStore(name, &traceCorpus{traces: batch.Traces})
+	}
+
+	return &TraceCorpus{
+		corpus: corpus.(*traceCorpus),
+		config: config,
+	}, nil
+}
+
+// loadTraceFiles reads the traces of a file, or of the files of a directory in name order
+func loadTraceFiles(path string) ([]ptrace.Traces, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trace corpus: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trace corpus: %w", err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	var traces []ptrace.Traces
+	for _, file := range files {
+		payloads, err := readTraceFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, payload := range payloads {
+			traces = append(traces, splitTraces(payload)...)
+		}
+	}
+	if len(traces) == 0 {
+		return nil, fmt.Errorf("trace corpus %s has no spans", path)
+	}
+	return traces, nil
+}
+
+// readTraceFile reads the OTLP payloads of a file. Files ending in .json, .jsonl or .ndjson hold
+// JSON payloads, one after the other; other files hold protobuf payloads, either one payload or,
+// like the collector file exporter writes them, payloads prefixed by their 4-byte big-endian length.
+func readTraceFile(path string) ([]ptrace.Traces, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trace corpus: %w", err)
+	}
+
+	var payloads []ptrace.Traces
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonl", ".ndjson":
+		unmarshaler := &ptrace.JSONUnmarshaler{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			payload, err := unmarshaler.UnmarshalTraces(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			payloads = append(payloads, payload)
+		}
+	default:
+		unmarshaler := &ptrace.ProtoUnmarshaler{}
+		// A payload starts with field 1 (0x0a); a length prefix with 0, as payloads are below 16MB
+		if len(data) > 0 && data[0] != 0 {
+			payload, err := unmarshaler.UnmarshalTraces(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			return []ptrace.Traces{payload}, nil
+		}
+		for offset := 0; offset < len(data); {
+			if len(data)-offset < 4 {
+				return nil, fmt.Errorf("failed to parse %s: truncated length prefix at byte %d", path, offset)
+			}
+			size := int(binary.BigEndian.Uint32(data[offset:]))
+			offset += 4
+			if len(data)-offset < size {
+				return nil, fmt.Errorf("failed to parse %s: truncated payload at byte %d", path, offset)
+			}
+			payload, err := unmarshaler.UnmarshalTraces(data[offset : offset+size])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			payloads = append(payloads, payload)
+			offset += size
+		}
+	}
+	return payloads, nil
+}
+
+// splitTraces splits a payload into one payload per trace, in order of first appearance, keeping
+// the resource and scope of every span
+func splitTraces(payload ptrace.Traces) []ptrace.Traces {
+	type scopeKey struct {
+		resource, scope int
+	}
+	type split struct {
+		traces    ptrace.Traces
+		resources map[int]ptrace.ResourceSpans
+		scopes    map[scopeKey]ptrace.ScopeSpans
+	}
+
+	var order []pcommon.TraceID
+	splits := make(map[pcommon.TraceID]*split)
+	for i := 0; i < payload.ResourceSpans().Len(); i++ {
+		rs := payload.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				s, ok := splits[span.TraceID()]
+				if !ok {
+					s = &split{
+						traces:    ptrace.NewTraces(),
+						resources: make(map[int]ptrace.ResourceSpans),
+						scopes:    make(map[scopeKey]ptrace.ScopeSpans),
+					}
+					splits[span.TraceID()] = s
+					order = append(order, span.TraceID())
+				}
+
+				resource, ok := s.resources[i]
+				if !ok {
+					resource = s.traces.ResourceSpans().AppendEmpty()
+					rs.Resource().CopyTo(resource.Resource())
+					resource.SetSchemaUrl(rs.SchemaUrl())
+					s.resources[i] = resource
+				}
+				key := scopeKey{resource: i, scope: j}
+				scope, ok := s.scopes[key]
+				if !ok {
+					scope = resource.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(scope.Scope())
+					scope.SetSchemaUrl(ss.SchemaUrl())
+					s.scopes[key] = scope
+				}
+				span.CopyTo(scope.Spans().AppendEmpty())
+			}
+		}
+	}
+
+	traces := make([]ptrace.Traces, 0, len(order))
+	for _, id := range order {
+		traces = append(traces, splits[id].traces)
+	}
+	return traces
+}
+
+// replay returns a copy of a trace of the corpus, anonymized, re-timed and with new IDs as configured
+func (c *TraceCorpus) replay(trace ptrace.Traces) (ptrace.Traces, error) {
+	replayed := ptrace.NewTraces()
+	trace.CopyTo(replayed)
+	if c.config.anonymizes() {
+		anonymize(replayed, c.config)
+	}
+
+	var shift time.Duration
+	if c.config.Retime {
+		var end pcommon.Timestamp
+		forEachSpan(replayed, func(span ptrace.Span) {
+			end = max(end, span.EndTimestamp())
+		})
+		shift = time.Since(end.AsTime())
+	}
+
+	if c.config.RegenerateIDs {
+		if err := regenerateIDs(replayed); err != nil {
+			return ptrace.Traces{}, err
+		}
+	}
+
+	if shift != 0 {
+		forEachSpan(replayed, func(span ptrace.Span) {
+			span.SetStartTimestamp(shiftTimestamp(span.StartTimestamp(), shift))
+			span.SetEndTimestamp(shiftTimestamp(span.EndTimestamp(), shift))
+			for i := 0; i < span.Events().Len(); i++ {
+				event := span.Events().At(i)
+				event.SetTimestamp(shiftTimestamp(event.Timestamp(), shift))
+			}
+		})
+	}
+	return replayed, nil
+}
+
+// regenerateIDs gives every trace of a payload a new trace ID and every span a new span ID.
+// Parents and links within the payload follow the spans they point to.
+func regenerateIDs(traces ptrace.Traces) error {
+	traceIDs := make(map[pcommon.TraceID]pcommon.TraceID)
+	spanIDs := make(map[pcommon.SpanID]pcommon.SpanID, traces.SpanCount())
+	forEachSpan(traces, func(span ptrace.Span) {
+		traceIDs[span.TraceID()] = pcommon.TraceID{}
+		spanIDs[span.SpanID()] = pcommon.SpanID{}
+	})
+
+	// Draw all IDs at once
+	random := make([]byte, 16*len(traceIDs)+8*len(spanIDs))
+	if _, err := cryptoRand.Read(random); err != nil {
+		return fmt.Errorf("failed to generate IDs: %w", err)
+	}
+	for original := range traceIDs {
+		var id pcommon.TraceID
+		random = random[copy(id[:], random):]
+		traceIDs[original] = id
+	}
+	for original := range spanIDs {
+		var id pcommon.SpanID
+		random = random[copy(id[:], random):]
+		spanIDs[original] = id
+	}
+
+	forEachSpan(traces, func(span ptrace.Span) {
+		span.SetTraceID(traceIDs[span.TraceID()])
+		span.SetSpanID(spanIDs[span.SpanID()])
+		if parent, ok := spanIDs[span.ParentSpanID()]; ok && !span.ParentSpanID().IsEmpty() {
+			span.SetParentSpanID(parent)
+		}
+		for i := 0; i < span.Links().Len(); i++ {
+			link := span.Links().At(i)
+			if traceID, ok := traceIDs[link.TraceID()]; ok {
+				link.SetTraceID(traceID)
+				if spanID, ok := spanIDs[link.SpanID()]; ok {
+					link.SetSpanID(spanID)
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// forEachSpan calls fn for every span of a payload
+func forEachSpan(traces ptrace.Traces, fn func(ptrace.Span)) {
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		scopes := traces.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopes.Len(); j++ {
+			spans := scopes.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				fn(spans.At(k))
+			}
+		}
+	}
+}
+
+// shiftTimestamp moves a timestamp by shift, leaving unset timestamps unset
+func shiftTimestamp(ts pcommon.Timestamp, shift time.Duration) pcommon.Timestamp {
+	if ts == 0 {
+		return 0
+	}
+	return pcommon.NewTimestampFromTime(ts.AsTime().Add(shift))
+}
+
+// JavaScript-friendly wrapper methods (exported, no context parameter required)
+
+// Next returns the next trace of the corpus, starting over after the last one (JavaScript-friendly)
+func (c *TraceCorpus) Next() (ptrace.Traces, error) {
+	index := c.corpus.next.Add(1) - 1
+	return c.replay(c.corpus.traces[index%uint64(len(c.corpus.traces))])
+}
+
+// Len returns the number of traces in the corpus (JavaScript-friendly)
+func (c *TraceCorpus) Len() int {
+	return len(c.corpus.traces)
+}
@@ -0,0 +1,56 @@
+package tempo
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// countDuplicateSpans returns the number of spans in a fetched trace and how many of them
+// repeat a span ID already seen in the same or another batch
+func (t *Trace) countDuplicateSpans() (spans, duplicates int) {
+	seen := make(map[string]struct{})
+	for _, batch := range t.Batches {
+		for _, scopeSpans := range batch.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				spans++
+				if _, ok := seen[span.SpanID]; ok {
+					duplicates++
+					continue
+				}
+				seen[span.SpanID] = struct{}{}
+			}
+		}
+	}
+	return spans, duplicates
+}
+
+// countDuplicateSpansOTLP is countDuplicateSpans for traces fetched as OTLP data
+func countDuplicateSpansOTLP(trace ptrace.Traces) (spans, duplicates int) {
+	seen := make(map[pcommon.SpanID]struct{})
+	for i := 0; i < trace.ResourceSpans().Len(); i++ {
+		scopeSpans := trace.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spanSlice := scopeSpans.At(j).Spans()
+			for k := 0; k < spanSlice.Len(); k++ {
+				spans++
+				id := spanSlice.At(k).SpanID()
+				if _, ok := seen[id]; ok {
+					duplicates++
+					continue
+				}
+				seen[id] = struct{}{}
+			}
+		}
+	}
+	return spans, duplicates
+}
+
+// recordDuplicates records duplicate span metrics for a fetched trace
+func (c *QueryClient) recordDuplicates(spans, duplicates int) {
+	if spans == 0 {
+		return
+	}
+	if state := c.vu.State(); state != nil {
+		RecordTraceDuplicates(state, c.metrics, duplicates)
+	}
+}
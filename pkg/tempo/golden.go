@@ -0,0 +1,67 @@
+package tempo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// goldenCache holds parsed golden fixtures so comparisons inside iterations don't re-read files
+var goldenCache sync.Map
+
+// ExportTraceJSON writes a trace to path as indented OTLP JSON, suitable for checking in as a golden fixture
+func ExportTraceJSON(trace ptrace.Traces, path string) error {
+	marshaler := &ptrace.JSONMarshaler{}
+	data, err := marshaler.MarshalTraces(trace)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trace: %w", err)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		return fmt.Errorf("failed to format trace: %w", err)
+	}
+	indented.WriteByte('\n')
+
+	if err := os.WriteFile(path, indented.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write golden trace: %w", err)
+	}
+	goldenCache.Delete(path)
+	return nil
+}
+
+// LoadGoldenTrace reads an OTLP JSON golden fixture. Parsed fixtures are cached by path.
+func LoadGoldenTrace(path string) (ptrace.Traces, error) {
+	if cached, ok := goldenCache.Load(path); ok {
+		return cached.(ptrace.Traces), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("failed to read golden trace: %w", err)
+	}
+
+	unmarshaler := &ptrace.JSONUnmarshaler{}
+	trace, err := unmarshaler.UnmarshalTraces(data)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("failed to parse golden trace %s: %w", path, err)
+	}
+
+	goldenCache.Store(path, trace)
+	return trace, nil
+}
+
+// CompareWithGolden compares a fetched trace with a golden fixture. Spans are matched by span ID,
+// so the golden trace should come from a seeded generator. Timestamps are compared relative to
+// each trace's earliest span start, since the fixture was recorded in an earlier run.
+func CompareWithGolden(fetched ptrace.Traces, goldenPath string) (*IntegrityDiff, error) {
+	golden, err := LoadGoldenTrace(goldenPath)
+	if err != nil {
+		return nil, err
+	}
+	return compareTraces(golden, fetched, true), nil
+}
@@ -2,36 +2,98 @@ package tempo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rvargasp/xk6-tempo/pkg/generator"
+	"github.com/rvargasp/xk6-tempo/pkg/jaeger"
 	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"github.com/rvargasp/xk6-tempo/pkg/zipkin"
 	"go.k6.io/k6/lib"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc/codes"
+)
+
+// Protocols lists the supported ingestion protocols
+var Protocols = []string{"otlp-http", "otlp-grpc", "zipkin-json", "jaeger-grpc"}
+
+// Endpoint strategies for clients with several endpoints
+const (
+	EndpointRoundRobin = "round-robin"
+	EndpointFailover   = "failover"
 )
 
 // IngestClient represents the Tempo ingestion client for k6
 type IngestClient struct {
-	exporter    otlpExporter
+	targets     []ingestTarget
+	next        uint64 // Round-robin position, or the active target for failover
+	tenants     *tenantPicker
+	queue       *exportQueue // Set when pushes are queued and exported in the background
+	late        *lateSpans   // Set when part of the spans of pushed traces are sent later
 	vu          VU
 	config      IngestConfig
 	testContext *TestContext
+	tags        RequestTags // Metric tags of all requests: protocol, tenant and test name
 	metrics     *tempoMetrics
+	deriveSeed  func(seed int64) int64 // Seeds the traces of streamed pushes; set by the module
+
+	backoffMu sync.Mutex
+	backoff   time.Duration // Pause before the next export while Tempo pushes back
+
+	closed    atomic.Bool
+	closeOnce sync.Once
+	closeErr  error
 }
 
+// errIngestClientClosed is returned by pushes after Close
+var errIngestClientClosed = errors.New("ingest client is closed")
+
 // VU is an interface for k6 VU to avoid import cycles
 type VU interface {
 	State() *lib.State
+	Context() context.Context
+}
+
+// vuContext returns the VU's context, which k6 cancels when the test is aborted or a graceful
+// stop ends the iteration, so waits and in-flight requests stop with it
+func vuContext(vu VU) context.Context {
+	if vu != nil {
+		if ctx := vu.Context(); ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
 }
 
 type otlpExporter interface {
-	ExportTraces(ctx context.Context, traces ptrace.Traces) error
-	ExportBatch(ctx context.Context, traces []ptrace.Traces) error
+	ExportTraces(ctx context.Context, traces ptrace.Traces) (otlp.ExportResult, error)
 	Shutdown(ctx context.Context) error
 }
 
+// ingestTarget is the exporter for one endpoint
+type ingestTarget struct {
+	endpoint string
+	tags     RequestTags // Metric tags of requests to the endpoint
+	exporter otlpExporter
+}
+
+// PushResult reports spans that Tempo rejected through OTLP partial success.
+// Pushes over zipkin-json and jaeger-grpc always return an empty result. Queued pushes
+// only report the spans dropped because the queue was full.
+type PushResult struct {
+	RejectedSpans int64  `js:"rejectedSpans"` // Spans dropped by Tempo
+	ErrorMessage  string `js:"errorMessage"`  // Tempo's explanation, also set for warnings without rejections
+	DroppedSpans  int64  `js:"droppedSpans"`  // Spans dropped because the export queue was full
+}
+
 // NewIngestClient creates a new Tempo ingestion client
 func NewIngestClient(vu VU, config IngestConfig, m *tempoMetrics) (*IngestClient, error) {
 	timeout := time.Duration(config.Timeout) * time.Second
@@ -39,19 +101,112 @@ func NewIngestClient(vu VU, config IngestConfig, m *tempoMetrics) (*IngestClient
 		timeout = 30 * time.Second
 	}
 
-	var exporter otlpExporter
-	var err error
+	if config.Encoding != "" && config.Encoding != otlp.EncodingProtobuf && config.Protocol != "otlp-http" && config.Protocol != "" {
+		return nil, fmt.Errorf("encoding %q is only supported with protocol 'otlp-http'", config.Encoding)
+	}
+	if config.Compression != "" && config.Compression != otlp.CompressionNone && !strings.HasPrefix(config.Protocol, "otlp-") && config.Protocol != "" {
+		return nil, fmt.Errorf("compression %q is only supported with protocols 'otlp-http' and 'otlp-grpc'", config.Compression)
+	}
+	if config.Retry.MaxRetries > 0 && !strings.HasPrefix(config.Protocol, "otlp-") && config.Protocol != "" {
+		return nil, fmt.Errorf("retry is only supported with protocols 'otlp-http' and 'otlp-grpc'")
+	}
+	if config.GRPC != nil && !strings.HasSuffix(config.Protocol, "-grpc") {
+		return nil, fmt.Errorf("grpc options are only supported with protocols 'otlp-grpc' and 'jaeger-grpc'")
+	}
+	if config.ProxyURL != "" && strings.HasSuffix(config.Protocol, "-grpc") {
+		return nil, fmt.Errorf("proxyURL is only supported with protocols 'otlp-http' and 'zipkin-json'; gRPC protocols use HTTPS_PROXY")
+	}
+	if config.LateSpanRate < 0 || config.LateSpanRate > 1 {
+		return nil, fmt.Errorf("lateSpanRate must be between 0 and 1, got %v", config.LateSpanRate)
+	}
+	if config.LateSpanFraction < 0 || config.LateSpanFraction > 1 {
+		return nil, fmt.Errorf("lateSpanFraction must be between 0 and 1, got %v", config.LateSpanFraction)
+	}
+	proxy, err := otlp.ParseProxyURL(config.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
 
-	switch config.Protocol {
-	case "otlp-grpc":
-		exporter, err = otlp.NewGRPCExporter(config.Endpoint, config.Tenant, timeout)
+	tlsConfig, err := config.TLS.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls config: %w", err)
+	}
+
+	// Resolve authentication; custom headers may override static credentials
+	var oauth2 *oauth2TokenSource
+	authorization := ""
+	if config.OAuth2 != nil {
+		oauth2, err = getOAuth2TokenSource(config.OAuth2, config.TLS, otlp.Options{TLS: tlsConfig, Proxy: proxy})
+	} else {
+		authorization, err = ResolveAuthorization(config.Username, config.Password, config.BearerToken, config.BearerTokenFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials: %w", err)
+	}
+	headers := make(map[string]string, len(config.Headers)+1)
+	if authorization != "" {
+		headers["Authorization"] = authorization
+	}
+	for key, value := range config.Headers {
+		headers[key] = value
+	}
+
+	retry, err := config.Retry.exporterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry config: %w", err)
+	}
+	grpcConfig, err := config.GRPC.exporterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid grpc config: %w", err)
+	}
+	opts := otlp.Options{
+		Encoding:    config.Encoding,
+		Compression: config.Compression,
+		TLS:         tlsConfig,
+		Headers:     headers,
+		Retry:       retry,
+		GRPC:        grpcConfig,
+		Proxy:       proxy,
+	}
+	if oauth2 != nil {
+		opts.Authorization = oauth2.Authorization
+	}
+
+	tenants, err := newTenantPicker(config.Tenants, config.TenantStrategy, config.TenantWeights)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := config.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []string{config.Endpoint}
+	}
+	switch config.EndpointStrategy {
+	case "", EndpointRoundRobin, EndpointFailover:
+	default:
+		return nil, fmt.Errorf("unsupported endpointStrategy: %s (use '%s' or '%s')", config.EndpointStrategy, EndpointRoundRobin, EndpointFailover)
+	}
+
+	protocol := config.Protocol
+	if protocol == "" {
+		protocol = "otlp-http"
+	}
+	tags := RequestTags{Protocol: protocol, Tenant: config.Tenant, TestName: config.TestName}
+
+	targets := make([]ingestTarget, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		target := ingestTarget{endpoint: endpoint, tags: tags}
+		target.tags.Endpoint = endpoint
+
+		endpointOpts := opts
+		endpointOpts.OnRetry = func(error) {
+			RecordIngestionRetry(vu.State(), m, target.tags)
+		}
+		target.exporter, err = newExporter(config.Protocol, endpoint, config.Tenant, timeout, endpointOpts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create gRPC exporter: %w", err)
+			return nil, err
 		}
-	case "otlp-http", "":
-		exporter = otlp.NewHTTPExporter(config.Endpoint, config.Tenant, timeout)
-	default:
-		return nil, fmt.Errorf("unsupported protocol: %s (use 'otlp-http' or 'otlp-grpc')", config.Protocol)
+		targets = append(targets, target)
 	}
 
 	// Extract test context from config if available
@@ -64,40 +219,134 @@ func NewIngestClient(vu VU, config IngestConfig, m *tempoMetrics) (*IngestClient
 		}
 	}
 
-	return &IngestClient{
-		exporter:    exporter,
+	client := &IngestClient{
+		targets:     targets,
+		next:        firstTarget(config.EndpointStrategy, len(targets)),
+		tenants:     tenants,
 		vu:          vu,
 		config:      config,
 		testContext: testCtx,
+		tags:        tags,
 		metrics:     m,
-	}, nil
+	}
+	client.queue = newExportQueue(client, config)
+	client.late = newLateSpans(client, config)
+	onTestEnd(vu, func() { _ = client.Close() })
+	return client, nil
+}
+
+// firstTarget spreads round-robin clients over the endpoints; failover clients start on the first one
+func firstTarget(strategy string, targets int) uint64 {
+	if strategy == EndpointFailover {
+		return 0
+	}
+	return uint64(rand.Intn(targets))
+}
+
+// newExporter creates the exporter for protocol
+func newExporter(protocol, endpoint, tenant string, timeout time.Duration, opts otlp.Options) (otlpExporter, error) {
+	switch protocol {
+	case "otlp-grpc":
+		exporter, err := otlp.NewGRPCExporter(endpoint, tenant, timeout, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gRPC exporter: %w", err)
+		}
+		return exporter, nil
+	case "otlp-http", "":
+		exporter, err := otlp.NewHTTPExporter(endpoint, tenant, timeout, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP exporter: %w", err)
+		}
+		return exporter, nil
+	case "jaeger-grpc":
+		exporter, err := jaeger.NewGRPCExporter(endpoint, tenant, timeout, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Jaeger gRPC exporter: %w", err)
+		}
+		return exporter, nil
+	case "zipkin-json":
+		return zipkin.NewJSONExporter(endpoint, tenant, timeout, opts), nil
+	default:
+		return nil, fmt.Errorf("unsupported protocol: %s (use one of: %s)", protocol, strings.Join(Protocols, ", "))
+	}
+}
+
+// exporterConfig converts the retry config to the exporter's form
+func (r RetryConfig) exporterConfig() (otlp.RetryConfig, error) {
+	cfg := otlp.RetryConfig{
+		MaxRetries:           r.MaxRetries,
+		RetryableStatusCodes: r.RetryableStatusCodes,
+	}
+
+	var err error
+	if cfg.InitialBackoff, err = time.ParseDuration(r.InitialBackoff); err != nil {
+		return otlp.RetryConfig{}, fmt.Errorf("invalid initialBackoff: %w", err)
+	}
+	if cfg.MaxBackoff, err = time.ParseDuration(r.MaxBackoff); err != nil {
+		return otlp.RetryConfig{}, fmt.Errorf("invalid maxBackoff: %w", err)
+	}
+	for _, name := range r.RetryableGRPCCodes {
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(name)))); err != nil {
+			return otlp.RetryConfig{}, fmt.Errorf("invalid gRPC code %q", name)
+		}
+		cfg.RetryableGRPCCodes = append(cfg.RetryableGRPCCodes, code)
+	}
+	return cfg, nil
+}
+
+// exporterConfig converts the gRPC config to the exporter's form; a nil config keeps the gRPC defaults
+func (g *GRPCConfig) exporterConfig() (otlp.GRPCConfig, error) {
+	if g == nil {
+		return otlp.GRPCConfig{}, nil
+	}
+
+	cfg := otlp.GRPCConfig{
+		MaxMessageSize: g.MaxMessageSize,
+		NumConnections: g.NumConnections,
+		LoadBalancing:  g.LoadBalancing,
+	}
+	var err error
+	if g.KeepaliveTime != "" {
+		if cfg.KeepaliveTime, err = time.ParseDuration(g.KeepaliveTime); err != nil {
+			return otlp.GRPCConfig{}, fmt.Errorf("invalid keepaliveTime: %w", err)
+		}
+	}
+	if g.KeepaliveTimeout != "" {
+		if cfg.KeepaliveTimeout, err = time.ParseDuration(g.KeepaliveTimeout); err != nil {
+			return otlp.GRPCConfig{}, fmt.Errorf("invalid keepaliveTimeout: %w", err)
+		}
+	}
+	if g.DialTimeout != "" {
+		if cfg.DialTimeout, err = time.ParseDuration(g.DialTimeout); err != nil {
+			return otlp.GRPCConfig{}, fmt.Errorf("invalid dialTimeout: %w", err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return otlp.GRPCConfig{}, err
+	}
+	return cfg, nil
 }
 
 // push pushes a single trace to Tempo (internal, requires context)
-func (c *IngestClient) push(ctx context.Context, trace ptrace.Traces) error {
+func (c *IngestClient) push(ctx context.Context, trace ptrace.Traces) (*PushResult, error) {
+	c.applyBackoff(ctx)
 	start := time.Now()
 
 	// Calculate size before export
 	size := estimateTraceSize(trace)
 
-	err := c.exporter.ExportTraces(ctx, trace)
-	duration := time.Since(start)
-
-	// Record metrics
-	if err == nil && c.vu.State() != nil {
-		RecordIngestionWithContext(c.vu.State(), c.metrics, c.testContext, int64(size), 1, duration)
-	}
-
-	return err
+	return c.send(ctx, trace, size, 1, start)
 }
 
 // pushBatchInternal pushes a batch of traces to Tempo (internal, requires context)
-func (c *IngestClient) pushBatchInternal(ctx context.Context, traces []ptrace.Traces) error {
+func (c *IngestClient) pushBatchInternal(ctx context.Context, traces []ptrace.Traces) (*PushResult, error) {
 	return c.pushBatchWithRateLimitInternal(ctx, traces, nil)
 }
 
 // pushBatchWithRateLimitInternal pushes a batch of traces to Tempo with rate limiting (internal, requires context)
-func (c *IngestClient) pushBatchWithRateLimitInternal(ctx context.Context, traces []ptrace.Traces, limiter *generator.ByteRateLimiter) error {
+func (c *IngestClient) pushBatchWithRateLimitInternal(ctx context.Context, traces []ptrace.Traces, limiter *generator.ByteRateLimiter) (*PushResult, error) {
+	c.applyBackoff(ctx)
 	start := time.Now()
 
 	// Calculate total size
@@ -109,41 +358,334 @@ func (c *IngestClient) pushBatchWithRateLimitInternal(ctx context.Context, trace
 	// Apply rate limiting if provided
 	if limiter != nil {
 		if err := limiter.Wait(ctx, totalSize); err != nil {
-			return fmt.Errorf("rate limiter wait failed: %w", err)
+			return nil, fmt.Errorf("rate limiter wait failed: %w", err)
 		}
 	}
 
-	err := c.exporter.ExportBatch(ctx, traces)
+	// Combine all traces into a single request
+	combined := ptrace.NewTraces()
+	for _, trace := range traces {
+		trace.ResourceSpans().MoveAndAppendTo(combined.ResourceSpans())
+	}
+
+	return c.send(ctx, combined, totalSize, len(traces), start)
+}
+
+// send exports traces as one request, records ingestion metrics and tracks the pushed trace IDs.
+// size and count are the request size in bytes and the number of new traces; start is when the push began.
+func (c *IngestClient) send(ctx context.Context, traces ptrace.Traces, size, count int, start time.Time) (*PushResult, error) {
+	if c.closed.Load() {
+		return nil, errIngestClientClosed
+	}
+	return c.sendBuffered(ctx, traces, size, count, start)
+}
+
+// sendBuffered is send for traces the export queue or the late-span scheduler accepted before the
+// client started closing; Close still exports them
+func (c *IngestClient) sendBuffered(ctx context.Context, traces ptrace.Traces, size, count int, start time.Time) (*PushResult, error) {
+	ctx, tenant := withRequestTenant(ctx, c.tenants)
+	chaos := generator.ChaosSpans(traces)
+	result, target, err := c.export(ctx, traces)
 	duration := time.Since(start)
 
 	// Record metrics
-	if err == nil && c.vu.State() != nil {
-		RecordIngestionWithContext(c.vu.State(), c.metrics, c.testContext, int64(totalSize), len(traces), duration)
+	rt := c.requestTags(target, tenant)
+	if err == nil {
+		RecordIngestionWithContext(c.vu.State(), c.metrics, c.testContext, rt, int64(size), count, duration)
+		RecordRejectedSpans(c.vu.State(), c.metrics, rt, result.RejectedSpans)
+		RecordPayloadSize(c.vu.State(), c.metrics, rt, result.PayloadBytes)
+		c.recordChaosSpans(rt, chaos, result.RejectedSpans, nil)
+	}
+	if err != nil {
+		RecordIngestionFailure(c.vu.State(), c.metrics, rt, otlp.ErrorClass(err), duration)
+		c.recordChaosSpans(rt, chaos, 0, err)
+		if otlp.IsTimeout(err) {
+			RecordIngestionTimeout(c.vu.State(), c.metrics, rt)
+		}
+		if c.updateBackoff(err) {
+			RecordIngestionBackoff(c.vu.State(), c.metrics, rt)
+		}
+		return nil, err
+	}
+	c.updateBackoff(nil)
+
+	// Requests holding no new traces carry the rest of traces tracked already
+	if count > 0 {
+		c.trackTraces(tenant, traces)
+	}
+	return newPushResult(result), nil
+}
+
+// requestTags returns the metric tags of a request to target, nil when no endpoint was tried, for
+// the given tenant, empty when none is set
+func (c *IngestClient) requestTags(target *ingestTarget, tenant string) RequestTags {
+	rt := c.tags
+	if target != nil {
+		rt = target.tags
+	}
+	if tenant != "" {
+		rt.Tenant = tenant
+	}
+	return rt
+}
+
+// recordChaosSpans records the outcome of the chaos spans of an export request. All of them are
+// rejected when Tempo refused the request as malformed and failed on other errors. Spans rejected
+// through partial success are attributed to the chaos spans first, in malformation order.
+func (c *IngestClient) recordChaosSpans(rt RequestTags, chaos map[string]int, rejected int64, err error) {
+	if len(chaos) == 0 {
+		return
+	}
+
+	kinds := make([]string, 0, len(chaos))
+	for kind := range chaos {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		spans := int64(chaos[kind])
+		switch {
+		case err != nil && otlp.Invalid(err):
+			RecordChaosSpans(c.vu.State(), c.metrics, rt, kind, ChaosOutcomeRejected, spans)
+		case err != nil:
+			RecordChaosSpans(c.vu.State(), c.metrics, rt, kind, ChaosOutcomeFailed, spans)
+		default:
+			n := min(spans, rejected)
+			rejected -= n
+			RecordChaosSpans(c.vu.State(), c.metrics, rt, kind, ChaosOutcomeRejected, n)
+			RecordChaosSpans(c.vu.State(), c.metrics, rt, kind, ChaosOutcomeAccepted, spans-n)
+		}
+	}
+}
+
+// applyBackoff waits out the current pushback pause, plus up to 10% jitter
+func (c *IngestClient) applyBackoff(ctx context.Context) {
+	c.backoffMu.Lock()
+	delay := c.backoff
+	c.backoffMu.Unlock()
+	if delay <= 0 {
+		return
+	}
+
+	delay += time.Duration(rand.Int63n(int64(delay)/10 + 1))
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
+
+// updateBackoff adjusts the pause after an export and reports whether Tempo pushed back.
+// On pushback the pause is Tempo's Retry-After or retry info, or grows by 1.5x from
+// minBackoffMs; a successful export clears it.
+func (c *IngestClient) updateBackoff(err error) bool {
+	if !c.config.EnableBackoff {
+		return false
+	}
+
+	wait, pushback := otlp.Pushback(err)
+	c.backoffMu.Lock()
+	defer c.backoffMu.Unlock()
+
+	switch {
+	case err == nil:
+		c.backoff = 0
+	case !pushback:
+		// Other failures leave the pause unchanged
+	case wait > 0:
+		c.backoff = wait
+	case c.backoff == 0:
+		c.backoff = time.Duration(c.config.MinBackoffMs) * time.Millisecond
+	default:
+		c.backoff = time.Duration(float64(c.backoff) * 1.5)
+	}
+	if maxBackoff := time.Duration(c.config.MaxBackoffMs) * time.Millisecond; maxBackoff > 0 && c.backoff > maxBackoff {
+		c.backoff = maxBackoff
+	}
+	return pushback
+}
+
+// export sends traces to the next endpoint. With the failover strategy, endpoints are tried in
+// order until one succeeds, and the client stays on that endpoint for later pushes.
+func (c *IngestClient) export(ctx context.Context, traces ptrace.Traces) (otlp.ExportResult, *ingestTarget, error) {
+	n := uint64(len(c.targets))
+	if n == 1 || c.config.EndpointStrategy != EndpointFailover {
+		target := &c.targets[(atomic.AddUint64(&c.next, 1)-1)%n]
+		result, err := target.exporter.ExportTraces(ctx, traces)
+		if err != nil && n > 1 {
+			err = fmt.Errorf("%s: %w", target.endpoint, err)
+		}
+		return result, target, err
+	}
+
+	active := atomic.LoadUint64(&c.next)
+	var errs []error
+	for i := uint64(0); i < n; i++ {
+		target := &c.targets[(active+i)%n]
+		result, err := target.exporter.ExportTraces(ctx, traces)
+		if err == nil {
+			atomic.StoreUint64(&c.next, (active+i)%n)
+			return result, target, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", target.endpoint, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return otlp.ExportResult{}, nil, errors.Join(errs...)
+}
+
+// newPushResult converts an exporter result for JavaScript
+func newPushResult(result otlp.ExportResult) *PushResult {
+	return &PushResult{
+		RejectedSpans: result.RejectedSpans,
+		ErrorMessage:  result.ErrorMessage,
+	}
+}
+
+// pinTenant returns ctx bound to the tenant of the next push, so reads made with the same ctx
+// go to the tenant the data was written to
+func (c *IngestClient) pinTenant(ctx context.Context) context.Context {
+	if ctx, tenant := withRequestTenant(ctx, c.tenants); tenant != "" {
+		return ctx
+	}
+	return otlp.WithTenant(ctx, c.config.Tenant)
+}
+
+// primaryTenant returns the first rotated tenant, or the configured tenant without rotation
+func (c *IngestClient) primaryTenant() string {
+	if len(c.config.Tenants) > 0 {
+		return c.config.Tenants[0]
+	}
+	return c.config.Tenant
+}
+
+// trackTraces records successfully pushed trace IDs in the shared registry when tracking is enabled
+func (c *IngestClient) trackTraces(tenant string, traces ...ptrace.Traces) {
+	if !c.config.TrackTraceIDs {
+		return
 	}
 
-	return err
+	registry := GetTraceRegistry()
+	now := time.Now()
+	for _, trace := range traces {
+		for _, tracked := range trackedTraces(trace, tenant, now) {
+			if c.config.TrackSampleRate >= 1 || rand.Float64() < c.config.TrackSampleRate {
+				registry.Add(tracked)
+			}
+		}
+	}
 }
 
 // JavaScript-friendly wrapper methods (exported, no context parameter required)
 
-// Push pushes a single trace to Tempo (JavaScript-friendly)
-func (c *IngestClient) Push(trace ptrace.Traces) error {
-	ctx := context.Background()
+// Push pushes a single trace to Tempo (JavaScript-friendly). With a queue, the trace is
+// exported in the background.
+func (c *IngestClient) Push(trace ptrace.Traces) (*PushResult, error) {
+	ctx := c.pinTenant(vuContext(c.vu))
+	c.holdBackLateSpans(ctx, trace)
+	if c.queue != nil {
+		return c.enqueue(ctx, trace)
+	}
+
 	return c.push(ctx, trace)
 }
 
-// PushBatch pushes a batch of traces to Tempo (JavaScript-friendly)
-func (c *IngestClient) PushBatch(traces []ptrace.Traces) error {
-	ctx := context.Background()
+// PushBatch pushes a batch of traces to Tempo (JavaScript-friendly). With a queue, the traces
+// are exported in the background.
+func (c *IngestClient) PushBatch(traces []ptrace.Traces) (*PushResult, error) {
+	ctx := c.pinTenant(vuContext(c.vu))
+	c.holdBackLateSpans(ctx, traces...)
+	if c.queue != nil {
+		return c.enqueue(ctx, traces...)
+	}
+
 	return c.pushBatchInternal(ctx, traces)
 }
 
-// PushBatchWithRateLimit pushes a batch of traces to Tempo with rate limiting (JavaScript-friendly)
-func (c *IngestClient) PushBatchWithRateLimit(traces []ptrace.Traces, limiter *generator.ByteRateLimiter) error {
-	ctx := context.Background()
+// PushBatchWithRateLimit pushes a batch of traces to Tempo with rate limiting (JavaScript-friendly).
+// With a queue, the rate limit applies to queueing.
+func (c *IngestClient) PushBatchWithRateLimit(traces []ptrace.Traces, limiter *generator.ByteRateLimiter) (*PushResult, error) {
+	ctx := c.pinTenant(vuContext(c.vu))
+	c.holdBackLateSpans(ctx, traces...)
+	if c.queue != nil {
+		if limiter != nil {
+			totalSize := 0
+			for _, trace := range traces {
+				totalSize += estimateTraceSize(trace)
+			}
+			if err := limiter.Wait(ctx, totalSize); err != nil {
+				return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+			}
+		}
+		return c.enqueue(ctx, traces...)
+	}
+
 	return c.pushBatchWithRateLimitInternal(ctx, traces, limiter)
 }
 
+// holdBackLateSpans removes the spans that arrive late from traces about to be pushed with ctx;
+// they are sent later to the tenant pinned on ctx, the tenant of the rest of the trace
+func (c *IngestClient) holdBackLateSpans(ctx context.Context, traces ...ptrace.Traces) {
+	if c.late != nil && !c.closed.Load() {
+		tenant, _ := otlp.TenantFromContext(ctx)
+		c.late.holdBack(tenant, traces...)
+	}
+}
+
+// enqueue adds traces to the export queue, to be exported to the tenant pinned on ctx
+func (c *IngestClient) enqueue(ctx context.Context, traces ...ptrace.Traces) (*PushResult, error) {
+	if c.closed.Load() {
+		return nil, errIngestClientClosed
+	}
+	tenant, _ := otlp.TenantFromContext(ctx)
+	dropped, err := c.queue.enqueue(tenant, traces...)
+	if err != nil {
+		return nil, err
+	}
+	return &PushResult{DroppedSpans: dropped}, nil
+}
+
+// Flush exports all queued traces and waits for the exports to finish (JavaScript-friendly).
+// It returns the errors of those exports and fails once Close started; without a queue it does nothing.
+func (c *IngestClient) Flush() error {
+	if c.queue == nil {
+		return nil
+	}
+	if c.closed.Load() {
+		return errIngestClientClosed
+	}
+	return c.queue.flush()
+}
+
+// Close flushes the export queue and closes the exporters' connections (JavaScript-friendly).
+// Pushes and flushes fail once it started. Clients are also closed when the test ends.
+func (c *IngestClient) Close() error {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+
+		var errs []error
+		if c.late != nil {
+			if err := c.late.close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if c.queue != nil {
+			if err := c.queue.close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		for _, target := range c.targets {
+			if err := target.exporter.Shutdown(context.Background()); err != nil {
+				errs = append(errs, fmt.Errorf("failed to shut down exporter for %s: %w", target.endpoint, err))
+			}
+		}
+		c.closeErr = errors.Join(errs...)
+	})
+	return c.closeErr
+}
+
 // estimateTraceSize calculates the actual protobuf-serialized size of a trace in bytes
 func estimateTraceSize(trace ptrace.Traces) int {
 	req := ptraceotlp.NewExportRequestFromTraces(trace)
@@ -0,0 +1,143 @@
+package tempo
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/rvargasp/xk6-tempo/pkg/generator"
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"go.k6.io/k6/lib"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// testVU is a VU without k6 state, so metrics are not emitted
+type testVU struct{}
+
+func (testVU) State() *lib.State        { return nil }
+func (testVU) Context() context.Context { return context.Background() }
+
+// recordingExporter records the spans of each trace it exports per tenant
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans map[string]map[string]int // Tenant -> trace ID -> spans
+}
+
+func (e *recordingExporter) ExportTraces(ctx context.Context, traces ptrace.Traces) (otlp.ExportResult, error) {
+	tenant, _ := otlp.TenantFromContext(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.spans == nil {
+		e.spans = make(map[string]map[string]int)
+	}
+	if e.spans[tenant] == nil {
+		e.spans[tenant] = make(map[string]int)
+	}
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		scopes := traces.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopes.Len(); j++ {
+			spans := scopes.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				e.spans[tenant][spans.At(k).TraceID().String()]++
+			}
+		}
+	}
+	return otlp.ExportResult{}, nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error { return nil }
+
+// newTestIngestClient creates a client exporting to exporter, with the queue and late-span
+// scheduler set up from config
+func newTestIngestClient(t *testing.T, config IngestConfig, exporter otlpExporter) *IngestClient {
+	t.Helper()
+	tenants, err := newTenantPicker(config.Tenants, config.TenantStrategy, config.TenantWeights)
+	if err != nil {
+		t.Fatalf("creating tenant picker: %v", err)
+	}
+	c := &IngestClient{
+		targets: []ingestTarget{{endpoint: "test", exporter: exporter}},
+		tenants: tenants,
+		vu:      testVU{},
+		config:  config,
+	}
+	c.queue = newExportQueue(c, config)
+	c.late = newLateSpans(c, config)
+	return c
+}
+
+// testTraces generates n seeded traces of several spans each
+func testTraces(n int) []ptrace.Traces {
+	config := generator.DefaultConfig()
+	traces := make([]ptrace.Traces, n)
+	for i := range traces {
+		config.Seed = int64(i + 1)
+		traces[i] = generator.GenerateTrace(config)
+	}
+	return traces
+}
+
+// traceSpans counts the spans of each trace ID in traces
+func traceSpans(traces []ptrace.Traces) map[string]int {
+	counts := make(map[string]int)
+	for _, trace := range traces {
+		for i := 0; i < trace.ResourceSpans().Len(); i++ {
+			scopes := trace.ResourceSpans().At(i).ScopeSpans()
+			for j := 0; j < scopes.Len(); j++ {
+				spans := scopes.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					counts[spans.At(k).TraceID().String()]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// assertSingleTenant checks that all spans of every trace in want reached exactly one tenant
+func assertSingleTenant(t *testing.T, exporter *recordingExporter, want map[string]int) {
+	t.Helper()
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	for traceID, spans := range want {
+		var tenants []string
+		for tenant, traceSpans := range exporter.spans {
+			if n, ok := traceSpans[traceID]; ok {
+				tenants = append(tenants, tenant)
+				if n != spans {
+					t.Errorf("trace %s: tenant %s got %d spans, want %d", traceID, tenant, n, spans)
+				}
+			}
+		}
+		if len(tenants) != 1 {
+			t.Errorf("trace %s reached tenants %v, want exactly one", traceID, tenants)
+		}
+	}
+}
+
+func TestPushBatchTracksTraces(t *testing.T) {
+	c := newTestIngestClient(t, IngestConfig{TrackTraceIDs: true, TrackSampleRate: 1}, &recordingExporter{})
+
+	traces := testTraces(3)
+	want := traceSpans(traces)
+	registry := GetTraceRegistry()
+	recorded := registry.Recorded()
+	if _, err := c.PushBatch(traces); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+
+	if got := registry.Recorded() - recorded; got != int64(len(want)) {
+		t.Errorf("recorded %d traces, want %d", got, len(want))
+	}
+	tracked := make(map[string]bool)
+	for _, trace := range registry.Sample(registry.Len()) {
+		tracked[trace.TraceID] = true
+	}
+	for traceID := range want {
+		if !tracked[traceID] {
+			t.Errorf("trace %s was not tracked", traceID)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package tempo
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// IntegrityDiff describes how a trace read back from Tempo differs from the trace that was pushed
+type IntegrityDiff struct {
+	Match             bool            `js:"match"`             // No differences were found
+	ExpectedSpans     int             `js:"expectedSpans"`     // Spans in the pushed trace
+	ActualSpans       int             `js:"actualSpans"`       // Spans in the fetched trace
+	MissingSpans      []string        `js:"missingSpans"`      // Span IDs that were not returned
+	MissingAttributes []AttributeDiff `js:"missingAttributes"` // Attributes absent from returned spans
+	MutatedAttributes []AttributeDiff `js:"mutatedAttributes"` // Attributes whose value changed
+	TimestampDiffs    []TimestampDiff `js:"timestampDiffs"`    // Start/end times that changed
+	FieldDiffs        []FieldDiff     `js:"fieldDiffs"`        // Span fields that were lost or changed
+	DuplicateSpans    int             `js:"duplicateSpans"`    // Fetched spans repeating an already-seen span ID
+}
+
+// AttributeDiff describes a missing or mutated span or resource attribute.
+// A value whose type changed (e.g. int coerced to string) counts as mutated.
+type AttributeDiff struct {
+	SpanID       string `js:"spanId"`
+	Key          string `js:"key"` // Resource attributes are prefixed with "resource."
+	Expected     string `js:"expected"`
+	Actual       string `js:"actual"`
+	ExpectedType string `js:"expectedType"`
+	ActualType   string `js:"actualType"`
+}
+
+// FieldDiff describes a span field whose value changed
+type FieldDiff struct {
+	SpanID   string `js:"spanId"`
+	Field    string `js:"field"` // "name", "kind", "parentSpanId", "status.code", "status.message", "events", "links", "resource.schemaUrl" or "scope.schemaUrl"
+	Expected string `js:"expected"`
+	Actual   string `js:"actual"`
+}
+
+// TimestampDiff describes a span timestamp discrepancy
+type TimestampDiff struct {
+	SpanID     string `js:"spanId"`
+	Field      string `js:"field"` // "start" or "end"
+	ExpectedNs int64  `js:"expectedNs"`
+	ActualNs   int64  `js:"actualNs"`
+	DeltaNs    int64  `js:"deltaNs"`
+}
+
+// indexedSpan is a span together with the resource it was emitted under
+type indexedSpan struct {
+	span              ptrace.Span
+	resource          pcommon.Map
+	resourceSchemaURL string
+	scopeSchemaURL    string
+}
+
+// CompareTraces compares a generated trace with the same trace fetched back from Tempo.
+// Spans are matched by span ID; extra spans or attributes added by Tempo are ignored.
+func CompareTraces(expected, actual ptrace.Traces) *IntegrityDiff {
+	return compareTraces(expected, actual, false)
+}
+
+// compareTraces implements CompareTraces. With relativeTimestamps, span timestamps are compared
+// as offsets from the earliest span start of their own trace, so traces recorded at different
+// times can be compared.
+func compareTraces(expected, actual ptrace.Traces, relativeTimestamps bool) *IntegrityDiff {
+	expectedSpans := indexSpans(expected)
+	actualSpans := indexSpans(actual)
+
+	actualCount, duplicates := countDuplicateSpansOTLP(actual)
+
+	diff := &IntegrityDiff{
+		ExpectedSpans:     len(expectedSpans),
+		ActualSpans:       actualCount,
+		DuplicateSpans:    duplicates,
+		MissingSpans:      []string{},
+		MissingAttributes: []AttributeDiff{},
+		MutatedAttributes: []AttributeDiff{},
+		TimestampDiffs:    []TimestampDiff{},
+		FieldDiffs:        []FieldDiff{},
+	}
+
+	var expectedBase, actualBase pcommon.Timestamp
+	if relativeTimestamps {
+		expectedBase = earliestStart(expectedSpans)
+		actualBase = earliestStart(actualSpans)
+	}
+
+	// Iterate in a stable order so diffs are reproducible
+	spanIDs := make([]pcommon.SpanID, 0, len(expectedSpans))
+	for id := range expectedSpans {
+		spanIDs = append(spanIDs, id)
+	}
+	sort.Slice(spanIDs, func(i, j int) bool {
+		return spanIDs[i].String() < spanIDs[j].String()
+	})
+
+	for _, id := range spanIDs {
+		want := expectedSpans[id]
+		spanID := id.String()
+
+		got, ok := actualSpans[id]
+		if !ok {
+			diff.MissingSpans = append(diff.MissingSpans, spanID)
+			continue
+		}
+
+		diff.compareAttributes(spanID, "", want.span.Attributes(), got.span.Attributes())
+		diff.compareAttributes(spanID, "resource.", want.resource, got.resource)
+		diff.compareTimestamp(spanID, "start", want.span.StartTimestamp()-expectedBase, got.span.StartTimestamp()-actualBase)
+		diff.compareTimestamp(spanID, "end", want.span.EndTimestamp()-expectedBase, got.span.EndTimestamp()-actualBase)
+		diff.compareFields(spanID, want, got)
+	}
+
+	diff.Match = len(diff.MissingSpans) == 0 &&
+		len(diff.MissingAttributes) == 0 &&
+		len(diff.MutatedAttributes) == 0 &&
+		len(diff.TimestampDiffs) == 0 &&
+		len(diff.FieldDiffs) == 0 &&
+		diff.DuplicateSpans == 0
+
+	return diff
+}
+
+// compareAttributes records missing and mutated attributes
+func (d *IntegrityDiff) compareAttributes(spanID, prefix string, want, got pcommon.Map) {
+	want.Range(func(key string, value pcommon.Value) bool {
+		expected := value.AsString()
+		actual, ok := got.Get(key)
+		switch {
+		case !ok:
+			d.MissingAttributes = append(d.MissingAttributes, AttributeDiff{
+				SpanID:       spanID,
+				Key:          prefix + key,
+				Expected:     expected,
+				ExpectedType: value.Type().String(),
+			})
+		case actual.AsString() != expected || actual.Type() != value.Type():
+			d.MutatedAttributes = append(d.MutatedAttributes, AttributeDiff{
+				SpanID:       spanID,
+				Key:          prefix + key,
+				Expected:     expected,
+				Actual:       actual.AsString(),
+				ExpectedType: value.Type().String(),
+				ActualType:   actual.Type().String(),
+			})
+		}
+		return true
+	})
+}
+
+// compareTimestamp records a timestamp discrepancy
+func (d *IntegrityDiff) compareTimestamp(spanID, field string, want, got pcommon.Timestamp) {
+	if want == got {
+		return
+	}
+	d.TimestampDiffs = append(d.TimestampDiffs, TimestampDiff{
+		SpanID:     spanID,
+		Field:      field,
+		ExpectedNs: int64(want),
+		ActualNs:   int64(got),
+		DeltaNs:    int64(got) - int64(want),
+	})
+}
+
+// compareFields records span fields that were lost or changed. Schema URLs are only compared when
+// the pushed trace has one.
+func (d *IntegrityDiff) compareFields(spanID string, want, got indexedSpan) {
+	fields := []struct {
+		name     string
+		expected string
+		actual   string
+	}{
+		{"name", want.span.Name(), got.span.Name()},
+		{"kind", want.span.Kind().String(), got.span.Kind().String()},
+		{"parentSpanId", want.span.ParentSpanID().String(), got.span.ParentSpanID().String()},
+		{"status.code", want.span.Status().Code().String(), got.span.Status().Code().String()},
+		{"status.message", want.span.Status().Message(), got.span.Status().Message()},
+		{"events", strconv.Itoa(want.span.Events().Len()), strconv.Itoa(got.span.Events().Len())},
+		{"links", strconv.Itoa(want.span.Links().Len()), strconv.Itoa(got.span.Links().Len())},
+		{"resource.schemaUrl", want.resourceSchemaURL, got.resourceSchemaURL},
+		{"scope.schemaUrl", want.scopeSchemaURL, got.scopeSchemaURL},
+	}
+
+	for _, f := range fields {
+		if f.expected != f.actual && (f.expected != "" || !strings.HasSuffix(f.name, "schemaUrl")) {
+			d.FieldDiffs = append(d.FieldDiffs, FieldDiff{
+				SpanID:   spanID,
+				Field:    f.name,
+				Expected: f.expected,
+				Actual:   f.actual,
+			})
+		}
+	}
+}
+
+// earliestStart returns the earliest span start timestamp
+func earliestStart(spans map[pcommon.SpanID]indexedSpan) pcommon.Timestamp {
+	var earliest pcommon.Timestamp
+	for _, s := range spans {
+		if start := s.span.StartTimestamp(); earliest == 0 || start < earliest {
+			earliest = start
+		}
+	}
+	return earliest
+}
+
+// indexSpans maps every span in a trace by span ID
+func indexSpans(trace ptrace.Traces) map[pcommon.SpanID]indexedSpan {
+	spans := make(map[pcommon.SpanID]indexedSpan)
+	for i := 0; i < trace.ResourceSpans().Len(); i++ {
+		resourceSpans := trace.ResourceSpans().At(i)
+		resource := resourceSpans.Resource().Attributes()
+		for j := 0; j < resourceSpans.ScopeSpans().Len(); j++ {
+			scope := resourceSpans.ScopeSpans().At(j)
+			scopeSpans := scope.Spans()
+			for k := 0; k < scopeSpans.Len(); k++ {
+				span := scopeSpans.At(k)
+				spans[span.SpanID()] = indexedSpan{
+					span:              span,
+					resource:          resource,
+					resourceSchemaURL: resourceSpans.SchemaUrl(),
+					scopeSchemaURL:    scope.SchemaUrl(),
+				}
+			}
+		}
+	}
+	return spans
+}
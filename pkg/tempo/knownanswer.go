@@ -0,0 +1,247 @@
+package tempo
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/generator"
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+)
+
+// knownAnswerQuery is a TraceQL filter whose matching traces can be computed from the corpus specs
+type knownAnswerQuery struct {
+	name   string
+	filter string
+	match  func(spec generator.KnownAnswerSpec) bool
+}
+
+// knownAnswerCatalog lists the queries checked against every known-answer corpus
+var knownAnswerCatalog = []knownAnswerQuery{
+	{
+		name:  "all",
+		match: func(generator.KnownAnswerSpec) bool { return true },
+	},
+	{
+		name:   "bucket_eq",
+		filter: "span.ka.bucket = 3",
+		match:  func(s generator.KnownAnswerSpec) bool { return s.Bucket == 3 },
+	},
+	{
+		name:   "bucket_gte",
+		filter: "span.ka.bucket >= 7",
+		match:  func(s generator.KnownAnswerSpec) bool { return s.Bucket >= 7 },
+	},
+	{
+		name:   "errors",
+		filter: "status = error",
+		match:  func(s generator.KnownAnswerSpec) bool { return s.Error },
+	},
+	{
+		name:   "slow",
+		filter: "duration > 150ms",
+		match:  func(s generator.KnownAnswerSpec) bool { return s.Duration > 150*time.Millisecond },
+	},
+	{
+		name:   "service",
+		filter: `resource.service.name = "ka-frontend"`,
+		match:  func(s generator.KnownAnswerSpec) bool { return s.Service == "ka-frontend" },
+	},
+	{
+		name:   "service_errors",
+		filter: `resource.service.name = "ka-checkout" && status = error`,
+		match:  func(s generator.KnownAnswerSpec) bool { return s.Service == "ka-checkout" && s.Error },
+	},
+}
+
+// KnownAnswerSuite ingests a seeded corpus and checks that TraceQL queries return exactly the expected traces
+type KnownAnswerSuite struct {
+	ingest       *IngestClient
+	query        *QueryClient
+	vu           VU
+	metrics      *tempoMetrics
+	specs        []generator.KnownAnswerSpec
+	tenant       string // Corpus and queries always use the ingest client's primary tenant
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+// KnownAnswerReport summarizes one run of the query catalog
+type KnownAnswerReport struct {
+	RunID    string              `js:"runId"`
+	Passed   int                 `js:"passed"`
+	Failed   int                 `js:"failed"`
+	Attempts int                 `js:"attempts"` // Catalog rounds executed before results converged or timed out
+	Results  []KnownAnswerResult `js:"results"`
+}
+
+// KnownAnswerResult is the outcome of one catalog query
+type KnownAnswerResult struct {
+	Name     string `js:"name"`
+	Query    string `js:"query"`
+	Expected int    `js:"expected"`
+	Actual   int    `js:"actual"`
+	Passed   bool   `js:"passed"`
+	Error    string `js:"error"`
+}
+
+// NewKnownAnswerSuite creates a new known-answer suite
+func NewKnownAnswerSuite(ingest *IngestClient, query *QueryClient, vu VU, config KnownAnswerConfig, m *tempoMetrics) (*KnownAnswerSuite, error) {
+	if ingest == nil {
+		return nil, fmt.Errorf("ingest client is required")
+	}
+	if query == nil {
+		return nil, fmt.Errorf("query client is required")
+	}
+	if config.Traces <= 0 {
+		return nil, fmt.Errorf("traces must be > 0, got %d", config.Traces)
+	}
+
+	pollInterval, err := time.ParseDuration(config.PollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pollInterval: %w", err)
+	}
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("pollInterval must be > 0, got %s", config.PollInterval)
+	}
+
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout: %w", err)
+	}
+
+	return &KnownAnswerSuite{
+		ingest:       ingest,
+		query:        query,
+		vu:           vu,
+		metrics:      m,
+		specs:        generator.KnownAnswerSpecs(config.Traces, config.Seed),
+		tenant:       ingest.primaryTenant(),
+		pollInterval: pollInterval,
+		timeout:      timeout,
+	}, nil
+}
+
+// ingestCorpus pushes the corpus under a fresh run ID and returns it
+func (s *KnownAnswerSuite) ingestCorpus(ctx context.Context) (string, error) {
+	runID, err := newRunID("ka")
+	if err != nil {
+		return "", err
+	}
+
+	ctx = otlp.WithTenant(ctx, s.tenant)
+	if _, err := s.ingest.push(ctx, generator.GenerateKnownAnswerTraces(runID, s.specs)); err != nil {
+		return "", fmt.Errorf("failed to push known-answer corpus: %w", err)
+	}
+	return runID, nil
+}
+
+// run executes the catalog for runID, repeating until every query passes or the timeout expires
+func (s *KnownAnswerSuite) run(ctx context.Context, runID string) (*KnownAnswerReport, error) {
+	if runID == "" {
+		return nil, fmt.Errorf("runId is required")
+	}
+
+	deadline := time.Now().Add(s.timeout)
+	report := &KnownAnswerReport{RunID: runID}
+
+	for {
+		report.Attempts++
+		report.Results = s.runCatalog(ctx, runID)
+
+		report.Passed, report.Failed = 0, 0
+		for _, result := range report.Results {
+			if result.Passed {
+				report.Passed++
+			} else {
+				report.Failed++
+			}
+		}
+
+		if report.Failed == 0 || time.Now().Add(s.pollInterval).After(deadline) {
+			break
+		}
+		if err := sleepContext(ctx, s.pollInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	if state := s.vu.State(); state != nil {
+		RecordKnownAnswer(state, s.metrics, report)
+	}
+	return report, nil
+}
+
+// runCatalog executes every catalog query once
+func (s *KnownAnswerSuite) runCatalog(ctx context.Context, runID string) []KnownAnswerResult {
+	// Leave headroom above the corpus size so overcounting is detected
+	options := QueryOptions{Start: "1h", End: "now", Limit: len(s.specs) * 2}
+	results := make([]KnownAnswerResult, 0, len(knownAnswerCatalog))
+
+	for _, q := range knownAnswerCatalog {
+		result := KnownAnswerResult{
+			Name:     q.name,
+			Query:    knownAnswerTraceQL(runID, q.filter),
+			Expected: s.expected(q),
+		}
+
+		start := time.Now()
+		resp, err := s.query.search(otlp.WithTenant(ctx, s.tenant), result.Query, options)
+		RecordQueryDetailed(s.vu.State(), s.metrics, s.query.requestTags(s.tenant), time.Since(start), 0, err == nil, q.name, 0)
+
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Actual = len(resp.Traces)
+			result.Passed = result.Actual == result.Expected
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// expected counts the corpus traces matched by q
+func (s *KnownAnswerSuite) expected(q knownAnswerQuery) int {
+	count := 0
+	for _, spec := range s.specs {
+		if q.match(spec) {
+			count++
+		}
+	}
+	return count
+}
+
+// knownAnswerTraceQL scopes a catalog filter to a run
+func knownAnswerTraceQL(runID, filter string) string {
+	scope := fmt.Sprintf(`resource.%s = "%s"`, generator.RunIDAttribute, runID)
+	if filter == "" {
+		return "{ " + scope + " }"
+	}
+	return "{ " + scope + " && " + filter + " }"
+}
+
+// newRunID returns a random run ID such as "ka-1f2e3d4c5b6a7980"
+func newRunID(prefix string) (string, error) {
+	id := make([]byte, 8)
+	if _, err := cryptoRand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate run ID: %w", err)
+	}
+	return prefix + "-" + hex.EncodeToString(id), nil
+}
+
+// JavaScript-friendly wrapper methods (exported, no context parameter required)
+
+// Ingest pushes the corpus under a fresh run ID and returns the run ID (JavaScript-friendly)
+func (s *KnownAnswerSuite) Ingest() (string, error) {
+	ctx := vuContext(s.vu)
+	return s.ingestCorpus(ctx)
+}
+
+// Run checks the catalog against a previously ingested run (JavaScript-friendly)
+func (s *KnownAnswerSuite) Run(runID string) (*KnownAnswerReport, error) {
+	ctx := vuContext(s.vu)
+	return s.run(ctx, runID)
+}
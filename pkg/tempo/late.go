@@ -0,0 +1,187 @@
+package tempo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// defaultLateSpanFraction is the share of a late trace's spans held back when lateSpanFraction is unset
+	defaultLateSpanFraction = 0.3
+	// defaultLateDelayMin and defaultLateDelayMax bound the delay of held-back spans when unset
+	defaultLateDelayMin = 30 * time.Second
+	defaultLateDelayMax = 120 * time.Second
+)
+
+// lateSpans holds back part of the spans of pushed traces and sends them after a delay, so
+// traces reach Tempo in several pieces, like spans of slow or buffering services
+type lateSpans struct {
+	client   *IngestClient
+	rate     float64
+	fraction float64
+	minDelay time.Duration
+	maxDelay time.Duration
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]*lateBatch
+	closed  bool
+}
+
+// lateBatch is a set of held-back spans waiting for their delivery time
+type lateBatch struct {
+	timer  *time.Timer
+	tenant string // Tenant the rest of the trace was pushed to
+	traces ptrace.Traces
+}
+
+// newLateSpans creates the late-span scheduler of client; it returns nil when lateSpanRate is unset
+func newLateSpans(client *IngestClient, config IngestConfig) *lateSpans {
+	if config.LateSpanRate <= 0 {
+		return nil
+	}
+
+	l := &lateSpans{
+		client:   client,
+		rate:     config.LateSpanRate,
+		fraction: config.LateSpanFraction,
+		minDelay: time.Duration(config.LateDelayMinMs) * time.Millisecond,
+		maxDelay: time.Duration(config.LateDelayMaxMs) * time.Millisecond,
+		pending:  make(map[uint64]*lateBatch),
+	}
+	if l.fraction <= 0 || l.fraction > 1 {
+		l.fraction = defaultLateSpanFraction
+	}
+	if l.minDelay <= 0 {
+		l.minDelay = defaultLateDelayMin
+	}
+	if l.maxDelay <= 0 {
+		l.maxDelay = defaultLateDelayMax
+	}
+	if l.maxDelay < l.minDelay {
+		l.maxDelay = l.minDelay
+	}
+	return l
+}
+
+// holdBack removes non-root spans from a share of the traces and schedules them for later
+// delivery to tenant
+func (l *lateSpans) holdBack(tenant string, traces ...ptrace.Traces) {
+	for _, trace := range traces {
+		if rand.Float64() >= l.rate {
+			continue
+		}
+		tail := splitTrace(trace, l.fraction)
+		if tail.SpanCount() == 0 {
+			continue
+		}
+
+		delay := l.minDelay
+		if l.maxDelay > l.minDelay {
+			delay += time.Duration(rand.Int63n(int64(l.maxDelay - l.minDelay)))
+		}
+		RecordLateSpans(l.client.vu.State(), l.client.metrics, l.client.tags, int64(tail.SpanCount()))
+		l.schedule(tenant, tail, delay)
+	}
+}
+
+// schedule sends traces to tenant after delay; nothing is scheduled once closed, as the client
+// rejects pushes
+func (l *lateSpans) schedule(tenant string, traces ptrace.Traces, delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return
+	}
+	id := l.nextID
+	l.nextID++
+	l.pending[id] = &lateBatch{
+		tenant: tenant,
+		traces: traces,
+		timer: time.AfterFunc(delay, func() {
+			l.mu.Lock()
+			batch, ok := l.pending[id]
+			delete(l.pending, id)
+			l.mu.Unlock()
+			if ok {
+				_ = l.deliver(batch)
+			}
+		}),
+	}
+}
+
+// deliver sends held-back spans to the tenant of their trace; they do not count as new traces
+func (l *lateSpans) deliver(batch *lateBatch) error {
+	ctx := otlp.WithTenant(context.Background(), batch.tenant)
+	l.client.applyBackoff(ctx)
+	traces := batch.traces
+	spans := traces.SpanCount()
+	if _, err := l.client.sendBuffered(ctx, traces, estimateTraceSize(traces), 0, time.Now()); err != nil {
+		RecordDroppedSpans(l.client.vu.State(), l.client.metrics, l.client.tags, DropReasonExportFailed, int64(spans))
+		return err
+	}
+	return nil
+}
+
+// close sends all held-back spans right away, so none are lost when the test ends
+func (l *lateSpans) close() error {
+	l.mu.Lock()
+	l.closed = true
+	batches := make([]*lateBatch, 0, len(l.pending))
+	for id, batch := range l.pending {
+		if batch.timer.Stop() {
+			batches = append(batches, batch)
+		}
+		delete(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	var errs []error
+	for _, batch := range batches {
+		if err := l.deliver(batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// splitTrace moves each non-root span of trace with probability fraction into the returned
+// traces, which keep the resource and scope of the moved spans
+func splitTrace(trace ptrace.Traces, fraction float64) ptrace.Traces {
+	tail := ptrace.NewTraces()
+	for i := 0; i < trace.ResourceSpans().Len(); i++ {
+		rs := trace.ResourceSpans().At(i)
+		var tailRS ptrace.ResourceSpans
+		hasRS := false
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			var tailSS ptrace.ScopeSpans
+			hasSS := false
+			ss.Spans().RemoveIf(func(span ptrace.Span) bool {
+				if span.ParentSpanID().IsEmpty() || rand.Float64() >= fraction {
+					return false
+				}
+				if !hasRS {
+					tailRS, hasRS = tail.ResourceSpans().AppendEmpty(), true
+					rs.Resource().CopyTo(tailRS.Resource())
+					tailRS.SetSchemaUrl(rs.SchemaUrl())
+				}
+				if !hasSS {
+					tailSS, hasSS = tailRS.ScopeSpans().AppendEmpty(), true
+					ss.Scope().CopyTo(tailSS.Scope())
+					tailSS.SetSchemaUrl(ss.SchemaUrl())
+				}
+				span.CopyTo(tailSS.Spans().AppendEmpty())
+				return true
+			})
+		}
+	}
+	return tail
+}
@@ -0,0 +1,41 @@
+package tempo
+
+import "testing"
+
+func TestLateSpansKeepTenant(t *testing.T) {
+	testLateSpansKeepTenant(t, 0)
+}
+
+func TestLateSpansKeepTenantQueued(t *testing.T) {
+	testLateSpansKeepTenant(t, 100000)
+}
+
+// testLateSpansKeepTenant pushes traces rotating over two tenants and checks that the late spans
+// of each trace reach the tenant of its head
+func testLateSpansKeepTenant(t *testing.T, queueSize int) {
+	exporter := &recordingExporter{}
+	c := newTestIngestClient(t, IngestConfig{
+		Tenants:          []string{"tenant-a", "tenant-b"},
+		QueueSize:        queueSize,
+		LateSpanRate:     1,
+		LateSpanFraction: 1,
+		LateDelayMinMs:   3600000,
+	}, exporter)
+
+	traces := testTraces(10)
+	want := traceSpans(traces)
+	for _, trace := range traces {
+		if _, err := c.Push(trace); err != nil {
+			t.Fatalf("push: %v", err)
+		}
+	}
+	// Close delivers the held-back spans and the queued traces right away
+	if err := c.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if len(exporter.spans) != 2 {
+		t.Fatalf("traces reached %d tenants, want 2", len(exporter.spans))
+	}
+	assertSingleTenant(t, exporter, want)
+}
@@ -2,6 +2,7 @@ package tempo
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"go.k6.io/k6/lib"
@@ -15,13 +16,48 @@ type TestContext struct {
 	TargetMBps float64
 }
 
+// RequestTags are per-request metric tags; empty values are not added
+type RequestTags struct {
+	Protocol  string // Ingestion protocol, or "http" and "grpc" for queries
+	Endpoint  string // Endpoint the request was sent to
+	Tenant    string // Set when the request has a tenant
+	TestName  string // Set when the client has a test name
+	Operation string // Set for query workload operations
+	Phase     string // Set when the query workload has a warm-up phase
+}
+
+// apply adds the non-empty request tags to tags
+func (rt RequestTags) apply(tags *metrics.TagSet) *metrics.TagSet {
+	if rt.Protocol != "" {
+		tags = tags.With("protocol", rt.Protocol)
+	}
+	if rt.Endpoint != "" {
+		tags = tags.With("endpoint", rt.Endpoint)
+	}
+	if rt.Tenant != "" {
+		tags = tags.With("tenant", rt.Tenant)
+	}
+	if rt.TestName != "" {
+		tags = tags.With("test_name", rt.TestName)
+	}
+	if rt.Operation != "" {
+		tags = tags.With("operation", rt.Operation)
+	}
+	if rt.Phase != "" {
+		tags = tags.With("phase", rt.Phase)
+	}
+	return tags
+}
+
 // RecordIngestion records ingestion metrics
 func RecordIngestion(state *lib.State, m *tempoMetrics, bytes int64, traces int, duration time.Duration) {
-	RecordIngestionWithContext(state, m, nil, bytes, traces, duration)
+	RecordIngestionWithContext(state, m, nil, RequestTags{}, bytes, traces, duration)
 }
 
-// RecordIngestionWithContext records ingestion metrics with test context tags
-func RecordIngestionWithContext(state *lib.State, m *tempoMetrics, testCtx *TestContext, bytes int64, traces int, duration time.Duration) {
+// RecordIngestionWithContext records ingestion metrics with test context and request tags. The test
+// summary counts the push even without VU state, as for exports of the queue goroutine.
+func RecordIngestionWithContext(state *lib.State, m *tempoMetrics, testCtx *TestContext, rt RequestTags, bytes int64, traces int, duration time.Duration) {
+	testSummary.recordPush(bytes, traces, testCtx)
 	if state == nil || state.Samples == nil || m == nil {
 		return
 	}
@@ -31,7 +67,10 @@ func RecordIngestionWithContext(state *lib.State, m *tempoMetrics, testCtx *Test
 
 	// Get tags from state
 	// Tags must not be nil to avoid nil pointer dereference in k6 metrics system
-	tags := state.Tags.GetCurrentValues().Tags
+	if rt.TestName == "" && testCtx != nil {
+		rt.TestName = testCtx.TestName
+	}
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
 
 	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
 		Time: now,
@@ -74,13 +113,208 @@ func RecordIngestionWithContext(state *lib.State, m *tempoMetrics, testCtx *Test
 	}
 }
 
+// RecordIngestionRetry records a retried export
+func RecordIngestionRetry(state *lib.State, m *tempoMetrics, rt RequestTags) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionRetries,
+			Tags:   tags,
+		},
+		Value: 1,
+	})
+}
+
+// RecordIngestionTimeout records an export that failed because its deadline expired
+func RecordIngestionTimeout(state *lib.State, m *tempoMetrics, rt RequestTags) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionTimeouts,
+			Tags:   tags,
+		},
+		Value: 1,
+	})
+}
+
+// RecordIngestionFailure records an export that failed, once retries and failover were exhausted,
+// and its duration, tagged with the error class
+func RecordIngestionFailure(state *lib.State, m *tempoMetrics, rt RequestTags, errorClass string, duration time.Duration) {
+	testSummary.recordPushFailure(errorClass)
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags).With("error_class", errorClass)
+
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionFailures,
+			Tags:   tags,
+		},
+		Value: 1,
+	})
+
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionDuration,
+			Tags:   tags,
+		},
+		Value: metrics.D(duration),
+	})
+}
+
+// RecordIngestionBackoff records an export that Tempo pushed back on
+func RecordIngestionBackoff(state *lib.State, m *tempoMetrics, rt RequestTags) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionBackoffEvents,
+			Tags:   tags,
+		},
+		Value: 1,
+	})
+}
+
+// RecordPayloadSize records the size of an export request as sent, after compression
+func RecordPayloadSize(state *lib.State, m *tempoMetrics, rt RequestTags, bytes int64) {
+	if state == nil || state.Samples == nil || m == nil || bytes <= 0 {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionPayloadBytes,
+			Tags:   tags,
+		},
+		Value: float64(bytes),
+	})
+}
+
+// RecordRejectedSpans records spans rejected through OTLP partial success
+func RecordRejectedSpans(state *lib.State, m *tempoMetrics, rt RequestTags, rejected int64) {
+	if state == nil || state.Samples == nil || m == nil || rejected <= 0 {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionRejectedSpans,
+			Tags:   tags,
+		},
+		Value: float64(rejected),
+	})
+}
+
+// RecordDroppedSpans records spans the export queue dropped, tagged with the reason
+func RecordDroppedSpans(state *lib.State, m *tempoMetrics, rt RequestTags, reason string, dropped int64) {
+	if state == nil || state.Samples == nil || m == nil || dropped <= 0 {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags).With("reason", reason)
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionDroppedSpans,
+			Tags:   tags,
+		},
+		Value: float64(dropped),
+	})
+}
+
+// RecordLateSpans records spans held back to be sent after a delay
+func RecordLateSpans(state *lib.State, m *tempoMetrics, rt RequestTags, late int64) {
+	if state == nil || state.Samples == nil || m == nil || late <= 0 {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionLateSpans,
+			Tags:   tags,
+		},
+		Value: float64(late),
+	})
+}
+
+// Outcomes of the chaos spans of an export request
+const (
+	ChaosOutcomeRejected = "rejected" // Refused as malformed, by status or partial success
+	ChaosOutcomeAccepted = "accepted" // Accepted although malformed
+	ChaosOutcomeFailed   = "failed"   // The request failed for another reason
+)
+
+// RecordChaosSpans records malformed spans sent, tagged with the malformation and the outcome
+func RecordChaosSpans(state *lib.State, m *tempoMetrics, rt RequestTags, kind, outcome string, spans int64) {
+	if state == nil || state.Samples == nil || m == nil || spans <= 0 {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags).With("kind", kind).With("outcome", outcome)
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IngestionChaosSpans,
+			Tags:   tags,
+		},
+		Value: float64(spans),
+	})
+}
+
 // RecordQuery records query metrics
 func RecordQuery(state *lib.State, m *tempoMetrics, duration time.Duration, spans int, success bool) {
-	RecordQueryDetailed(state, m, duration, spans, success, "", 0)
+	RecordQueryDetailed(state, m, RequestTags{}, duration, spans, success, "", 0)
 }
 
-// RecordQueryDetailed records query metrics with additional context
-func RecordQueryDetailed(state *lib.State, m *tempoMetrics, duration time.Duration, spans int, success bool, queryName string, statusCode int) {
+// RecordQueryDetailed records query metrics with additional context, tagged with the query name
+// and the HTTP status code when they are known. The test summary counts the query even without VU
+// state.
+func RecordQueryDetailed(state *lib.State, m *tempoMetrics, rt RequestTags, duration time.Duration, spans int, success bool, queryName string, statusCode int) {
+	testSummary.recordQuery(queryName, duration, success)
 	if state == nil || state.Samples == nil || m == nil {
 		return
 	}
@@ -89,7 +323,13 @@ func RecordQueryDetailed(state *lib.State, m *tempoMetrics, duration time.Durati
 	ctx := context.Background()
 
 	// Get tags from state
-	tags := state.Tags.GetCurrentValues().Tags
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+	if queryName != "" {
+		tags = tags.With("query", queryName)
+	}
+	if statusCode > 0 {
+		tags = tags.With("status", strconv.Itoa(statusCode))
+	}
 
 	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
 		Time: now,
@@ -143,6 +383,25 @@ func RecordQueryDetailed(state *lib.State, m *tempoMetrics, duration time.Durati
 	}
 }
 
+// RecordQueryRetry records a query request that failed with a retryable error and is retried
+func RecordQueryRetry(state *lib.State, m *tempoMetrics, rt RequestTags) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.QueryRetries,
+			Tags:   tags,
+		},
+		Value: 1,
+	})
+}
+
 // RecordBackoff records backoff events
 func RecordBackoff(state *lib.State, m *tempoMetrics, duration time.Duration) {
 	if state == nil || state.Samples == nil || m == nil {
@@ -174,10 +433,30 @@ func RecordBackoff(state *lib.State, m *tempoMetrics, duration time.Duration) {
 	})
 }
 
+// RecordSharedBackoffWait records how long a workload paused for the backoff of another VU
+func RecordSharedBackoffWait(state *lib.State, m *tempoMetrics, duration time.Duration) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.QuerySharedBackoffWait,
+			Tags:   tags,
+		},
+		Value: metrics.D(duration),
+	})
+}
+
 // MetricsState wraps lib.State and metrics for trace fetch
 type MetricsState struct {
 	State   *lib.State
 	Metrics *tempoMetrics
+	Tags    RequestTags
 }
 
 // RecordTraceFetch records trace fetch metrics
@@ -192,7 +471,7 @@ func RecordTraceFetch(metricsState *MetricsState, duration time.Duration, succes
 	ctx := context.Background()
 
 	// Get tags from state
-	tags := state.Tags.GetCurrentValues().Tags
+	tags := metricsState.Tags.apply(state.Tags.GetCurrentValues().Tags)
 
 	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
 		Time: now,
@@ -215,8 +494,8 @@ func RecordTraceFetch(metricsState *MetricsState, duration time.Duration, succes
 	}
 }
 
-// RecordTimeBucketQuery records time bucket query metrics
-func RecordTimeBucketQuery(state *lib.State, m *tempoMetrics, bucketName string, duration time.Duration) {
+// RecordTimeBucketQuery records time bucket query metrics, tagged with the bucket name
+func RecordTimeBucketQuery(state *lib.State, m *tempoMetrics, rt RequestTags, bucketName string, duration time.Duration) {
 	if state == nil || state.Samples == nil || m == nil {
 		return
 	}
@@ -225,7 +504,7 @@ func RecordTimeBucketQuery(state *lib.State, m *tempoMetrics, bucketName string,
 	ctx := context.Background()
 
 	// Get tags from state
-	tags := state.Tags.GetCurrentValues().Tags
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags).With("bucket", bucketName)
 
 	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
 		Time: now,
@@ -245,3 +524,590 @@ func RecordTimeBucketQuery(state *lib.State, m *tempoMetrics, bucketName string,
 		Value: metrics.D(duration),
 	})
 }
+
+// RecordStreamingSearch records a streaming search: the time until the first partial result,
+// unless no trace was found, and the duration of the whole stream
+func RecordStreamingSearch(state *lib.State, m *tempoMetrics, rt RequestTags, firstResult, duration time.Duration) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	if firstResult > 0 {
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.QueryStreamFirstResult,
+				Tags:   tags,
+			},
+			Value: metrics.D(firstResult),
+		})
+	}
+
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.QueryStreamDuration,
+			Tags:   tags,
+		},
+		Value: metrics.D(duration),
+	})
+}
+
+// RecordSearchInspection records the backend work Tempo reports for a search. Recent Tempo
+// versions no longer report inspected blocks, so the blocks the search covered are used instead.
+func RecordSearchInspection(state *lib.State, m *tempoMetrics, rt RequestTags, resp *SearchResponse) {
+	if state == nil || state.Samples == nil || m == nil || resp == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	blocks := resp.Metrics.InspectedBlocks
+	if blocks == 0 {
+		blocks = resp.Metrics.TotalBlocks
+	}
+
+	for _, sample := range []struct {
+		metric *metrics.Metric
+		value  FlexInt
+	}{
+		{m.QueryInspectedBytes, resp.Metrics.InspectedBytes},
+		{m.QueryInspectedTraces, resp.Metrics.InspectedTraces},
+		{m.QueryInspectedBlocks, blocks},
+	} {
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: sample.metric,
+				Tags:   tags,
+			},
+			Value: float64(sample.value),
+		})
+	}
+}
+
+// RecordResultFreshness records the age of the newest and the oldest trace a search returned, from
+// their start time to the end of the requested window. Searches without results record nothing;
+// traces starting after the end of the window count as 0.
+func RecordResultFreshness(state *lib.State, m *tempoMetrics, rt RequestTags, resp *SearchResponse, windowEnd time.Time) {
+	if state == nil || state.Samples == nil || m == nil || resp == nil {
+		return
+	}
+
+	var newest, oldest int64
+	for _, trace := range resp.Traces {
+		start := int64(trace.StartTime)
+		if start <= 0 {
+			continue
+		}
+		if newest == 0 || start > newest {
+			newest = start
+		}
+		if oldest == 0 || start < oldest {
+			oldest = start
+		}
+	}
+	if newest == 0 {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	for _, sample := range []struct {
+		metric *metrics.Metric
+		start  int64
+	}{
+		{m.QueryResultNewestAge, newest},
+		{m.QueryResultOldestAge, oldest},
+	} {
+		age := max(windowEnd.Sub(time.Unix(0, sample.start)), 0)
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: sample.metric,
+				Tags:   tags,
+			},
+			Value: metrics.D(age),
+		})
+	}
+}
+
+// RecordSearchPage records the latency of one page of a paginated search, tagged with the page number
+func RecordSearchPage(state *lib.State, m *tempoMetrics, rt RequestTags, page int, duration time.Duration) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags).With("page", strconv.Itoa(page))
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.QueryPageDuration,
+			Tags:   tags,
+		},
+		Value: metrics.D(duration),
+	})
+}
+
+// RecordReplay records how late a replayed query started and, when the recorded latency is known,
+// the ratio of the replayed to the recorded latency
+func RecordReplay(state *lib.State, m *tempoMetrics, rt RequestTags, lag, duration time.Duration, originalLatencyMs float64) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := rt.apply(state.Tags.GetCurrentValues().Tags)
+
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.QueryReplayLag,
+			Tags:   tags,
+		},
+		Value: metrics.D(lag),
+	})
+
+	if originalLatencyMs > 0 {
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.QueryReplayLatencyRatio,
+				Tags:   tags,
+			},
+			Value: metrics.D(duration) / originalLatencyMs,
+		})
+	}
+}
+
+// RecordAdaptiveQPS records the current and the sustainable query rate of the adaptive controllers,
+// summed over all VUs
+func RecordAdaptiveQPS(state *lib.State, m *tempoMetrics, current, sustainable float64) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.QueryAdaptiveQPS,
+			Tags:   tags,
+		},
+		Value: current,
+	})
+
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.QuerySustainableQPS,
+			Tags:   tags,
+		},
+		Value: sustainable,
+	})
+}
+
+// RecordTraceDuplicates records whether a fetched trace contained duplicated spans
+func RecordTraceDuplicates(state *lib.State, m *tempoMetrics, duplicates int) {
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	duplicated := 0.0
+	if duplicates > 0 {
+		duplicated = 1
+	}
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.TraceDuplicationRate,
+			Tags:   tags,
+		},
+		Value: duplicated,
+	})
+
+	if duplicates > 0 {
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.TraceDuplicateSpans,
+				Tags:   tags,
+			},
+			Value: float64(duplicates),
+		})
+	}
+}
+
+// RecordStructureViolations records structural violations found in a trace, tagged by kind
+func RecordStructureViolations(state *lib.State, m *tempoMetrics, report *StructureReport) {
+	if state == nil || state.Samples == nil || m == nil || report == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	counts := map[string]int{
+		ViolationContainment: report.ContainmentViolations,
+		ViolationTimestamp:   report.TimestampViolations,
+		ViolationZeroID:      report.ZeroIDs,
+		ViolationOrphan:      report.OrphanedSpans,
+	}
+	for kind, count := range counts {
+		if count == 0 {
+			continue
+		}
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.TraceStructureViolations,
+				Tags:   tags.With("kind", kind),
+			},
+			Value: float64(count),
+		})
+	}
+}
+
+// RecordVerification records read-after-write verification metrics
+func RecordVerification(state *lib.State, m *tempoMetrics, result *VerificationResult) {
+	if result == nil {
+		return
+	}
+	testSummary.recordVerification(result)
+	if state == nil || state.Samples == nil || m == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	notFound := 0.0
+	if !result.Found {
+		notFound = 1
+	}
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.VerificationNotFound,
+			Tags:   tags,
+		},
+		Value: notFound,
+	})
+
+	if result.Found {
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.VerificationFreshness,
+				Tags:   tags,
+			},
+			Value: metrics.D(time.Duration(result.FreshnessMs) * time.Millisecond),
+		})
+	}
+
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.VerificationSpanCompleteness,
+			Tags:   tags,
+		},
+		Value: result.Completeness,
+	})
+
+	success := 0.0
+	if result.Success {
+		success = 1
+	}
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.VerificationSuccess,
+			Tags:   tags,
+		},
+		Value: success,
+	})
+
+	if result.MissingSpans > 0 {
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.VerificationMissingSpans,
+				Tags:   tags,
+			},
+			Value: float64(result.MissingSpans),
+		})
+	}
+}
+
+// RecordTimeToQueryable records the time until a pushed trace was queryable, or a failure when it never was
+func RecordTimeToQueryable(state *lib.State, m *tempoMetrics, result *QueryableResult) {
+	if state == nil || state.Samples == nil || m == nil || result == nil {
+		return
+	}
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	if !result.Queryable {
+		metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+			Time: time.Now(),
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.TimeToQueryableFailures,
+				Tags:   tags,
+			},
+			Value: 1,
+		})
+		return
+	}
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.TimeToQueryable,
+			Tags:   tags,
+		},
+		Value: metrics.D(time.Duration(result.LatencyMs) * time.Millisecond),
+	})
+}
+
+// RecordIntegrity records span and attribute integrity metrics
+func RecordIntegrity(state *lib.State, m *tempoMetrics, diff *IntegrityDiff) {
+	if state == nil || state.Samples == nil || m == nil || diff == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	match := 0.0
+	if diff.Match {
+		match = 1
+	}
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		Time: now,
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.IntegrityMatchRate,
+			Tags:   tags,
+		},
+		Value: match,
+	})
+
+	counts := []struct {
+		metric *metrics.Metric
+		value  int
+	}{
+		{m.IntegrityMissingSpans, len(diff.MissingSpans)},
+		{m.IntegrityMissingAttributes, len(diff.MissingAttributes)},
+		{m.IntegrityMutatedAttributes, len(diff.MutatedAttributes)},
+		{m.IntegrityTimestampDiffs, len(diff.TimestampDiffs)},
+		{m.IntegrityFieldDiffs, len(diff.FieldDiffs)},
+	}
+	for _, c := range counts {
+		if c.value == 0 {
+			continue
+		}
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: c.metric,
+				Tags:   tags,
+			},
+			Value: float64(c.value),
+		})
+	}
+}
+
+// RecordDataLoss records the result of a data-loss audit
+func RecordDataLoss(state *lib.State, m *tempoMetrics, report *DataLossReport) {
+	if state == nil || state.Samples == nil || m == nil || report == nil {
+		return
+	}
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	metrics.PushIfNotDone(context.Background(), state.Samples, metrics.Sample{
+		Time: time.Now(),
+		TimeSeries: metrics.TimeSeries{
+			Metric: m.DataLossRatio,
+			Tags:   tags,
+		},
+		Value: report.LossRatio,
+	})
+}
+
+// RecordMetricsGeneratorValidation records per-series metrics-generator deviations
+func RecordMetricsGeneratorValidation(state *lib.State, m *tempoMetrics, report *MetricsGeneratorReport) {
+	if state == nil || state.Samples == nil || m == nil || report == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	for _, series := range report.Series {
+		seriesTags := tags.With("kind", series.Kind).With("series", series.Series)
+
+		if !series.Found {
+			metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+				Time: now,
+				TimeSeries: metrics.TimeSeries{
+					Metric: m.MetricsGeneratorMissingSeries,
+					Tags:   seriesTags,
+				},
+				Value: 1,
+			})
+			continue
+		}
+
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.MetricsGeneratorDeviation,
+				Tags:   seriesTags,
+			},
+			Value: series.Deviation,
+		})
+	}
+}
+
+// RecordKnownAnswer records the pass/fail outcome of each known-answer query
+func RecordKnownAnswer(state *lib.State, m *tempoMetrics, report *KnownAnswerReport) {
+	if state == nil || state.Samples == nil || m == nil || report == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags
+
+	for _, result := range report.Results {
+		passed := 0.0
+		if result.Passed {
+			passed = 1
+		}
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.KnownAnswerChecks,
+				Tags:   tags.With("query", result.Name),
+			},
+			Value: passed,
+		})
+	}
+}
+
+// RecordRetention records per-trace retrieval and search results of a retention check, tagged with the cohort age
+func RecordRetention(state *lib.State, m *tempoMetrics, check *RetentionCheck) {
+	if state == nil || state.Samples == nil || m == nil || check == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags.With("age", check.Age)
+
+	rates := []struct {
+		metric *metrics.Metric
+		hits   int
+	}{
+		{m.RetentionRetrievalRate, check.Retrieved},
+		{m.RetentionSearchRate, check.Searchable},
+	}
+	for _, r := range rates {
+		for i := 0; i < check.Checked; i++ {
+			value := 0.0
+			if i < r.hits {
+				value = 1
+			}
+			metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+				Time: now,
+				TimeSeries: metrics.TimeSeries{
+					Metric: r.metric,
+					Tags:   tags,
+				},
+				Value: value,
+			})
+		}
+	}
+}
+
+// RecordSchemaViolations records unknown and missing response fields, tagged with endpoint and kind
+func RecordSchemaViolations(state *lib.State, m *tempoMetrics, report *SchemaReport) {
+	if state == nil || state.Samples == nil || m == nil || report == nil {
+		return
+	}
+
+	now := time.Now()
+	ctx := context.Background()
+
+	// Get tags from state
+	tags := state.Tags.GetCurrentValues().Tags.With("endpoint", report.Endpoint)
+
+	counts := []struct {
+		kind  string
+		value int
+	}{
+		{"unknown", len(report.Unknown)},
+		{"missing", len(report.Missing)},
+	}
+	for _, c := range counts {
+		if c.value == 0 {
+			continue
+		}
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			Time: now,
+			TimeSeries: metrics.TimeSeries{
+				Metric: m.SchemaViolations,
+				Tags:   tags.With("kind", c.kind),
+			},
+			Value: float64(c.value),
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/rvargasp/xk6-tempo/pkg/generator"
+)
+
+// MetricsGeneratorReport compares metrics-generator series with the rates expected from the test topology
+type MetricsGeneratorReport struct {
+	Series          []SeriesDeviation `js:"series"`
+	MaxDeviation    float64           `js:"maxDeviation"`    // Largest absolute relative deviation
+	MissingSeries   int               `js:"missingSeries"`   // Expected series absent from Prometheus
+	WithinTolerance bool              `js:"withinTolerance"` // All series found and within tolerance
+}
+
+// SeriesDeviation describes the observed vs expected rate of one series
+type SeriesDeviation struct {
+	Kind         string  `js:"kind"`   // "spanmetrics" or "servicegraph"
+	Series       string  `js:"series"` // Service name, or "client->server" for service-graph edges
+	ExpectedRate float64 `js:"expectedRate"`
+	ObservedRate float64 `js:"observedRate"`
+	Deviation    float64 `js:"deviation"` // (observed - expected) / expected
+	Found        bool    `js:"found"`
+}
+
+// promQueryResponse is the Prometheus instant query API response
+type promQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// ValidateMetricsGenerator queries span-metrics and service-graph rates and compares them with
+// the rates expected from tree and the configured trace rate
+func ValidateMetricsGenerator(ctx context.Context, prom *QueryClient, tree *generator.TraceTreeConfig, config MetricsGeneratorValidationConfig) (*MetricsGeneratorReport, error) {
+	if tree == nil || tree.Root == nil {
+		return nil, fmt.Errorf("trace tree with a root node is required")
+	}
+	if config.TracesPerSecond <= 0 {
+		return nil, fmt.Errorf("tracesPerSecond must be > 0, got %f", config.TracesPerSecond)
+	}
+
+	topology := generator.ExpectedTreeTopology(tree)
+
+	spanRates, err := promInstantQuery(ctx, prom, fmt.Sprintf(config.SpanMetricsQuery, config.Window), func(labels map[string]string) string {
+		return labels["service"]
+	})
+	if err != nil {
+		return nil, fmt.Errorf("span metrics query failed: %w", err)
+	}
+
+	edgeRates, err := promInstantQuery(ctx, prom, fmt.Sprintf(config.ServiceGraphQuery, config.Window), func(labels map[string]string) string {
+		return edgeName(labels["client"], labels["server"])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service graph query failed: %w", err)
+	}
+
+	report := &MetricsGeneratorReport{Series: []SeriesDeviation{}}
+	for service, perTrace := range topology.SpansPerService {
+		report.add("spanmetrics", service, perTrace*config.TracesPerSecond, spanRates)
+	}
+	for edge, perTrace := range topology.CallsPerEdge {
+		report.add("servicegraph", edgeName(edge.Client, edge.Server), perTrace*config.TracesPerSecond, edgeRates)
+	}
+
+	sort.Slice(report.Series, func(i, j int) bool {
+		if report.Series[i].Kind != report.Series[j].Kind {
+			return report.Series[i].Kind > report.Series[j].Kind
+		}
+		return report.Series[i].Series < report.Series[j].Series
+	})
+
+	report.WithinTolerance = report.MissingSeries == 0 && report.MaxDeviation <= config.Tolerance
+	return report, nil
+}
+
+// add records the deviation of one expected series
+func (r *MetricsGeneratorReport) add(kind, series string, expected float64, observed map[string]float64) {
+	entry := SeriesDeviation{
+		Kind:         kind,
+		Series:       series,
+		ExpectedRate: expected,
+		Deviation:    -1,
+	}
+
+	if rate, ok := observed[series]; ok {
+		entry.Found = true
+		entry.ObservedRate = rate
+		if expected > 0 {
+			entry.Deviation = (rate - expected) / expected
+		}
+	} else {
+		r.MissingSeries++
+	}
+
+	r.MaxDeviation = math.Max(r.MaxDeviation, math.Abs(entry.Deviation))
+	r.Series = append(r.Series, entry)
+}
+
+// promInstantQuery runs a PromQL instant query and returns the values keyed by key(labels)
+func promInstantQuery(ctx context.Context, prom *QueryClient, query string, key func(map[string]string) string) (map[string]float64, error) {
+	params := url.Values{}
+	params.Set("query", query)
+
+	req, err := prom.newRequest(ctx, "/api/v1/query", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp promQueryResponse
+	if _, err := prom.doJSON(req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("prometheus returned status %q: %s", resp.Status, resp.Error)
+	}
+
+	values := make(map[string]float64, len(resp.Data.Result))
+	for _, result := range resp.Data.Result {
+		if len(result.Value) != 2 {
+			continue
+		}
+		str, ok := result.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(str, 64)
+		if err != nil || math.IsNaN(value) {
+			continue
+		}
+		values[key(result.Metric)] += value
+	}
+	return values, nil
+}
+
+// edgeName formats a service-graph edge
+func edgeName(client, server string) string {
+	return client + "->" + server
+}
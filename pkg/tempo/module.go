@@ -20,19 +20,79 @@ type tempoMetrics struct {
 	IngestionRateBytesPerSec *metrics.Metric
 	IngestionTracesTotal     *metrics.Metric
 	IngestionDuration        *metrics.Metric
+	IngestionRetries         *metrics.Metric
+	IngestionRejectedSpans   *metrics.Metric
+	IngestionDroppedSpans    *metrics.Metric
+	IngestionLateSpans       *metrics.Metric
+	IngestionChaosSpans      *metrics.Metric
+	IngestionTimeouts        *metrics.Metric
+	IngestionFailures        *metrics.Metric
+	IngestionBackoffEvents   *metrics.Metric
+	IngestionPayloadBytes    *metrics.Metric
 
 	// Query metrics
-	QueryDuration           *metrics.Metric
-	QueryRequestsTotal      *metrics.Metric
-	QueryFailuresTotal      *metrics.Metric
-	QuerySpansReturned      *metrics.Metric
-	QueryFailuresByStatus   *metrics.Metric
-	QueryBackoffEvents      *metrics.Metric
-	QueryBackoffDuration    *metrics.Metric
-	TraceFetchLatency       *metrics.Metric
-	TraceFetchFailures      *metrics.Metric
-	QueryTimeBucketQueries  *metrics.Metric
-	QueryTimeBucketDuration *metrics.Metric
+	QueryDuration            *metrics.Metric
+	QueryRequestsTotal       *metrics.Metric
+	QueryFailuresTotal       *metrics.Metric
+	QuerySpansReturned       *metrics.Metric
+	QueryFailuresByStatus    *metrics.Metric
+	QueryBackoffEvents       *metrics.Metric
+	QueryBackoffDuration     *metrics.Metric
+	TraceFetchLatency        *metrics.Metric
+	TraceFetchFailures       *metrics.Metric
+	QueryTimeBucketQueries   *metrics.Metric
+	QueryTimeBucketDuration  *metrics.Metric
+	QueryStreamFirstResult   *metrics.Metric
+	QueryStreamDuration      *metrics.Metric
+	QueryInspectedBytes      *metrics.Metric
+	QueryInspectedTraces     *metrics.Metric
+	QueryInspectedBlocks     *metrics.Metric
+	QueryResultNewestAge     *metrics.Metric
+	QueryResultOldestAge     *metrics.Metric
+	QueryPageDuration        *metrics.Metric
+	QueryRetries             *metrics.Metric
+	QueryReplayLag           *metrics.Metric
+	QueryReplayLatencyRatio  *metrics.Metric
+	QueryAdaptiveQPS         *metrics.Metric
+	QuerySharedBackoffWait   *metrics.Metric
+	QuerySustainableQPS      *metrics.Metric
+	TraceDuplicationRate     *metrics.Metric
+	TraceDuplicateSpans      *metrics.Metric
+	TraceStructureViolations *metrics.Metric
+
+	// Verification metrics
+	VerificationFreshness        *metrics.Metric
+	VerificationNotFound         *metrics.Metric
+	VerificationSpanCompleteness *metrics.Metric
+	VerificationSuccess          *metrics.Metric
+	VerificationMissingSpans     *metrics.Metric
+	TimeToQueryable              *metrics.Metric
+	TimeToQueryableFailures      *metrics.Metric
+
+	// Integrity metrics
+	IntegrityMatchRate         *metrics.Metric
+	IntegrityMissingSpans      *metrics.Metric
+	IntegrityMissingAttributes *metrics.Metric
+	IntegrityMutatedAttributes *metrics.Metric
+	IntegrityTimestampDiffs    *metrics.Metric
+	IntegrityFieldDiffs        *metrics.Metric
+
+	// Audit metrics
+	DataLossRatio *metrics.Metric
+
+	// Metrics-generator validation metrics
+	MetricsGeneratorDeviation     *metrics.Metric
+	MetricsGeneratorMissingSeries *metrics.Metric
+
+	// Known-answer metrics
+	KnownAnswerChecks *metrics.Metric
+
+	// Schema metrics
+	SchemaViolations *metrics.Metric
+
+	// Retention metrics
+	RetentionRetrievalRate *metrics.Metric
+	RetentionSearchRate    *metrics.Metric
 }
 
 // registerMetrics registers all custom metrics with the k6 registry
@@ -62,6 +122,51 @@ func registerMetrics(vu modules.VU) (*tempoMetrics, error) {
 		return nil, err
 	}
 
+	m.IngestionRetries, err = registry.NewMetric("tempo_ingestion_retries_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IngestionRejectedSpans, err = registry.NewMetric("tempo_ingestion_rejected_spans_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IngestionDroppedSpans, err = registry.NewMetric("tempo_ingestion_dropped_spans_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IngestionLateSpans, err = registry.NewMetric("tempo_ingestion_late_spans_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IngestionChaosSpans, err = registry.NewMetric("tempo_ingestion_chaos_spans_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IngestionTimeouts, err = registry.NewMetric("tempo_ingestion_timeouts_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IngestionFailures, err = registry.NewMetric("tempo_ingestion_failures_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IngestionBackoffEvents, err = registry.NewMetric("tempo_ingestion_backoff_events_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IngestionPayloadBytes, err = registry.NewMetric("tempo_ingestion_payload_bytes", metrics.Trend, metrics.Data)
+	if err != nil {
+		return nil, err
+	}
+
 	// Query metrics
 	m.QueryDuration, err = registry.NewMetric("tempo_query_duration_seconds", metrics.Trend, metrics.Time)
 	if err != nil {
@@ -118,6 +223,198 @@ func registerMetrics(vu modules.VU) (*tempoMetrics, error) {
 		return nil, err
 	}
 
+	m.QueryStreamFirstResult, err = registry.NewMetric("tempo_query_stream_first_result_seconds", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryStreamDuration, err = registry.NewMetric("tempo_query_stream_duration_seconds", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryInspectedBytes, err = registry.NewMetric("tempo_query_inspected_bytes", metrics.Trend, metrics.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryInspectedTraces, err = registry.NewMetric("tempo_query_inspected_traces", metrics.Trend, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryInspectedBlocks, err = registry.NewMetric("tempo_query_inspected_blocks", metrics.Trend, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryResultNewestAge, err = registry.NewMetric("tempo_query_result_newest_age_seconds", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryResultOldestAge, err = registry.NewMetric("tempo_query_result_oldest_age_seconds", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryPageDuration, err = registry.NewMetric("tempo_query_page_duration_seconds", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryRetries, err = registry.NewMetric("tempo_query_retries_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryReplayLag, err = registry.NewMetric("tempo_query_replay_lag_seconds", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryReplayLatencyRatio, err = registry.NewMetric("tempo_query_replay_latency_ratio", metrics.Trend, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QuerySharedBackoffWait, err = registry.NewMetric("tempo_query_shared_backoff_wait_seconds", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QueryAdaptiveQPS, err = registry.NewMetric("tempo_query_adaptive_qps", metrics.Gauge, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.QuerySustainableQPS, err = registry.NewMetric("tempo_query_sustainable_qps", metrics.Gauge, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.TraceDuplicationRate, err = registry.NewMetric("tempo_trace_duplication_rate", metrics.Rate, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.TraceDuplicateSpans, err = registry.NewMetric("tempo_trace_duplicate_spans_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.TraceStructureViolations, err = registry.NewMetric("tempo_trace_structure_violations_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verification metrics
+	m.VerificationFreshness, err = registry.NewMetric("tempo_verification_freshness_seconds", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	m.VerificationNotFound, err = registry.NewMetric("tempo_verification_not_found_rate", metrics.Rate, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.VerificationSpanCompleteness, err = registry.NewMetric("tempo_verification_span_completeness", metrics.Trend, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.VerificationSuccess, err = registry.NewMetric("tempo_verification_success_rate", metrics.Rate, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.VerificationMissingSpans, err = registry.NewMetric("tempo_verification_missing_spans_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.TimeToQueryable, err = registry.NewMetric("tempo_time_to_queryable_seconds", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	m.TimeToQueryableFailures, err = registry.NewMetric("tempo_time_to_queryable_failures_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	// Integrity metrics
+	m.IntegrityMatchRate, err = registry.NewMetric("tempo_integrity_match_rate", metrics.Rate, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IntegrityMissingSpans, err = registry.NewMetric("tempo_integrity_missing_spans_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IntegrityMissingAttributes, err = registry.NewMetric("tempo_integrity_missing_attributes_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IntegrityMutatedAttributes, err = registry.NewMetric("tempo_integrity_mutated_attributes_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IntegrityTimestampDiffs, err = registry.NewMetric("tempo_integrity_timestamp_mismatches_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IntegrityFieldDiffs, err = registry.NewMetric("tempo_integrity_field_mismatches_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	// Audit metrics
+	m.DataLossRatio, err = registry.NewMetric("tempo_data_loss_ratio", metrics.Gauge, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	// Metrics-generator validation metrics
+	m.MetricsGeneratorDeviation, err = registry.NewMetric("tempo_metrics_generator_deviation", metrics.Trend, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.MetricsGeneratorMissingSeries, err = registry.NewMetric("tempo_metrics_generator_missing_series_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	// Known-answer metrics
+	m.KnownAnswerChecks, err = registry.NewMetric("tempo_known_answer_checks", metrics.Rate, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schema metrics
+	m.SchemaViolations, err = registry.NewMetric("tempo_schema_violations_total", metrics.Counter, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retention metrics
+	m.RetentionRetrievalRate, err = registry.NewMetric("tempo_retention_retrieval_rate", metrics.Rate, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	m.RetentionSearchRate, err = registry.NewMetric("tempo_retention_search_rate", metrics.Rate, metrics.Default)
+	if err != nil {
+		return nil, err
+	}
+
 	return m, nil
 }
 
@@ -135,6 +432,20 @@ func getIntValue(v interface{}) (int, bool) {
 	}
 }
 
+// getFloatValue extracts a float from a JS number, which may arrive as an integer
+func getFloatValue(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case int:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	default:
+		return 0, false
+	}
+}
+
 // RootModule is the global module instance
 type RootModule struct{}
 
@@ -142,6 +453,9 @@ type RootModule struct{}
 type ModuleInstance struct {
 	vu      modules.VU
 	metrics *tempoMetrics
+
+	seedIteration int64 // Iteration seedTraces counts seeded traces of
+	seedTraces    int64 // Seeded traces generated in seedIteration
 }
 
 // NewModuleInstance implements the modules.Module interface
@@ -161,34 +475,160 @@ func (r *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
 func (mi *ModuleInstance) Exports() modules.Exports {
 	return modules.Exports{
 		Named: map[string]interface{}{
-			"IngestClient":        mi.newIngestClient,
-			"QueryClient":         mi.newQueryClient,
-			"generateTrace":       mi.generateTrace,
-			"generateBatch":       mi.generateBatch,
-			"createRateLimiter":   mi.createRateLimiter,
-			"createQueryWorkload": mi.createQueryWorkload,
-			"estimateTraceSize":   mi.estimateTraceSize,
-			"calculateThroughput": mi.calculateThroughput,
+			"IngestClient":              mi.newIngestClient,
+			"QueryClient":               mi.newQueryClient,
+			"generateTrace":             mi.generateTrace,
+			"generateBatch":             mi.generateBatch,
+			"generateBatchWithMetadata": mi.generateBatchWithMetadata,
+			"generateBatchParallel":     mi.generateBatchParallel,
+			"createBatchIterator":       mi.createBatchIterator,
+			"createHugeTraceIterator":   mi.createHugeTraceIterator,
+			"registerWorkflow":          mi.registerWorkflow,
+			"registerAttributePack":     mi.registerAttributePack,
+			"loadTopology":              mi.loadTopology,
+			"loadTraces":                mi.loadTraces,
+			"pregenerateTraces":         mi.pregenerateTraces,
+			"createRateLimiter":         mi.createRateLimiter,
+			"createQueryWorkload":       mi.createQueryWorkload,
+			"estimateTraceSize":         mi.estimateTraceSize,
+			"calculateThroughput":       mi.calculateThroughput,
+			"createVerifier":            mi.createVerifier,
+			"compareTraces":             mi.compareTraces,
+			"validateTraceStructure":    mi.validateTraceStructure,
+			"exportTraceJSON":           mi.exportTraceJSON,
+			"compareWithGolden":         mi.compareWithGolden,
+			"auditDataLoss":             mi.auditDataLoss,
+			"validateMetricsGenerator":  mi.validateMetricsGenerator,
+			"createKnownAnswerSuite":    mi.createKnownAnswerSuite,
+			"createRetentionValidator":  mi.createRetentionValidator,
+			"traceql":                   mi.traceql,
+			"createQueryReplay":         mi.createQueryReplay,
+			"createQueryableProbe":      mi.createQueryableProbe,
+			"getSummary":                mi.getSummary,
 		},
 	}
 }
 
 // newIngestClient creates a new Tempo ingestion client
 func (mi *ModuleInstance) newIngestClient(config map[string]interface{}) (*IngestClient, error) {
-	// Convert map to IngestConfig struct
+	client, err := NewIngestClient(mi.vu, parseIngestConfig(config), mi.metrics)
+	if err != nil {
+		return nil, err
+	}
+	client.deriveSeed = mi.deriveSeed
+	return client, nil
+}
+
+// parseIngestConfig converts the JS config of an ingest client to an IngestConfig
+func parseIngestConfig(config map[string]interface{}) IngestConfig {
 	cfg := DefaultIngestConfig()
 	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
 		cfg.Endpoint = endpoint
 	}
+	if endpoints, ok := config["endpoints"].([]interface{}); ok {
+		for _, endpoint := range endpoints {
+			if e, ok := endpoint.(string); ok && e != "" {
+				cfg.Endpoints = append(cfg.Endpoints, e)
+			}
+		}
+	}
+	if endpointStrategy, ok := config["endpointStrategy"].(string); ok && endpointStrategy != "" {
+		cfg.EndpointStrategy = endpointStrategy
+	}
 	if protocol, ok := config["protocol"].(string); ok && protocol != "" {
 		cfg.Protocol = protocol
 	}
 	if tenant, ok := config["tenant"].(string); ok {
 		cfg.Tenant = tenant
 	}
+	if tenants, ok := config["tenants"].([]interface{}); ok {
+		for _, tenant := range tenants {
+			if t, ok := tenant.(string); ok && t != "" {
+				cfg.Tenants = append(cfg.Tenants, t)
+			}
+		}
+	}
+	if tenantStrategy, ok := config["tenantStrategy"].(string); ok && tenantStrategy != "" {
+		cfg.TenantStrategy = tenantStrategy
+	}
+	if tenantWeights, ok := config["tenantWeights"].([]interface{}); ok {
+		for _, weight := range tenantWeights {
+			if w, ok := weight.(float64); ok {
+				cfg.TenantWeights = append(cfg.TenantWeights, w)
+			} else if w, ok := getIntValue(weight); ok {
+				cfg.TenantWeights = append(cfg.TenantWeights, float64(w))
+			}
+		}
+	}
 	if timeout, ok := getIntValue(config["timeout"]); ok && timeout > 0 {
 		cfg.Timeout = timeout
 	}
+	if encoding, ok := config["encoding"].(string); ok && encoding != "" {
+		cfg.Encoding = encoding
+	}
+	if compression, ok := config["compression"].(string); ok && compression != "" {
+		cfg.Compression = compression
+	}
+	cfg.TLS = parseTLSConfig(config["tls"])
+	if proxyURL, ok := config["proxyURL"].(string); ok {
+		cfg.ProxyURL = proxyURL
+	}
+	if headers, ok := config["headers"].(map[string]interface{}); ok {
+		cfg.Headers = parseHeaders(headers)
+	}
+	if retry, ok := config["retry"].(map[string]interface{}); ok {
+		cfg.Retry = parseRetryConfig(retry)
+	}
+	cfg.GRPC = parseGRPCConfig(config["grpc"])
+	if enableBackoff, ok := config["enableBackoff"].(bool); ok {
+		cfg.EnableBackoff = enableBackoff
+	}
+	if minBackoffMs, ok := getIntValue(config["minBackoffMs"]); ok && minBackoffMs > 0 {
+		cfg.MinBackoffMs = minBackoffMs
+	}
+	if maxBackoffMs, ok := getIntValue(config["maxBackoffMs"]); ok && maxBackoffMs > 0 {
+		cfg.MaxBackoffMs = maxBackoffMs
+	}
+	if queueSize, ok := getIntValue(config["queueSize"]); ok && queueSize > 0 {
+		cfg.QueueSize = queueSize
+	}
+	if flushIntervalMs, ok := getIntValue(config["flushIntervalMs"]); ok && flushIntervalMs > 0 {
+		cfg.FlushIntervalMs = flushIntervalMs
+	}
+	if maxBatchBytes, ok := getIntValue(config["maxBatchBytes"]); ok && maxBatchBytes > 0 {
+		cfg.MaxBatchBytes = maxBatchBytes
+	}
+	if streamChunkBytes, ok := getIntValue(config["streamChunkBytes"]); ok && streamChunkBytes > 0 {
+		cfg.StreamChunkBytes = streamChunkBytes
+	}
+	if hugeTraceChunkSpans, ok := getIntValue(config["hugeTraceChunkSpans"]); ok && hugeTraceChunkSpans > 0 {
+		cfg.HugeTraceChunkSpans = hugeTraceChunkSpans
+	}
+	if lateSpanRate, ok := getFloatValue(config["lateSpanRate"]); ok && lateSpanRate >= 0 && lateSpanRate <= 1 {
+		cfg.LateSpanRate = lateSpanRate
+	}
+	if lateSpanFraction, ok := getFloatValue(config["lateSpanFraction"]); ok && lateSpanFraction > 0 && lateSpanFraction <= 1 {
+		cfg.LateSpanFraction = lateSpanFraction
+	}
+	if lateDelayMinMs, ok := getIntValue(config["lateDelayMinMs"]); ok && lateDelayMinMs > 0 {
+		cfg.LateDelayMinMs = lateDelayMinMs
+	}
+	if lateDelayMaxMs, ok := getIntValue(config["lateDelayMaxMs"]); ok && lateDelayMaxMs > 0 {
+		cfg.LateDelayMaxMs = lateDelayMaxMs
+	}
+	if bearerToken, ok := config["bearerToken"].(string); ok {
+		cfg.BearerToken = bearerToken
+	}
+	if bearerTokenFile, ok := config["bearerTokenFile"].(string); ok {
+		cfg.BearerTokenFile = bearerTokenFile
+	}
+	if username, ok := config["username"].(string); ok {
+		cfg.Username = username
+	}
+	if password, ok := config["password"].(string); ok {
+		cfg.Password = password
+	}
+	cfg.OAuth2 = parseOAuth2Config(config["oauth2"])
 	if testName, ok := config["testName"].(string); ok {
 		cfg.TestName = testName
 	}
@@ -198,8 +638,84 @@ func (mi *ModuleInstance) newIngestClient(config map[string]interface{}) (*Inges
 	if targetMBps, ok := config["targetMBps"].(float64); ok && targetMBps > 0 {
 		cfg.TargetMBps = targetMBps
 	}
+	if trackTraceIDs, ok := config["trackTraceIds"].(bool); ok {
+		cfg.TrackTraceIDs = trackTraceIDs
+	}
+	if trackSampleRate, ok := getFloatValue(config["trackSampleRate"]); ok && trackSampleRate > 0 {
+		cfg.TrackSampleRate = trackSampleRate
+	}
+	return cfg
+}
+
+// parseHeaders parses a JS object of header names to string values; other values are ignored
+func parseHeaders(headers map[string]interface{}) map[string]string {
+	parsed := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if str, ok := v.(string); ok {
+			parsed[k] = str
+		}
+	}
+	return parsed
+}
 
-	return NewIngestClient(mi.vu, cfg, mi.metrics)
+// parseRetryConfig converts a JavaScript retry object, keeping defaults for unset fields
+func parseRetryConfig(config map[string]interface{}) RetryConfig {
+	cfg := DefaultRetryConfig()
+	if maxRetries, ok := getIntValue(config["maxRetries"]); ok && maxRetries >= 0 {
+		cfg.MaxRetries = maxRetries
+	}
+	if initialBackoff, ok := config["initialBackoff"].(string); ok && initialBackoff != "" {
+		cfg.InitialBackoff = initialBackoff
+	}
+	if maxBackoff, ok := config["maxBackoff"].(string); ok && maxBackoff != "" {
+		cfg.MaxBackoff = maxBackoff
+	}
+	if statusCodes, ok := config["retryableStatusCodes"].([]interface{}); ok {
+		cfg.RetryableStatusCodes = make([]int, 0, len(statusCodes))
+		for _, code := range statusCodes {
+			if c, ok := getIntValue(code); ok {
+				cfg.RetryableStatusCodes = append(cfg.RetryableStatusCodes, c)
+			}
+		}
+	}
+	if grpcCodes, ok := config["retryableGrpcCodes"].([]interface{}); ok {
+		cfg.RetryableGRPCCodes = make([]string, 0, len(grpcCodes))
+		for _, code := range grpcCodes {
+			if c, ok := code.(string); ok {
+				cfg.RetryableGRPCCodes = append(cfg.RetryableGRPCCodes, c)
+			}
+		}
+	}
+	return cfg
+}
+
+// parseGRPCConfig converts a JavaScript grpc object; it returns nil when v is not an object
+func parseGRPCConfig(v interface{}) *GRPCConfig {
+	config, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	cfg := &GRPCConfig{}
+	if keepaliveTime, ok := config["keepaliveTime"].(string); ok {
+		cfg.KeepaliveTime = keepaliveTime
+	}
+	if keepaliveTimeout, ok := config["keepaliveTimeout"].(string); ok {
+		cfg.KeepaliveTimeout = keepaliveTimeout
+	}
+	if maxMessageSize, ok := getIntValue(config["maxMessageSize"]); ok {
+		cfg.MaxMessageSize = maxMessageSize
+	}
+	if numConnections, ok := getIntValue(config["numConnections"]); ok {
+		cfg.NumConnections = numConnections
+	}
+	if loadBalancing, ok := config["loadBalancing"].(string); ok {
+		cfg.LoadBalancing = loadBalancing
+	}
+	if dialTimeout, ok := config["dialTimeout"].(string); ok {
+		cfg.DialTimeout = dialTimeout
+	}
+	return cfg
 }
 
 // newQueryClient creates a new Tempo query client
@@ -212,6 +728,25 @@ func (mi *ModuleInstance) newQueryClient(config map[string]interface{}) (*QueryC
 	if tenant, ok := config["tenant"].(string); ok {
 		cfg.Tenant = tenant
 	}
+	if tenants, ok := config["tenants"].([]interface{}); ok {
+		for _, tenant := range tenants {
+			if t, ok := tenant.(string); ok && t != "" {
+				cfg.Tenants = append(cfg.Tenants, t)
+			}
+		}
+	}
+	if tenantStrategy, ok := config["tenantStrategy"].(string); ok && tenantStrategy != "" {
+		cfg.TenantStrategy = tenantStrategy
+	}
+	if tenantWeights, ok := config["tenantWeights"].([]interface{}); ok {
+		for _, weight := range tenantWeights {
+			if w, ok := weight.(float64); ok {
+				cfg.TenantWeights = append(cfg.TenantWeights, w)
+			} else if w, ok := getIntValue(weight); ok {
+				cfg.TenantWeights = append(cfg.TenantWeights, float64(w))
+			}
+		}
+	}
 	if timeout, ok := getIntValue(config["timeout"]); ok && timeout > 0 {
 		cfg.Timeout = timeout
 	}
@@ -221,30 +756,365 @@ func (mi *ModuleInstance) newQueryClient(config map[string]interface{}) (*QueryC
 	if bearerTokenFile, ok := config["bearerTokenFile"].(string); ok {
 		cfg.BearerTokenFile = bearerTokenFile
 	}
+	cfg.OAuth2 = parseOAuth2Config(config["oauth2"])
+	if validateStructure, ok := config["validateStructure"].(bool); ok {
+		cfg.ValidateStructure = validateStructure
+	}
+	if validateSchema, ok := config["validateSchema"].(bool); ok {
+		cfg.ValidateSchema = validateSchema
+	}
+	cfg.TLS = parseTLSConfig(config["tls"])
+	if proxyURL, ok := config["proxyURL"].(string); ok {
+		cfg.ProxyURL = proxyURL
+	}
+	if schemaVersion, ok := config["schemaVersion"].(string); ok && schemaVersion != "" {
+		cfg.SchemaVersion = schemaVersion
+	}
+	if grpcEndpoint, ok := config["grpcEndpoint"].(string); ok {
+		cfg.GRPCEndpoint = grpcEndpoint
+	}
+	if traceAPIVersion, ok := config["traceAPIVersion"].(string); ok {
+		cfg.TraceAPIVersion = traceAPIVersion
+	}
+	if traceFormat, ok := config["traceFormat"].(string); ok {
+		cfg.TraceFormat = traceFormat
+	}
+	if testName, ok := config["testName"].(string); ok {
+		cfg.TestName = testName
+	}
+	if retry, ok := config["retry"].(map[string]interface{}); ok {
+		cfg.Retry = parseRetryConfig(retry)
+	}
+
+	return NewQueryClient(mi.vu, cfg, mi.metrics)
+}
+
+// createQueryWorkload creates a query workload manager
+func (mi *ModuleInstance) createQueryWorkload(queryClient *QueryClient, workloadConfig map[string]interface{}, queries map[string]interface{}) (*QueryWorkload, error) {
+	return CreateQueryWorkload(queryClient, mi.vu, mi.metrics, workloadConfig, queries)
+}
+
+// createVerifier creates a read-after-write verifier
+func (mi *ModuleInstance) createVerifier(ingestClient *IngestClient, queryClient *QueryClient, config map[string]interface{}) (*Verifier, error) {
+	cfg := DefaultVerifierConfig()
+	if pollInterval, ok := config["pollInterval"].(string); ok && pollInterval != "" {
+		cfg.PollInterval = pollInterval
+	}
+	if timeout, ok := config["timeout"].(string); ok && timeout != "" {
+		cfg.Timeout = timeout
+	}
+	if delay, ok := config["delay"].(string); ok && delay != "" {
+		cfg.Delay = delay
+	}
+	if search, ok := config["search"].(bool); ok {
+		cfg.Search = search
+	}
+
+	return NewVerifier(ingestClient, queryClient, mi.vu, cfg, mi.metrics)
+}
+
+// createQueryableProbe creates a time-to-queryable probe
+func (mi *ModuleInstance) createQueryableProbe(ingestClient *IngestClient, queryClient *QueryClient, config map[string]interface{}) (*QueryableProbe, error) {
+	cfg := DefaultQueryableProbeConfig()
+	if pollInterval, ok := config["pollInterval"].(string); ok && pollInterval != "" {
+		cfg.PollInterval = pollInterval
+	}
+	if maxPollInterval, ok := config["maxPollInterval"].(string); ok && maxPollInterval != "" {
+		cfg.MaxPollInterval = maxPollInterval
+	}
+	if maxAttempts, ok := getIntValue(config["maxAttempts"]); ok {
+		cfg.MaxAttempts = maxAttempts
+	}
+
+	return NewQueryableProbe(ingestClient, queryClient, mi.vu, cfg, mi.metrics)
+}
+
+// getSummary returns the totals of the test recorded so far by all VUs, for handleSummary
+func (mi *ModuleInstance) getSummary() *TestSummary {
+	return testSummary.summary()
+}
+
+// createKnownAnswerSuite creates a known-answer TraceQL correctness suite
+func (mi *ModuleInstance) createKnownAnswerSuite(ingestClient *IngestClient, queryClient *QueryClient, config map[string]interface{}) (*KnownAnswerSuite, error) {
+	cfg := DefaultKnownAnswerConfig()
+	if traces, ok := getIntValue(config["traces"]); ok && traces > 0 {
+		cfg.Traces = traces
+	}
+	if seed, ok := getIntValue(config["seed"]); ok {
+		cfg.Seed = int64(seed)
+	}
+	if pollInterval, ok := config["pollInterval"].(string); ok && pollInterval != "" {
+		cfg.PollInterval = pollInterval
+	}
+	if timeout, ok := config["timeout"].(string); ok && timeout != "" {
+		cfg.Timeout = timeout
+	}
+
+	return NewKnownAnswerSuite(ingestClient, queryClient, mi.vu, cfg, mi.metrics)
+}
+
+// createRetentionValidator creates a backend/retention validator
+func (mi *ModuleInstance) createRetentionValidator(ingestClient *IngestClient, queryClient *QueryClient, config map[string]interface{}) (*RetentionValidator, error) {
+	cfg := DefaultRetentionConfig()
+	if traces, ok := getIntValue(config["traces"]); ok && traces > 0 {
+		cfg.Traces = traces
+	}
+	if ages, ok := config["ages"].([]interface{}); ok && len(ages) > 0 {
+		cfg.Ages = make([]string, 0, len(ages))
+		for _, age := range ages {
+			if s, ok := age.(string); ok {
+				cfg.Ages = append(cfg.Ages, s)
+			}
+		}
+	}
+
+	return NewRetentionValidator(ingestClient, queryClient, mi.vu, cfg, mi.metrics)
+}
+
+// createQueryReplay creates a replay of a recorded query log
+func (mi *ModuleInstance) createQueryReplay(queryClient *QueryClient, config map[string]interface{}) (*QueryReplay, error) {
+	cfg := DefaultReplayConfig()
+	if path, ok := config["path"].(string); ok {
+		cfg.Path = path
+	}
+	if speed, ok := getFloatValue(config["speed"]); ok {
+		cfg.Speed = speed
+	}
+	if loop, ok := config["loop"].(bool); ok {
+		cfg.Loop = loop
+	}
+
+	return NewQueryReplay(queryClient, mi.vu, cfg, mi.metrics)
+}
+
+// compareTraces compares a generated trace with the copy fetched back from Tempo and records integrity metrics
+func (mi *ModuleInstance) compareTraces(expected ptrace.Traces, actual ptrace.Traces) *IntegrityDiff {
+	diff := CompareTraces(expected, actual)
+	if state := mi.vu.State(); state != nil {
+		RecordIntegrity(state, mi.metrics, diff)
+	}
+	return diff
+}
+
+// exportTraceJSON writes a trace to path as an OTLP JSON golden fixture
+func (mi *ModuleInstance) exportTraceJSON(trace ptrace.Traces, path string) error {
+	return ExportTraceJSON(trace, path)
+}
+
+// compareWithGolden compares a fetched trace with a golden fixture and records integrity metrics
+func (mi *ModuleInstance) compareWithGolden(fetched ptrace.Traces, goldenPath string) (*IntegrityDiff, error) {
+	diff, err := CompareWithGolden(fetched, goldenPath)
+	if err != nil {
+		return nil, err
+	}
+	if state := mi.vu.State(); state != nil {
+		RecordIntegrity(state, mi.metrics, diff)
+	}
+	return diff, nil
+}
+
+// traceql creates a TraceQL query builder
+func (mi *ModuleInstance) traceql() *TraceQLBuilder {
+	return NewTraceQLBuilder()
+}
+
+// validateTraceStructure checks the structure of a generated or fetched trace and records violations
+func (mi *ModuleInstance) validateTraceStructure(trace ptrace.Traces) *StructureReport {
+	report := ValidateStructure(trace)
+	if state := mi.vu.State(); state != nil {
+		RecordStructureViolations(state, mi.metrics, report)
+	}
+	return report
+}
+
+// auditDataLoss looks up a sample of tracked trace IDs, typically from teardown, and records the data loss ratio
+func (mi *ModuleInstance) auditDataLoss(queryClient *QueryClient, config map[string]interface{}) (*DataLossReport, error) {
+	cfg := DefaultDataLossAuditConfig()
+	if sampleSize, ok := getIntValue(config["sampleSize"]); ok && sampleSize > 0 {
+		cfg.SampleSize = sampleSize
+	}
+	if concurrency, ok := getIntValue(config["concurrency"]); ok && concurrency > 0 {
+		cfg.Concurrency = concurrency
+	}
+
+	report, err := AuditDataLoss(vuContext(mi.vu), queryClient, GetTraceRegistry(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if state := mi.vu.State(); state != nil && report.Checked > report.Errors {
+		RecordDataLoss(state, mi.metrics, report)
+	}
+	return report, nil
+}
+
+// validateMetricsGenerator compares span-metrics and service-graph rates in Prometheus with the rates expected from a trace tree
+func (mi *ModuleInstance) validateMetricsGenerator(traceTree map[string]interface{}, config map[string]interface{}) (*MetricsGeneratorReport, error) {
+	tree, err := parseTraceTree(traceTree)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trace tree: %w", err)
+	}
+
+	cfg := parseMetricsGeneratorValidationConfig(config)
+
+	prom, err := NewQueryClient(mi.vu, QueryConfig{
+		Endpoint:        cfg.Endpoint,
+		Tenant:          cfg.Tenant,
+		Timeout:         cfg.Timeout,
+		BearerToken:     cfg.BearerToken,
+		BearerTokenFile: cfg.BearerTokenFile,
+	}, mi.metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := ValidateMetricsGenerator(vuContext(mi.vu), prom, tree, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if state := mi.vu.State(); state != nil {
+		RecordMetricsGeneratorValidation(state, mi.metrics, report)
+	}
+	return report, nil
+}
+
+// parseMetricsGeneratorValidationConfig parses the options of a metrics-generator validation
+func parseMetricsGeneratorValidationConfig(config map[string]interface{}) MetricsGeneratorValidationConfig {
+	cfg := DefaultMetricsGeneratorValidationConfig()
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		cfg.Endpoint = endpoint
+	}
+	if tenant, ok := config["tenant"].(string); ok {
+		cfg.Tenant = tenant
+	}
+	if timeout, ok := getIntValue(config["timeout"]); ok && timeout > 0 {
+		cfg.Timeout = timeout
+	}
+	if bearerToken, ok := config["bearerToken"].(string); ok {
+		cfg.BearerToken = bearerToken
+	}
+	if bearerTokenFile, ok := config["bearerTokenFile"].(string); ok {
+		cfg.BearerTokenFile = bearerTokenFile
+	}
+	if tracesPerSecond, ok := getFloatValue(config["tracesPerSecond"]); ok {
+		cfg.TracesPerSecond = tracesPerSecond
+	}
+	if window, ok := config["window"].(string); ok && window != "" {
+		cfg.Window = window
+	}
+	if tolerance, ok := getFloatValue(config["tolerance"]); ok && tolerance >= 0 {
+		cfg.Tolerance = tolerance
+	}
+	if query, ok := config["spanMetricsQuery"].(string); ok && query != "" {
+		cfg.SpanMetricsQuery = query
+	}
+	if query, ok := config["serviceGraphQuery"].(string); ok && query != "" {
+		cfg.ServiceGraphQuery = query
+	}
+	return cfg
+}
+
+// generateTrace generates a single trace
+func (mi *ModuleInstance) generateTrace(config map[string]interface{}) (ptrace.Traces, error) {
+	cfg, err := parseTraceConfig(config)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	cfg.Seed = mi.deriveSeed(cfg.Seed)
+	return generator.GenerateTrace(cfg), nil
+}
+
+// parseTraceConfig parses and validates a trace generator configuration
+func parseTraceConfig(config map[string]interface{}) (generator.Config, error) {
+	cfg := generator.DefaultConfig()
+	populateConfigFromMap(&cfg, config)
+	if err := validateGeneratorConfig(cfg); err != nil {
+		return generator.Config{}, err
+	}
+	return cfg, nil
+}
+
+// validateGeneratorConfig checks the topology, profiles and start window of a trace generator configuration
+func validateGeneratorConfig(cfg generator.Config) error {
+	if cfg.Topology != nil {
+		if err := cfg.Topology.Validate(); err != nil {
+			return fmt.Errorf("invalid topology: %w", err)
+		}
+	}
+	if err := cfg.ValidateProfiles(); err != nil {
+		return fmt.Errorf("invalid profiles: %w", err)
+	}
+	return cfg.ValidateStartWindow()
+}
+
+// deriveSeed gives each seeded trace of a run its own seed, derived from the configured seed, the
+// VU, the iteration and the number of seeded traces generated before in the iteration. Two runs
+// with the same seed thus generate the same traces in every VU and iteration. 0 stays unseeded.
+func (mi *ModuleInstance) deriveSeed(seed int64) int64 {
+	if seed == 0 {
+		return 0
+	}
+
+	var vuID uint64
+	iteration := int64(-1) // Init context
+	if state := mi.vu.State(); state != nil {
+		vuID, iteration = state.VUID, state.Iteration
+	}
+	if iteration != mi.seedIteration {
+		mi.seedIteration, mi.seedTraces = iteration, 0
+	}
+	mi.seedTraces++
+	return generator.DeriveSeed(seed, int64(vuID), iteration, mi.seedTraces)
+}
 
-	return NewQueryClient(cfg)
+// generateBatch generates a batch of traces
+func (mi *ModuleInstance) generateBatch(config map[string]interface{}) ([]ptrace.Traces, error) {
+	batchConfig, err := mi.parseBatchConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return generator.GenerateBatch(batchConfig), nil
 }
 
-// createQueryWorkload creates a query workload manager
-func (mi *ModuleInstance) createQueryWorkload(queryClient *QueryClient, workloadConfig map[string]interface{}, queries map[string]interface{}) (*QueryWorkload, error) {
-	return CreateQueryWorkload(queryClient, mi.vu, mi.metrics, workloadConfig, queries)
+// generateBatchParallel generates a batch of traces with a pool of workers
+func (mi *ModuleInstance) generateBatchParallel(config map[string]interface{}, workers interface{}) ([]ptrace.Traces, error) {
+	batchConfig, err := mi.parseBatchConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	n, _ := getIntValue(workers)
+	return generator.GenerateBatchParallel(batchConfig, n).Traces, nil
 }
 
-// generateTrace generates a single trace
-func (mi *ModuleInstance) generateTrace(config map[string]interface{}) (ptrace.Traces, error) {
-	cfg := generator.DefaultConfig()
-	populateConfigFromMap(&cfg, config)
-	return generator.GenerateTrace(cfg), nil
+// generateBatchWithMetadata generates a batch of traces and reports its traces, spans and bytes
+func (mi *ModuleInstance) generateBatchWithMetadata(config map[string]interface{}) (*generator.BatchResult, error) {
+	batchConfig, err := mi.parseBatchConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	result := generator.GenerateBatchResult(batchConfig)
+	return &result, nil
 }
 
-// generateBatch generates a batch of traces
-func (mi *ModuleInstance) generateBatch(config map[string]interface{}) ([]ptrace.Traces, error) {
+// parseBatchConfig parses and validates a batch configuration
+func (mi *ModuleInstance) parseBatchConfig(config map[string]interface{}) (generator.BatchConfig, error) {
 	batchConfig := generator.BatchConfig{}
 
-	if targetSize, ok := getIntValue(config["targetSizeBytes"]); ok && targetSize > 0 {
+	if targetSize, ok := getIntValue(config["targetSizeBytes"]); ok {
 		batchConfig.TargetSizeBytes = targetSize
-	} else {
-		return nil, fmt.Errorf("targetSizeBytes is required")
+	}
+	if sizeTolerance, ok := getFloatValue(config["sizeTolerance"]); ok {
+		batchConfig.SizeTolerance = sizeTolerance
+	}
+	if targetSpans, ok := getIntValue(config["targetSpans"]); ok {
+		batchConfig.TargetSpans = targetSpans
+	}
+	if targetTraces, ok := getIntValue(config["targetTraces"]); ok {
+		batchConfig.TargetTraces = targetTraces
+	}
+	if err := batchConfig.Validate(); err != nil {
+		return generator.BatchConfig{}, err
 	}
 
 	// Parse traceConfig
@@ -261,9 +1131,98 @@ func (mi *ModuleInstance) generateBatch(config map[string]interface{}) ([]ptrace
 			}
 		}
 	}
+	if err := validateGeneratorConfig(traceConfig); err != nil {
+		return generator.BatchConfig{}, err
+	}
+	traceConfig.Seed = mi.deriveSeed(traceConfig.Seed)
 	batchConfig.TraceConfig = traceConfig
 
-	return generator.GenerateBatch(batchConfig), nil
+	return batchConfig, nil
+}
+
+// createBatchIterator creates an iterator generating traces in chunks of bounded size
+func (mi *ModuleInstance) createBatchIterator(config map[string]interface{}) (*generator.BatchIterator, error) {
+	cfg, err := parseTraceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Seed = mi.deriveSeed(cfg.Seed)
+	return generator.NewBatchIterator(cfg), nil
+}
+
+// createHugeTraceIterator creates an iterator generating a trace of spanCount spans in parts
+func (mi *ModuleInstance) createHugeTraceIterator(config map[string]interface{}, spanCount int) (*generator.HugeTraceIterator, error) {
+	cfg, err := parseTraceConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Seed = mi.deriveSeed(cfg.Seed)
+	return generator.NewHugeTraceIterator(cfg, spanCount)
+}
+
+// registerWorkflow registers a custom workflow for workflow-based generation
+func (mi *ModuleInstance) registerWorkflow(name string, steps []interface{}) error {
+	workflowSteps := make([]generator.WorkflowStep, 0, len(steps))
+	for i, s := range steps {
+		stepObj, ok := s.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("workflow %q step %d must be an object", name, i)
+		}
+		step := generator.WorkflowStep{
+			SpanKind:   "server",
+			DurationMs: 50,
+		}
+		if service, ok := stepObj["service"].(string); ok {
+			step.Service = service
+		}
+		if operation, ok := stepObj["operation"].(string); ok {
+			step.Operation = operation
+		}
+		if spanKind, ok := stepObj["spanKind"].(string); ok {
+			step.SpanKind = spanKind
+		}
+		if durationMs, ok := getIntValue(stepObj["durationMs"]); ok {
+			step.DurationMs = durationMs
+		}
+		if canParallel, ok := stepObj["canParallel"].(bool); ok {
+			step.CanParallel = canParallel
+		}
+		workflowSteps = append(workflowSteps, step)
+	}
+	return generator.RegisterWorkflow(name, workflowSteps)
+}
+
+// registerAttributePack registers a custom attribute pack of value pools that services can select
+// through semanticPacks
+func (mi *ModuleInstance) registerAttributePack(name string, packObj map[string]interface{}) error {
+	var spec generator.AttributePackSpec
+	if poolsObj, ok := packObj["attributes"].(map[string]interface{}); ok {
+		spec.Attributes = make(map[string][]string, len(poolsObj))
+		for k, v := range poolsObj {
+			if values, ok := v.([]interface{}); ok {
+				pool := make([]string, 0, len(values))
+				for _, value := range values {
+					if str, ok := value.(string); ok {
+						pool = append(pool, str)
+					}
+				}
+				spec.Attributes[k] = pool
+			}
+		}
+	}
+	if kinds, ok := packObj["spanKinds"].([]interface{}); ok {
+		for _, kind := range kinds {
+			if str, ok := kind.(string); ok {
+				spec.SpanKinds = append(spec.SpanKinds, str)
+			}
+		}
+	}
+
+	pack, err := spec.Pack()
+	if err != nil {
+		return fmt.Errorf("attribute pack %q: %w", name, err)
+	}
+	return generator.RegisterAttributePack(name, pack)
 }
 
 // createRateLimiter creates a new byte-based rate limiter
@@ -350,6 +1309,88 @@ func populateConfigFromMap(cfg *generator.Config, config map[string]interface{})
 	if eventCount, ok := getIntValue(config["eventCount"]); ok {
 		cfg.EventCount = eventCount
 	}
+	if attributeValueMode, ok := config["attributeValueMode"].(string); ok {
+		cfg.AttributeValueMode = attributeValueMode
+	}
+	if textValueRatio, ok := getFloatValue(config["textValueRatio"]); ok && textValueRatio >= 0 && textValueRatio <= 1 {
+		cfg.TextValueRatio = textValueRatio
+	}
+	if attributeKeyPool, ok := getIntValue(config["attributeKeyPool"]); ok && attributeKeyPool >= 0 {
+		cfg.AttributeKeyPool = attributeKeyPool
+	}
+	if attributeKeySkew, ok := getFloatValue(config["attributeKeySkew"]); ok && attributeKeySkew >= 0 {
+		cfg.AttributeKeySkew = attributeKeySkew
+	}
+	if attributeTypeWeights, ok := config["attributeTypeWeights"].(map[string]interface{}); ok {
+		cfg.AttributeTypeWeights = make(map[string]float64)
+		for k, v := range attributeTypeWeights {
+			if weight, ok := getFloatValue(v); ok && weight >= 0 {
+				cfg.AttributeTypeWeights[k] = weight
+			}
+		}
+	}
+	if resourceArrayAttrs, ok := config["resourceArrayAttributes"].(map[string]interface{}); ok {
+		cfg.ResourceArrayAttributes = make(map[string][]string)
+		for k, v := range resourceArrayAttrs {
+			if values, ok := v.([]interface{}); ok {
+				strs := make([]string, 0, len(values))
+				for _, value := range values {
+					if str, ok := value.(string); ok {
+						strs = append(strs, str)
+					}
+				}
+				cfg.ResourceArrayAttributes[k] = strs
+			}
+		}
+	}
+	if resourceSchemaURL, ok := config["resourceSchemaUrl"].(string); ok {
+		cfg.ResourceSchemaURL = resourceSchemaURL
+	}
+	if scopeSchemaURL, ok := config["scopeSchemaUrl"].(string); ok {
+		cfg.ScopeSchemaURL = scopeSchemaURL
+	}
+	if scopes, ok := config["instrumentationScopes"].([]interface{}); ok {
+		cfg.InstrumentationScopes = make([]generator.InstrumentationScope, 0, len(scopes))
+		for _, s := range scopes {
+			scopeObj, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			scope := generator.InstrumentationScope{}
+			if name, ok := scopeObj["name"].(string); ok {
+				scope.Name = name
+			}
+			if version, ok := scopeObj["version"].(string); ok {
+				scope.Version = version
+			}
+			if attrs, ok := scopeObj["attributes"].(map[string]interface{}); ok {
+				scope.Attributes = make(map[string]string)
+				for k, v := range attrs {
+					if str, ok := v.(string); ok {
+						scope.Attributes[k] = str
+					}
+				}
+			}
+			if scope.Name != "" {
+				cfg.InstrumentationScopes = append(cfg.InstrumentationScopes, scope)
+			}
+		}
+	}
+	if linkCount, ok := getIntValue(config["linkCount"]); ok && linkCount >= 0 {
+		cfg.LinkCount = linkCount
+	}
+	if linkMode, ok := config["linkMode"].(string); ok {
+		cfg.LinkMode = linkMode
+	}
+	if traceIDFormat, ok := config["traceIdFormat"].(string); ok && traceIDFormat != "" {
+		cfg.TraceIDFormat = traceIDFormat
+	}
+	if samplingProbability, ok := getFloatValue(config["samplingProbability"]); ok && samplingProbability >= 0 && samplingProbability <= 1 {
+		cfg.SamplingProbability = samplingProbability
+	}
+	if unsampledRate, ok := getFloatValue(config["unsampledRate"]); ok && unsampledRate >= 0 && unsampledRate <= 1 {
+		cfg.UnsampledRate = unsampledRate
+	}
 	if resourceAttrs, ok := config["resourceAttributes"].(map[string]interface{}); ok {
 		cfg.ResourceAttributes = make(map[string]string)
 		for k, v := range resourceAttrs {
@@ -364,9 +1405,100 @@ func populateConfigFromMap(cfg *generator.Config, config map[string]interface{})
 	if durationVarianceMs, ok := getIntValue(config["durationVarianceMs"]); ok && durationVarianceMs >= 0 {
 		cfg.DurationVarianceMs = durationVarianceMs
 	}
+	if distribution, ok := config["durationDistribution"].(string); ok {
+		cfg.DurationDistribution = distribution
+	}
+	if sigma, ok := getFloatValue(config["durationSigma"]); ok && sigma >= 0 {
+		cfg.DurationSigma = sigma
+	}
+	if alpha, ok := getFloatValue(config["durationParetoAlpha"]); ok && alpha >= 0 {
+		cfg.DurationParetoAlpha = alpha
+	}
+	if hitRate, ok := getFloatValue(config["durationHitRate"]); ok && hitRate >= 0 && hitRate <= 1 {
+		cfg.DurationHitRate = hitRate
+	}
+	if missMs, ok := getIntValue(config["durationMissMs"]); ok && missMs >= 0 {
+		cfg.DurationMissMs = missMs
+	}
+	if clockSkew, ok := config["clockSkewMs"].(map[string]interface{}); ok {
+		cfg.ClockSkewMs = make(map[string]int)
+		for k, v := range clockSkew {
+			if skewMs, ok := getIntValue(v); ok {
+				cfg.ClockSkewMs[k] = skewMs
+			}
+		}
+	}
+	if earlyChildRate, ok := getFloatValue(config["earlyChildRate"]); ok && earlyChildRate >= 0 && earlyChildRate <= 1 {
+		cfg.EarlyChildRate = earlyChildRate
+	}
+	if lateChildRate, ok := getFloatValue(config["lateChildRate"]); ok && lateChildRate >= 0 && lateChildRate <= 1 {
+		cfg.LateChildRate = lateChildRate
+	}
+	if anomalyOffsetMs, ok := getIntValue(config["anomalyOffsetMs"]); ok && anomalyOffsetMs > 0 {
+		cfg.AnomalyOffsetMs = anomalyOffsetMs
+	}
+	if orphanSpanRate, ok := getFloatValue(config["orphanSpanRate"]); ok && orphanSpanRate >= 0 && orphanSpanRate <= 1 {
+		cfg.OrphanSpanRate = orphanSpanRate
+	}
+	if dropRootRate, ok := getFloatValue(config["dropRootRate"]); ok && dropRootRate >= 0 && dropRootRate <= 1 {
+		cfg.DropRootRate = dropRootRate
+	}
+	if chaosRate, ok := getFloatValue(config["chaosRate"]); ok && chaosRate >= 0 && chaosRate <= 1 {
+		cfg.ChaosRate = chaosRate
+	}
+	if chaosKinds, ok := config["chaosKinds"].([]interface{}); ok {
+		cfg.ChaosKinds = make([]string, 0, len(chaosKinds))
+		for _, v := range chaosKinds {
+			if kind, ok := v.(string); ok {
+				cfg.ChaosKinds = append(cfg.ChaosKinds, kind)
+			}
+		}
+	}
+	if chaosAttributeBytes, ok := getIntValue(config["chaosAttributeBytes"]); ok && chaosAttributeBytes > 0 {
+		cfg.ChaosAttributeBytes = chaosAttributeBytes
+	}
+	if messagingRate, ok := getFloatValue(config["messagingRate"]); ok && messagingRate >= 0 && messagingRate <= 1 {
+		cfg.MessagingRate = messagingRate
+	}
+	if messagingSystem, ok := config["messagingSystem"].(string); ok && messagingSystem != "" {
+		cfg.MessagingSystem = messagingSystem
+	}
+	if queueDelayMinMs, ok := getIntValue(config["queueDelayMinMs"]); ok && queueDelayMinMs >= 0 {
+		cfg.QueueDelayMinMs = queueDelayMinMs
+	}
+	if queueDelayMaxMs, ok := getIntValue(config["queueDelayMaxMs"]); ok && queueDelayMaxMs >= 0 {
+		cfg.QueueDelayMaxMs = queueDelayMaxMs
+	}
+	if startWindowMs, ok := getIntValue(config["startWindowMs"]); ok && startWindowMs >= 0 {
+		cfg.StartWindowMs = startWindowMs
+	}
+	if longTraceRate, ok := getFloatValue(config["longTraceRate"]); ok && longTraceRate >= 0 && longTraceRate <= 1 {
+		cfg.LongTraceRate = longTraceRate
+	}
+	if longTraceMinMs, ok := getIntValue(config["longTraceMinMs"]); ok && longTraceMinMs > 0 {
+		cfg.LongTraceMinMs = longTraceMinMs
+	}
+	if longTraceMaxMs, ok := getIntValue(config["longTraceMaxMs"]); ok && longTraceMaxMs > 0 {
+		cfg.LongTraceMaxMs = longTraceMaxMs
+	}
+	if seed, ok := getIntValue(config["seed"]); ok {
+		cfg.Seed = int64(seed)
+	}
+	if baseTimeMs, ok := getIntValue(config["baseTimeMs"]); ok && baseTimeMs >= 0 {
+		cfg.BaseTimeMs = int64(baseTimeMs)
+	}
 	if errorRate, ok := config["errorRate"].(float64); ok && errorRate >= 0 && errorRate <= 1 {
 		cfg.ErrorRate = errorRate
 	}
+	if errorPropagationRate, ok := getFloatValue(config["errorPropagationRate"]); ok && errorPropagationRate >= 0 && errorPropagationRate <= 1 {
+		cfg.ErrorPropagationRate = errorPropagationRate
+	}
+	if exceptionEvents, ok := config["exceptionEvents"].(bool); ok {
+		cfg.ExceptionEvents = exceptionEvents
+	}
+	if stacktraceFrames, ok := getIntValue(config["stacktraceFrames"]); ok && stacktraceFrames >= 0 {
+		cfg.StacktraceFrames = stacktraceFrames
+	}
 	if maxFanOut, ok := getIntValue(config["maxFanOut"]); ok && maxFanOut > 0 {
 		cfg.MaxFanOut = maxFanOut
 	}
@@ -376,6 +1508,26 @@ func populateConfigFromMap(cfg *generator.Config, config map[string]interface{})
 	if useSemantic, ok := config["useSemanticAttributes"].(bool); ok {
 		cfg.UseSemanticAttributes = useSemantic
 	}
+	if realisticValues, ok := config["realisticValues"].(bool); ok {
+		cfg.RealisticValues = realisticValues
+	}
+	if sqlStatementLength, ok := getIntValue(config["sqlStatementLength"]); ok && sqlStatementLength >= 0 {
+		cfg.SQLStatementLength = sqlStatementLength
+	}
+	if semanticPacks, ok := config["semanticPacks"].(map[string]interface{}); ok {
+		cfg.SemanticPacks = make(map[string][]string)
+		for service, v := range semanticPacks {
+			if packs, ok := v.([]interface{}); ok {
+				names := make([]string, 0, len(packs))
+				for _, pack := range packs {
+					if name, ok := pack.(string); ok {
+						names = append(names, name)
+					}
+				}
+				cfg.SemanticPacks[service] = names
+			}
+		}
+	}
 	if spanKindWeights, ok := config["spanKindWeights"].(map[string]interface{}); ok {
 		cfg.SpanKindWeights = make(map[string]float64)
 		for k, v := range spanKindWeights {
@@ -415,6 +1567,14 @@ func populateConfigFromMap(cfg *generator.Config, config map[string]interface{})
 			}
 		}
 	}
+	if cardinalitySkew, ok := config["cardinalitySkew"].(map[string]interface{}); ok {
+		cfg.CardinalitySkew = make(map[string]float64)
+		for k, v := range cardinalitySkew {
+			if skew, ok := getFloatValue(v); ok && skew >= 0 {
+				cfg.CardinalitySkew[k] = skew
+			}
+		}
+	}
 	// Tree-based generation
 	if useTraceTree, ok := config["useTraceTree"].(bool); ok && useTraceTree {
 		if traceTreeObj, ok := config["traceTree"].(map[string]interface{}); ok {
@@ -425,6 +1585,86 @@ func populateConfigFromMap(cfg *generator.Config, config map[string]interface{})
 			}
 		}
 	}
+	// Topology-based generation
+	if topologyObj, ok := config["topology"].(map[string]interface{}); ok {
+		cfg.Topology = parseCallGraph(topologyObj)
+	}
+	// Mixed workloads
+	if profilesArr, ok := config["profiles"].([]interface{}); ok {
+		cfg.Profiles = parseTraceProfiles(profilesArr)
+	}
+}
+
+// parseTraceProfiles parses weighted trace profiles; each profile's config starts from the defaults
+func parseTraceProfiles(profilesArr []interface{}) []generator.TraceProfile {
+	profiles := make([]generator.TraceProfile, 0, len(profilesArr))
+	for _, v := range profilesArr {
+		profileObj, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		profile := generator.TraceProfile{Config: generator.DefaultConfig()}
+		if weight, ok := getFloatValue(profileObj["weight"]); ok {
+			profile.Weight = weight
+		}
+		if configObj, ok := profileObj["config"].(map[string]interface{}); ok {
+			populateConfigFromMap(&profile.Config, configObj)
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// parseCallGraph parses a weighted call graph from a JavaScript object; Validate reports
+// missing or inconsistent fields
+func parseCallGraph(jsObj map[string]interface{}) *generator.CallGraphConfig {
+	graph := &generator.CallGraphConfig{}
+
+	if nodesArr, ok := jsObj["nodes"].([]interface{}); ok {
+		for _, v := range nodesArr {
+			nodeObj, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			node := generator.CallGraphNode{}
+			node.Service, _ = nodeObj["service"].(string)
+			if operations, ok := nodeObj["operations"].([]interface{}); ok {
+				for _, op := range operations {
+					if str, ok := op.(string); ok {
+						node.Operations = append(node.Operations, str)
+					}
+				}
+			}
+			if entryWeight, ok := getFloatValue(nodeObj["entryWeight"]); ok {
+				node.EntryWeight = entryWeight
+			}
+			graph.Nodes = append(graph.Nodes, node)
+		}
+	}
+
+	if edgesArr, ok := jsObj["edges"].([]interface{}); ok {
+		for _, v := range edgesArr {
+			edgeObj, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			edge := generator.CallGraphEdge{}
+			edge.From, _ = edgeObj["from"].(string)
+			edge.To, _ = edgeObj["to"].(string)
+			if weight, ok := getFloatValue(edgeObj["weight"]); ok {
+				edge.Weight = weight
+			}
+			if latencyObj, ok := edgeObj["latency"].(map[string]interface{}); ok {
+				edge.Latency = parseDurationConfig(latencyObj)
+			}
+			if errorRate, ok := getFloatValue(edgeObj["errorRate"]); ok {
+				edge.ErrorRate = errorRate
+			}
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	return graph
 }
 
 // parseTraceTree parses a trace tree from a JavaScript object
@@ -462,6 +1702,16 @@ func parseTraceTree(jsObj map[string]interface{}) (*generator.TraceTreeConfig, e
 			}
 		}
 
+		// Parse skew
+		if skewObj, ok := contextObj["skew"].(map[string]interface{}); ok {
+			ctx.Skew = make(map[string]float64)
+			for k, v := range skewObj {
+				if skew, ok := getFloatValue(v); ok && skew >= 0 {
+					ctx.Skew[k] = skew
+				}
+			}
+		}
+
 		config.Context = ctx
 	}
 
@@ -479,7 +1729,7 @@ func parseTraceTree(jsObj map[string]interface{}) (*generator.TraceTreeConfig, e
 		if enableTags, ok := defaultsObj["enableTags"].(bool); ok {
 			defs.EnableTags = enableTags
 		}
-		if tagDensity, ok := defaultsObj["tagDensity"].(float64); ok {
+		if tagDensity, ok := getFloatValue(defaultsObj["tagDensity"]); ok {
 			defs.TagDensity = tagDensity
 		}
 
@@ -504,6 +1754,28 @@ func parseTraceTree(jsObj map[string]interface{}) (*generator.TraceTreeConfig, e
 		return nil, fmt.Errorf("root node is required")
 	}
 
+	// Parse subtrees
+	if subtreesObj, ok := jsObj["subtrees"].(map[string]interface{}); ok {
+		config.Subtrees = make(map[string]*generator.TraceTreeNode, len(subtreesObj))
+		for name, v := range subtreesObj {
+			subtreeObj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("subtree %q must be an object", name)
+			}
+			subtree, err := parseTraceTreeNode(subtreeObj)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse subtree %q: %v", name, err)
+			}
+			config.Subtrees[name] = subtree
+		}
+	}
+	if maxRefDepth, ok := getIntValue(jsObj["maxRefDepth"]); ok {
+		config.MaxRefDepth = maxRefDepth
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
 	return config, nil
 }
 
@@ -511,6 +1783,12 @@ func parseTraceTree(jsObj map[string]interface{}) (*generator.TraceTreeConfig, e
 func parseTraceTreeNode(jsObj map[string]interface{}) (*generator.TraceTreeNode, error) {
 	node := &generator.TraceTreeNode{}
 
+	// Ref, standing for a subtree
+	if ref, ok := jsObj["ref"].(string); ok && ref != "" {
+		node.Ref = ref
+		return node, nil
+	}
+
 	// Service (required)
 	if service, ok := jsObj["service"].(string); ok {
 		node.Service = service
@@ -540,24 +1818,66 @@ func parseTraceTreeNode(jsObj map[string]interface{}) (*generator.TraceTreeNode,
 		}
 	}
 
+	// Tag values
+	if tagValuesObj, ok := jsObj["tagValues"].(map[string]interface{}); ok {
+		node.TagValues = make(map[string]generator.TagValueSpec, len(tagValuesObj))
+		for k, v := range tagValuesObj {
+			specObj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("tagValues[%s] must be an object", k)
+			}
+			spec := generator.TagValueSpec{}
+			if pool, ok := specObj["pool"].(string); ok {
+				spec.Pool = pool
+			}
+			if cardinality, ok := getIntValue(specObj["cardinality"]); ok {
+				spec.Cardinality = cardinality
+			}
+			if oneOf, ok := specObj["oneOf"].([]interface{}); ok {
+				for _, value := range oneOf {
+					if str, ok := value.(string); ok {
+						spec.OneOf = append(spec.OneOf, str)
+					}
+				}
+			}
+			if weights, ok := specObj["weights"].([]interface{}); ok {
+				for _, w := range weights {
+					if weight, ok := getFloatValue(w); ok {
+						spec.Weights = append(spec.Weights, weight)
+					}
+				}
+			}
+			node.TagValues[k] = spec
+		}
+	}
+
+	// Attribute pools
+	if poolsObj, ok := jsObj["attributePools"].(map[string]interface{}); ok {
+		node.AttributePools = make(map[string][]string)
+		for k, v := range poolsObj {
+			if values, ok := v.([]interface{}); ok {
+				pool := make([]string, 0, len(values))
+				for _, value := range values {
+					if str, ok := value.(string); ok {
+						pool = append(pool, str)
+					}
+				}
+				node.AttributePools[k] = pool
+			}
+		}
+	}
+
 	// Duration
 	if durationObj, ok := jsObj["duration"].(map[string]interface{}); ok {
-		dur := generator.DurationConfig{}
-		if baseMs, ok := getIntValue(durationObj["baseMs"]); ok {
-			dur.BaseMs = baseMs
-		} else if baseMsFloat, ok := durationObj["baseMs"].(float64); ok {
-			dur.BaseMs = int(baseMsFloat)
-		}
-		if varianceMs, ok := getIntValue(durationObj["varianceMs"]); ok {
-			dur.VarianceMs = varianceMs
-		} else if varianceMsFloat, ok := durationObj["varianceMs"].(float64); ok {
-			dur.VarianceMs = int(varianceMsFloat)
+		dur := parseDurationConfig(durationObj)
+		if !generator.IsDistribution(dur.Distribution) {
+			return nil, fmt.Errorf("unknown duration distribution %q", dur.Distribution)
 		}
 		node.Duration = dur
 	}
 
 	// ErrorRate
-	if errorRate, ok := jsObj["errorRate"].(float64); ok {
+	if errorRate, ok := getFloatValue(jsObj["errorRate"]); ok {
 		node.ErrorRate = errorRate
 	}
 
@@ -566,6 +1886,46 @@ func parseTraceTreeNode(jsObj map[string]interface{}) (*generator.TraceTreeNode,
 		node.ErrorPropagates = errorPropagates
 	}
 
+	// Events
+	if eventsArr, ok := jsObj["events"].([]interface{}); ok {
+		node.Events = make([]generator.TraceTreeEvent, 0, len(eventsArr))
+		for i, e := range eventsArr {
+			eventObj, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("event %d must be an object", i)
+			}
+			event := generator.TraceTreeEvent{Attrs: parseStringMap(eventObj["attrs"])}
+			if name, ok := eventObj["name"].(string); ok && name != "" {
+				event.Name = name
+			} else {
+				return nil, fmt.Errorf("name is required for event %d", i)
+			}
+			if offsetPct, ok := getFloatValue(eventObj["offsetPct"]); ok {
+				if offsetPct < 0 || offsetPct > 100 {
+					return nil, fmt.Errorf("event %q: offsetPct must be in range [0, 100], got %f", event.Name, offsetPct)
+				}
+				event.OffsetPct = offsetPct
+			}
+			node.Events = append(node.Events, event)
+		}
+	}
+
+	// Links
+	if linksArr, ok := jsObj["links"].([]interface{}); ok {
+		node.Links = make([]generator.TraceTreeLink, 0, len(linksArr))
+		for i, l := range linksArr {
+			linkObj, ok := l.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("link %d must be an object", i)
+			}
+			link := generator.TraceTreeLink{Attrs: parseStringMap(linkObj["attrs"])}
+			if operation, ok := linkObj["operation"].(string); ok {
+				link.Operation = operation
+			}
+			node.Links = append(node.Links, link)
+		}
+	}
+
 	// Children
 	if childrenArr, ok := jsObj["children"].([]interface{}); ok {
 		node.Children = make([]generator.TraceTreeEdge, 0, len(childrenArr))
@@ -583,12 +1943,58 @@ func parseTraceTreeNode(jsObj map[string]interface{}) (*generator.TraceTreeNode,
 	return node, nil
 }
 
+// parseStringMap parses an object of string values, skipping other values
+func parseStringMap(v interface{}) map[string]string {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	m := make(map[string]string, len(obj))
+	for k, value := range obj {
+		if str, ok := value.(string); ok {
+			m[k] = str
+		}
+	}
+	return m
+}
+
+// parseDurationConfig parses the duration of a tree node or the latency of a topology edge
+func parseDurationConfig(jsObj map[string]interface{}) generator.DurationConfig {
+	dur := generator.DurationConfig{}
+	if baseMs, ok := getIntValue(jsObj["baseMs"]); ok {
+		dur.BaseMs = baseMs
+	} else if baseMsFloat, ok := jsObj["baseMs"].(float64); ok {
+		dur.BaseMs = int(baseMsFloat)
+	}
+	if varianceMs, ok := getIntValue(jsObj["varianceMs"]); ok {
+		dur.VarianceMs = varianceMs
+	} else if varianceMsFloat, ok := jsObj["varianceMs"].(float64); ok {
+		dur.VarianceMs = int(varianceMsFloat)
+	}
+	if distribution, ok := jsObj["distribution"].(string); ok {
+		dur.Distribution = distribution
+	}
+	if sigma, ok := getFloatValue(jsObj["sigma"]); ok {
+		dur.Sigma = sigma
+	}
+	if alpha, ok := getFloatValue(jsObj["alpha"]); ok {
+		dur.Alpha = alpha
+	}
+	if hitRate, ok := getFloatValue(jsObj["hitRate"]); ok {
+		dur.HitRate = hitRate
+	}
+	if missMs, ok := getIntValue(jsObj["missMs"]); ok {
+		dur.MissMs = missMs
+	}
+	return dur
+}
+
 // parseTraceTreeEdge parses a tree edge
 func parseTraceTreeEdge(jsObj map[string]interface{}) (*generator.TraceTreeEdge, error) {
 	edge := &generator.TraceTreeEdge{}
 
 	// Weight
-	if weight, ok := jsObj["weight"].(float64); ok {
+	if weight, ok := getFloatValue(jsObj["weight"]); ok {
 		edge.Weight = weight
 	}
 
@@ -626,3 +2032,180 @@ func parseTraceTreeEdge(jsObj map[string]interface{}) (*generator.TraceTreeEdge,
 
 	return edge, nil
 }
+
+// loadTopology compiles a YAML or JSON topology file into a trace tree, returned as an object
+// usable as traceTree in generator configs and validateMetricsGenerator
+func (mi *ModuleInstance) loadTopology(path string) (map[string]interface{}, error) {
+	config, err := generator.LoadTopology(path)
+	if err != nil {
+		return nil, err
+	}
+	return traceTreeToMap(config), nil
+}
+
+// loadTraces loads an OTLP trace corpus, e.g. exported by the collector file exporter, for replay
+func (mi *ModuleInstance) loadTraces(path string, config map[string]interface{}) (*TraceCorpus, error) {
+	return LoadTraceCorpus(path, parseCorpusConfig(config))
+}
+
+// pregenerateTraces generates a corpus of template traces once per test, to replay with new IDs and timestamps
+func (mi *ModuleInstance) pregenerateTraces(name string, config map[string]interface{}) (*TraceCorpus, error) {
+	templates := defaultCorpusTemplates
+	if t, ok := getIntValue(config["templates"]); ok {
+		templates = t
+	}
+	traceConfig := generator.DefaultConfig()
+	if traceCfgMap, ok := config["traceConfig"].(map[string]interface{}); ok {
+		var err error
+		if traceConfig, err = parseTraceConfig(traceCfgMap); err != nil {
+			return nil, err
+		}
+	}
+	return GenerateTraceCorpus(name, templates, traceConfig, parseCorpusConfig(config))
+}
+
+// parseCorpusConfig parses the replay options of a trace corpus
+func parseCorpusConfig(config map[string]interface{}) CorpusConfig {
+	cfg := DefaultCorpusConfig()
+	if retime, ok := config["retime"].(bool); ok {
+		cfg.Retime = retime
+	}
+	if regenerateIDs, ok := config["regenerateIds"].(bool); ok {
+		cfg.RegenerateIDs = regenerateIDs
+	}
+	if hashKeys, ok := config["hashKeys"].([]interface{}); ok {
+		for _, key := range hashKeys {
+			if k, ok := key.(string); ok && k != "" {
+				cfg.HashKeys = append(cfg.HashKeys, k)
+			}
+		}
+	}
+	if hashSalt, ok := config["hashSalt"].(string); ok {
+		cfg.HashSalt = hashSalt
+	}
+	if prefixes, ok := config["dropKeyPrefixes"].([]interface{}); ok {
+		for _, prefix := range prefixes {
+			if p, ok := prefix.(string); ok && p != "" {
+				cfg.DropKeyPrefixes = append(cfg.DropKeyPrefixes, p)
+			}
+		}
+	}
+	cfg.ServiceNames = parseStringMap(config["serviceNames"])
+	return cfg
+}
+
+// traceTreeToMap converts a trace tree into the object shape parsed by parseTraceTree
+func traceTreeToMap(config *generator.TraceTreeConfig) map[string]interface{} {
+	subtrees := make(map[string]interface{}, len(config.Subtrees))
+	for name, subtree := range config.Subtrees {
+		subtrees[name] = traceTreeNodeToMap(subtree)
+	}
+	return map[string]interface{}{
+		"seed": config.Seed,
+		"context": map[string]interface{}{
+			"propagate":   toInterfaceSlice(config.Context.Propagate),
+			"cardinality": toInterfaceMap(config.Context.Cardinality),
+			"skew":        toInterfaceMap(config.Context.Skew),
+		},
+		"defaults": map[string]interface{}{
+			"useSemanticAttributes": config.Defaults.UseSemanticAttributes,
+			"enableTags":            config.Defaults.EnableTags,
+			"tagDensity":            config.Defaults.TagDensity,
+		},
+		"root":        traceTreeNodeToMap(config.Root),
+		"subtrees":    subtrees,
+		"maxRefDepth": config.MaxRefDepth,
+	}
+}
+
+// traceTreeNodeToMap converts a tree node into the object shape parsed by parseTraceTreeNode
+func traceTreeNodeToMap(node *generator.TraceTreeNode) map[string]interface{} {
+	if node.Ref != "" {
+		return map[string]interface{}{"ref": node.Ref}
+	}
+	tags := make(map[string]interface{}, len(node.Tags))
+	for k, v := range node.Tags {
+		tags[k] = v
+	}
+	tagValues := make(map[string]interface{}, len(node.TagValues))
+	for k, spec := range node.TagValues {
+		tagValues[k] = map[string]interface{}{
+			"pool":        spec.Pool,
+			"cardinality": spec.Cardinality,
+			"oneOf":       toInterfaceSlice(spec.OneOf),
+			"weights":     toInterfaceSlice(spec.Weights),
+		}
+	}
+	pools := make(map[string]interface{}, len(node.AttributePools))
+	for k, v := range node.AttributePools {
+		pools[k] = toInterfaceSlice(v)
+	}
+	events := make([]interface{}, 0, len(node.Events))
+	for _, event := range node.Events {
+		events = append(events, map[string]interface{}{
+			"name":      event.Name,
+			"attrs":     toInterfaceMap(event.Attrs),
+			"offsetPct": event.OffsetPct,
+		})
+	}
+	links := make([]interface{}, 0, len(node.Links))
+	for _, link := range node.Links {
+		links = append(links, map[string]interface{}{
+			"operation": link.Operation,
+			"attrs":     toInterfaceMap(link.Attrs),
+		})
+	}
+	children := make([]interface{}, 0, len(node.Children))
+	for _, edge := range node.Children {
+		children = append(children, map[string]interface{}{
+			"weight":   edge.Weight,
+			"parallel": edge.Parallel,
+			"count": map[string]interface{}{
+				"min": edge.Count.Min,
+				"max": edge.Count.Max,
+			},
+			"node": traceTreeNodeToMap(edge.Node),
+		})
+	}
+
+	return map[string]interface{}{
+		"service":        node.Service,
+		"operation":      node.Operation,
+		"spanKind":       node.SpanKind,
+		"tags":           tags,
+		"tagValues":      tagValues,
+		"attributePools": pools,
+		"duration": map[string]interface{}{
+			"baseMs":       node.Duration.BaseMs,
+			"varianceMs":   node.Duration.VarianceMs,
+			"distribution": node.Duration.Distribution,
+			"sigma":        node.Duration.Sigma,
+			"alpha":        node.Duration.Alpha,
+			"hitRate":      node.Duration.HitRate,
+			"missMs":       node.Duration.MissMs,
+		},
+		"errorRate":       node.ErrorRate,
+		"errorPropagates": node.ErrorPropagates,
+		"events":          events,
+		"links":           links,
+		"children":        children,
+	}
+}
+
+// toInterfaceSlice converts values into a JS array
+func toInterfaceSlice[V any](values []V) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+// toInterfaceMap converts a map into a JS object
+func toInterfaceMap[V any](values map[string]V) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		result[k] = v
+	}
+	return result
+}
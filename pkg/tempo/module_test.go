@@ -0,0 +1,61 @@
+package tempo
+
+import "testing"
+
+func TestParseMetricsGeneratorValidationConfigIntegers(t *testing.T) {
+	// goja hands integer JS numbers over as int64
+	cfg := parseMetricsGeneratorValidationConfig(map[string]interface{}{
+		"tracesPerSecond": int64(10),
+		"tolerance":       int64(0),
+	})
+	if cfg.TracesPerSecond != 10 {
+		t.Errorf("TracesPerSecond = %v, want 10", cfg.TracesPerSecond)
+	}
+	if cfg.Tolerance != 0 {
+		t.Errorf("Tolerance = %v, want 0", cfg.Tolerance)
+	}
+}
+
+func TestParseMetricsGeneratorValidationConfigFloats(t *testing.T) {
+	cfg := parseMetricsGeneratorValidationConfig(map[string]interface{}{
+		"tracesPerSecond": 2.5,
+		"tolerance":       0.25,
+	})
+	if cfg.TracesPerSecond != 2.5 {
+		t.Errorf("TracesPerSecond = %v, want 2.5", cfg.TracesPerSecond)
+	}
+	if cfg.Tolerance != 0.25 {
+		t.Errorf("Tolerance = %v, want 0.25", cfg.Tolerance)
+	}
+}
+
+func TestParseIngestConfigTrackSampleRate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  float64
+	}{
+		{name: "integer", value: int64(1), want: 1},
+		{name: "float", value: 0.5, want: 0.5},
+		{name: "zero keeps the default", value: int64(0), want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := parseIngestConfig(map[string]interface{}{"trackSampleRate": tt.value})
+			if cfg.TrackSampleRate != tt.want {
+				t.Errorf("TrackSampleRate = %v, want %v", cfg.TrackSampleRate, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTraceConfigStartWindow(t *testing.T) {
+	if _, err := parseTraceConfig(map[string]interface{}{"startWindowMs": int64(500)}); err == nil {
+		t.Error("parseTraceConfig() with startWindowMs 500 succeeded, want an error")
+	}
+	for _, window := range []int64{0, 1000} {
+		if _, err := parseTraceConfig(map[string]interface{}{"startWindowMs": window}); err != nil {
+			t.Errorf("parseTraceConfig() with startWindowMs %d error = %v", window, err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+)
+
+const (
+	// oauth2DefaultLifetime is assumed when the token response has no expires_in
+	oauth2DefaultLifetime = time.Hour
+	// oauth2RefreshFraction is the part of a token's lifetime after which it is refreshed in the background
+	oauth2RefreshFraction = 0.8
+)
+
+// OAuth2Config represents OAuth2 client-credentials settings
+type OAuth2Config struct {
+	TokenURL     string   `js:"tokenURL"`     // Token endpoint of the identity provider
+	ClientID     string   `js:"clientID"`     // Client ID
+	ClientSecret string   `js:"clientSecret"` // Client secret, sent in the request body
+	Scopes       []string `js:"scopes"`       // Scopes requested with the token (optional)
+}
+
+// oauth2TokenSource fetches client-credentials tokens and refreshes them before they expire
+type oauth2TokenSource struct {
+	config OAuth2Config
+	client *http.Client
+
+	fetchMu sync.Mutex // Serializes token requests
+
+	mu         sync.Mutex
+	token      string
+	expiry     time.Time
+	refreshAt  time.Time
+	refreshing bool
+}
+
+// oauth2Sources holds one token source per set of credentials and transport, shared by all VUs
+var oauth2Sources sync.Map
+
+// getOAuth2TokenSource returns the shared token source for config. Token requests use the
+// transport of the owning client: opts holds its proxy and built TLS config, and tlsConfig the
+// settings it was built from. Sources with different transports are not shared. No token is
+// requested until first use.
+func getOAuth2TokenSource(config *OAuth2Config, tlsConfig *TLSConfig, opts otlp.Options) (*oauth2TokenSource, error) {
+	if config.TokenURL == "" || config.ClientID == "" {
+		return nil, fmt.Errorf("oauth2 requires tokenURL and clientID")
+	}
+
+	transport := ""
+	if tlsConfig != nil {
+		transport = fmt.Sprintf("%+v", *tlsConfig)
+	}
+	if opts.Proxy != nil {
+		transport += " proxy=" + opts.Proxy.String()
+	}
+	key := strings.Join([]string{config.TokenURL, config.ClientID, config.ClientSecret, strings.Join(config.Scopes, " "), transport}, "\x00")
+	source, _ := oauth2Sources.LoadOrStore(key, &oauth2TokenSource{
+		config: *config,
+		client: opts.HTTPClient(30 * time.Second),
+	})
+	return source.(*oauth2TokenSource), nil
+}
+
+// Authorization returns the Authorization header value. A valid token is returned from the cache
+// and refreshed in the background once it nears expiry; an expired token is replaced before returning.
+func (s *oauth2TokenSource) Authorization(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	now := time.Now()
+	token, valid := s.token, now.Before(s.expiry)
+	if valid && now.After(s.refreshAt) && !s.refreshing {
+		s.refreshing = true
+		go s.backgroundRefresh()
+	}
+	s.mu.Unlock()
+
+	if !valid {
+		var err error
+		if token, err = s.fetch(ctx, false); err != nil {
+			return "", err
+		}
+	}
+	return "Bearer " + token, nil
+}
+
+// backgroundRefresh replaces the token ahead of expiry. Failures are left to the next caller,
+// which fetches synchronously once the token has expired.
+func (s *oauth2TokenSource) backgroundRefresh() {
+	_, _ = s.fetch(context.Background(), true)
+
+	s.mu.Lock()
+	s.refreshing = false
+	s.mu.Unlock()
+}
+
+// fetch requests a new token. Unless force is set, a token that became valid while waiting
+// for another request is returned instead.
+func (s *oauth2TokenSource) fetch(ctx context.Context, force bool) (string, error) {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	if !force {
+		s.mu.Lock()
+		token, valid := s.token, time.Now().Before(s.expiry)
+		s.mu.Unlock()
+		if valid {
+			return token, nil
+		}
+	}
+
+	token, lifetime, err := s.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.token = token
+	s.expiry = now.Add(lifetime)
+	s.refreshAt = now.Add(time.Duration(float64(lifetime) * oauth2RefreshFraction))
+	s.mu.Unlock()
+
+	return token, nil
+}
+
+// requestToken performs the client-credentials grant
+func (s *oauth2TokenSource) requestToken(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", s.config.ClientID)
+	form.Set("client_secret", s.config.ClientSecret)
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to request oauth2 token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", 0, fmt.Errorf("oauth2 token request failed with HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", 0, fmt.Errorf("failed to decode oauth2 token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", 0, fmt.Errorf("oauth2 token response has no access_token")
+	}
+
+	lifetime := oauth2DefaultLifetime
+	if tokenResp.ExpiresIn > 0 {
+		lifetime = time.Duration(tokenResp.ExpiresIn) * time.Second
+	}
+	return tokenResp.AccessToken, lifetime, nil
+}
+
+// parseOAuth2Config converts a JavaScript oauth2 object; it returns nil when v is not an object
+func parseOAuth2Config(v interface{}) *OAuth2Config {
+	config, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	cfg := &OAuth2Config{}
+	if tokenURL, ok := config["tokenURL"].(string); ok {
+		cfg.TokenURL = tokenURL
+	}
+	if clientID, ok := config["clientID"].(string); ok {
+		cfg.ClientID = clientID
+	}
+	if clientSecret, ok := config["clientSecret"].(string); ok {
+		cfg.ClientSecret = clientSecret
+	}
+	if scopes, ok := config["scopes"].([]interface{}); ok {
+		for _, scope := range scopes {
+			if s, ok := scope.(string); ok {
+				cfg.Scopes = append(cfg.Scopes, s)
+			}
+		}
+	}
+	return cfg
+}
@@ -0,0 +1,118 @@
+package tempo
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+)
+
+// newTokenServer starts an HTTPS token endpoint with a self-signed certificate and returns it
+// with a CA file that trusts the certificate
+func newTokenServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token-1","expires_in":3600}`)
+	}))
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0o600); err != nil {
+		t.Fatalf("writing CA file: %v", err)
+	}
+	return server, caFile
+}
+
+func TestOAuth2TokenSourceUsesClientTLS(t *testing.T) {
+	server, caFile := newTokenServer(t)
+	tlsSettings := &TLSConfig{CAFile: caFile}
+	tlsConfig, err := tlsSettings.ClientConfig()
+	if err != nil {
+		t.Fatalf("building TLS config: %v", err)
+	}
+
+	source, err := getOAuth2TokenSource(&OAuth2Config{TokenURL: server.URL, ClientID: "tls-client"}, tlsSettings, otlp.Options{TLS: tlsConfig})
+	if err != nil {
+		t.Fatalf("getOAuth2TokenSource() error = %v", err)
+	}
+	authorization, err := source.Authorization(context.Background())
+	if err != nil {
+		t.Fatalf("Authorization() error = %v", err)
+	}
+	if authorization != "Bearer token-1" {
+		t.Errorf("Authorization() = %q, want %q", authorization, "Bearer token-1")
+	}
+}
+
+func TestOAuth2TokenSourceUsesClientProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.Host
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token-2","expires_in":3600}`)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("parsing proxy URL: %v", err)
+	}
+
+	// The token endpoint is only reachable through the proxy
+	config := &OAuth2Config{TokenURL: "http://idp.invalid/token", ClientID: "proxy-client"}
+	source, err := getOAuth2TokenSource(config, nil, otlp.Options{Proxy: proxyURL})
+	if err != nil {
+		t.Fatalf("getOAuth2TokenSource() error = %v", err)
+	}
+	authorization, err := source.Authorization(context.Background())
+	if err != nil {
+		t.Fatalf("Authorization() error = %v", err)
+	}
+	if authorization != "Bearer token-2" {
+		t.Errorf("Authorization() = %q, want %q", authorization, "Bearer token-2")
+	}
+	if proxiedHost != "idp.invalid" {
+		t.Errorf("proxy got a request for %q, want %q", proxiedHost, "idp.invalid")
+	}
+
+	direct, err := getOAuth2TokenSource(config, nil, otlp.Options{})
+	if err != nil {
+		t.Fatalf("getOAuth2TokenSource() error = %v", err)
+	}
+	if direct == source {
+		t.Error("clients with and without a proxy share a token source")
+	}
+}
+
+func TestOAuth2TokenSourceSharedPerTransport(t *testing.T) {
+	config := &OAuth2Config{TokenURL: "https://idp.example/token", ClientID: "shared-client"}
+	tlsSettings := &TLSConfig{ServerName: "idp.example"}
+
+	plain, err := getOAuth2TokenSource(config, nil, otlp.Options{})
+	if err != nil {
+		t.Fatalf("getOAuth2TokenSource() error = %v", err)
+	}
+	withTLS, err := getOAuth2TokenSource(config, tlsSettings, otlp.Options{})
+	if err != nil {
+		t.Fatalf("getOAuth2TokenSource() error = %v", err)
+	}
+	again, err := getOAuth2TokenSource(config, &TLSConfig{ServerName: "idp.example"}, otlp.Options{})
+	if err != nil {
+		t.Fatalf("getOAuth2TokenSource() error = %v", err)
+	}
+
+	if plain == withTLS {
+		t.Error("clients with and without TLS settings share a token source")
+	}
+	if withTLS != again {
+		t.Error("clients with the same TLS settings do not share a token source")
+	}
+}
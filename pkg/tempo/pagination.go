@@ -0,0 +1,81 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// defaultMaxPages bounds a paginated search when the caller sets no limit
+const defaultMaxPages = 10
+
+// PaginatedSearchResponse aggregates the pages of a paginated search
+type PaginatedSearchResponse struct {
+	Traces          []SearchResult `js:"traces"`          // Unique traces of all pages, most recent first
+	Pages           int            `js:"pages"`           // Pages fetched
+	PageDurationsMs []float64      `js:"pageDurationsMs"` // Latency of each page
+	Complete        bool           `js:"complete"`        // Whether the last page was short, i.e. no results are left
+}
+
+// searchPaginated pages through a search like Grafana Explore does when scrolling: each page asks
+// for options.Limit traces ending where the previous page's oldest trace started. Tempo's search
+// has no page tokens, so traces already seen are dropped (internal, requires context).
+func (c *QueryClient) searchPaginated(ctx context.Context, query string, options QueryOptions, maxPages int) (*PaginatedSearchResponse, error) {
+	if options.Limit <= 0 {
+		options.Limit = 20
+	}
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+
+	// Pages go to the same tenant
+	ctx, tenant := withRequestTenant(ctx, c.tenants)
+
+	result := &PaginatedSearchResponse{Traces: []SearchResult{}, PageDurationsMs: []float64{}}
+	seen := make(map[string]struct{})
+	for result.Pages < maxPages {
+		pageStart := time.Now()
+		page, err := c.search(ctx, query, options)
+		duration := time.Since(pageStart)
+		result.Pages++
+		result.PageDurationsMs = append(result.PageDurationsMs, float64(duration.Microseconds())/1000)
+		RecordSearchPage(c.vu.State(), c.metrics, c.requestTags(tenant), result.Pages, duration)
+		if err != nil {
+			return result, fmt.Errorf("page %d: %w", result.Pages, err)
+		}
+
+		added := 0
+		oldest := int64(0)
+		for _, trace := range page.Traces {
+			if oldest == 0 || int64(trace.StartTime) < oldest {
+				oldest = int64(trace.StartTime)
+			}
+			if _, ok := seen[trace.TraceID]; ok {
+				continue
+			}
+			seen[trace.TraceID] = struct{}{}
+			result.Traces = append(result.Traces, trace)
+			added++
+		}
+
+		if len(page.Traces) < options.Limit {
+			result.Complete = true
+			break
+		}
+		if added == 0 || oldest == 0 {
+			// More than a page of traces share the oldest start time; the window cannot move further
+			break
+		}
+		options.End = strconv.FormatInt(oldest, 10)
+	}
+
+	return result, nil
+}
+
+// SearchPaginated pages through a search, options.Limit traces per page and at most maxPages pages
+// (default: 10), and aggregates the results (JavaScript-friendly)
+func (c *QueryClient) SearchPaginated(query string, options QueryOptions, maxPages int) (*PaginatedSearchResponse, error) {
+	ctx := vuContext(c.vu)
+	return c.searchPaginated(ctx, query, options, maxPages)
+}
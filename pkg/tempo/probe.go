@@ -0,0 +1,89 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// BuildInfo is the build information of a Tempo instance
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision"`
+	Branch    string `json:"branch"`
+	BuildUser string `json:"buildUser"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// getText sends a GET request and returns the body of a successful response (internal, requires context)
+func (c *QueryClient) getText(ctx context.Context, path string) (string, *http.Response, error) {
+	req, err := c.newRequest(ctx, path, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return "", resp, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", resp, fmt.Errorf("failed to read response: %w", err)
+	}
+	return string(body), resp, nil
+}
+
+// ready reports whether Tempo answers /ready with 200. Only transport failures are errors
+// (internal, requires context).
+func (c *QueryClient) ready(ctx context.Context) (bool, error) {
+	_, resp, err := c.getText(ctx, "/ready")
+	if err != nil && resp == nil {
+		return false, err
+	}
+	return err == nil, nil
+}
+
+// buildinfo retrieves Tempo's build information (internal, requires context)
+func (c *QueryClient) buildinfo(ctx context.Context) (*BuildInfo, error) {
+	req, err := c.newRequest(ctx, "/api/status/buildinfo", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info BuildInfo
+	if _, err := c.doJSON(req, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Status returns the text of Tempo's /status page: version, runtime config and service states (JavaScript-friendly)
+func (c *QueryClient) Status() (string, error) {
+	ctx := vuContext(c.vu)
+	body, _, err := c.getText(ctx, "/status")
+	return body, err
+}
+
+// Ready reports whether Tempo is ready to serve requests, for gating setup() on a healthy cluster (JavaScript-friendly)
+func (c *QueryClient) Ready() (bool, error) {
+	ctx := vuContext(c.vu)
+	return c.ready(ctx)
+}
+
+// Echo calls /api/echo, which answers without touching the backend, and returns its body (JavaScript-friendly)
+func (c *QueryClient) Echo() (string, error) {
+	ctx := vuContext(c.vu)
+	body, _, err := c.getText(ctx, "/api/echo")
+	return strings.TrimSpace(body), err
+}
+
+// Buildinfo retrieves Tempo's version and build information (JavaScript-friendly)
+func (c *QueryClient) Buildinfo() (*BuildInfo, error) {
+	ctx := vuContext(c.vu)
+	return c.buildinfo(ctx)
+}
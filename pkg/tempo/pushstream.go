@@ -0,0 +1,137 @@
+package tempo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/generator"
+)
+
+// defaultStreamChunkBytes is the size of the chunks of a streamed push when streamChunkBytes is unset
+const defaultStreamChunkBytes = 1 << 20
+
+// defaultHugeTraceChunkSpans is the number of spans per request of a huge trace when hugeTraceChunkSpans is unset
+const defaultHugeTraceChunkSpans = 2000
+
+// StreamResult reports what a streamed push generated and sent
+type StreamResult struct {
+	Chunks        int    `js:"chunks"`        // Export requests sent, or queued pushes
+	TraceCount    int    `js:"traceCount"`    // Generated traces
+	SpanCount     int    `js:"spanCount"`     // Spans of all traces
+	SizeBytes     int    `js:"sizeBytes"`     // OTLP protobuf size of all traces
+	RejectedSpans int64  `js:"rejectedSpans"` // Spans dropped by Tempo
+	ErrorMessage  string `js:"errorMessage"`  // Tempo's last explanation of rejected spans
+	DroppedSpans  int64  `js:"droppedSpans"`  // Spans dropped because the export queue was full
+}
+
+// PushStream generates traces with generatorConfig and pushes them in chunks of streamChunkBytes
+// until totalBytes are sent (JavaScript-friendly). Only one chunk is held in memory at a time, so
+// large volumes can be sent with flat memory use. The last chunk may take the total slightly over.
+func (c *IngestClient) PushStream(generatorConfig map[string]interface{}, totalBytes int) (*StreamResult, error) {
+	if totalBytes <= 0 {
+		return nil, fmt.Errorf("totalBytes must be > 0, got %d", totalBytes)
+	}
+	cfg, err := parseTraceConfig(generatorConfig)
+	if err != nil {
+		return nil, err
+	}
+	if c.deriveSeed != nil {
+		cfg.Seed = c.deriveSeed(cfg.Seed)
+	}
+
+	chunkBytes := c.config.StreamChunkBytes
+	if chunkBytes <= 0 {
+		chunkBytes = defaultStreamChunkBytes
+	}
+
+	iterator := generator.NewBatchIterator(cfg)
+	result := &StreamResult{}
+	for result.SizeBytes < totalBytes {
+		chunk, err := iterator.NextChunk(min(chunkBytes, totalBytes-result.SizeBytes))
+		if err != nil {
+			return nil, err
+		}
+
+		// Count the chunk before pushing: pushes move the spans out of the traces
+		result.Chunks++
+		result.TraceCount += chunk.TraceCount
+		result.SpanCount += chunk.SpanCount
+		result.SizeBytes += chunk.SizeBytes
+
+		pushed, err := c.PushBatch(chunk.Traces)
+		if err != nil {
+			return nil, fmt.Errorf("failed to push chunk %d: %w", result.Chunks, err)
+		}
+		result.RejectedSpans += pushed.RejectedSpans
+		result.DroppedSpans += pushed.DroppedSpans
+		if pushed.ErrorMessage != "" {
+			result.ErrorMessage = pushed.ErrorMessage
+		}
+	}
+	return result, nil
+}
+
+// HugeTraceResult reports what a huge trace push generated and sent
+type HugeTraceResult struct {
+	TraceID       string `js:"traceId"`       // ID of the trace
+	Chunks        int    `js:"chunks"`        // Export requests sent
+	SpanCount     int    `js:"spanCount"`     // Spans of the trace
+	SizeBytes     int    `js:"sizeBytes"`     // OTLP protobuf size of the trace
+	RejectedSpans int64  `js:"rejectedSpans"` // Spans dropped by Tempo
+	ErrorMessage  string `js:"errorMessage"`  // Tempo's last explanation of rejected spans
+}
+
+// PushHugeTrace generates a single trace of spanCount spans with generatorConfig and pushes it in
+// requests of hugeTraceChunkSpans spans sharing the trace ID (JavaScript-friendly), to exercise
+// Tempo's trace size limits and the combining of trace parts. Only one request is held in memory
+// at a time. All requests go to the same tenant and bypass the export queue and late spans; the
+// trace counts once in the ingestion metrics. Throws if a push fails.
+func (c *IngestClient) PushHugeTrace(generatorConfig map[string]interface{}, spanCount int) (*HugeTraceResult, error) {
+	cfg, err := parseTraceConfig(generatorConfig)
+	if err != nil {
+		return nil, err
+	}
+	if c.deriveSeed != nil {
+		cfg.Seed = c.deriveSeed(cfg.Seed)
+	}
+	iterator, err := generator.NewHugeTraceIterator(cfg, spanCount)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSpans := c.config.HugeTraceChunkSpans
+	if chunkSpans <= 0 {
+		chunkSpans = defaultHugeTraceChunkSpans
+	}
+
+	ctx := c.pinTenant(vuContext(c.vu))
+	result := &HugeTraceResult{}
+	for iterator.Remaining() > 0 {
+		c.applyBackoff(ctx)
+		start := time.Now()
+		part, err := iterator.NextPart(chunkSpans)
+		if err != nil {
+			return nil, err
+		}
+
+		// The first request starts the trace; the others carry the rest of it
+		count := 0
+		if result.Chunks == 0 {
+			count = 1
+		}
+		result.TraceID = part.TraceID
+		result.Chunks++
+		result.SpanCount += part.SpanCount
+		result.SizeBytes += part.SizeBytes
+
+		pushed, err := c.send(ctx, part.Trace, part.SizeBytes, count, start)
+		if err != nil {
+			return nil, fmt.Errorf("failed to push chunk %d of trace %s: %w", result.Chunks, result.TraceID, err)
+		}
+		result.RejectedSpans += pushed.RejectedSpans
+		if pushed.ErrorMessage != "" {
+			result.ErrorMessage = pushed.ErrorMessage
+		}
+	}
+	return result, nil
+}
@@ -8,7 +8,13 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"github.com/rvargasp/xk6-tempo/pkg/tempopb"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
 // FlexInt handles JSON numbers that may be strings or integers
@@ -73,11 +79,45 @@ func (fi *FlexInt64) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// FlexFloat64 handles JSON numbers that may be strings, such as the "NaN" and "Inf" values
+// of metrics samples
+type FlexFloat64 float64
+
+func (ff *FlexFloat64) UnmarshalJSON(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			*ff = 0
+			return nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		*ff = FlexFloat64(f)
+		return nil
+	}
+	var f float64
+	if err := json.Unmarshal(b, &f); err != nil {
+		return err
+	}
+	*ff = FlexFloat64(f)
+	return nil
+}
+
 // QueryOptions represents options for trace search queries
 type QueryOptions struct {
-	Start string `js:"start"` // Relative time like "1h", "30m", or absolute timestamp
-	End   string `js:"end"`   // Relative time like "now" or absolute timestamp
-	Limit int    `js:"limit"` // Maximum number of results
+	Start   string            `js:"start"`   // Relative time like "1h", "30m", or absolute timestamp
+	End     string            `js:"end"`     // Relative time like "now" or absolute timestamp
+	Limit   int               `js:"limit"`   // Maximum number of results
+	Params  map[string]string `js:"params"`  // Extra query parameters, e.g. {"spss": "10"}; q, start, end and limit take precedence
+	Headers map[string]string `js:"headers"` // Extra HTTP headers, e.g. {"Cache-Control": "no-cache"}; they override the client's headers
 }
 
 // SearchResult represents a single search result
@@ -102,9 +142,89 @@ type SearchResponse struct {
 	} `json:"metrics"`
 }
 
+// MetricsLabel is a label of a TraceQL metrics series; Value holds one OTLP AnyValue field,
+// e.g. {"stringValue": "checkout"}
+type MetricsLabel struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+// MetricsInstantSeries is a series of an instant TraceQL metrics query
+type MetricsInstantSeries struct {
+	Labels     []MetricsLabel `json:"labels"`
+	Value      FlexFloat64    `json:"value"`
+	PromLabels string         `json:"promLabels"`
+}
+
+// MetricsInstantResponse represents the response from Tempo's instant metrics API
+type MetricsInstantResponse struct {
+	Series  []MetricsInstantSeries `json:"series"`
+	Metrics struct {
+		InspectedTraces FlexInt `json:"inspectedTraces"`
+		InspectedBytes  FlexInt `json:"inspectedBytes"`
+		InspectedSpans  FlexInt `json:"inspectedSpans"`
+		TotalBlocks     FlexInt `json:"totalBlocks"`
+	} `json:"metrics"`
+}
+
+// SearchTagsResponse represents the response from Tempo's /api/v2/search/tags
+type SearchTagsResponse struct {
+	Scopes []struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	} `json:"scopes"`
+	Metrics struct {
+		InspectedBytes FlexInt `json:"inspectedBytes"`
+	} `json:"metrics"`
+}
+
+// SearchTagValuesResponse represents the response from Tempo's /api/v2/search/tag/{tag}/values
+type SearchTagValuesResponse struct {
+	TagValues []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"tagValues"`
+	Metrics struct {
+		InspectedBytes FlexInt `json:"inspectedBytes"`
+	} `json:"metrics"`
+}
+
+// MetricsSample is a point of a TraceQL metrics range series
+type MetricsSample struct {
+	TimestampMs FlexInt64   `json:"timestampMs"`
+	Value       FlexFloat64 `json:"value"`
+}
+
+// MetricsRangeSeries is a series of a range TraceQL metrics query
+type MetricsRangeSeries struct {
+	Labels     []MetricsLabel  `json:"labels"`
+	Samples    []MetricsSample `json:"samples"`
+	PromLabels string          `json:"promLabels"`
+}
+
+// MetricsRangeResponse represents the response from Tempo's range metrics API
+type MetricsRangeResponse struct {
+	Series  []MetricsRangeSeries `json:"series"`
+	Metrics struct {
+		InspectedTraces FlexInt `json:"inspectedTraces"`
+		InspectedBytes  FlexInt `json:"inspectedBytes"`
+		InspectedSpans  FlexInt `json:"inspectedSpans"`
+		TotalBlocks     FlexInt `json:"totalBlocks"`
+	} `json:"metrics"`
+}
+
 // Trace represents a full trace retrieved by ID
 type Trace struct {
 	Batches []TraceBatch `json:"batches"`
+
+	DuplicateSpans int `json:"-"` // Spans repeating an already-seen span ID
+}
+
+// traceByIDResponse is the JSON envelope of /api/v2/traces/{id}
+type traceByIDResponse struct {
+	Trace struct {
+		ResourceSpans []TraceBatch `json:"resourceSpans"`
+	} `json:"trace"`
 }
 
 // TraceBatch represents a batch of spans in a trace
@@ -137,22 +257,97 @@ type Span struct {
 // QueryClient handles queries to Tempo's search API
 type QueryClient struct {
 	client      *http.Client
+	vu          VU
 	baseURL     string
 	tenant      string
+	tenants     *tenantPicker
 	bearerToken string
+	oauth2      *oauth2TokenSource
+	testName    string
+	metrics     *tempoMetrics
+
+	validateStructure bool
+	schemaVersion     string // Empty unless schema validation is enabled
+	traceAPIVersion   string // TraceAPIVersionV1 or TraceAPIVersionV2
+	traceFormat       string // TraceFormatJSON or TraceFormatProtobuf
+	retry             otlp.RetryConfig
+
+	// StreamingQuerier connections, dialed on the first streaming search
+	grpcEndpoint string
+	grpcOpts     otlp.Options
+	grpcMu       sync.Mutex
+	grpcConns    *otlp.GRPCConns
+	timeout      time.Duration
 }
 
 // NewQueryClient creates a new query client
-func NewQueryClient(config QueryConfig) (*QueryClient, error) {
+func NewQueryClient(vu VU, config QueryConfig, m *tempoMetrics) (*QueryClient, error) {
 	timeout := time.Duration(config.Timeout) * time.Second
 	if timeout == 0 {
 		timeout = 30 * time.Second
 	}
 
-	// Resolve bearer token
-	bearerToken, err := ResolveBearerToken(config.BearerToken, config.BearerTokenFile)
+	tlsConfig, err := config.TLS.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls config: %w", err)
+	}
+	proxy, err := otlp.ParseProxyURL(config.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve bearer token, unless it is replaced by OAuth2
+	var oauth2 *oauth2TokenSource
+	bearerToken := ""
+	if config.OAuth2 != nil {
+		if oauth2, err = getOAuth2TokenSource(config.OAuth2, config.TLS, otlp.Options{TLS: tlsConfig, Proxy: proxy}); err != nil {
+			return nil, err
+		}
+	} else {
+		bearerToken, err = ResolveBearerToken(config.BearerToken, config.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve bearer token: %w", err)
+		}
+	}
+
+	schemaVersion := ""
+	if config.ValidateSchema {
+		schemaVersion = config.SchemaVersion
+		if schemaVersion == "" {
+			schemaVersion = DefaultSchemaVersion
+		}
+		if _, ok := responseSchemas[schemaVersion]; !ok {
+			return nil, fmt.Errorf("unknown schemaVersion %q, supported: %s", schemaVersion, strings.Join(SchemaVersions(), ", "))
+		}
+	}
+
+	traceAPIVersion := config.TraceAPIVersion
+	switch traceAPIVersion {
+	case "":
+		traceAPIVersion = TraceAPIVersionV1
+	case TraceAPIVersionV1, TraceAPIVersionV2:
+	default:
+		return nil, fmt.Errorf("unsupported traceAPIVersion: %s (use '%s' or '%s')", traceAPIVersion, TraceAPIVersionV1, TraceAPIVersionV2)
+	}
+	traceFormat := config.TraceFormat
+	switch traceFormat {
+	case "":
+		traceFormat = TraceFormatJSON
+	case TraceFormatJSON, TraceFormatProtobuf:
+	default:
+		return nil, fmt.Errorf("unsupported traceFormat: %s (use '%s' or '%s')", traceFormat, TraceFormatJSON, TraceFormatProtobuf)
+	}
+
+	var retry otlp.RetryConfig
+	if config.Retry.MaxRetries > 0 {
+		if retry, err = config.Retry.exporterConfig(); err != nil {
+			return nil, fmt.Errorf("invalid retry config: %w", err)
+		}
+	}
+
+	tenants, err := newTenantPicker(config.Tenants, config.TenantStrategy, config.TenantWeights)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve bearer token: %w", err)
+		return nil, err
 	}
 
 	// Ensure baseURL doesn't end with /
@@ -161,14 +356,44 @@ func NewQueryClient(config QueryConfig) (*QueryClient, error) {
 		baseURL = baseURL[:len(baseURL)-1]
 	}
 
-	return &QueryClient{
-		client: &http.Client{
-			Timeout: timeout,
-		},
+	grpcEndpoint := config.GRPCEndpoint
+	if grpcEndpoint == "" {
+		grpcEndpoint = baseURL
+	}
+
+	client := &QueryClient{
+		client:      otlp.Options{TLS: tlsConfig, Proxy: proxy}.HTTPClient(timeout),
+		vu:          vu,
 		baseURL:     baseURL,
 		tenant:      config.Tenant,
+		tenants:     tenants,
 		bearerToken: bearerToken,
-	}, nil
+		oauth2:      oauth2,
+		testName:    config.TestName,
+		metrics:     m,
+
+		validateStructure: config.ValidateStructure,
+		schemaVersion:     schemaVersion,
+		traceAPIVersion:   traceAPIVersion,
+		traceFormat:       traceFormat,
+		retry:             retry,
+
+		grpcEndpoint: grpcEndpoint,
+		grpcOpts:     otlp.Options{TLS: tlsConfig},
+		timeout:      timeout,
+	}
+	if oauth2 != nil || bearerToken != "" {
+		client.grpcOpts.Authorization = client.grpcAuthorization
+	}
+	onTestEnd(vu, client.Close)
+	return client, nil
+}
+
+// Close closes idle connections and the streaming search connections (JavaScript-friendly). The
+// client stays usable and reconnects on the next request. Clients are also closed when the test ends.
+func (c *QueryClient) Close() {
+	c.client.CloseIdleConnections()
+	c.closeStreamConns()
 }
 
 // SearchResponseWithHTTP wraps SearchResponse with HTTP response info
@@ -177,162 +402,551 @@ type SearchResponseWithHTTP struct {
 	HTTPResponse *http.Response
 }
 
-// search performs a TraceQL search query (internal, requires context)
-func (c *QueryClient) search(ctx context.Context, query string, options QueryOptions) (*SearchResponse, error) {
-	result, _, err := c.searchWithHTTP(ctx, query, options)
-	return result, err
-}
+// newRequest builds a GET request against the Tempo API with tenant and auth headers applied
+func (c *QueryClient) newRequest(ctx context.Context, path string, params url.Values) (*http.Request, error) {
+	fullURL := c.baseURL + path
+	if len(params) > 0 {
+		fullURL += "?" + params.Encode()
+	}
 
-// searchWithHTTP performs a TraceQL search query and returns HTTP response info (internal, requires context)
-func (c *QueryClient) searchWithHTTP(ctx context.Context, query string, options QueryOptions) (*SearchResponse, *http.Response, error) {
-	// Build URL
-	apiURL := c.baseURL + "/api/search"
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-	// Parse query options
-	params := url.Values{}
-	params.Set("q", query)
+	// Set tenant header if configured; a tenant on ctx or a rotated tenant takes precedence
+	tenant := c.tenant
+	if _, requestTenant := withRequestTenant(ctx, c.tenants); requestTenant != "" {
+		tenant = requestTenant
+	}
+	if tenant != "" {
+		req.Header.Set("X-Scope-OrgID", tenant)
+	}
 
-	// Parse start time
-	if options.Start != "" {
-		startTime, err := parseTime(options.Start)
+	// Set bearer token if configured
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	if c.oauth2 != nil {
+		authorization, err := c.oauth2.Authorization(ctx)
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid start time: %w", err)
+			return nil, fmt.Errorf("failed to get oauth2 token: %w", err)
 		}
-		params.Set("start", strconv.FormatInt(startTime, 10))
+		req.Header.Set("Authorization", authorization)
 	}
 
-	// Parse end time
-	if options.End != "" && options.End != "now" {
-		endTime, err := parseTime(options.End)
+	return req, nil
+}
+
+// Protocols of the query metric tags
+const (
+	queryProtocolHTTP = "http"
+	queryProtocolGRPC = "grpc"
+)
+
+// requestTags returns the metric tags of an HTTP API request for the given tenant, the client's
+// tenant when empty
+func (c *QueryClient) requestTags(tenant string) RequestTags {
+	if tenant == "" {
+		tenant = c.tenant
+	}
+	return RequestTags{Protocol: queryProtocolHTTP, Endpoint: c.baseURL, Tenant: tenant, TestName: c.testName}
+}
+
+// streamTags returns the metric tags of a streaming search for the given tenant, the client's
+// tenant when empty
+func (c *QueryClient) streamTags(tenant string) RequestTags {
+	rt := c.requestTags(tenant)
+	rt.Protocol = queryProtocolGRPC
+	rt.Endpoint = c.grpcEndpoint
+	return rt
+}
+
+// send sends the request, retrying transport failures and retryable statuses as configured.
+// Unless err is set, the response has a 2xx status and its body must be closed; a failed
+// response is returned with its body closed.
+func (c *QueryClient) send(req *http.Request) (*http.Response, error) {
+	onRetry := func(error) {
+		tenant, _ := otlp.TenantFromContext(req.Context())
+		RecordQueryRetry(c.vu.State(), c.metrics, c.requestTags(tenant))
+	}
+
+	var resp *http.Response
+	err := c.retry.DoHTTP(req.Context(), onRetry, func() error {
+		var err error
+		resp, err = c.client.Do(req)
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid end time: %w", err)
+			return fmt.Errorf("failed to send request: %w", err)
 		}
-		params.Set("end", strconv.FormatInt(endTime, 10))
+		if err := checkStatus(resp); err != nil {
+			resp.Body.Close()
+			return err
+		}
+		return nil
+	})
+	return resp, err
+}
+
+// doJSON sends the request and decodes a successful JSON response into out
+func (c *QueryClient) doJSON(req *http.Request, out interface{}) (*http.Response, error) {
+	resp, err := c.send(req)
+	if err != nil {
+		return resp, err
 	}
+	defer resp.Body.Close()
 
-	if options.Limit > 0 {
-		params.Set("limit", strconv.Itoa(options.Limit))
+	if endpoint := schemaEndpoint(req.URL.Path); c.schemaVersion != "" && endpoint != "" {
+		return resp, c.decodeWithSchema(resp, endpoint, out)
 	}
 
-	fullURL := apiURL + "?" + params.Encode()
+	// Parse response
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp, fmt.Errorf("failed to decode response: %w", err)
+	}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	return resp, nil
+}
+
+// decodeWithSchema checks the response body against the configured schema, records violations and decodes it into out
+func (c *QueryClient) decodeWithSchema(resp *http.Response, endpoint string, out interface{}) error {
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Set tenant header if configured
-	if c.tenant != "" {
-		req.Header.Set("X-Scope-OrgID", c.tenant)
+	report, err := validateSchema(c.schemaVersion, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if report != nil {
+		c.recordSchema(report)
 	}
 
-	// Set bearer token if configured
-	if c.bearerToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// doProto sends the request and decodes a successful protobuf trace response
+func (c *QueryClient) doProto(req *http.Request) (ptrace.Traces, *http.Response, error) {
+	req.Header.Set("Accept", "application/protobuf")
+
+	resp, err := c.send(req)
+	if err != nil {
+		return ptrace.Traces{}, resp, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ptrace.Traces{}, resp, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Send request
-	resp, err := c.client.Do(req)
+	// tempopb.Trace shares its wire format with OTLP TracesData (field 1 holds the resource spans);
+	// the v2 API wraps it in a TraceByIDResponse
+	var traces ptrace.Traces
+	if schemaEndpoint(req.URL.Path) == SchemaTraceV2 {
+		traces, err = tempopb.UnmarshalTraceByIDResponse(body)
+	} else {
+		unmarshaler := &ptrace.ProtoUnmarshaler{}
+		traces, err = unmarshaler.UnmarshalTraces(body)
+	}
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to send request: %w", err)
+		return ptrace.Traces{}, resp, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// Check status code
+	return traces, resp, nil
+}
+
+// checkStatus returns an *otlp.StatusError carrying the response body for non-2xx responses
+func checkStatus(resp *http.Response) error {
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		return nil, resp, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(body))
+		return otlp.NewStatusError(resp, body)
+	}
+	return nil
+}
+
+// search performs a TraceQL search query (internal, requires context)
+func (c *QueryClient) search(ctx context.Context, query string, options QueryOptions) (*SearchResponse, error) {
+	result, _, err := c.searchWithHTTP(ctx, query, options)
+	return result, err
+}
+
+// searchWithHTTP performs a TraceQL search query and returns HTTP response info (internal, requires context)
+func (c *QueryClient) searchWithHTTP(ctx context.Context, query string, options QueryOptions) (*SearchResponse, *http.Response, error) {
+	// Parse query options
+	params, err := queryParams(query, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	if options.Limit > 0 {
+		params.Set("limit", strconv.Itoa(options.Limit))
+	}
+
+	req, err := c.newRequest(ctx, "/api/search", params)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range options.Headers {
+		req.Header.Set(key, value)
 	}
 
-	// Parse response
 	var searchResp SearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
-		resp.Body.Close()
-		return nil, resp, fmt.Errorf("failed to decode response: %w", err)
+	resp, err := c.doJSON(req, &searchResp)
+	if err != nil {
+		return nil, resp, err
 	}
-	resp.Body.Close()
 
 	return &searchResp, resp, nil
 }
 
+// metricsQueryInstantWithHTTP runs an instant TraceQL metrics query, which evaluates the query
+// over the whole [start, end] window, and returns HTTP response info (internal, requires context)
+func (c *QueryClient) metricsQueryInstantWithHTTP(ctx context.Context, query string, options QueryOptions) (*MetricsInstantResponse, *http.Response, error) {
+	params, err := queryParams(query, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := c.newRequest(ctx, "/api/metrics/query", params)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	var metricsResp MetricsInstantResponse
+	resp, err := c.doJSON(req, &metricsResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &metricsResp, resp, nil
+}
+
+// metricsQueryRangeWithHTTP runs a range TraceQL metrics query, which returns a series of samples
+// per step (set with the step parameter), and returns HTTP response info (internal, requires context)
+func (c *QueryClient) metricsQueryRangeWithHTTP(ctx context.Context, query string, options QueryOptions) (*MetricsRangeResponse, *http.Response, error) {
+	params, err := queryParams(query, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := c.newRequest(ctx, "/api/metrics/query_range", params)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	var metricsResp MetricsRangeResponse
+	resp, err := c.doJSON(req, &metricsResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &metricsResp, resp, nil
+}
+
+// searchTagsWithHTTP lists the tag names of the matching spans, like Grafana's query editor does, and
+// returns HTTP response info. An empty query matches all spans (internal, requires context).
+func (c *QueryClient) searchTagsWithHTTP(ctx context.Context, query string, options QueryOptions) (*SearchTagsResponse, *http.Response, error) {
+	params, err := tagParams(query, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := c.newRequest(ctx, "/api/v2/search/tags", params)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	var tagsResp SearchTagsResponse
+	resp, err := c.doJSON(req, &tagsResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &tagsResp, resp, nil
+}
+
+// searchTagValuesWithHTTP lists the values of a tag such as resource.service.name among the matching
+// spans and returns HTTP response info. An empty query matches all spans (internal, requires context).
+func (c *QueryClient) searchTagValuesWithHTTP(ctx context.Context, tag, query string, options QueryOptions) (*SearchTagValuesResponse, *http.Response, error) {
+	if tag == "" {
+		return nil, nil, fmt.Errorf("tag is required")
+	}
+	params, err := tagParams(query, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := c.newRequest(ctx, "/api/v2/search/tag/"+url.PathEscape(tag)+"/values", params)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	var valuesResp SearchTagValuesResponse
+	resp, err := c.doJSON(req, &valuesResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &valuesResp, resp, nil
+}
+
+// tagParams returns the query string parameters of the tag endpoints, where the query is an optional
+// filter. The result limit does not apply; pass scope or limit as extra parameters.
+func tagParams(query string, options QueryOptions) (url.Values, error) {
+	params, err := queryParams(query, options)
+	if err != nil {
+		return nil, err
+	}
+	if query == "" {
+		params.Del("q")
+	}
+	return params, nil
+}
+
+// queryParams returns the query string parameters shared by search and metrics queries:
+// the extra parameters, the TraceQL query and the time range
+func queryParams(query string, options QueryOptions) (url.Values, error) {
+	params := url.Values{}
+	for key, value := range options.Params {
+		params.Set(key, value)
+	}
+	params.Set("q", query)
+
+	// Parse start time
+	if options.Start != "" {
+		startTime, err := parseTime(options.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time: %w", err)
+		}
+		params.Set("start", strconv.FormatInt(startTime, 10))
+	}
+
+	// Parse end time
+	if options.End != "" && options.End != "now" {
+		endTime, err := parseTime(options.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end time: %w", err)
+		}
+		params.Set("end", strconv.FormatInt(endTime, 10))
+	}
+
+	return params, nil
+}
+
 // getTrace retrieves a full trace by trace ID (internal, requires context)
 func (c *QueryClient) getTrace(ctx context.Context, traceID string) (*Trace, error) {
 	result, _, err := c.getTraceWithHTTP(ctx, traceID)
 	return result, err
 }
 
-// getTraceWithHTTP retrieves a full trace by trace ID and returns HTTP response info (internal, requires context)
+// getTraceWithHTTP retrieves a full trace by trace ID and returns HTTP response info (internal, requires context).
+// With the protobuf trace format the trace is decoded as OTLP data and converted, without parsing JSON.
 func (c *QueryClient) getTraceWithHTTP(ctx context.Context, traceID string) (*Trace, *http.Response, error) {
-	// Build URL - Tempo legacy API uses /api/traces/{traceID}
-	apiURL := fmt.Sprintf("%s/api/traces/%s", c.baseURL, traceID)
+	if c.traceFormat == TraceFormatProtobuf {
+		traces, resp, err := c.getTraceOTLP(ctx, traceID)
+		if err != nil {
+			return nil, resp, err
+		}
+		trace := traceFromOTLP(traces)
+		_, trace.DuplicateSpans = countDuplicateSpansOTLP(traces)
+		return trace, resp, nil
+	}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	req, err := c.newRequest(ctx, c.tracePath(traceID), nil)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, err
 	}
 
-	// Set tenant header if configured
-	if c.tenant != "" {
-		req.Header.Set("X-Scope-OrgID", c.tenant)
+	var trace Trace
+	var resp *http.Response
+	if c.traceAPIVersion == TraceAPIVersionV2 {
+		var v2 traceByIDResponse
+		resp, err = c.doJSON(req, &v2)
+		trace.Batches = v2.Trace.ResourceSpans
+	} else {
+		resp, err = c.doJSON(req, &trace)
+	}
+	if err != nil {
+		return nil, resp, err
 	}
 
-	// Set bearer token if configured
-	if c.bearerToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	spans, duplicates := trace.countDuplicateSpans()
+	trace.DuplicateSpans = duplicates
+	c.recordDuplicates(spans, duplicates)
+	if c.validateStructure {
+		c.recordStructure(trace.validateStructure())
 	}
 
-	// Send request
-	resp, err := c.client.Do(req)
+	return &trace, resp, nil
+}
+
+// getTraceOTLP retrieves a full trace by trace ID as OTLP data (internal, requires context)
+func (c *QueryClient) getTraceOTLP(ctx context.Context, traceID string) (ptrace.Traces, *http.Response, error) {
+	req, err := c.newRequest(ctx, c.tracePath(traceID), nil)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to send request: %w", err)
+		return ptrace.Traces{}, nil, err
 	}
 
-	// Check status code
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		return nil, resp, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(body))
+	traces, resp, err := c.doProto(req)
+	if err != nil {
+		return traces, resp, err
 	}
 
-	// Parse response
-	var trace Trace
-	if err := json.NewDecoder(resp.Body).Decode(&trace); err != nil {
-		resp.Body.Close()
-		return nil, resp, fmt.Errorf("failed to decode response: %w", err)
+	c.recordDuplicates(countDuplicateSpansOTLP(traces))
+	if c.validateStructure {
+		c.recordStructure(ValidateStructure(traces))
 	}
-	resp.Body.Close()
+	return traces, resp, nil
+}
 
-	return &trace, resp, nil
+// traceFromOTLP converts OTLP data to the JSON trace shape. IDs are hex encoded and attributes
+// are maps of plain values.
+func traceFromOTLP(traces ptrace.Traces) *Trace {
+	trace := &Trace{Batches: make([]TraceBatch, 0, traces.ResourceSpans().Len())}
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+		batch := TraceBatch{
+			Resource:   map[string]interface{}{"attributes": rs.Resource().Attributes().AsRaw()},
+			ScopeSpans: make([]ScopeSpan, 0, rs.ScopeSpans().Len()),
+		}
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			scopeSpans := ScopeSpan{
+				Scope: map[string]interface{}{"name": ss.Scope().Name(), "version": ss.Scope().Version()},
+				Spans: make([]Span, 0, ss.Spans().Len()),
+			}
+			for k := 0; k < ss.Spans().Len(); k++ {
+				scopeSpans.Spans = append(scopeSpans.Spans, spanFromOTLP(ss.Spans().At(k)))
+			}
+			batch.ScopeSpans = append(batch.ScopeSpans, scopeSpans)
+		}
+		trace.Batches = append(trace.Batches, batch)
+	}
+	return trace
+}
+
+// spanFromOTLP converts a single span, naming kinds and status codes as OTLP JSON does
+func spanFromOTLP(s ptrace.Span) Span {
+	span := Span{
+		TraceID:    s.TraceID().String(),
+		SpanID:     s.SpanID().String(),
+		Name:       s.Name(),
+		Kind:       "SPAN_KIND_" + strings.ToUpper(s.Kind().String()),
+		StartTime:  FlexInt64(s.StartTimestamp()),
+		EndTime:    FlexInt64(s.EndTimestamp()),
+		Attributes: s.Attributes().AsRaw(),
+		Status: map[string]interface{}{
+			"code":    "STATUS_CODE_" + strings.ToUpper(s.Status().Code().String()),
+			"message": s.Status().Message(),
+		},
+		Events: make([]interface{}, 0, s.Events().Len()),
+		Links:  make([]interface{}, 0, s.Links().Len()),
+	}
+	if !s.ParentSpanID().IsEmpty() {
+		span.ParentSpanID = s.ParentSpanID().String()
+	}
+	for i := 0; i < s.Events().Len(); i++ {
+		event := s.Events().At(i)
+		span.Events = append(span.Events, map[string]interface{}{
+			"name":         event.Name(),
+			"timeUnixNano": uint64(event.Timestamp()),
+			"attributes":   event.Attributes().AsRaw(),
+		})
+	}
+	for i := 0; i < s.Links().Len(); i++ {
+		link := s.Links().At(i)
+		span.Links = append(span.Links, map[string]interface{}{
+			"traceId":    link.TraceID().String(),
+			"spanId":     link.SpanID().String(),
+			"attributes": link.Attributes().AsRaw(),
+		})
+	}
+	return span
+}
+
+// tracePath returns the trace-by-ID path of the configured API version
+func (c *QueryClient) tracePath(traceID string) string {
+	if c.traceAPIVersion == TraceAPIVersionV2 {
+		return "/api/v2/traces/" + traceID
+	}
+	// Tempo legacy API uses /api/traces/{traceID}
+	return "/api/traces/" + traceID
 }
 
 // JavaScript-friendly wrapper methods (exported, no context parameter required)
 
 // Search performs a TraceQL search query (JavaScript-friendly)
 func (c *QueryClient) Search(query string, options QueryOptions) (*SearchResponse, error) {
-	ctx := context.Background()
+	ctx := vuContext(c.vu)
 	return c.search(ctx, query, options)
 }
 
 // SearchWithHTTP performs a TraceQL search query and returns HTTP response info (JavaScript-friendly)
 func (c *QueryClient) SearchWithHTTP(query string, options QueryOptions) (*SearchResponse, *http.Response, error) {
-	ctx := context.Background()
+	ctx := vuContext(c.vu)
 	return c.searchWithHTTP(ctx, query, options)
 }
 
+// StreamingSearch performs a TraceQL search over Tempo's gRPC StreamingQuerier API, as Grafana
+// does, and returns the merged results once the stream ends (JavaScript-friendly)
+func (c *QueryClient) StreamingSearch(query string, options QueryOptions) (*SearchResponse, error) {
+	ctx := vuContext(c.vu)
+	return c.streamingSearch(ctx, query, options)
+}
+
+// MetricsQueryInstant runs an instant TraceQL metrics query over the options' time range (JavaScript-friendly)
+func (c *QueryClient) MetricsQueryInstant(query string, options QueryOptions) (*MetricsInstantResponse, error) {
+	ctx := vuContext(c.vu)
+	result, _, err := c.metricsQueryInstantWithHTTP(ctx, query, options)
+	return result, err
+}
+
 // GetTrace retrieves a full trace by trace ID (JavaScript-friendly)
 func (c *QueryClient) GetTrace(traceID string) (*Trace, error) {
-	ctx := context.Background()
+	ctx := vuContext(c.vu)
 	return c.getTrace(ctx, traceID)
 }
 
 // GetTraceWithHTTP retrieves a full trace by trace ID and returns HTTP response info (JavaScript-friendly)
 func (c *QueryClient) GetTraceWithHTTP(traceID string) (*Trace, *http.Response, error) {
-	ctx := context.Background()
+	ctx := vuContext(c.vu)
 	return c.getTraceWithHTTP(ctx, traceID)
 }
 
+// GetTraceOTLP retrieves a full trace by trace ID as OTLP data, for comparison with generated traces (JavaScript-friendly)
+func (c *QueryClient) GetTraceOTLP(traceID string) (ptrace.Traces, error) {
+	ctx := vuContext(c.vu)
+	traces, _, err := c.getTraceOTLP(ctx, traceID)
+	return traces, err
+}
+
+// searchWindowEnd returns the end of the window a search sent at requestTime covers
+func searchWindowEnd(options QueryOptions, requestTime time.Time) time.Time {
+	if options.End == "" || options.End == "now" {
+		return requestTime
+	}
+	end, err := parseTime(options.End)
+	if err != nil {
+		return requestTime
+	}
+	return time.Unix(0, end)
+}
+
 // parseTime parses a time string (relative like "1h" or absolute timestamp)
 func parseTime(timeStr string) (int64, error) {
 	// Try relative time first
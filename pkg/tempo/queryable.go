@@ -0,0 +1,121 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// QueryableProbe measures how long pushed traces take to become visible through /api/traces/{id}
+type QueryableProbe struct {
+	ingest          *IngestClient
+	query           *QueryClient
+	vu              VU
+	metrics         *tempoMetrics
+	pollInterval    time.Duration
+	maxPollInterval time.Duration
+	maxAttempts     int
+}
+
+// QueryableResult describes one time-to-queryable measurement
+type QueryableResult struct {
+	TraceID   string `js:"traceId"`
+	Queryable bool   `js:"queryable"` // The trace was returned before the attempts ran out
+	LatencyMs int64  `js:"latencyMs"` // Time from the accepted push to the first successful lookup
+	Attempts  int    `js:"attempts"`  // Lookups performed
+	Error     string `js:"error"`     // Last lookup error other than 404, if any
+}
+
+// NewQueryableProbe creates a new time-to-queryable probe
+func NewQueryableProbe(ingest *IngestClient, query *QueryClient, vu VU, config QueryableProbeConfig, m *tempoMetrics) (*QueryableProbe, error) {
+	if ingest == nil {
+		return nil, fmt.Errorf("ingest client is required")
+	}
+	if query == nil {
+		return nil, fmt.Errorf("query client is required")
+	}
+
+	pollInterval, err := time.ParseDuration(config.PollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pollInterval: %w", err)
+	}
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("pollInterval must be > 0, got %s", config.PollInterval)
+	}
+
+	maxPollInterval, err := time.ParseDuration(config.MaxPollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maxPollInterval: %w", err)
+	}
+	if maxPollInterval < pollInterval {
+		return nil, fmt.Errorf("maxPollInterval (%s) must be >= pollInterval (%s)", config.MaxPollInterval, config.PollInterval)
+	}
+
+	if config.MaxAttempts <= 0 {
+		return nil, fmt.Errorf("maxAttempts must be > 0, got %d", config.MaxAttempts)
+	}
+
+	return &QueryableProbe{
+		ingest:          ingest,
+		query:           query,
+		vu:              vu,
+		metrics:         m,
+		pollInterval:    pollInterval,
+		maxPollInterval: maxPollInterval,
+		maxAttempts:     config.MaxAttempts,
+	}, nil
+}
+
+// measure pushes a single trace and looks it up by ID, doubling the poll interval after every
+// miss up to maxPollInterval, until it is returned or maxAttempts lookups were made. The clock
+// starts when the distributor accepted the push, so export latency is not counted.
+func (p *QueryableProbe) measure(ctx context.Context, trace ptrace.Traces) (*QueryableResult, error) {
+	traceID, _, err := markerTraceInfo(trace)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = p.ingest.pinTenant(ctx)
+	if _, err := p.ingest.push(ctx, trace); err != nil {
+		return nil, fmt.Errorf("failed to push trace: %w", err)
+	}
+	accepted := time.Now()
+
+	result := &QueryableResult{TraceID: traceID}
+	interval := p.pollInterval
+	for result.Attempts < p.maxAttempts {
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		interval *= 2
+		if interval > p.maxPollInterval {
+			interval = p.maxPollInterval
+		}
+
+		result.Attempts++
+		_, resp, err := p.query.getTraceWithHTTP(ctx, traceID)
+		if err == nil {
+			result.Queryable = true
+			result.LatencyMs = time.Since(accepted).Milliseconds()
+			result.Error = ""
+			break
+		}
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			result.Error = err.Error()
+		}
+	}
+
+	RecordTimeToQueryable(p.vu.State(), p.metrics, result)
+	return result, nil
+}
+
+// JavaScript-friendly wrapper methods (exported, no context parameter required)
+
+// Measure pushes a trace and returns how long it took to become queryable by ID (JavaScript-friendly)
+func (p *QueryableProbe) Measure(trace ptrace.Traces) (*QueryableResult, error) {
+	ctx := vuContext(p.vu)
+	return p.measure(ctx, trace)
+}
@@ -0,0 +1,242 @@
+package tempo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// defaultFlushInterval is the export interval of the queue when flushIntervalMs is unset
+	defaultFlushInterval = 5 * time.Second
+	// defaultMaxBatchBytes is the size limit of a queued export request when maxBatchBytes is unset
+	defaultMaxBatchBytes = 1 << 20
+	// queueCloseTimeout bounds how long close waits for queued traces to be exported
+	queueCloseTimeout = 30 * time.Second
+)
+
+// Reasons for dropped spans
+const (
+	DropReasonQueueFull    = "queue_full"
+	DropReasonExportFailed = "export_failed"
+)
+
+// errExportQueueStopped is returned for traces queued or flushes requested after the queue closed
+var errExportQueueStopped = errors.New("export queue is stopped")
+
+// exportQueue buffers pushed traces and exports them in batches from a background goroutine,
+// like the batch span processor of the OpenTelemetry SDKs
+type exportQueue struct {
+	client        *IngestClient
+	maxSpans      int
+	maxBatchBytes int
+	interval      time.Duration
+	closeTimeout  time.Duration // Exports still running this long after close started are cancelled
+
+	startOnce sync.Once
+	ready     chan struct{}   // Signaled when a full batch is queued
+	flushes   chan chan error // Flush requests, answered once the queue is drained
+	stop      chan struct{}   // Closed to stop the background goroutine
+
+	// ctx is the context of background exports, cancelled when close times out or returns
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	pending []queuedTrace
+	spans   int
+	bytes   int
+	stopped bool // Set when close starts draining; nothing is queued afterwards
+}
+
+// queuedTrace is a buffered trace with its serialized size and the tenant it was pushed to
+type queuedTrace struct {
+	trace  ptrace.Traces
+	tenant string
+	size   int
+	spans  int
+}
+
+// newExportQueue creates the queue of client; it returns nil when queueing is disabled
+func newExportQueue(client *IngestClient, config IngestConfig) *exportQueue {
+	if config.QueueSize <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &exportQueue{
+		client:        client,
+		maxSpans:      config.QueueSize,
+		maxBatchBytes: config.MaxBatchBytes,
+		interval:      time.Duration(config.FlushIntervalMs) * time.Millisecond,
+		closeTimeout:  queueCloseTimeout,
+		ready:         make(chan struct{}, 1),
+		flushes:       make(chan chan error),
+		stop:          make(chan struct{}),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+	if q.maxBatchBytes <= 0 {
+		q.maxBatchBytes = defaultMaxBatchBytes
+	}
+	if q.interval <= 0 {
+		q.interval = defaultFlushInterval
+	}
+	return q
+}
+
+// enqueue buffers copies of traces for tenant without blocking and returns the spans dropped
+// because the queue is full. Callers may reuse traces once it returns. It fails once close
+// started, as the traces would never be exported.
+func (q *exportQueue) enqueue(tenant string, traces ...ptrace.Traces) (int64, error) {
+	q.startOnce.Do(func() { go q.run() })
+
+	// Copy and serialize outside the lock so the background export is not held up
+	queued := make([]queuedTrace, len(traces))
+	for i, trace := range traces {
+		copied := ptrace.NewTraces()
+		trace.CopyTo(copied)
+		queued[i] = queuedTrace{trace: copied, tenant: tenant, size: estimateTraceSize(copied), spans: copied.SpanCount()}
+	}
+
+	var dropped int64
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return 0, errExportQueueStopped
+	}
+	for _, qt := range queued {
+		if q.spans+qt.spans > q.maxSpans {
+			dropped += int64(qt.spans)
+			continue
+		}
+		q.pending = append(q.pending, qt)
+		q.spans += qt.spans
+		q.bytes += qt.size
+	}
+	full := q.bytes >= q.maxBatchBytes
+	q.mu.Unlock()
+
+	if full {
+		select {
+		case q.ready <- struct{}{}:
+		default:
+		}
+	}
+	if dropped > 0 {
+		RecordDroppedSpans(q.client.vu.State(), q.client.metrics, q.client.tags, DropReasonQueueFull, dropped)
+	}
+	return dropped, nil
+}
+
+// flush exports all queued traces and returns the errors of those exports; it fails once the
+// background goroutine stopped
+func (q *exportQueue) flush() error {
+	q.startOnce.Do(func() { go q.run() })
+
+	done := make(chan error, 1)
+	select {
+	case q.flushes <- done:
+	case <-q.stop:
+		return errExportQueueStopped
+	}
+	return <-done
+}
+
+// close stops queueing, exports all queued traces and stops the background goroutine. Exports
+// still running after closeTimeout are cancelled, and the traces left are dropped.
+func (q *exportQueue) close() error {
+	q.mu.Lock()
+	q.stopped = true
+	q.mu.Unlock()
+
+	timer := time.AfterFunc(q.closeTimeout, q.cancel)
+	err := q.flush()
+	timer.Stop()
+	q.cancel()
+	close(q.stop)
+	return err
+}
+
+// run exports full batches as they are queued, everything queued on every interval, and
+// everything queued on flush
+func (q *exportQueue) run() {
+	ticker := time.NewTicker(q.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-q.ready:
+			q.export(false)
+		case <-ticker.C:
+			q.export(true)
+		case done := <-q.flushes:
+			done <- q.export(true)
+		case <-q.stop:
+			return
+		}
+	}
+}
+
+// export sends the queued traces in batches of up to maxBatchBytes. Unless all is set, a
+// trailing partial batch stays queued for the next interval.
+func (q *exportQueue) export(all bool) error {
+	var errs []error
+	for {
+		batch, size := q.take(all)
+		if len(batch) == 0 {
+			return errors.Join(errs...)
+		}
+
+		ctx := otlp.WithTenant(q.ctx, batch[0].tenant)
+		q.client.applyBackoff(ctx)
+		start := time.Now()
+		combined := ptrace.NewTraces()
+		for _, queued := range batch {
+			queued.trace.ResourceSpans().MoveAndAppendTo(combined.ResourceSpans())
+		}
+		spans := combined.SpanCount()
+		if _, err := q.client.sendBuffered(ctx, combined, size, len(batch), start); err != nil {
+			RecordDroppedSpans(q.client.vu.State(), q.client.metrics, q.client.tags, DropReasonExportFailed, int64(spans))
+			errs = append(errs, err)
+		}
+	}
+}
+
+// take removes the next batch from the queue: the oldest traces pushed to the tenant of the
+// oldest trace, up to maxBatchBytes. Without all, only a full queue yields a batch.
+func (q *exportQueue) take(all bool) ([]queuedTrace, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 || (!all && q.bytes < q.maxBatchBytes) {
+		return nil, 0
+	}
+
+	tenant := q.pending[0].tenant
+	var batch, rest []queuedTrace
+	size, spans, full := 0, 0, false
+	for _, qt := range q.pending {
+		if full || qt.tenant != tenant {
+			rest = append(rest, qt)
+			continue
+		}
+		if len(batch) > 0 && size+qt.size > q.maxBatchBytes {
+			full = true
+			rest = append(rest, qt)
+			continue
+		}
+		batch = append(batch, qt)
+		size += qt.size
+		spans += qt.spans
+	}
+
+	q.pending = rest
+	q.spans -= spans
+	q.bytes -= size
+	return batch, size
+}
@@ -0,0 +1,59 @@
+package tempo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// blockingExporter blocks every export until its context is cancelled
+type blockingExporter struct{}
+
+func (blockingExporter) ExportTraces(ctx context.Context, _ ptrace.Traces) (otlp.ExportResult, error) {
+	<-ctx.Done()
+	return otlp.ExportResult{}, ctx.Err()
+}
+
+func (blockingExporter) Shutdown(context.Context) error { return nil }
+
+func TestExportQueueCloseCancelsExports(t *testing.T) {
+	c := newTestIngestClient(t, IngestConfig{QueueSize: 100000}, blockingExporter{})
+	c.queue.closeTimeout = 50 * time.Millisecond
+
+	if _, err := c.PushBatch(testTraces(3)); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+
+	start := time.Now()
+	err := c.Close()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("close error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("close took %v, want the in-flight export cancelled after the close timeout", elapsed)
+	}
+}
+
+func TestExportQueueCopiesTraces(t *testing.T) {
+	exporter := &recordingExporter{}
+	c := newTestIngestClient(t, IngestConfig{QueueSize: 100000}, exporter)
+
+	traces := testTraces(3)
+	want := traceSpans(traces)
+	if _, err := c.PushBatch(traces); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	// The caller may reuse the pushed traces before the queue exports them
+	for _, trace := range traces {
+		trace.ResourceSpans().RemoveIf(func(ptrace.ResourceSpans) bool { return true })
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	assertSingleTenant(t, exporter, want)
+}
@@ -0,0 +1,174 @@
+package tempo
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/generator"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// defaultRegistryCapacity bounds the number of trace IDs kept in memory
+const defaultRegistryCapacity = 100000
+
+// TrackedTrace is a pushed trace ID and the tenant it was pushed to
+type TrackedTrace struct {
+	TraceID         string
+	Tenant          string // Empty unless the ingest client rotates tenants
+	PushedAt        time.Time
+	RootServiceName string // Service of the root span, empty if the payload holds no root span
+	RootSpanName    string
+}
+
+// TraceRegistry records pushed trace IDs shared across all VUs.
+// Once full it keeps a uniform random sample of everything recorded (reservoir sampling).
+type TraceRegistry struct {
+	mu       sync.Mutex
+	capacity int
+	recorded int64
+	ids      []TrackedTrace
+}
+
+var globalTraceRegistry *TraceRegistry
+var traceRegistryOnce sync.Once
+
+// GetTraceRegistry returns the global trace registry
+func GetTraceRegistry() *TraceRegistry {
+	traceRegistryOnce.Do(func() {
+		globalTraceRegistry = NewTraceRegistry(defaultRegistryCapacity)
+	})
+	return globalTraceRegistry
+}
+
+// NewTraceRegistry creates a registry holding at most capacity trace IDs
+func NewTraceRegistry(capacity int) *TraceRegistry {
+	return &TraceRegistry{
+		capacity: capacity,
+		ids:      make([]TrackedTrace, 0, capacity),
+	}
+}
+
+// Add records a trace ID
+func (r *TraceRegistry) Add(trace TrackedTrace) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recorded++
+	if len(r.ids) < r.capacity {
+		r.ids = append(r.ids, trace)
+		return
+	}
+
+	if j := rand.Int63n(r.recorded); j < int64(r.capacity) {
+		r.ids[j] = trace
+	}
+}
+
+// Sample returns up to n distinct trace IDs chosen at random
+func (r *TraceRegistry) Sample(n int) []TrackedTrace {
+	r.mu.Lock()
+	ids := make([]TrackedTrace, len(r.ids))
+	copy(ids, r.ids)
+	r.mu.Unlock()
+
+	rand.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+	if n < len(ids) {
+		ids = ids[:n]
+	}
+	return ids
+}
+
+// registryPickAttempts bounds the random picks of Random
+const registryPickAttempts = 16
+
+// Random returns a random trace ID pushed at least minAge ago. It tries a few random picks,
+// so ok may be false while most of the registry is younger than minAge.
+func (r *TraceRegistry) Random(minAge time.Duration) (TrackedTrace, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.ids) == 0 {
+		return TrackedTrace{}, false
+	}
+	cutoff := time.Now().Add(-minAge)
+	for i := 0; i < registryPickAttempts; i++ {
+		trace := r.ids[rand.Intn(len(r.ids))]
+		if minAge <= 0 || !trace.PushedAt.After(cutoff) {
+			return trace, true
+		}
+	}
+	return TrackedTrace{}, false
+}
+
+// Len returns the number of trace IDs currently held
+func (r *TraceRegistry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.ids)
+}
+
+// Recorded returns the total number of trace IDs ever added
+func (r *TraceRegistry) Recorded() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recorded
+}
+
+// traceIDs returns the distinct trace IDs contained in a payload
+func traceIDs(trace ptrace.Traces) []string {
+	seen := make(map[pcommon.TraceID]struct{})
+	ids := []string{}
+	for i := 0; i < trace.ResourceSpans().Len(); i++ {
+		scopeSpans := trace.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spans := scopeSpans.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				id := spans.At(k).TraceID()
+				if _, ok := seen[id]; ok {
+					continue
+				}
+				seen[id] = struct{}{}
+				ids = append(ids, id.String())
+			}
+		}
+	}
+	return ids
+}
+
+// trackedTraces returns the traces contained in a payload with their root span, if present
+func trackedTraces(trace ptrace.Traces, tenant string, pushedAt time.Time) []TrackedTrace {
+	index := make(map[pcommon.TraceID]int)
+	tracked := []TrackedTrace{}
+	for i := 0; i < trace.ResourceSpans().Len(); i++ {
+		rs := trace.ResourceSpans().At(i)
+		if _, ok := rs.Resource().Attributes().Get(generator.ChaosAttribute); ok {
+			// Malformed spans are expected to be rejected, so they are not verified
+			continue
+		}
+		service := ""
+		if v, ok := rs.Resource().Attributes().Get("service.name"); ok {
+			service = v.AsString()
+		}
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				n, ok := index[span.TraceID()]
+				if !ok {
+					n = len(tracked)
+					index[span.TraceID()] = n
+					tracked = append(tracked, TrackedTrace{TraceID: span.TraceID().String(), Tenant: tenant, PushedAt: pushedAt})
+				}
+				if span.ParentSpanID().IsEmpty() {
+					tracked[n].RootServiceName = service
+					tracked[n].RootSpanName = span.Name()
+				}
+			}
+		}
+	}
+	return tracked
+}
@@ -0,0 +1,288 @@
+package tempo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+)
+
+// replayRecord is one line of a recorded query log (JSON lines)
+type replayRecord struct {
+	Timestamp string  `json:"timestamp"` // RFC3339 time the query was issued; schedules the replay
+	Name      string  `json:"name"`      // Tags the query metrics (default: "replay")
+	Type      string  `json:"type"`      // "search" (default) or "metricsInstant"
+	Query     string  `json:"query"`     // TraceQL query
+	Start     int64   `json:"start"`     // Unix seconds; shifted by the age of the record when it has a timestamp
+	End       int64   `json:"end"`       // Unix seconds; shifted like start
+	Limit     int     `json:"limit"`     // Result limit of searches
+	LatencyMs float64 `json:"latencyMs"` // Latency observed when the query was recorded
+	Tenant    string  `json:"tenant"`    // Tenant the query was issued for
+}
+
+// replayEntry is a parsed record
+type replayEntry struct {
+	replayRecord
+	issued time.Time
+	offset time.Duration // Issue time relative to the first record
+}
+
+// replayLog holds the records of a query log and the replay position, shared by all VUs replaying it
+type replayLog struct {
+	entries []replayEntry
+	cycle   time.Duration // Offset of the last record, after which a loop starts over
+	speed   float64
+	loop    bool
+
+	mu     sync.Mutex
+	next   int
+	origin time.Time // Set when the first query is claimed
+}
+
+// replayLogs holds one replay position per log and replay settings
+var replayLogs sync.Map
+
+// ReplayResult describes one replayed query
+type ReplayResult struct {
+	Index             int     `js:"index"`             // Position in the log, counting loops
+	Name              string  `js:"name"`              // Query name
+	Query             string  `js:"query"`             // TraceQL query
+	DurationMs        float64 `js:"durationMs"`        // Latency of the replayed query
+	OriginalLatencyMs float64 `js:"originalLatencyMs"` // Recorded latency, 0 when unknown
+	LagMs             float64 `js:"lagMs"`             // How late the query started compared to its schedule
+	Traces            int     `js:"traces"`            // Traces returned by a search
+}
+
+// QueryReplay replays a recorded query log against Tempo, preserving the relative timing of the
+// records at a configurable speed. All VUs replaying the same log share one position, so the log
+// is replayed once across the test and concurrent queries overlap like they did in production.
+type QueryReplay struct {
+	query   *QueryClient
+	vu      VU
+	metrics *tempoMetrics
+	log     *replayLog
+}
+
+// NewQueryReplay creates a replay of the query log in config.Path
+func NewQueryReplay(query *QueryClient, vu VU, config ReplayConfig, m *tempoMetrics) (*QueryReplay, error) {
+	if query == nil {
+		return nil, fmt.Errorf("query client is required")
+	}
+	if config.Path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	if config.Speed < 0 {
+		return nil, fmt.Errorf("speed must be >= 0, got %g", config.Speed)
+	}
+
+	key := config.Path + "\x00" + strconv.FormatFloat(config.Speed, 'g', -1, 64) + "\x00" + strconv.FormatBool(config.Loop)
+	log, ok := replayLogs.Load(key)
+	if !ok {
+		entries, err := loadReplayLog(config.Path)
+		if err != nil {
+			return nil, err
+		}
+		log, _ = replayLogs.LoadOrStore(key, &replayLog{
+			entries: entries,
+			cycle:   entries[len(entries)-1].offset,
+			speed:   config.Speed,
+			loop:    config.Loop,
+		})
+	}
+
+	return &QueryReplay{
+		query:   query,
+		vu:      vu,
+		metrics: m,
+		log:     log.(*replayLog),
+	}, nil
+}
+
+// loadReplayLog reads and validates a query log. Records without a timestamp are issued at the
+// same time as the previous one.
+func loadReplayLog(path string) ([]replayEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []replayEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry replayEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry.replayRecord); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if entry.Query == "" {
+			return nil, fmt.Errorf("%s:%d: query is required", path, line)
+		}
+		switch entry.Type {
+		case "", QueryTypeSearch, QueryTypeMetricsInstant:
+		default:
+			return nil, fmt.Errorf("%s:%d: unsupported type: %s", path, line, entry.Type)
+		}
+		if entry.Timestamp != "" {
+			if entry.issued, err = time.Parse(time.RFC3339Nano, entry.Timestamp); err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid timestamp: %w", path, line, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query log: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("query log %s is empty", path)
+	}
+
+	// Offsets relative to the first timestamp; the log need not be sorted
+	var first time.Time
+	for _, e := range entries {
+		if !e.issued.IsZero() && (first.IsZero() || e.issued.Before(first)) {
+			first = e.issued
+		}
+	}
+	var offset time.Duration
+	for i := range entries {
+		if !entries[i].issued.IsZero() {
+			offset = entries[i].issued.Sub(first)
+		}
+		entries[i].offset = offset
+	}
+	return entries, nil
+}
+
+// claim returns the next entry, its position and when it is due. ok is false once the log is
+// exhausted and does not loop.
+func (l *replayLog) claim() (entry replayEntry, index int, due time.Time, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.loop && l.next >= len(l.entries) {
+		return replayEntry{}, 0, time.Time{}, false
+	}
+	if l.origin.IsZero() {
+		l.origin = time.Now()
+	}
+	index = l.next
+	l.next++
+
+	entry = l.entries[index%len(l.entries)]
+	due = l.origin
+	if l.speed > 0 {
+		offset := time.Duration(index/len(l.entries))*l.cycle + entry.offset
+		due = l.origin.Add(time.Duration(float64(offset) / l.speed))
+	}
+	return entry, index, due, true
+}
+
+// options returns the query options of an entry. A recorded time range moves forward by the age
+// of the record, so the replayed query covers data of the same age.
+func (e *replayEntry) options() QueryOptions {
+	options := QueryOptions{Start: "1h", End: "now", Limit: e.Limit}
+	var shift time.Duration
+	if !e.issued.IsZero() {
+		shift = time.Since(e.issued)
+	}
+	if e.Start > 0 {
+		options.Start = strconv.FormatInt(time.Unix(e.Start, 0).Add(shift).UnixNano(), 10)
+	}
+	if e.End > 0 {
+		options.End = strconv.FormatInt(time.Unix(e.End, 0).Add(shift).UnixNano(), 10)
+	}
+	if options.Limit == 0 {
+		options.Limit = 20
+	}
+	return options
+}
+
+// next waits until the next query of the log is due and runs it. It returns nil once a log
+// that does not loop is exhausted (internal, requires context).
+func (r *QueryReplay) next(ctx context.Context) (*ReplayResult, error) {
+	entry, index, due, ok := r.log.claim()
+	if !ok {
+		return nil, nil
+	}
+
+	if wait := time.Until(due); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	lag := time.Since(due)
+
+	if entry.Tenant != "" {
+		ctx = otlp.WithTenant(ctx, entry.Tenant)
+	}
+	ctx, tenant := withRequestTenant(ctx, r.query.tenants)
+	rt := r.query.requestTags(tenant)
+
+	name := entry.Name
+	if name == "" {
+		name = "replay"
+	}
+
+	var result *SearchResponse
+	var httpResp *http.Response
+	var err error
+	start := time.Now()
+	switch entry.Type {
+	case QueryTypeMetricsInstant:
+		_, httpResp, err = r.query.metricsQueryInstantWithHTTP(ctx, entry.Query, entry.options())
+	default:
+		result, httpResp, err = r.query.searchWithHTTP(ctx, entry.Query, entry.options())
+	}
+	duration := time.Since(start)
+
+	statusCode := 0
+	if httpResp != nil {
+		statusCode = httpResp.StatusCode
+	}
+	traces := 0
+	if result != nil {
+		traces = len(result.Traces)
+	}
+	RecordQueryDetailed(r.vu.State(), r.metrics, rt, duration, traces, err == nil, name, statusCode)
+	RecordSearchInspection(r.vu.State(), r.metrics, rt, result)
+	RecordResultFreshness(r.vu.State(), r.metrics, rt, result, searchWindowEnd(entry.options(), start))
+	RecordReplay(r.vu.State(), r.metrics, rt, lag, duration, entry.LatencyMs)
+	if err != nil {
+		return nil, fmt.Errorf("replayed query %d (%s): %w", index, name, err)
+	}
+
+	return &ReplayResult{
+		Index:             index,
+		Name:              name,
+		Query:             entry.Query,
+		DurationMs:        float64(duration.Microseconds()) / 1000,
+		OriginalLatencyMs: entry.LatencyMs,
+		LagMs:             float64(lag.Microseconds()) / 1000,
+		Traces:            traces,
+	}, nil
+}
+
+// JavaScript-friendly wrapper methods (exported, no context parameter required)
+
+// Next waits until the next recorded query is due and replays it; it returns null once the log
+// is exhausted (JavaScript-friendly)
+func (r *QueryReplay) Next() (*ReplayResult, error) {
+	ctx := vuContext(r.vu)
+	return r.next(ctx)
+}
@@ -0,0 +1,240 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/generator"
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+)
+
+// RetentionValidator ingests marker traces and checks that they stay retrievable and searchable
+// as they move from the ingester/WAL to backend blocks and through compaction
+type RetentionValidator struct {
+	ingest  *IngestClient
+	query   *QueryClient
+	vu      VU
+	metrics *tempoMetrics
+	traces  int
+	ages    []time.Duration
+}
+
+// RetentionCohort identifies a set of marker traces ingested together.
+// It is a plain object so it can be returned from setup() and passed to the default function.
+type RetentionCohort struct {
+	RunID      string   `js:"runId"`
+	TraceIDs   []string `js:"traceIds"`
+	IngestedAt int64    `js:"ingestedAt"` // Unix milliseconds
+	Tenant     string   `js:"tenant"`     // Tenant the cohort was pushed to; checks query the same tenant
+}
+
+// RetentionCheck is the outcome of checking a cohort at one age
+type RetentionCheck struct {
+	Age           string  `js:"age"`        // Largest configured age the cohort has reached, or "<first age" before that
+	AgeSeconds    float64 `js:"ageSeconds"` // Actual cohort age when checked
+	Checked       int     `js:"checked"`
+	Retrieved     int     `js:"retrieved"`  // Traces returned by trace-by-ID lookup
+	Searchable    int     `js:"searchable"` // Traces returned by a TraceQL search for the cohort run ID
+	Errors        int     `js:"errors"`     // Lookups that failed with anything other than a 404
+	RetrievalRate float64 `js:"retrievalRate"`
+	SearchRate    float64 `js:"searchRate"`
+	SearchError   string  `js:"searchError"`
+}
+
+// RetentionReport collects the checks of a full run
+type RetentionReport struct {
+	RunID  string           `js:"runId"`
+	Checks []RetentionCheck `js:"checks"`
+}
+
+// NewRetentionValidator creates a new retention validator
+func NewRetentionValidator(ingest *IngestClient, query *QueryClient, vu VU, config RetentionConfig, m *tempoMetrics) (*RetentionValidator, error) {
+	if ingest == nil {
+		return nil, fmt.Errorf("ingest client is required")
+	}
+	if query == nil {
+		return nil, fmt.Errorf("query client is required")
+	}
+	if config.Traces <= 0 {
+		return nil, fmt.Errorf("traces must be > 0, got %d", config.Traces)
+	}
+	if len(config.Ages) == 0 {
+		return nil, fmt.Errorf("at least one age is required")
+	}
+
+	ages := make([]time.Duration, 0, len(config.Ages))
+	for _, a := range config.Ages {
+		age, err := time.ParseDuration(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age %q: %w", a, err)
+		}
+		if age < 0 {
+			return nil, fmt.Errorf("age must be >= 0, got %s", a)
+		}
+		ages = append(ages, age)
+	}
+	sort.Slice(ages, func(i, j int) bool { return ages[i] < ages[j] })
+
+	return &RetentionValidator{
+		ingest:  ingest,
+		query:   query,
+		vu:      vu,
+		metrics: m,
+		traces:  config.Traces,
+		ages:    ages,
+	}, nil
+}
+
+// ingestCohort pushes a new cohort of marker traces
+func (r *RetentionValidator) ingestCohort(ctx context.Context) (*RetentionCohort, error) {
+	runID, err := newRunID("rt")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = r.ingest.pinTenant(ctx)
+	tenant, _ := otlp.TenantFromContext(ctx)
+
+	traces := generator.GenerateMarkerTraces(runID, r.traces)
+	if _, err := r.ingest.push(ctx, traces); err != nil {
+		return nil, fmt.Errorf("failed to push retention cohort: %w", err)
+	}
+
+	return &RetentionCohort{
+		RunID:      runID,
+		Tenant:     tenant,
+		TraceIDs:   traceIDs(traces),
+		IngestedAt: time.Now().UnixMilli(),
+	}, nil
+}
+
+// check looks up every trace of the cohort by ID, searches for the cohort run ID and records per-age metrics
+func (r *RetentionValidator) check(ctx context.Context, cohort *RetentionCohort) (*RetentionCheck, error) {
+	if cohort == nil || cohort.RunID == "" || len(cohort.TraceIDs) == 0 {
+		return nil, fmt.Errorf("cohort with runId and traceIds is required")
+	}
+
+	ctx = otlp.WithTenant(ctx, cohort.Tenant)
+	ingestedAt := time.UnixMilli(cohort.IngestedAt)
+	age := time.Since(ingestedAt)
+	result := &RetentionCheck{
+		Age:        r.ageLabel(age),
+		AgeSeconds: age.Seconds(),
+		Checked:    len(cohort.TraceIDs),
+	}
+
+	for _, traceID := range cohort.TraceIDs {
+		trace, resp, err := r.query.getTraceWithHTTP(ctx, traceID)
+		switch {
+		case err == nil:
+			if spans, _ := trace.countDuplicateSpans(); spans > 0 {
+				result.Retrieved++
+			}
+		case resp != nil && resp.StatusCode == http.StatusNotFound:
+		default:
+			result.Errors++
+		}
+	}
+
+	query := fmt.Sprintf(`{ resource.%s = "%s" }`, generator.RunIDAttribute, cohort.RunID)
+	options := QueryOptions{
+		Start: strconv.FormatInt(ingestedAt.Add(-time.Minute).UnixNano(), 10),
+		End:   "now",
+		Limit: len(cohort.TraceIDs) * 2,
+	}
+	if resp, err := r.query.search(ctx, query, options); err != nil {
+		result.SearchError = err.Error()
+	} else {
+		result.Searchable = countCohortMatches(cohort.TraceIDs, resp.Traces)
+	}
+
+	result.RetrievalRate = float64(result.Retrieved) / float64(result.Checked)
+	result.SearchRate = float64(result.Searchable) / float64(result.Checked)
+
+	if state := r.vu.State(); state != nil {
+		RecordRetention(state, r.metrics, result)
+	}
+	return result, nil
+}
+
+// run ingests a cohort and checks it as it reaches each configured age.
+// It blocks until the oldest age is reached, so run it in a dedicated scenario.
+func (r *RetentionValidator) run(ctx context.Context) (*RetentionReport, error) {
+	cohort, err := r.ingestCohort(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RetentionReport{RunID: cohort.RunID, Checks: []RetentionCheck{}}
+	ingestedAt := time.UnixMilli(cohort.IngestedAt)
+
+	for _, age := range r.ages {
+		if wait := time.Until(ingestedAt.Add(age)); wait > 0 {
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+
+		result, err := r.check(ctx, cohort)
+		if err != nil {
+			return nil, err
+		}
+		report.Checks = append(report.Checks, *result)
+	}
+
+	return report, nil
+}
+
+// ageLabel returns the largest configured age not exceeding age
+func (r *RetentionValidator) ageLabel(age time.Duration) string {
+	label := "<" + r.ages[0].String()
+	for _, a := range r.ages {
+		if age < a {
+			break
+		}
+		label = a.String()
+	}
+	return label
+}
+
+// countCohortMatches counts search results that belong to the cohort.
+// Tempo may strip leading zeros from trace IDs in search results.
+func countCohortMatches(traceIDs []string, results []SearchResult) int {
+	cohort := make(map[string]struct{}, len(traceIDs))
+	for _, id := range traceIDs {
+		cohort[strings.TrimLeft(id, "0")] = struct{}{}
+	}
+
+	matches := 0
+	for _, result := range results {
+		if _, ok := cohort[strings.TrimLeft(result.TraceID, "0")]; ok {
+			matches++
+		}
+	}
+	return matches
+}
+
+// JavaScript-friendly wrapper methods (exported, no context parameter required)
+
+// Ingest pushes a cohort of marker traces, typically from setup() (JavaScript-friendly)
+func (r *RetentionValidator) Ingest() (*RetentionCohort, error) {
+	ctx := vuContext(r.vu)
+	return r.ingestCohort(ctx)
+}
+
+// Check verifies a previously ingested cohort at its current age (JavaScript-friendly)
+func (r *RetentionValidator) Check(cohort *RetentionCohort) (*RetentionCheck, error) {
+	ctx := vuContext(r.vu)
+	return r.check(ctx, cohort)
+}
+
+// Run ingests a cohort and checks it at every configured age (JavaScript-friendly)
+func (r *RetentionValidator) Run() (*RetentionReport, error) {
+	ctx := vuContext(r.vu)
+	return r.run(ctx)
+}
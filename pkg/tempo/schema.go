@@ -0,0 +1,335 @@
+package tempo
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Schema endpoints, also used as the endpoint tag on the schema violation counter
+const (
+	SchemaSearch         = "search"
+	SchemaTrace          = "trace"
+	SchemaTraceV2        = "trace_v2"
+	SchemaTags           = "tags"
+	SchemaTagsV2         = "tags_v2"
+	SchemaTagValues      = "tag_values"
+	SchemaTagValuesV2    = "tag_values_v2"
+	SchemaMetricsRange   = "metrics_range"
+	SchemaMetricsInstant = "metrics_instant"
+)
+
+// DefaultSchemaVersion is the Tempo version whose response schemas are used when none is configured
+const DefaultSchemaVersion = "2.7"
+
+// SchemaReport lists response fields that do not match the schema of the configured Tempo version
+type SchemaReport struct {
+	Endpoint string   `js:"endpoint"`
+	Version  string   `js:"version"`
+	Unknown  []string `js:"unknown"` // Field paths present in the response but not in the schema
+	Missing  []string `js:"missing"` // Required field paths absent from the response
+}
+
+// schemaNode describes the expected shape of a JSON value. A nil node accepts any value.
+type schemaNode struct {
+	fields   map[string]*schemaNode // Known fields of an object
+	required []string               // Fields that must be present
+	items    *schemaNode            // Element schema of an array
+	values   *schemaNode            // Value schema of a map with arbitrary keys
+}
+
+// object returns a node for an object with the given known fields
+func object(fields map[string]*schemaNode, required ...string) *schemaNode {
+	return &schemaNode{fields: fields, required: required}
+}
+
+// arrayOf returns a node for an array whose elements match items
+func arrayOf(items *schemaNode) *schemaNode {
+	return &schemaNode{items: items}
+}
+
+// mapOf returns a node for an object with arbitrary keys whose values match values
+func mapOf(values *schemaNode) *schemaNode {
+	return &schemaNode{values: values}
+}
+
+// Building blocks shared by several responses
+var (
+	keyValueSchema = object(map[string]*schemaNode{"key": nil, "value": nil}, "key")
+
+	resourceSchema = object(map[string]*schemaNode{
+		"attributes":             arrayOf(keyValueSchema),
+		"droppedAttributesCount": nil,
+	})
+
+	spanSchema = object(map[string]*schemaNode{
+		"traceId":                nil,
+		"spanId":                 nil,
+		"parentSpanId":           nil,
+		"traceState":             nil,
+		"flags":                  nil,
+		"name":                   nil,
+		"kind":                   nil,
+		"startTimeUnixNano":      nil,
+		"endTimeUnixNano":        nil,
+		"attributes":             arrayOf(keyValueSchema),
+		"droppedAttributesCount": nil,
+		"events":                 nil,
+		"droppedEventsCount":     nil,
+		"links":                  nil,
+		"droppedLinksCount":      nil,
+		"status":                 object(map[string]*schemaNode{"code": nil, "message": nil}),
+	}, "traceId", "spanId", "startTimeUnixNano")
+
+	resourceSpansSchema = object(map[string]*schemaNode{
+		"resource": resourceSchema,
+		"scopeSpans": arrayOf(object(map[string]*schemaNode{
+			"scope": object(map[string]*schemaNode{
+				"name":                   nil,
+				"version":                nil,
+				"attributes":             arrayOf(keyValueSchema),
+				"droppedAttributesCount": nil,
+			}),
+			"spans":     arrayOf(spanSchema),
+			"schemaUrl": nil,
+		})),
+		"schemaUrl": nil,
+	})
+
+	// Backend work counters returned by search, tag and metrics queries
+	queryMetricsSchema = object(map[string]*schemaNode{
+		"inspectedTraces": nil,
+		"inspectedBytes":  nil,
+		"inspectedSpans":  nil,
+		"inspectedBlocks": nil,
+		"totalBlocks":     nil,
+		"completedJobs":   nil,
+		"totalJobs":       nil,
+		"totalBlockBytes": nil,
+	})
+
+	spanSetSchema = object(map[string]*schemaNode{
+		"spans": arrayOf(object(map[string]*schemaNode{
+			"spanID":            nil,
+			"name":              nil,
+			"startTimeUnixNano": nil,
+			"durationNanos":     nil,
+			"attributes":        arrayOf(keyValueSchema),
+		}, "spanID")),
+		"matched":    nil,
+		"attributes": arrayOf(keyValueSchema),
+	})
+
+	metricsSeriesLabels = arrayOf(keyValueSchema)
+)
+
+// tempo24Schemas are the response schemas of Tempo 2.4
+var tempo24Schemas = map[string]*schemaNode{
+	SchemaSearch: object(map[string]*schemaNode{
+		"traces": arrayOf(object(map[string]*schemaNode{
+			"traceID":           nil,
+			"rootServiceName":   nil,
+			"rootTraceName":     nil,
+			"startTimeUnixNano": nil,
+			"durationMs":        nil,
+			"spanSet":           spanSetSchema,
+			"spanSets":          arrayOf(spanSetSchema),
+			"serviceStats":      mapOf(object(map[string]*schemaNode{"spanCount": nil, "errorCount": nil})),
+		}, "traceID")),
+		"metrics": queryMetricsSchema,
+	}, "traces"),
+	SchemaTrace: object(map[string]*schemaNode{
+		"batches": arrayOf(resourceSpansSchema),
+	}, "batches"),
+	SchemaTags: object(map[string]*schemaNode{
+		"tagNames": nil,
+		"metrics":  queryMetricsSchema,
+	}, "tagNames"),
+	SchemaTagsV2: object(map[string]*schemaNode{
+		"scopes":  arrayOf(object(map[string]*schemaNode{"name": nil, "tags": nil}, "name")),
+		"metrics": queryMetricsSchema,
+	}, "scopes"),
+	SchemaTagValues: object(map[string]*schemaNode{
+		"tagValues": nil,
+		"metrics":   queryMetricsSchema,
+	}, "tagValues"),
+	SchemaTagValuesV2: object(map[string]*schemaNode{
+		"tagValues": arrayOf(object(map[string]*schemaNode{"type": nil, "value": nil}, "type")),
+		"metrics":   queryMetricsSchema,
+	}, "tagValues"),
+	SchemaMetricsRange: object(map[string]*schemaNode{
+		"series": arrayOf(object(map[string]*schemaNode{
+			"labels":     metricsSeriesLabels,
+			"samples":    arrayOf(object(map[string]*schemaNode{"timestampMs": nil, "value": nil})),
+			"promLabels": nil,
+			"exemplars":  nil,
+		})),
+		"metrics": queryMetricsSchema,
+	}),
+}
+
+// tempo27Schemas are the response schemas of Tempo 2.7, which adds the v2 trace-by-ID and
+// instant metrics endpoints
+var tempo27Schemas = withSchemas(tempo24Schemas, map[string]*schemaNode{
+	SchemaTraceV2: object(map[string]*schemaNode{
+		"trace":   object(map[string]*schemaNode{"resourceSpans": arrayOf(resourceSpansSchema)}),
+		"status":  nil,
+		"message": nil,
+	}, "trace"),
+	SchemaMetricsInstant: object(map[string]*schemaNode{
+		"series": arrayOf(object(map[string]*schemaNode{
+			"labels":     metricsSeriesLabels,
+			"value":      nil,
+			"promLabels": nil,
+		})),
+		"metrics": queryMetricsSchema,
+	}),
+})
+
+// responseSchemas maps Tempo versions to their response schemas by endpoint
+var responseSchemas = map[string]map[string]*schemaNode{
+	"2.4": tempo24Schemas,
+	"2.7": tempo27Schemas,
+}
+
+// withSchemas returns base extended with extra endpoint schemas
+func withSchemas(base, extra map[string]*schemaNode) map[string]*schemaNode {
+	merged := make(map[string]*schemaNode, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
+
+// SchemaVersions returns the Tempo versions with known response schemas
+func SchemaVersions() []string {
+	versions := make([]string, 0, len(responseSchemas))
+	for v := range responseSchemas {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// schemaEndpoint classifies a Tempo API path, ignoring any prefix before "/api/" (e.g. a gateway
+// mount point). It returns "" for paths without a schema.
+func schemaEndpoint(path string) string {
+	if i := strings.Index(path, "/api/"); i > 0 {
+		path = path[i:]
+	}
+
+	switch {
+	case path == "/api/search":
+		return SchemaSearch
+	case strings.HasPrefix(path, "/api/v2/traces/"):
+		return SchemaTraceV2
+	case strings.HasPrefix(path, "/api/traces/"):
+		return SchemaTrace
+	case path == "/api/v2/search/tags":
+		return SchemaTagsV2
+	case path == "/api/search/tags":
+		return SchemaTags
+	case strings.HasPrefix(path, "/api/v2/search/tag/") && strings.HasSuffix(path, "/values"):
+		return SchemaTagValuesV2
+	case strings.HasPrefix(path, "/api/search/tag/") && strings.HasSuffix(path, "/values"):
+		return SchemaTagValues
+	case path == "/api/metrics/query_range":
+		return SchemaMetricsRange
+	case path == "/api/metrics/query":
+		return SchemaMetricsInstant
+	default:
+		return ""
+	}
+}
+
+// validateSchema checks a JSON response body against the schema of version for endpoint.
+// It returns nil when the version has no schema for the endpoint.
+func validateSchema(version, endpoint string, body []byte) (*SchemaReport, error) {
+	root, ok := responseSchemas[version][endpoint]
+	if !ok {
+		return nil, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	unknown := make(map[string]struct{})
+	missing := make(map[string]struct{})
+	root.validate("", value, unknown, missing)
+
+	return &SchemaReport{
+		Endpoint: endpoint,
+		Version:  version,
+		Unknown:  sortedKeys(unknown),
+		Missing:  sortedKeys(missing),
+	}, nil
+}
+
+// validate walks value and collects unknown and missing field paths. Array elements share the
+// path "parent[]" and map values "parent.*", so a field missing from every span is reported once.
+func (n *schemaNode) validate(path string, value interface{}, unknown, missing map[string]struct{}) {
+	if n == nil {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if n.values != nil {
+			for _, child := range v {
+				n.values.validate(joinPath(path, "*"), child, unknown, missing)
+			}
+			return
+		}
+		if n.fields == nil {
+			return
+		}
+		for _, key := range n.required {
+			if _, ok := v[key]; !ok {
+				missing[joinPath(path, key)] = struct{}{}
+			}
+		}
+		for key, child := range v {
+			field, ok := n.fields[key]
+			if !ok {
+				unknown[joinPath(path, key)] = struct{}{}
+				continue
+			}
+			field.validate(joinPath(path, key), child, unknown, missing)
+		}
+	case []interface{}:
+		for _, item := range v {
+			n.items.validate(path+"[]", item, unknown, missing)
+		}
+	}
+}
+
+// joinPath appends a field name to a dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// recordSchema records schema violations for a response
+func (c *QueryClient) recordSchema(report *SchemaReport) {
+	if state := c.vu.State(); state != nil {
+		RecordSchemaViolations(state, c.metrics, report)
+	}
+}
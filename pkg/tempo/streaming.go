@@ -0,0 +1,181 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"github.com/rvargasp/xk6-tempo/pkg/tempopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// streamingSearch performs a TraceQL search over Tempo's gRPC StreamingQuerier API and merges
+// the streamed partial results (internal, requires context)
+func (c *QueryClient) streamingSearch(ctx context.Context, query string, options QueryOptions) (*SearchResponse, error) {
+	req, err := streamingSearchRequest(query, options)
+	if err != nil {
+		return nil, err
+	}
+
+	conns, err := c.streamConns()
+	if err != nil {
+		return nil, err
+	}
+
+	// Set tenant header if configured; a tenant on ctx or a rotated tenant takes precedence
+	ctx, requestTenant := withRequestTenant(ctx, c.tenants)
+	tenant := c.tenant
+	if requestTenant != "" {
+		tenant = requestTenant
+	}
+	if md := c.grpcOpts.GRPCMetadata(tenant); md != nil {
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	ctx, cancel := otlp.CallContext(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	var firstResult time.Duration
+	merged := newSearchMerger()
+	err = tempopb.StreamingSearch(ctx, conns.Next(), req, func(resp tempopb.SearchResponse) error {
+		if firstResult == 0 && len(resp.Traces) > 0 {
+			firstResult = time.Since(start)
+		}
+		merged.add(resp)
+		return nil
+	})
+	RecordStreamingSearch(c.vu.State(), c.metrics, c.streamTags(tenant), firstResult, time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+
+	result := merged.result(options.Limit)
+	RecordSearchInspection(c.vu.State(), c.metrics, c.streamTags(tenant), result)
+	RecordResultFreshness(c.vu.State(), c.metrics, c.streamTags(tenant), result, searchWindowEnd(options, start))
+	return result, nil
+}
+
+// streamConns returns the connections to the StreamingQuerier endpoint, dialing them on first use
+// so that HTTP-only scripts never open them
+func (c *QueryClient) streamConns() (*otlp.GRPCConns, error) {
+	c.grpcMu.Lock()
+	defer c.grpcMu.Unlock()
+
+	if c.grpcConns == nil {
+		dialOpts := append([]grpc.DialOption{
+			grpc.WithTransportCredentials(c.grpcOpts.GRPCCredentials(c.grpcEndpoint)),
+		}, c.grpcOpts.GRPCDialOptions()...)
+		conns, err := c.grpcOpts.DialGRPC(otlp.GRPCTarget(c.grpcEndpoint, "3200"), dialOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gRPC connection: %w", err)
+		}
+		c.grpcConns = conns
+	}
+	return c.grpcConns, nil
+}
+
+// closeStreamConns closes the StreamingQuerier connections, if any; the next streaming search redials
+func (c *QueryClient) closeStreamConns() {
+	c.grpcMu.Lock()
+	defer c.grpcMu.Unlock()
+
+	if c.grpcConns != nil {
+		_ = c.grpcConns.Close()
+		c.grpcConns = nil
+	}
+}
+
+// grpcAuthorization returns the Authorization of streamed queries, the same as for HTTP requests
+func (c *QueryClient) grpcAuthorization(ctx context.Context) (string, error) {
+	if c.oauth2 != nil {
+		authorization, err := c.oauth2.Authorization(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get oauth2 token: %w", err)
+		}
+		return authorization, nil
+	}
+	return "Bearer " + c.bearerToken, nil
+}
+
+// streamingSearchRequest converts search options to a StreamingQuerier request, whose time range is in seconds
+func streamingSearchRequest(query string, options QueryOptions) (tempopb.SearchRequest, error) {
+	req := tempopb.SearchRequest{Query: query}
+	if options.Limit > 0 {
+		req.Limit = uint32(options.Limit)
+	}
+
+	// Parse start time
+	if options.Start != "" {
+		startTime, err := parseTime(options.Start)
+		if err != nil {
+			return req, fmt.Errorf("invalid start time: %w", err)
+		}
+		req.Start = uint32(time.Unix(0, startTime).Unix())
+	}
+
+	// Parse end time
+	if options.End != "" && options.End != "now" {
+		endTime, err := parseTime(options.End)
+		if err != nil {
+			return req, fmt.Errorf("invalid end time: %w", err)
+		}
+		req.End = uint32(time.Unix(0, endTime).Unix())
+	}
+
+	return req, nil
+}
+
+// searchMerger combines streamed search responses. Traces are keyed by ID, so both cumulative
+// responses and responses holding only new or changed traces merge correctly; the backend
+// counters are cumulative and the last ones win.
+type searchMerger struct {
+	traces  []SearchResult
+	index   map[string]int
+	metrics tempopb.SearchMetrics
+}
+
+// newSearchMerger creates an empty merger
+func newSearchMerger() *searchMerger {
+	return &searchMerger{index: make(map[string]int)}
+}
+
+// add merges one streamed response
+func (m *searchMerger) add(resp tempopb.SearchResponse) {
+	for _, t := range resp.Traces {
+		result := SearchResult{
+			TraceID:         t.TraceID,
+			RootServiceName: t.RootServiceName,
+			RootTraceName:   t.RootTraceName,
+			StartTime:       FlexInt64(t.StartTimeUnixNano),
+			DurationMs:      FlexInt64(t.DurationMs),
+		}
+		if i, ok := m.index[t.TraceID]; ok {
+			m.traces[i] = result
+			continue
+		}
+		m.index[t.TraceID] = len(m.traces)
+		m.traces = append(m.traces, result)
+	}
+	m.metrics = resp.Metrics
+}
+
+// result returns the merged response, most recent traces first and at most limit of them unless limit is 0
+func (m *searchMerger) result(limit int) *SearchResponse {
+	traces := m.traces
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traces[i].StartTime > traces[j].StartTime
+	})
+	if limit > 0 && len(traces) > limit {
+		traces = traces[:limit]
+	}
+
+	resp := &SearchResponse{Traces: traces}
+	resp.Metrics.InspectedTraces = FlexInt(m.metrics.InspectedTraces)
+	resp.Metrics.InspectedBytes = FlexInt(m.metrics.InspectedBytes)
+	resp.Metrics.TotalBlocks = FlexInt(m.metrics.TotalBlocks)
+	return resp
+}
@@ -0,0 +1,171 @@
+package tempo
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Structure violation kinds, also used as the kind tag on the violation counter
+const (
+	ViolationContainment = "containment" // Child span starts before or ends after its parent
+	ViolationTimestamp   = "timestamp"   // Span ends before it starts
+	ViolationZeroID      = "zero_id"     // Trace or span ID is empty or all zeros
+	ViolationOrphan      = "orphan"      // Parent span ID is not present in the trace
+)
+
+// StructureReport lists structural problems found in a trace
+type StructureReport struct {
+	Valid                 bool                 `js:"valid"`
+	Spans                 int                  `js:"spans"`
+	ContainmentViolations int                  `js:"containmentViolations"`
+	TimestampViolations   int                  `js:"timestampViolations"`
+	ZeroIDs               int                  `js:"zeroIds"`
+	OrphanedSpans         int                  `js:"orphanedSpans"`
+	Violations            []StructureViolation `js:"violations"`
+}
+
+// StructureViolation describes a single structural problem
+type StructureViolation struct {
+	SpanID string `js:"spanId"`
+	Kind   string `js:"kind"`
+	Detail string `js:"detail"`
+}
+
+// structureSpan is the subset of a span the validator needs, independent of the wire format
+type structureSpan struct {
+	spanID   string
+	parentID string
+	zeroID   bool
+	start    int64
+	end      int64
+}
+
+// ValidateStructure checks parent/child timestamp containment, start/end ordering,
+// non-zero IDs and orphaned parents of an OTLP trace
+func ValidateStructure(trace ptrace.Traces) *StructureReport {
+	spans := []structureSpan{}
+	for i := 0; i < trace.ResourceSpans().Len(); i++ {
+		scopeSpans := trace.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spanSlice := scopeSpans.At(j).Spans()
+			for k := 0; k < spanSlice.Len(); k++ {
+				span := spanSlice.At(k)
+				parentID := ""
+				if !span.ParentSpanID().IsEmpty() {
+					parentID = span.ParentSpanID().String()
+				}
+				spans = append(spans, structureSpan{
+					spanID:   span.SpanID().String(),
+					parentID: parentID,
+					zeroID:   span.TraceID().IsEmpty() || span.SpanID().IsEmpty(),
+					start:    int64(span.StartTimestamp()),
+					end:      int64(span.EndTimestamp()),
+				})
+			}
+		}
+	}
+	return validateStructure(spans)
+}
+
+// validateStructure checks a trace fetched through the JSON API
+func (t *Trace) validateStructure() *StructureReport {
+	spans := []structureSpan{}
+	for _, batch := range t.Batches {
+		for _, scopeSpans := range batch.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				spans = append(spans, structureSpan{
+					spanID:   span.SpanID,
+					parentID: span.ParentSpanID,
+					zeroID:   isZeroID(span.TraceID) || isZeroID(span.SpanID),
+					start:    int64(span.StartTime),
+					end:      int64(span.EndTime),
+				})
+			}
+		}
+	}
+	return validateStructure(spans)
+}
+
+// validateStructure runs all structural checks
+func validateStructure(spans []structureSpan) *StructureReport {
+	report := &StructureReport{
+		Spans:      len(spans),
+		Violations: []StructureViolation{},
+	}
+
+	byID := make(map[string]structureSpan, len(spans))
+	for _, span := range spans {
+		byID[span.spanID] = span
+	}
+
+	for _, span := range spans {
+		if span.zeroID {
+			report.add(span.spanID, ViolationZeroID, "trace or span ID is empty or all zeros")
+		}
+		if span.end < span.start {
+			report.add(span.spanID, ViolationTimestamp, fmt.Sprintf("ends %dns before it starts", span.start-span.end))
+		}
+		if span.parentID == "" || isZeroID(span.parentID) {
+			continue
+		}
+
+		parent, ok := byID[span.parentID]
+		if !ok {
+			report.add(span.spanID, ViolationOrphan, "parent "+span.parentID+" not found")
+			continue
+		}
+		if span.start < parent.start || span.end > parent.end {
+			report.add(span.spanID, ViolationContainment, fmt.Sprintf("[%d, %d] outside parent [%d, %d]", span.start, span.end, parent.start, parent.end))
+		}
+	}
+
+	report.Valid = len(report.Violations) == 0
+	return report
+}
+
+// add records a violation
+func (r *StructureReport) add(spanID, kind, detail string) {
+	switch kind {
+	case ViolationContainment:
+		r.ContainmentViolations++
+	case ViolationTimestamp:
+		r.TimestampViolations++
+	case ViolationZeroID:
+		r.ZeroIDs++
+	case ViolationOrphan:
+		r.OrphanedSpans++
+	}
+	r.Violations = append(r.Violations, StructureViolation{SpanID: spanID, Kind: kind, Detail: detail})
+}
+
+// recordStructure records structure violations for a fetched trace
+func (c *QueryClient) recordStructure(report *StructureReport) {
+	if state := c.vu.State(); state != nil {
+		RecordStructureViolations(state, c.metrics, report)
+	}
+}
+
+// isZeroID reports whether a hex or base64 encoded ID is empty or all zeros
+func isZeroID(id string) bool {
+	if id == "" {
+		return true
+	}
+
+	decoded, err := hex.DecodeString(id)
+	if err != nil {
+		decoded, err = base64.StdEncoding.DecodeString(id)
+		if err != nil {
+			return false
+		}
+	}
+
+	for _, b := range decoded {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
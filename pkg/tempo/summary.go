@@ -0,0 +1,280 @@
+package tempo
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// summaryLatencyCapacity bounds the latencies kept per query for the summary percentiles
+const summaryLatencyCapacity = 10000
+
+// testSummary aggregates the pushes, queries and verifications of all VUs of the process, for
+// getSummary
+var testSummary = newSummaryCollector()
+
+// TestSummary holds the totals of a test, for handleSummary
+type TestSummary struct {
+	Ingestion    IngestionSummary    `js:"ingestion"`
+	Queries      QueriesSummary      `js:"queries"`
+	Verification VerificationSummary `js:"verification"`
+}
+
+// IngestionSummary holds the totals of all pushes
+type IngestionSummary struct {
+	Requests        int64            `js:"requests"`        // Export requests that succeeded
+	Failures        int64            `js:"failures"`        // Export requests that failed
+	FailuresByClass map[string]int64 `js:"failuresByClass"` // Failures by error class, as tagged on tempo_ingestion_failures_total
+	BytesSent       int64            `js:"bytesSent"`       // OTLP protobuf size of the traces pushed
+	TracesSent      int64            `js:"tracesSent"`      // Traces pushed
+	DurationSeconds float64          `js:"durationSeconds"` // Time from the first to the last push
+	AchievedMBps    float64          `js:"achievedMBps"`    // BytesSent / DurationSeconds in MB/s
+	TargetMBps      float64          `js:"targetMBps"`      // Highest targetMBps of the ingest clients; 0 when none is set
+	TargetRatio     float64          `js:"targetRatio"`     // AchievedMBps / TargetMBps; 0 without a target
+	AchievedQPS     float64          `js:"achievedQPS"`     // Requests / DurationSeconds
+	TargetQPS       float64          `js:"targetQPS"`       // Highest targetQPS of the ingest clients; 0 when none is set
+	TargetQPSRatio  float64          `js:"targetQPSRatio"`  // AchievedQPS / TargetQPS; 0 without a target
+}
+
+// QueriesSummary holds the totals of all recorded queries
+type QueriesSummary struct {
+	Requests        int64                   `js:"requests"`        // Queries run
+	Failures        int64                   `js:"failures"`        // Queries that failed
+	DurationSeconds float64                 `js:"durationSeconds"` // Time from the first to the last query
+	AchievedQPS     float64                 `js:"achievedQPS"`     // Requests / DurationSeconds
+	TargetQPS       float64                 `js:"targetQPS"`       // Sum of the per-VU targetQPS of the query workloads that ran queries; 0 without workloads
+	TargetRatio     float64                 `js:"targetRatio"`     // AchievedQPS / TargetQPS; 0 without a target
+	ByQuery         map[string]QuerySummary `js:"byQuery"`         // Per query name
+}
+
+// QuerySummary holds the totals and latency percentiles of one query
+type QuerySummary struct {
+	Requests int64   `js:"requests"`
+	Failures int64   `js:"failures"`
+	P50Ms    float64 `js:"p50Ms"`
+	P95Ms    float64 `js:"p95Ms"`
+	P99Ms    float64 `js:"p99Ms"`
+	MaxMs    float64 `js:"maxMs"`
+}
+
+// VerificationSummary holds the totals of read-after-write verifications
+type VerificationSummary struct {
+	Verified       int64   `js:"verified"`       // Traces verified
+	Succeeded      int64   `js:"succeeded"`      // Verifications that succeeded
+	NotFound       int64   `js:"notFound"`       // Traces never found
+	SuccessRate    float64 `js:"successRate"`    // Succeeded / Verified
+	FreshnessP50Ms float64 `js:"freshnessP50Ms"` // Median time from push to first successful lookup
+	FreshnessP95Ms float64 `js:"freshnessP95Ms"`
+}
+
+// summaryCollector accumulates the totals of a TestSummary
+type summaryCollector struct {
+	mu sync.Mutex
+
+	pushes          int64
+	pushFailures    map[string]int64
+	bytes, traces   int64
+	firstPush       time.Time
+	lastPush        time.Time
+	targetMBps      float64
+	ingestTargetQPS float64
+	queries         map[string]*queryTotals
+	firstQuery      time.Time
+	lastQuery       time.Time
+	queryTargetQPS  float64
+	verified        int64
+	verifySucceeded int64
+	verifyNotFound  int64
+	freshness       latencyReservoir
+}
+
+// queryTotals accumulates the totals of one query
+type queryTotals struct {
+	requests, failures int64
+	max                time.Duration
+	latencies          latencyReservoir
+}
+
+// latencyReservoir keeps a uniform random sample of at most summaryLatencyCapacity latencies
+type latencyReservoir struct {
+	seen    int64
+	samples []time.Duration
+}
+
+// newSummaryCollector creates an empty collector
+func newSummaryCollector() *summaryCollector {
+	return &summaryCollector{
+		pushFailures: make(map[string]int64),
+		queries:      make(map[string]*queryTotals),
+	}
+}
+
+// add records a latency
+func (r *latencyReservoir) add(d time.Duration) {
+	r.seen++
+	if len(r.samples) < summaryLatencyCapacity {
+		r.samples = append(r.samples, d)
+		return
+	}
+	if j := rand.Int63n(r.seen); j < summaryLatencyCapacity {
+		r.samples[j] = d
+	}
+}
+
+// percentileMs returns the p-th percentile of the sampled latencies in milliseconds
+func (r *latencyReservoir) percentileMs(p float64) float64 {
+	samples := make([]time.Duration, len(r.samples))
+	copy(samples, r.samples)
+	return durationMs(percentile(samples, p))
+}
+
+// durationMs converts d to fractional milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// recordPush records a successful export and the MB/s target of its client
+func (s *summaryCollector) recordPush(bytes int64, traces int, testCtx *TestContext) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pushes++
+	s.bytes += bytes
+	s.traces += int64(traces)
+	if s.firstPush.IsZero() {
+		s.firstPush = now
+	}
+	s.lastPush = now
+	if testCtx != nil {
+		s.targetMBps = max(s.targetMBps, testCtx.TargetMBps)
+		s.ingestTargetQPS = max(s.ingestTargetQPS, float64(testCtx.TargetQPS))
+	}
+}
+
+// recordPushFailure records a failed export
+func (s *summaryCollector) recordPushFailure(errorClass string) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pushFailures[errorClass]++
+	if s.firstPush.IsZero() {
+		s.firstPush = now
+	}
+	s.lastPush = now
+}
+
+// recordQuery records a query and its latency
+func (s *summaryCollector) recordQuery(name string, duration time.Duration, success bool) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	totals, ok := s.queries[name]
+	if !ok {
+		totals = &queryTotals{}
+		s.queries[name] = totals
+	}
+	totals.requests++
+	if !success {
+		totals.failures++
+	}
+	totals.max = max(totals.max, duration)
+	totals.latencies.add(duration)
+
+	if s.firstQuery.IsZero() {
+		s.firstQuery = now
+	}
+	s.lastQuery = now
+}
+
+// addQueryTarget adds the per-VU target rate of a query workload to the test's target
+func (s *summaryCollector) addQueryTarget(qps float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queryTargetQPS += qps
+}
+
+// recordVerification records the result of a read-after-write verification
+func (s *summaryCollector) recordVerification(result *VerificationResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.verified++
+	if result.Success {
+		s.verifySucceeded++
+	}
+	if !result.Found {
+		s.verifyNotFound++
+		return
+	}
+	s.freshness.add(time.Duration(result.FreshnessMs) * time.Millisecond)
+}
+
+// summary returns the totals recorded so far
+func (s *summaryCollector) summary() *TestSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ingestion := IngestionSummary{
+		Requests:        s.pushes,
+		FailuresByClass: make(map[string]int64, len(s.pushFailures)),
+		BytesSent:       s.bytes,
+		TracesSent:      s.traces,
+		DurationSeconds: s.lastPush.Sub(s.firstPush).Seconds(),
+		TargetMBps:      s.targetMBps,
+		TargetQPS:       s.ingestTargetQPS,
+	}
+	for class, failures := range s.pushFailures {
+		ingestion.Failures += failures
+		ingestion.FailuresByClass[class] = failures
+	}
+	if ingestion.DurationSeconds > 0 {
+		ingestion.AchievedMBps = float64(s.bytes) / (1024 * 1024) / ingestion.DurationSeconds
+		ingestion.AchievedQPS = float64(s.pushes) / ingestion.DurationSeconds
+	}
+	if ingestion.TargetMBps > 0 {
+		ingestion.TargetRatio = ingestion.AchievedMBps / ingestion.TargetMBps
+	}
+	if ingestion.TargetQPS > 0 {
+		ingestion.TargetQPSRatio = ingestion.AchievedQPS / ingestion.TargetQPS
+	}
+
+	queries := QueriesSummary{
+		DurationSeconds: s.lastQuery.Sub(s.firstQuery).Seconds(),
+		TargetQPS:       s.queryTargetQPS,
+		ByQuery:         make(map[string]QuerySummary, len(s.queries)),
+	}
+	for name, totals := range s.queries {
+		queries.Requests += totals.requests
+		queries.Failures += totals.failures
+		queries.ByQuery[name] = QuerySummary{
+			Requests: totals.requests,
+			Failures: totals.failures,
+			P50Ms:    totals.latencies.percentileMs(0.50),
+			P95Ms:    totals.latencies.percentileMs(0.95),
+			P99Ms:    totals.latencies.percentileMs(0.99),
+			MaxMs:    durationMs(totals.max),
+		}
+	}
+	if queries.DurationSeconds > 0 {
+		queries.AchievedQPS = float64(queries.Requests) / queries.DurationSeconds
+	}
+	if queries.TargetQPS > 0 {
+		queries.TargetRatio = queries.AchievedQPS / queries.TargetQPS
+	}
+
+	verification := VerificationSummary{
+		Verified:       s.verified,
+		Succeeded:      s.verifySucceeded,
+		NotFound:       s.verifyNotFound,
+		FreshnessP50Ms: s.freshness.percentileMs(0.50),
+		FreshnessP95Ms: s.freshness.percentileMs(0.95),
+	}
+	if s.verified > 0 {
+		verification.SuccessRate = float64(s.verifySucceeded) / float64(s.verified)
+	}
+
+	return &TestSummary{Ingestion: ingestion, Queries: queries, Verification: verification}
+}
@@ -0,0 +1,38 @@
+package tempo
+
+import (
+	"go.k6.io/k6/js/common"
+)
+
+// k6EventTestEnd is k6's event.TestEnd, emitted once the test, including teardown(), has finished.
+// The event package is internal to k6, so its value is repeated here.
+const k6EventTestEnd = 3
+
+// eventVU is implemented by k6's modules.VU
+type eventVU interface {
+	Events() common.Events
+}
+
+// onTestEnd calls cleanup when the test ends, so clients drain and release their connections
+// even if the script never closes them. Without k6's event system, e.g. outside k6, it does nothing.
+func onTestEnd(vu VU, cleanup func()) {
+	ev, ok := vu.(eventVU)
+	if !ok {
+		return
+	}
+	global := ev.Events().Global
+	if global == nil {
+		return
+	}
+
+	subID, events := global.Subscribe(k6EventTestEnd)
+	go func() {
+		e, ok := <-events
+		if !ok {
+			return
+		}
+		cleanup()
+		e.Done()
+		global.Unsubscribe(subID)
+	}()
+}
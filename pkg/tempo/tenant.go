@@ -0,0 +1,92 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+)
+
+// Tenant rotation strategies
+const (
+	TenantRoundRobin = "round-robin"
+	TenantRandom     = "random"
+	TenantWeighted   = "weighted"
+)
+
+// tenantPicker selects the tenant of each request from a fixed list
+type tenantPicker struct {
+	tenants    []string
+	strategy   string
+	cumulative []float64 // Cumulative weights for the weighted strategy
+	next       uint64
+}
+
+// newTenantPicker creates a picker; it returns nil when tenants is empty
+func newTenantPicker(tenants []string, strategy string, weights []float64) (*tenantPicker, error) {
+	if len(tenants) == 0 {
+		return nil, nil
+	}
+
+	p := &tenantPicker{
+		tenants:  tenants,
+		strategy: strategy,
+		next:     uint64(rand.Intn(len(tenants))),
+	}
+	switch strategy {
+	case "", TenantRoundRobin:
+		p.strategy = TenantRoundRobin
+	case TenantRandom:
+	case TenantWeighted:
+		if len(weights) != len(tenants) {
+			return nil, fmt.Errorf("tenantWeights must have one weight per tenant, got %d weights for %d tenants", len(weights), len(tenants))
+		}
+		total := 0.0
+		for i, weight := range weights {
+			if weight < 0 {
+				return nil, fmt.Errorf("tenantWeights must not be negative, got %v for tenant %s", weight, tenants[i])
+			}
+			total += weight
+			p.cumulative = append(p.cumulative, total)
+		}
+		if total == 0 {
+			return nil, fmt.Errorf("tenantWeights must not all be zero")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported tenantStrategy: %s (use '%s', '%s' or '%s')", strategy, TenantRoundRobin, TenantRandom, TenantWeighted)
+	}
+	return p, nil
+}
+
+// pick returns the tenant for the next request
+func (p *tenantPicker) pick() string {
+	switch p.strategy {
+	case TenantRandom:
+		return p.tenants[rand.Intn(len(p.tenants))]
+	case TenantWeighted:
+		r := rand.Float64() * p.cumulative[len(p.cumulative)-1]
+		for i, c := range p.cumulative {
+			if r < c {
+				return p.tenants[i]
+			}
+		}
+		return p.tenants[len(p.tenants)-1]
+	default:
+		return p.tenants[(atomic.AddUint64(&p.next, 1)-1)%uint64(len(p.tenants))]
+	}
+}
+
+// withRequestTenant returns ctx carrying the tenant of the next request and that tenant.
+// A tenant already set on ctx is kept; without rotation ctx is returned unchanged with an empty tenant.
+func withRequestTenant(ctx context.Context, p *tenantPicker) (context.Context, string) {
+	if tenant, ok := otlp.TenantFromContext(ctx); ok {
+		return ctx, tenant
+	}
+	if p == nil {
+		return ctx, ""
+	}
+	tenant := p.pick()
+	return otlp.WithTenant(ctx, tenant), tenant
+}
@@ -0,0 +1,81 @@
+package tempo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig represents client TLS settings for ingest and query clients
+type TLSConfig struct {
+	CAFile             string `js:"caFile"`             // PEM bundle used to verify the server (default: system roots)
+	CertFile           string `js:"certFile"`           // Client certificate for mTLS
+	KeyFile            string `js:"keyFile"`            // Client key for mTLS
+	InsecureSkipVerify bool   `js:"insecureSkipVerify"` // Skip server certificate verification
+	ServerName         string `js:"serverName"`         // Override the server name used for verification and SNI
+}
+
+// ClientConfig builds a crypto/tls client config. A nil TLSConfig returns nil.
+func (c *TLSConfig) ClientConfig() (*tls.Config, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("certFile and keyFile must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// parseTLSConfig converts a JavaScript tls object; it returns nil when v is not an object
+func parseTLSConfig(v interface{}) *TLSConfig {
+	config, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	cfg := &TLSConfig{}
+	if caFile, ok := config["caFile"].(string); ok {
+		cfg.CAFile = caFile
+	}
+	if certFile, ok := config["certFile"].(string); ok {
+		cfg.CertFile = certFile
+	}
+	if keyFile, ok := config["keyFile"].(string); ok {
+		cfg.KeyFile = keyFile
+	}
+	if insecureSkipVerify, ok := config["insecureSkipVerify"].(bool); ok {
+		cfg.InsecureSkipVerify = insecureSkipVerify
+	}
+	if serverName, ok := config["serverName"].(string); ok {
+		cfg.ServerName = serverName
+	}
+	return cfg
+}
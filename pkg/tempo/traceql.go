@@ -0,0 +1,208 @@
+package tempo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// traceQLComparisons are the comparison operators of TraceQL field expressions
+var traceQLComparisons = map[string]bool{
+	"=": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true, "=~": true, "!~": true,
+}
+
+// traceQLRegexOperators are the comparison operators that only apply to strings
+var traceQLRegexOperators = map[string]bool{"=~": true, "!~": true}
+
+// traceQLIntrinsics are the intrinsic span fields that are written without a scope
+var traceQLIntrinsics = map[string]bool{
+	"name": true, "status": true, "statusMessage": true, "kind": true, "duration": true,
+	"rootName": true, "rootServiceName": true, "traceDuration": true,
+	"event:name": true, "link:traceID": true, "link:spanID": true,
+	"trace:id": true, "trace:rootName": true, "trace:rootService": true, "trace:duration": true,
+	"span:id": true, "span:name": true, "span:status": true, "span:statusMessage": true,
+	"span:kind": true, "span:duration": true,
+}
+
+// traceQLScopes are the attribute scopes a name may already carry
+var traceQLScopes = []string{"span.", "resource.", "event.", "link.", "instrumentation.", "."}
+
+var (
+	// traceQLDuration matches a TraceQL duration literal such as 100ms or 1.5s
+	traceQLDuration = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h)$`)
+	// traceQLBareName matches attribute names that need no quoting
+	traceQLBareName = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.\-/:]*$`)
+)
+
+// traceQLStatuses and traceQLKinds are the values of the status and kind intrinsics
+var (
+	traceQLStatuses = map[string]bool{"error": true, "ok": true, "unset": true}
+	traceQLKinds    = map[string]bool{
+		"unspecified": true, "internal": true, "server": true, "client": true, "producer": true, "consumer": true,
+	}
+)
+
+// TraceQLBuilder builds TraceQL queries from spanset conditions joined by structural or logical
+// operators, e.g. { span.http.method = "GET" && duration > 100ms } >> { status = error }.
+// Attribute values are quoted and escaped, so interpolated values cannot break the query.
+type TraceQLBuilder struct {
+	spansets  [][]string // Conditions of each spanset, joined with &&
+	operators []string   // Operator between spanset i and i+1
+}
+
+// NewTraceQLBuilder creates a builder with one empty spanset
+func NewTraceQLBuilder() *TraceQLBuilder {
+	return &TraceQLBuilder{spansets: [][]string{nil}}
+}
+
+// condition adds a field expression to the current spanset
+func (b *TraceQLBuilder) condition(field, op, value string) (*TraceQLBuilder, error) {
+	if !traceQLComparisons[op] {
+		return b, fmt.Errorf("invalid TraceQL operator %q for %s", op, field)
+	}
+	last := len(b.spansets) - 1
+	b.spansets[last] = append(b.spansets[last], field+" "+op+" "+value)
+	return b, nil
+}
+
+// attribute adds an attribute condition, using scope unless the name is already scoped
+func (b *TraceQLBuilder) attribute(scope, name, op string, value interface{}) (*TraceQLBuilder, error) {
+	if name == "" {
+		return b, fmt.Errorf("empty TraceQL attribute name")
+	}
+	literal, err := traceQLValue(value)
+	if err != nil {
+		return b, fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+	if traceQLRegexOperators[op] && !strings.HasPrefix(literal, `"`) {
+		return b, fmt.Errorf("operator %s needs a string value for %s", op, name)
+	}
+	return b.condition(traceQLAttribute(scope, name), op, literal)
+}
+
+// join closes the current spanset and starts a new one after op
+func (b *TraceQLBuilder) join(op string) (*TraceQLBuilder, error) {
+	b.operators = append(b.operators, op)
+	b.spansets = append(b.spansets, nil)
+	return b, nil
+}
+
+// Span matches a span attribute; names without a scope get the span scope (JavaScript-friendly)
+func (b *TraceQLBuilder) Span(name, op string, value interface{}) (*TraceQLBuilder, error) {
+	return b.attribute("span.", name, op, value)
+}
+
+// Resource matches a resource attribute such as service.name (JavaScript-friendly)
+func (b *TraceQLBuilder) Resource(name, op string, value interface{}) (*TraceQLBuilder, error) {
+	return b.attribute("resource.", name, op, value)
+}
+
+// Attr matches an unscoped attribute, i.e. a span or resource attribute (JavaScript-friendly)
+func (b *TraceQLBuilder) Attr(name, op string, value interface{}) (*TraceQLBuilder, error) {
+	return b.attribute(".", name, op, value)
+}
+
+// Duration matches the span duration against a duration such as "100ms" (JavaScript-friendly)
+func (b *TraceQLBuilder) Duration(op, value string) (*TraceQLBuilder, error) {
+	if traceQLRegexOperators[op] {
+		return b, fmt.Errorf("operator %s needs a string value for duration", op)
+	}
+	if !traceQLDuration.MatchString(value) {
+		return b, fmt.Errorf("invalid TraceQL duration %q", value)
+	}
+	return b.condition("duration", op, value)
+}
+
+// Name matches the span name (JavaScript-friendly)
+func (b *TraceQLBuilder) Name(op, value string) (*TraceQLBuilder, error) {
+	return b.condition("name", op, strconv.Quote(value))
+}
+
+// Status matches the span status: "error", "ok" or "unset" (JavaScript-friendly)
+func (b *TraceQLBuilder) Status(value string) (*TraceQLBuilder, error) {
+	if !traceQLStatuses[value] {
+		return b, fmt.Errorf("invalid TraceQL status %q (use error, ok or unset)", value)
+	}
+	return b.condition("status", "=", value)
+}
+
+// Kind matches the span kind, e.g. "server" or "client" (JavaScript-friendly)
+func (b *TraceQLBuilder) Kind(value string) (*TraceQLBuilder, error) {
+	if !traceQLKinds[value] {
+		return b, fmt.Errorf("invalid TraceQL span kind %q", value)
+	}
+	return b.condition("kind", "=", value)
+}
+
+// Descendant starts a spanset matching descendants of the previous one (>>) (JavaScript-friendly)
+func (b *TraceQLBuilder) Descendant() (*TraceQLBuilder, error) { return b.join(">>") }
+
+// Child starts a spanset matching direct children of the previous one (>) (JavaScript-friendly)
+func (b *TraceQLBuilder) Child() (*TraceQLBuilder, error) { return b.join(">") }
+
+// Ancestor starts a spanset matching ancestors of the previous one (<<) (JavaScript-friendly)
+func (b *TraceQLBuilder) Ancestor() (*TraceQLBuilder, error) { return b.join("<<") }
+
+// Parent starts a spanset matching the parent of the previous one (<) (JavaScript-friendly)
+func (b *TraceQLBuilder) Parent() (*TraceQLBuilder, error) { return b.join("<") }
+
+// Sibling starts a spanset matching siblings of the previous one (~) (JavaScript-friendly)
+func (b *TraceQLBuilder) Sibling() (*TraceQLBuilder, error) { return b.join("~") }
+
+// And starts a spanset that must match in the same trace as the previous one (&&) (JavaScript-friendly)
+func (b *TraceQLBuilder) And() (*TraceQLBuilder, error) { return b.join("&&") }
+
+// Or starts a spanset that may match instead of the previous one (||) (JavaScript-friendly)
+func (b *TraceQLBuilder) Or() (*TraceQLBuilder, error) { return b.join("||") }
+
+// Build returns the query. Empty spansets match every span: {} (JavaScript-friendly)
+func (b *TraceQLBuilder) Build() string {
+	var sb strings.Builder
+	for i, conditions := range b.spansets {
+		if i > 0 {
+			sb.WriteString(" " + b.operators[i-1] + " ")
+		}
+		if len(conditions) == 0 {
+			sb.WriteString("{}")
+			continue
+		}
+		sb.WriteString("{ " + strings.Join(conditions, " && ") + " }")
+	}
+	return sb.String()
+}
+
+// traceQLAttribute renders an attribute reference, quoting names with characters TraceQL does not allow bare
+func traceQLAttribute(scope, name string) string {
+	if traceQLIntrinsics[name] {
+		return name
+	}
+	for _, s := range traceQLScopes {
+		if strings.HasPrefix(name, s) && len(name) > len(s) {
+			scope, name = s, name[len(s):]
+			break
+		}
+	}
+	if !traceQLBareName.MatchString(name) {
+		name = strconv.Quote(name)
+	}
+	return scope + name
+}
+
+// traceQLValue renders a JS value as a TraceQL literal
+func traceQLValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T (use a string, number or boolean)", value)
+	}
+}
@@ -0,0 +1,24 @@
+package tempo
+
+import "testing"
+
+func TestTraceQLBuilderDuration(t *testing.T) {
+	for _, op := range []string{"=", "!=", ">", ">=", "<", "<="} {
+		b, err := NewTraceQLBuilder().Duration(op, "100ms")
+		if err != nil {
+			t.Errorf("Duration(%q) error = %v", op, err)
+			continue
+		}
+		if want := "{ duration " + op + " 100ms }"; b.Build() != want {
+			t.Errorf("Duration(%q) built %q, want %q", op, b.Build(), want)
+		}
+	}
+}
+
+func TestTraceQLBuilderDurationInvalidOperator(t *testing.T) {
+	for _, op := range []string{"=~", "!~", "&&", ""} {
+		if _, err := NewTraceQLBuilder().Duration(op, "100ms"); err == nil {
+			t.Errorf("Duration(%q) succeeded, want an error", op)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package tempo
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// VerificationMarkerAttribute is the resource attribute holding the unique marker of a verified trace
+const VerificationMarkerAttribute = "k6.verification_marker"
+
+// Verifier pushes marker traces and polls Tempo until they become queryable
+type Verifier struct {
+	ingest       *IngestClient
+	query        *QueryClient
+	vu           VU
+	metrics      *tempoMetrics
+	pollInterval time.Duration
+	timeout      time.Duration
+	delay        time.Duration
+	search       bool
+}
+
+// VerificationResult describes the outcome of a read-after-write check
+type VerificationResult struct {
+	TraceID              string  `js:"traceId"`
+	Marker               string  `js:"marker"`               // Value of the marker attribute stamped on the trace
+	Success              bool    `js:"success"`              // Complete, attributes match and, with search enabled, searchable
+	Found                bool    `js:"found"`                // Trace was returned by the query API
+	Complete             bool    `js:"complete"`             // All pushed spans were returned
+	Searchable           bool    `js:"searchable"`           // A search for the marker returned the trace; only checked with search enabled
+	AttributesMatch      bool    `js:"attributesMatch"`      // Returned spans carry the pushed span and resource attributes
+	FreshnessMs          int64   `js:"freshnessMs"`          // Time from push to first successful lookup
+	ExpectedSpans        int     `js:"expectedSpans"`        // Spans pushed
+	ReturnedSpans        int     `js:"returnedSpans"`        // Spans returned by the last lookup
+	MissingSpans         int     `js:"missingSpans"`         // ExpectedSpans - ReturnedSpans
+	MismatchedAttributes int     `js:"mismatchedAttributes"` // Attributes missing or changed in the returned spans
+	Completeness         float64 `js:"completeness"`         // ReturnedSpans / ExpectedSpans
+	Attempts             int     `js:"attempts"`             // Number of lookups performed
+	Error                string  `js:"error"`                // Last lookup error, if any
+}
+
+// NewVerifier creates a new read-after-write verifier
+func NewVerifier(ingest *IngestClient, query *QueryClient, vu VU, config VerifierConfig, m *tempoMetrics) (*Verifier, error) {
+	if ingest == nil {
+		return nil, fmt.Errorf("ingest client is required")
+	}
+	if query == nil {
+		return nil, fmt.Errorf("query client is required")
+	}
+
+	pollInterval, err := time.ParseDuration(config.PollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pollInterval: %w", err)
+	}
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("pollInterval must be > 0, got %s", config.PollInterval)
+	}
+
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout: %w", err)
+	}
+	if timeout < pollInterval {
+		return nil, fmt.Errorf("timeout (%s) must be >= pollInterval (%s)", config.Timeout, config.PollInterval)
+	}
+
+	delay, err := time.ParseDuration(config.Delay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delay: %w", err)
+	}
+	if delay < 0 {
+		return nil, fmt.Errorf("delay must be >= 0, got %s", config.Delay)
+	}
+
+	return &Verifier{
+		ingest:       ingest,
+		query:        query,
+		vu:           vu,
+		metrics:      m,
+		pollInterval: pollInterval,
+		timeout:      timeout,
+		delay:        delay,
+		search:       config.Search,
+	}, nil
+}
+
+// verify stamps a trace with a unique marker, pushes it, waits for the configured delay and polls
+// until all of its spans are queryable with their attributes or the timeout expires
+func (v *Verifier) verify(ctx context.Context, trace ptrace.Traces) (*VerificationResult, error) {
+	traceID, spanCount, err := markerTraceInfo(trace)
+	if err != nil {
+		return nil, err
+	}
+	marker, err := stampVerificationMarker(trace)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = v.ingest.pinTenant(ctx)
+	if _, err := v.ingest.push(ctx, trace); err != nil {
+		return nil, fmt.Errorf("failed to push marker trace: %w", err)
+	}
+
+	result := &VerificationResult{
+		TraceID:       traceID,
+		Marker:        marker,
+		ExpectedSpans: spanCount,
+	}
+
+	pushed := time.Now()
+	deadline := pushed.Add(v.delay + v.timeout)
+	if err := sleepContext(ctx, v.delay); err != nil {
+		return nil, err
+	}
+
+	for {
+		result.Attempts++
+		returned, actual, err := v.lookup(ctx, traceID)
+		if err != nil {
+			result.Error = err.Error()
+		} else if returned > 0 {
+			if !result.Found {
+				result.Found = true
+				result.FreshnessMs = time.Since(pushed).Milliseconds()
+			}
+			result.ReturnedSpans = returned
+			result.Error = ""
+			if returned >= spanCount {
+				result.Complete = true
+				diff := CompareTraces(trace, actual)
+				result.MismatchedAttributes = len(diff.MissingAttributes) + len(diff.MutatedAttributes)
+				result.AttributesMatch = result.MismatchedAttributes == 0
+			}
+		}
+		if result.Complete && v.search && !result.Searchable {
+			if result.Searchable, err = v.searchMarker(ctx, traceID, marker); err != nil {
+				result.Error = err.Error()
+			}
+		}
+		if result.Complete && (result.Searchable || !v.search) {
+			break
+		}
+
+		if time.Now().Add(v.pollInterval).After(deadline) {
+			break
+		}
+		if err := sleepContext(ctx, v.pollInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	if spanCount > 0 {
+		result.Completeness = float64(result.ReturnedSpans) / float64(spanCount)
+	}
+	if result.ReturnedSpans < spanCount {
+		result.MissingSpans = spanCount - result.ReturnedSpans
+	}
+	result.Success = result.Complete && result.AttributesMatch && (result.Searchable || !v.search)
+
+	RecordVerification(v.vu.State(), v.metrics, result)
+
+	return result, nil
+}
+
+// lookup fetches a trace by ID as OTLP data and returns the number of distinct spans Tempo returned.
+// A 404 is reported as zero spans so callers keep polling.
+func (v *Verifier) lookup(ctx context.Context, traceID string) (int, ptrace.Traces, error) {
+	trace, resp, err := v.query.getTraceOTLP(ctx, traceID)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return 0, trace, nil
+		}
+		return 0, trace, err
+	}
+
+	// Duplicated spans must not make a partial trace look complete
+	spans, duplicates := countDuplicateSpansOTLP(trace)
+	return spans - duplicates, trace, nil
+}
+
+// searchMarker reports whether a TraceQL search for the marker returns the trace
+func (v *Verifier) searchMarker(ctx context.Context, traceID, marker string) (bool, error) {
+	query := fmt.Sprintf(`{ resource.%s = "%s" }`, VerificationMarkerAttribute, marker)
+	resp, err := v.query.search(ctx, query, QueryOptions{Start: (v.delay + v.timeout + time.Minute).String(), End: "now", Limit: 20})
+	if err != nil {
+		return false, err
+	}
+	// Search results omit leading zeros of trace IDs
+	for _, t := range resp.Traces {
+		if strings.TrimLeft(t.TraceID, "0") == strings.TrimLeft(traceID, "0") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// stampVerificationMarker sets a new random marker on every resource of the trace and returns it
+func stampVerificationMarker(trace ptrace.Traces) (string, error) {
+	id := make([]byte, 8)
+	if _, err := cryptoRand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate marker: %w", err)
+	}
+	marker := "verify-" + hex.EncodeToString(id)
+	for i := 0; i < trace.ResourceSpans().Len(); i++ {
+		trace.ResourceSpans().At(i).Resource().Attributes().PutStr(VerificationMarkerAttribute, marker)
+	}
+	return marker, nil
+}
+
+// markerTraceInfo returns the trace ID and span count of a single-trace payload
+func markerTraceInfo(trace ptrace.Traces) (string, int, error) {
+	var traceID pcommon.TraceID
+	spanCount := 0
+
+	for i := 0; i < trace.ResourceSpans().Len(); i++ {
+		scopeSpans := trace.ResourceSpans().At(i).ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spans := scopeSpans.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				id := spans.At(k).TraceID()
+				if spanCount == 0 {
+					traceID = id
+				} else if id != traceID {
+					return "", 0, fmt.Errorf("marker payload must contain a single trace, found %s and %s", traceID, id)
+				}
+				spanCount++
+			}
+		}
+	}
+
+	if spanCount == 0 {
+		return "", 0, fmt.Errorf("marker trace has no spans")
+	}
+	return traceID.String(), spanCount, nil
+}
+
+// sleepContext sleeps for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// JavaScript-friendly wrapper methods (exported, no context parameter required)
+
+// Verify pushes a marker trace and waits until it is queryable (JavaScript-friendly)
+func (v *Verifier) Verify(trace ptrace.Traces) (*VerificationResult, error) {
+	ctx := vuContext(v.vu)
+	return v.verify(ctx, trace)
+}
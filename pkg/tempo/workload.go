@@ -6,10 +6,15 @@ import (
 	"math"
 	"math/rand"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/rvargasp/xk6-tempo/pkg/generator"
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"go.k6.io/k6/lib"
 	"golang.org/x/time/rate"
 )
 
@@ -17,7 +22,7 @@ import (
 func CreateQueryWorkload(queryClient *QueryClient, vu VU, m *tempoMetrics, workloadConfig map[string]interface{}, queries map[string]interface{}) (*QueryWorkload, error) {
 	// Parse workload config
 	cfg := DefaultQueryWorkloadConfig()
-	if targetQPS, ok := workloadConfig["targetQPS"].(float64); ok {
+	if targetQPS, ok := getFloatValue(workloadConfig["targetQPS"]); ok {
 		cfg.TargetQPS = targetQPS
 	}
 	if burstMult, ok := workloadConfig["burstMultiplier"].(float64); ok {
@@ -26,6 +31,78 @@ func CreateQueryWorkload(queryClient *QueryClient, vu VU, m *tempoMetrics, workl
 	if qpsMult, ok := workloadConfig["qpsMultiplier"].(float64); ok {
 		cfg.QPSMultiplier = qpsMult
 	}
+	if stages, ok := workloadConfig["stages"].([]interface{}); ok {
+		cfg.Stages = make([]QPSStage, 0, len(stages))
+		for i, st := range stages {
+			stMap, ok := st.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("stages[%d]: must be an object", i)
+			}
+			var stage QPSStage
+			if durationMs, ok := getIntValue(stMap["durationMs"]); ok {
+				stage.DurationMs = durationMs
+			}
+			if targetQPS, ok := getFloatValue(stMap["targetQPS"]); ok {
+				stage.TargetQPS = targetQPS
+			}
+			if stage.DurationMs < 0 || stage.TargetQPS <= 0 {
+				return nil, fmt.Errorf("stages[%d]: durationMs must not be negative and targetQPS must be positive", i)
+			}
+			cfg.Stages = append(cfg.Stages, stage)
+		}
+		if len(cfg.Stages) > 0 && cfg.TargetQPS <= 0 {
+			return nil, fmt.Errorf("targetQPS must be positive; stages ramp from it")
+		}
+	}
+	if warmupDuration, ok := getIntValue(workloadConfig["warmupDurationMs"]); ok {
+		cfg.WarmupDurationMs = warmupDuration
+	}
+	if warmupQPS, ok := getFloatValue(workloadConfig["warmupQPS"]); ok {
+		cfg.WarmupQPS = warmupQPS
+	}
+	if suppress, ok := workloadConfig["suppressWarmupMetrics"].(bool); ok {
+		cfg.SuppressWarmupMetrics = suppress
+	}
+	if cfg.WarmupDurationMs < 0 || cfg.WarmupQPS < 0 {
+		return nil, fmt.Errorf("warmupDurationMs and warmupQPS must not be negative")
+	}
+	if adaptive, ok := workloadConfig["adaptive"].(map[string]interface{}); ok {
+		if len(cfg.Stages) > 0 {
+			return nil, fmt.Errorf("stages and adaptive cannot be combined")
+		}
+		adaptiveCfg := DefaultAdaptiveQPSConfig()
+		if targetP95, ok := getFloatValue(adaptive["targetP95Ms"]); ok {
+			adaptiveCfg.TargetP95Ms = targetP95
+		}
+		if maxErrorRate, ok := getFloatValue(adaptive["maxErrorRate"]); ok {
+			adaptiveCfg.MaxErrorRate = maxErrorRate
+		}
+		if intervalMs, ok := getIntValue(adaptive["intervalMs"]); ok {
+			adaptiveCfg.IntervalMs = intervalMs
+		}
+		if minSamples, ok := getIntValue(adaptive["minSamples"]); ok {
+			adaptiveCfg.MinSamples = minSamples
+		}
+		if increase, ok := getFloatValue(adaptive["increaseFactor"]); ok {
+			adaptiveCfg.IncreaseFactor = increase
+		}
+		if decrease, ok := getFloatValue(adaptive["decreaseFactor"]); ok {
+			adaptiveCfg.DecreaseFactor = decrease
+		}
+		if minQPS, ok := getFloatValue(adaptive["minQPS"]); ok {
+			adaptiveCfg.MinQPS = minQPS
+		}
+		if maxQPS, ok := getFloatValue(adaptive["maxQPS"]); ok {
+			adaptiveCfg.MaxQPS = maxQPS
+		}
+		if err := adaptiveCfg.validate(); err != nil {
+			return nil, err
+		}
+		if cfg.TargetQPS <= 0 {
+			return nil, fmt.Errorf("targetQPS must be positive; adaptive starts from it")
+		}
+		cfg.Adaptive = &adaptiveCfg
+	}
 	if enableBackoff, ok := workloadConfig["enableBackoff"].(bool); ok {
 		cfg.EnableBackoff = enableBackoff
 	}
@@ -38,13 +115,43 @@ func CreateQueryWorkload(queryClient *QueryClient, vu VU, m *tempoMetrics, workl
 	if backoffJitter, ok := workloadConfig["backoffJitter"].(bool); ok {
 		cfg.BackoffJitter = backoffJitter
 	}
+	if sharedBackoff, ok := workloadConfig["sharedBackoff"].(bool); ok {
+		cfg.SharedBackoff = sharedBackoff
+	}
 	if traceFetchProb, ok := workloadConfig["traceFetchProbability"].(float64); ok {
 		cfg.TraceFetchProbability = traceFetchProb
 	}
+	if fetchCount, ok := getIntValue(workloadConfig["traceFetchCount"]); ok {
+		cfg.TraceFetchCount = fetchCount
+	}
+	if fetchConcurrency, ok := getIntValue(workloadConfig["traceFetchConcurrency"]); ok {
+		cfg.TraceFetchConcurrency = fetchConcurrency
+	}
+	if cfg.TraceFetchCount < 1 || cfg.TraceFetchConcurrency < 1 {
+		return nil, fmt.Errorf("traceFetchCount and traceFetchConcurrency must be at least 1")
+	}
+	if minAge, ok := getIntValue(workloadConfig["knownTraceMinAgeMs"]); ok {
+		cfg.KnownTraceMinAgeMs = minAge
+	}
 	if timeWindowJitter, ok := workloadConfig["timeWindowJitterMs"].(int); ok {
 		cfg.TimeWindowJitterMs = timeWindowJitter
 	}
 
+	if templateValues, ok := workloadConfig["templateValues"].(map[string]interface{}); ok {
+		cfg.TemplateValues = make(map[string][]string, len(templateValues))
+		for name, v := range templateValues {
+			list, ok := v.([]interface{})
+			if !ok || len(list) == 0 {
+				return nil, fmt.Errorf("templateValues.%s: must be a non-empty array", name)
+			}
+			values := make([]string, 0, len(list))
+			for _, value := range list {
+				values = append(values, fmt.Sprint(value))
+			}
+			cfg.TemplateValues[name] = values
+		}
+	}
+
 	// Parse time buckets
 	if timeBuckets, ok := workloadConfig["timeBuckets"].([]interface{}); ok {
 		cfg.TimeBuckets = make([]TimeBucketConfig, 0, len(timeBuckets))
@@ -65,6 +172,9 @@ func CreateQueryWorkload(queryClient *QueryClient, vu VU, m *tempoMetrics, workl
 				if weight, ok := tbMap["weight"].(float64); ok {
 					bucket.Weight = weight
 				}
+				if headers, ok := tbMap["headers"].(map[string]interface{}); ok {
+					bucket.Headers = parseHeaders(headers)
+				}
 				cfg.TimeBuckets = append(cfg.TimeBuckets, bucket)
 			}
 		}
@@ -87,6 +197,14 @@ func CreateQueryWorkload(queryClient *QueryClient, vu VU, m *tempoMetrics, workl
 				if weight, ok := epMap["weight"].(float64); ok {
 					entry.Weight = weight
 				}
+				if operationType, ok := epMap["operationType"].(string); ok {
+					entry.OperationType = operationType
+				}
+				switch entry.OperationType {
+				case "", OperationSearch, OperationSearchTags, OperationTagValues, OperationMetricsRange, OperationTraceByID:
+				default:
+					return nil, fmt.Errorf("executionPlan: unsupported operationType: %s", entry.OperationType)
+				}
 				cfg.ExecutionPlan = append(cfg.ExecutionPlan, entry)
 			}
 		}
@@ -100,6 +218,9 @@ func CreateQueryWorkload(queryClient *QueryClient, vu VU, m *tempoMetrics, workl
 				Name:  name,
 				Limit: 20,
 			}
+			if queryType, ok := qMap["type"].(string); ok {
+				def.Type = queryType
+			}
 			if query, ok := qMap["query"].(string); ok {
 				def.Query = query
 			}
@@ -109,6 +230,17 @@ func CreateQueryWorkload(queryClient *QueryClient, vu VU, m *tempoMetrics, workl
 			if options, ok := qMap["options"].(map[string]interface{}); ok {
 				def.Options = options
 			}
+			if headers, ok := qMap["headers"].(map[string]interface{}); ok {
+				def.Headers = parseHeaders(headers)
+			}
+			if tag, ok := qMap["tag"].(string); ok {
+				def.Tag = tag
+			}
+			switch def.Type {
+			case "", QueryTypeSearch, QueryTypeMetricsInstant:
+			default:
+				return nil, fmt.Errorf("query %s: unsupported type: %s (use '%s' or '%s')", name, def.Type, QueryTypeSearch, QueryTypeMetricsInstant)
+			}
 			queryDefs[name] = def
 		}
 	}
@@ -137,6 +269,30 @@ type QueryWorkload struct {
 	planIndex       int
 	planMutex       sync.Mutex
 	metrics         *tempoMetrics
+	templateRng     *rand.Rand          // Picks placeholder values; a workload belongs to one VU
+	adaptive        *adaptiveController // nil unless config.Adaptive is set
+	sharedBackoff   *backoffCoordinator // nil unless config.SharedBackoff is set
+	stats           WorkloadStats
+	statsMutex      sync.Mutex
+	warmupDone      bool      // Set once the rate left the warm-up QPS
+	started         sync.Once // Registers the workload's target and adaptive controller when the first query runs
+}
+
+// WorkloadStats counts what a workload executed, for scripts to log progress or check behavior mid-test
+type WorkloadStats struct {
+	Queries          int64                     `js:"queries"`          // Queries run
+	Errors           int64                     `js:"errors"`           // Queries that failed
+	TraceFetches     int64                     `js:"traceFetches"`     // Trace fetches, after searches and of known traces
+	TraceFetchErrors int64                     `js:"traceFetchErrors"` // Trace fetches that failed
+	BackoffMs        float64                   `js:"backoffMs"`        // Current backoff
+	ByQuery          map[string]*WorkloadCount `js:"byQuery"`          // Queries by query name
+	ByBucket         map[string]*WorkloadCount `js:"byBucket"`         // Queries by time bucket; runs on the default time range are not included
+}
+
+// WorkloadCount counts the queries of one query name or time bucket
+type WorkloadCount struct {
+	Queries int64 `js:"queries"`
+	Errors  int64 `js:"errors"`
 }
 
 // WorkloadState holds k6 VU for metrics in workload
@@ -154,6 +310,9 @@ func NewQueryWorkload(
 ) *QueryWorkload {
 	// Calculate per-VU QPS (k6 handles VU distribution, so we use target QPS directly)
 	perVUQPS := config.TargetQPS * config.QPSMultiplier
+	if config.WarmupDurationMs > 0 && config.WarmupQPS > 0 {
+		perVUQPS = config.WarmupQPS * config.QPSMultiplier
+	}
 	burstSize := int(perVUQPS * config.BurstMultiplier)
 	if burstSize < 1 {
 		burstSize = 1
@@ -161,6 +320,16 @@ func NewQueryWorkload(
 
 	limiter := rate.NewLimiter(rate.Limit(perVUQPS), burstSize)
 
+	var adaptive *adaptiveController
+	if config.Adaptive != nil {
+		adaptive = newAdaptiveController(*config.Adaptive, config.TargetQPS)
+		onTestEnd(state.VU, adaptive.release)
+	}
+	var sharedBackoff *backoffCoordinator
+	if config.SharedBackoff && queryClient != nil {
+		sharedBackoff = getBackoffCoordinator(queryClient.baseURL)
+	}
+
 	return &QueryWorkload{
 		config:        config,
 		queryClient:   queryClient,
@@ -169,12 +338,23 @@ func NewQueryWorkload(
 		rateLimiter:   limiter,
 		testStartTime: time.Now(),
 		metrics:       m,
+		templateRng:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		adaptive:      adaptive,
+		sharedBackoff: sharedBackoff,
+		stats: WorkloadStats{
+			ByQuery:  make(map[string]*WorkloadCount),
+			ByBucket: make(map[string]*WorkloadCount),
+		},
 	}
 }
 
 // executeNext executes the next query from the execution plan (internal, requires context)
 func (qw *QueryWorkload) executeNext(ctx context.Context) (*SearchResponse, error) {
-	// Wait for rate limiter
+	// After the warm-up, follow the ramping schedule or the adaptive controller, then wait for rate limiter
+	if !qw.applyWarmup() {
+		qw.applySchedule()
+		qw.applyAdaptive()
+	}
 	if err := qw.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
 	}
@@ -188,27 +368,51 @@ func (qw *QueryWorkload) executeNext(ctx context.Context) (*SearchResponse, erro
 		return nil, fmt.Errorf("no eligible plan entry found")
 	}
 
+	// Trace by ID needs a recorded trace rather than a query
+	if planEntry.OperationType == OperationTraceByID {
+		tracked, ok := GetTraceRegistry().Random(time.Duration(qw.config.KnownTraceMinAgeMs) * time.Millisecond)
+		if !ok {
+			return nil, nil
+		}
+		_, err := qw.fetchKnownTrace(ctx, tracked, OperationTraceByID)
+		return nil, err
+	}
+
 	// Get query definition
 	queryDef, ok := qw.queries[planEntry.QueryName]
 	if !ok {
 		return nil, fmt.Errorf("query definition not found: %s", planEntry.QueryName)
 	}
+	operation := queryOperation(&queryDef, planEntry.OperationType)
 
-	// Get time bucket
-	bucket, err := qw.getTimeBucket(planEntry.BucketName)
-	if err != nil {
-		return nil, fmt.Errorf("time bucket not found: %s: %w", planEntry.BucketName, err)
+	// Get time bucket; entries without one pick a bucket by weight
+	elapsed := time.Since(qw.testStartTime)
+	var bucket *TimeBucketConfig
+	var err error
+	if planEntry.BucketName == "" {
+		bucket, err = qw.selectTimeBucket(elapsed)
+		if err != nil {
+			return nil, err
+		}
+		if bucket == nil {
+			// No bucket reachable yet, fall back to the default time range
+			return qw.executeWithDefaultTimeRange(ctx, &queryDef, operation)
+		}
+	} else {
+		bucket, err = qw.getTimeBucket(planEntry.BucketName)
+		if err != nil {
+			return nil, fmt.Errorf("time bucket not found: %s: %w", planEntry.BucketName, err)
+		}
 	}
 
 	// Calculate time range
-	elapsed := time.Since(qw.testStartTime)
 	start, end, eligible, err := bucket.ParseTimeRanges(elapsed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse time bucket: %w", err)
 	}
 	if !eligible {
-		// Try to find an eligible bucket or use default
-		return qw.executeWithDefaultTimeRange(ctx, &queryDef)
+		// Bucket not reachable yet, fall back to the default time range
+		return qw.executeWithDefaultTimeRange(ctx, &queryDef, operation)
 	}
 
 	// Apply bidirectional jitter to shift the entire time window (defeat caching)
@@ -221,18 +425,72 @@ func (qw *QueryWorkload) executeNext(ctx context.Context) (*SearchResponse, erro
 
 	// Build query options
 	options := QueryOptions{
-		Start: fmt.Sprintf("%d", start.UnixNano()),
-		End:   fmt.Sprintf("%d", end.UnixNano()),
-		Limit: queryDef.Limit,
+		Start:   fmt.Sprintf("%d", start.UnixNano()),
+		End:     fmt.Sprintf("%d", end.UnixNano()),
+		Limit:   queryDef.Limit,
+		Params:  queryDef.params(),
+		Headers: mergeHeaders(bucket.Headers, queryDef.Headers),
+	}
+
+	return qw.runQuery(ctx, &queryDef, operation, bucket.Name, options)
+}
+
+// queryOperation returns the operation a plan entry runs with a query definition; search entries
+// run the definition by its type
+func queryOperation(queryDef *QueryDefinition, operationType string) string {
+	if operationType != "" && operationType != OperationSearch {
+		return operationType
+	}
+	if queryDef.Type == QueryTypeMetricsInstant {
+		return QueryTypeMetricsInstant
 	}
+	return OperationSearch
+}
+
+// runQuery executes an operation of a query definition, records metrics and updates backoff state.
+// The search response is nil for other operations. bucketName is empty when the query runs outside
+// of a time bucket.
+func (qw *QueryWorkload) runQuery(ctx context.Context, queryDef *QueryDefinition, operation, bucketName string, options QueryOptions) (*SearchResponse, error) {
 	if options.Limit == 0 {
 		options.Limit = 20
 	}
 
-	// Execute search with HTTP response info
+	// targetQPS applies per VU, so the test's target and the adaptive totals sum over the workloads
+	// that run queries; workloads of the init context never do
+	qw.started.Do(func() {
+		testSummary.addQueryTarget(qw.config.TargetQPS)
+		if qw.adaptive != nil {
+			qw.adaptive.register()
+		}
+	})
+
+	ctx, tenant := withRequestTenant(ctx, qw.queryClient.tenants)
+	rt := qw.requestTags(tenant, operation)
+
+	query := qw.expandTemplate(queryDef.Query)
+
+	// Execute query with HTTP response info
+	var result *SearchResponse
+	var httpResp *http.Response
+	var err error
 	searchStart := time.Now()
-	result, httpResp, err := qw.queryClient.searchWithHTTP(ctx, queryDef.Query, options)
+	switch operation {
+	case QueryTypeMetricsInstant:
+		_, httpResp, err = qw.queryClient.metricsQueryInstantWithHTTP(ctx, query, options)
+	case OperationMetricsRange:
+		_, httpResp, err = qw.queryClient.metricsQueryRangeWithHTTP(ctx, query, options)
+	case OperationSearchTags:
+		_, httpResp, err = qw.queryClient.searchTagsWithHTTP(ctx, query, options)
+	case OperationTagValues:
+		_, httpResp, err = qw.queryClient.searchTagValuesWithHTTP(ctx, queryDef.Tag, query, options)
+	default:
+		result, httpResp, err = qw.queryClient.searchWithHTTP(ctx, query, options)
+	}
 	searchDuration := time.Since(searchStart)
+	if qw.adaptive != nil && !qw.inWarmup() {
+		qw.adaptive.observe(searchDuration, err != nil)
+	}
+	qw.countQuery(queryDef.Name, bucketName, err == nil)
 
 	// Record metrics
 	statusCode := 0
@@ -243,30 +501,29 @@ func (qw *QueryWorkload) executeNext(ctx context.Context) (*SearchResponse, erro
 	if result != nil {
 		spans = len(result.Traces)
 	}
-	if qw.state.VU.State() != nil {
-		RecordQueryDetailed(qw.state.VU.State(), qw.metrics, searchDuration, spans, err == nil, planEntry.QueryName, statusCode)
-		RecordTimeBucketQuery(qw.state.VU.State(), qw.metrics, planEntry.BucketName, searchDuration)
+	if !qw.warmupSuppressed() {
+		state := qw.state.VU.State()
+		RecordQueryDetailed(state, qw.metrics, rt, searchDuration, spans, err == nil, queryDef.Name, statusCode)
+		RecordSearchInspection(state, qw.metrics, rt, result)
+		RecordResultFreshness(state, qw.metrics, rt, result, searchWindowEnd(options, searchStart))
+		if bucketName != "" {
+			RecordTimeBucketQuery(state, qw.metrics, rt, bucketName, searchDuration)
+		}
 	}
 
 	// Handle HTTP response for backoff
-	oldBackoff := qw.backoffDuration
+	oldBackoff := qw.GetBackoffDuration()
 	if httpResp != nil {
 		qw.HandleHTTPResponse(httpResp)
-	} else if err != nil {
-		// Error without HTTP response - reset backoff
-		qw.backoffMutex.Lock()
-		qw.backoffDuration = 0
-		qw.backoffMutex.Unlock()
 	} else {
-		// Success - reset backoff
-		qw.backoffMutex.Lock()
-		qw.backoffDuration = 0
-		qw.backoffMutex.Unlock()
+		// Success or error without HTTP response - reset backoff
+		qw.resetBackoff()
 	}
 
 	// Record backoff if it changed
-	if qw.config.EnableBackoff && qw.backoffDuration > oldBackoff && qw.state.VU.State() != nil {
-		RecordBackoff(qw.state.VU.State(), qw.metrics, qw.backoffDuration-oldBackoff)
+	newBackoff := qw.GetBackoffDuration()
+	if state := qw.metricsVUState(); qw.config.EnableBackoff && newBackoff > oldBackoff && state != nil {
+		RecordBackoff(state, qw.metrics, newBackoff-oldBackoff)
 	}
 
 	return result, err
@@ -274,6 +531,9 @@ func (qw *QueryWorkload) executeNext(ctx context.Context) (*SearchResponse, erro
 
 // executeSearchAndFetch executes a search and optionally fetches the full trace (internal, requires context)
 func (qw *QueryWorkload) executeSearchAndFetch(ctx context.Context) error {
+	// Search and fetch go to the same tenant
+	ctx, tenant := withRequestTenant(ctx, qw.queryClient.tenants)
+
 	// Execute search
 	result, err := qw.executeNext(ctx)
 	if err != nil {
@@ -284,32 +544,133 @@ func (qw *QueryWorkload) executeSearchAndFetch(ctx context.Context) error {
 		return nil
 	}
 
-	// Probabilistically fetch trace
+	// Probabilistically fetch the top traces, like a results page loading them
 	if rand.Float64() < qw.config.TraceFetchProbability {
-		traceID := result.Traces[0].TraceID
-		fetchStart := time.Now()
-		_, httpResp, fetchErr := qw.queryClient.getTraceWithHTTP(ctx, traceID)
-		fetchDuration := time.Since(fetchStart)
-
-		// Handle HTTP response for backoff
-		if httpResp != nil {
-			qw.HandleHTTPResponse(httpResp)
+		count := qw.config.TraceFetchCount
+		if count > len(result.Traces) {
+			count = len(result.Traces)
 		}
+		qw.fetchTraces(ctx, tenant, result.Traces[:count])
+	}
 
-		// Record trace fetch metrics
-		metricsState := &MetricsState{
-			State:   qw.state.VU.State(),
-			Metrics: qw.metrics,
-		}
-		if fetchErr != nil {
-			// Record fetch failure but don't fail the whole operation
-			RecordTraceFetch(metricsState, fetchDuration, false)
-		} else {
-			RecordTraceFetch(metricsState, fetchDuration, true)
+	return nil
+}
+
+// fetchTraces fetches traces by ID with up to traceFetchConcurrency requests in flight. Failures
+// are recorded but don't fail the search.
+func (qw *QueryWorkload) fetchTraces(ctx context.Context, tenant string, traces []SearchResult) {
+	metricsState := &MetricsState{
+		State:   qw.metricsVUState(),
+		Metrics: qw.metrics,
+		Tags:    qw.requestTags(tenant, ""),
+	}
+
+	concurrency := qw.config.TraceFetchConcurrency
+	if concurrency > len(traces) {
+		concurrency = len(traces)
+	}
+
+	var wg sync.WaitGroup
+	ids := make(chan string)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for traceID := range ids {
+				qw.applyBackoff(ctx)
+				fetchStart := time.Now()
+				_, httpResp, fetchErr := qw.queryClient.getTraceWithHTTP(ctx, traceID)
+				fetchDuration := time.Since(fetchStart)
+
+				// Handle HTTP response for backoff
+				if httpResp != nil {
+					qw.HandleHTTPResponse(httpResp)
+				}
+				RecordTraceFetch(metricsState, fetchDuration, fetchErr == nil)
+				qw.countTraceFetch(fetchErr == nil)
+			}
+		}()
+	}
+
+	for _, trace := range traces {
+		ids <- trace.TraceID
+	}
+	close(ids)
+	wg.Wait()
+}
+
+// KnownTraceFetch describes a fetch of a trace ID recorded by an ingest client
+type KnownTraceFetch struct {
+	TraceID         string  `js:"traceId"`
+	Tenant          string  `js:"tenant"`
+	RootServiceName string  `js:"rootServiceName"`
+	RootSpanName    string  `js:"rootSpanName"`
+	AgeMs           float64 `js:"ageMs"`      // Time since the trace was pushed
+	Spans           int     `js:"spans"`      // Spans of the fetched trace
+	DurationMs      float64 `js:"durationMs"` // Fetch latency
+}
+
+// executeKnownTraceFetch fetches a random trace ID from the registry that ingest clients with
+// trackTraceIds fill, so the by-ID read path also sees traces no search returned. It returns nil
+// when the registry holds no trace old enough (internal, requires context).
+func (qw *QueryWorkload) executeKnownTraceFetch(ctx context.Context) (*KnownTraceFetch, error) {
+	tracked, ok := GetTraceRegistry().Random(time.Duration(qw.config.KnownTraceMinAgeMs) * time.Millisecond)
+	if !ok {
+		return nil, nil
+	}
+
+	// Wait for rate limiter
+	if err := qw.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter wait failed: %w", err)
+	}
+	qw.applyBackoff(ctx)
+
+	return qw.fetchKnownTrace(ctx, tracked, "")
+}
+
+// fetchKnownTrace fetches a recorded trace from the tenant it was pushed to, records the trace
+// fetch metrics tagged with operation, and updates backoff state
+func (qw *QueryWorkload) fetchKnownTrace(ctx context.Context, tracked TrackedTrace, operation string) (*KnownTraceFetch, error) {
+	if tracked.Tenant != "" {
+		ctx = otlp.WithTenant(ctx, tracked.Tenant)
+	}
+	ctx, tenant := withRequestTenant(ctx, qw.queryClient.tenants)
+
+	fetchStart := time.Now()
+	trace, httpResp, err := qw.queryClient.getTraceWithHTTP(ctx, tracked.TraceID)
+	fetchDuration := time.Since(fetchStart)
+
+	if httpResp != nil {
+		qw.HandleHTTPResponse(httpResp)
+	} else {
+		qw.resetBackoff()
+	}
+	RecordTraceFetch(&MetricsState{
+		State:   qw.metricsVUState(),
+		Metrics: qw.metrics,
+		Tags:    qw.requestTags(tenant, operation),
+	}, fetchDuration, err == nil)
+	qw.countTraceFetch(err == nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch known trace %s: %w", tracked.TraceID, err)
+	}
+
+	spans := 0
+	for _, batch := range trace.Batches {
+		for _, scopeSpans := range batch.ScopeSpans {
+			spans += len(scopeSpans.Spans)
 		}
 	}
 
-	return nil
+	return &KnownTraceFetch{
+		TraceID:         tracked.TraceID,
+		Tenant:          tenant,
+		RootServiceName: tracked.RootServiceName,
+		RootSpanName:    tracked.RootSpanName,
+		AgeMs:           float64(time.Since(tracked.PushedAt).Milliseconds()),
+		Spans:           spans,
+		DurationMs:      float64(fetchDuration.Microseconds()) / 1000,
+	}, nil
 }
 
 // selectPlanEntry selects the next plan entry using weighted random selection
@@ -356,6 +717,191 @@ func (qw *QueryWorkload) selectPlanEntry() *PlanEntry {
 	return &qw.config.ExecutionPlan[0]
 }
 
+// inWarmup reports whether the workload is still in its warm-up phase
+func (qw *QueryWorkload) inWarmup() bool {
+	return time.Since(qw.testStartTime) < time.Duration(qw.config.WarmupDurationMs)*time.Millisecond
+}
+
+// sinceWarmup returns the time since the warm-up ended, or since the start without one
+func (qw *QueryWorkload) sinceWarmup() time.Duration {
+	return time.Since(qw.testStartTime) - time.Duration(qw.config.WarmupDurationMs)*time.Millisecond
+}
+
+// applyWarmup keeps the rate limiter at the warm-up QPS during the warm-up and reports whether it
+// is still running. When it ends, the rate returns to targetQPS and the adaptive controller starts.
+func (qw *QueryWorkload) applyWarmup() bool {
+	if qw.config.WarmupDurationMs == 0 {
+		return false
+	}
+	if qw.inWarmup() {
+		return true
+	}
+	if !qw.warmupDone {
+		qw.warmupDone = true
+		perVUQPS := qw.config.TargetQPS * qw.config.QPSMultiplier
+		now := time.Now()
+		qw.rateLimiter.SetLimitAt(now, rate.Limit(perVUQPS))
+		qw.rateLimiter.SetBurstAt(now, CalculateBurstSize(perVUQPS, qw.config.BurstMultiplier))
+		if qw.adaptive != nil {
+			qw.adaptive.restart(now)
+		}
+	}
+	return false
+}
+
+// requestTags returns the metric tags of a workload request, with the phase when there is a warm-up
+func (qw *QueryWorkload) requestTags(tenant, operation string) RequestTags {
+	rt := qw.queryClient.requestTags(tenant)
+	rt.Operation = operation
+	if qw.config.WarmupDurationMs > 0 {
+		rt.Phase = "steady"
+		if qw.inWarmup() {
+			rt.Phase = "warmup"
+		}
+	}
+	return rt
+}
+
+// metricsVUState returns the VU state to record metrics with; nil while warm-up metrics are suppressed
+func (qw *QueryWorkload) metricsVUState() *lib.State {
+	if qw.warmupSuppressed() {
+		return nil
+	}
+	return qw.state.VU.State()
+}
+
+// warmupSuppressed reports whether metrics of the warm-up are suppressed and it is still running
+func (qw *QueryWorkload) warmupSuppressed() bool {
+	return qw.config.SuppressWarmupMetrics && qw.inWarmup()
+}
+
+// applySchedule sets the rate limiter to the QPS the stages prescribe at this point of the test
+func (qw *QueryWorkload) applySchedule() {
+	if len(qw.config.Stages) == 0 {
+		return
+	}
+	perVUQPS := qw.scheduledQPS(qw.sinceWarmup()) * qw.config.QPSMultiplier
+	if rate.Limit(perVUQPS) == qw.rateLimiter.Limit() {
+		return
+	}
+	now := time.Now()
+	qw.rateLimiter.SetLimitAt(now, rate.Limit(perVUQPS))
+	qw.rateLimiter.SetBurstAt(now, CalculateBurstSize(perVUQPS, qw.config.BurstMultiplier))
+}
+
+// applyAdaptive sets the rate limiter to the adaptive controller's rate once it closed a window
+func (qw *QueryWorkload) applyAdaptive() {
+	if qw.adaptive == nil {
+		return
+	}
+	qps, changed := qw.adaptive.evaluate(time.Now())
+	if !changed {
+		return
+	}
+	perVUQPS := qps * qw.config.QPSMultiplier
+	now := time.Now()
+	qw.rateLimiter.SetLimitAt(now, rate.Limit(perVUQPS))
+	qw.rateLimiter.SetBurstAt(now, CalculateBurstSize(perVUQPS, qw.config.BurstMultiplier))
+	if state := qw.metricsVUState(); state != nil {
+		current, sustainable := adaptiveTotals()
+		RecordAdaptiveQPS(state, qw.metrics, current, sustainable)
+	}
+}
+
+// scheduledQPS interpolates the target QPS of the stages at elapsed; after the last stage its target holds
+func (qw *QueryWorkload) scheduledQPS(elapsed time.Duration) float64 {
+	from := qw.config.TargetQPS
+	for _, stage := range qw.config.Stages {
+		duration := time.Duration(stage.DurationMs) * time.Millisecond
+		if elapsed < duration {
+			return from + (stage.TargetQPS-from)*float64(elapsed)/float64(duration)
+		}
+		elapsed -= duration
+		from = stage.TargetQPS
+	}
+	return from
+}
+
+// selectTimeBucket picks a time bucket using weighted random selection among the buckets whose
+// time range the test has reached. It returns nil when none is reachable yet.
+func (qw *QueryWorkload) selectTimeBucket(elapsed time.Duration) (*TimeBucketConfig, error) {
+	eligible := make([]*TimeBucketConfig, 0, len(qw.config.TimeBuckets))
+	totalWeight := 0.0
+	for i := range qw.config.TimeBuckets {
+		bucket := &qw.config.TimeBuckets[i]
+		_, _, ok, err := bucket.ParseTimeRanges(elapsed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time bucket %s: %w", bucket.Name, err)
+		}
+		if ok {
+			eligible = append(eligible, bucket)
+			totalWeight += bucketWeight(bucket)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil, nil
+	}
+
+	r := rand.Float64() * totalWeight
+	currentWeight := 0.0
+	for _, bucket := range eligible {
+		currentWeight += bucketWeight(bucket)
+		if r <= currentWeight {
+			return bucket, nil
+		}
+	}
+	return eligible[len(eligible)-1], nil
+}
+
+// bucketWeight returns the selection weight of a bucket, treating unset weights as 1.0 like plan entries
+func bucketWeight(bucket *TimeBucketConfig) float64 {
+	if bucket.Weight <= 0 {
+		return 1.0
+	}
+	return bucket.Weight
+}
+
+// countQuery adds a query to the workload stats
+func (qw *QueryWorkload) countQuery(queryName, bucketName string, success bool) {
+	qw.statsMutex.Lock()
+	defer qw.statsMutex.Unlock()
+
+	qw.stats.Queries++
+	counts := []*WorkloadCount{qw.stats.ByQuery[queryName]}
+	if counts[0] == nil {
+		counts[0] = &WorkloadCount{}
+		qw.stats.ByQuery[queryName] = counts[0]
+	}
+	if bucketName != "" {
+		bucket := qw.stats.ByBucket[bucketName]
+		if bucket == nil {
+			bucket = &WorkloadCount{}
+			qw.stats.ByBucket[bucketName] = bucket
+		}
+		counts = append(counts, bucket)
+	}
+	for _, count := range counts {
+		count.Queries++
+	}
+	if !success {
+		qw.stats.Errors++
+		for _, count := range counts {
+			count.Errors++
+		}
+	}
+}
+
+// countTraceFetch adds a trace fetch to the workload stats
+func (qw *QueryWorkload) countTraceFetch(success bool) {
+	qw.statsMutex.Lock()
+	defer qw.statsMutex.Unlock()
+
+	qw.stats.TraceFetches++
+	if !success {
+		qw.stats.TraceFetchErrors++
+	}
+}
+
 // getTimeBucket retrieves a time bucket by name
 func (qw *QueryWorkload) getTimeBucket(name string) (*TimeBucketConfig, error) {
 	for i := range qw.config.TimeBuckets {
@@ -367,64 +913,103 @@ func (qw *QueryWorkload) getTimeBucket(name string) (*TimeBucketConfig, error) {
 }
 
 // executeWithDefaultTimeRange executes a query with default time range
-func (qw *QueryWorkload) executeWithDefaultTimeRange(ctx context.Context, queryDef *QueryDefinition) (*SearchResponse, error) {
+func (qw *QueryWorkload) executeWithDefaultTimeRange(ctx context.Context, queryDef *QueryDefinition, operation string) (*SearchResponse, error) {
 	options := QueryOptions{
-		Start: "1h",
-		End:   "now",
-		Limit: queryDef.Limit,
-	}
-	if options.Limit == 0 {
-		options.Limit = 20
+		Start:   "1h",
+		End:     "now",
+		Limit:   queryDef.Limit,
+		Params:  queryDef.params(),
+		Headers: queryDef.Headers,
 	}
+	return qw.runQuery(ctx, queryDef, operation, "", options)
+}
 
-	searchStart := time.Now()
-	result, httpResp, err := qw.queryClient.searchWithHTTP(ctx, queryDef.Query, options)
-	searchDuration := time.Since(searchStart)
+// templatePlaceholder matches ${name} placeholders in query definitions
+var templatePlaceholder = regexp.MustCompile(`\$\{([A-Za-z0-9_.:/-]+)\}`)
 
-	// Record metrics
-	statusCode := 0
-	if httpResp != nil {
-		statusCode = httpResp.StatusCode
+// expandTemplate replaces ${name} placeholders with a value from templateValues or, for names
+// without a list, from the generator's cardinality pool of the attribute, so that queries match
+// values that are actually ingested. Values are escaped for use inside TraceQL string literals.
+func (qw *QueryWorkload) expandTemplate(query string) string {
+	if !strings.Contains(query, "${") {
+		return query
 	}
-	spans := 0
-	if result != nil {
-		spans = len(result.Traces)
+	return templatePlaceholder.ReplaceAllStringFunc(query, func(placeholder string) string {
+		name := placeholder[2 : len(placeholder)-1]
+		var value string
+		if values, ok := qw.config.TemplateValues[name]; ok {
+			value = values[qw.templateRng.Intn(len(values))]
+		} else {
+			value = generator.GetCardinalityManager().SampleValue(name, qw.templateRng)
+		}
+		quoted := strconv.Quote(value)
+		return quoted[1 : len(quoted)-1]
+	})
+}
+
+// params returns the definition's options as extra query parameters
+func (d *QueryDefinition) params() map[string]string {
+	if len(d.Options) == 0 {
+		return nil
 	}
-	if qw.state.VU.State() != nil {
-		RecordQueryDetailed(qw.state.VU.State(), qw.metrics, searchDuration, spans, err == nil, queryDef.Name, statusCode)
+	params := make(map[string]string, len(d.Options))
+	for key, value := range d.Options {
+		params[key] = fmt.Sprint(value)
 	}
+	return params
+}
 
-	if httpResp != nil {
-		qw.HandleHTTPResponse(httpResp)
-	} else if err != nil {
-		qw.backoffMutex.Lock()
-		qw.backoffDuration = 0
-		qw.backoffMutex.Unlock()
-	} else {
-		qw.backoffMutex.Lock()
-		qw.backoffDuration = 0
-		qw.backoffMutex.Unlock()
+// mergeHeaders combines bucket and query headers; query headers win
+func mergeHeaders(bucket, query map[string]string) map[string]string {
+	if len(bucket) == 0 {
+		return query
 	}
-	return result, err
+	if len(query) == 0 {
+		return bucket
+	}
+	merged := make(map[string]string, len(bucket)+len(query))
+	for key, value := range bucket {
+		merged[key] = value
+	}
+	for key, value := range query {
+		merged[key] = value
+	}
+	return merged
 }
 
-// applyBackoff applies backoff delay if needed
+// resetBackoff clears the current backoff duration
+func (qw *QueryWorkload) resetBackoff() {
+	qw.backoffMutex.Lock()
+	qw.backoffDuration = 0
+	qw.backoffMutex.Unlock()
+}
+
+// applyBackoff applies backoff delay if needed. The delay is read under backoffMutex but waited
+// out without it, so concurrent trace fetches back off in parallel.
 func (qw *QueryWorkload) applyBackoff(ctx context.Context) {
 	if !qw.config.EnableBackoff {
 		return
 	}
 
 	qw.backoffMutex.Lock()
-	defer qw.backoffMutex.Unlock()
+	delay := qw.backoffDuration
+	qw.backoffMutex.Unlock()
 
-	if qw.backoffDuration > 0 {
-		// Add jitter if configured
-		delay := qw.backoffDuration
-		if qw.config.BackoffJitter {
-			jitter := time.Duration(rand.Intn(int(delay.Milliseconds()/10))) * time.Millisecond
-			delay += jitter
+	// Add jitter if configured
+	if delay > 0 && qw.config.BackoffJitter {
+		jitter := time.Duration(rand.Intn(int(delay.Milliseconds()/10)+1)) * time.Millisecond
+		delay += jitter
+	}
+
+	// Wait longer when another VU signaled a longer backoff
+	if qw.sharedBackoff != nil {
+		if shared := qw.sharedBackoff.remaining(); shared > delay {
+			delay = shared
+			RecordSharedBackoffWait(qw.metricsVUState(), qw.metrics, shared)
 		}
+	}
 
+	if delay > 0 {
 		select {
 		case <-ctx.Done():
 			return
@@ -453,6 +1038,7 @@ func (qw *QueryWorkload) HandleHTTPResponse(resp *http.Response) {
 				if qw.backoffDuration > time.Duration(qw.config.MaxBackoffMs)*time.Millisecond {
 					qw.backoffDuration = time.Duration(qw.config.MaxBackoffMs) * time.Millisecond
 				}
+				qw.signalSharedBackoff()
 				return
 			}
 		}
@@ -466,12 +1052,20 @@ func (qw *QueryWorkload) HandleHTTPResponse(resp *http.Response) {
 				qw.backoffDuration = time.Duration(qw.config.MaxBackoffMs) * time.Millisecond
 			}
 		}
+		qw.signalSharedBackoff()
 	} else {
 		// Success - reset backoff
 		qw.backoffDuration = 0
 	}
 }
 
+// signalSharedBackoff passes the current backoff to the other VUs; the caller holds backoffMutex
+func (qw *QueryWorkload) signalSharedBackoff() {
+	if qw.sharedBackoff != nil {
+		qw.sharedBackoff.signal(qw.backoffDuration)
+	}
+}
+
 // GetBackoffDuration returns the current backoff duration
 func (qw *QueryWorkload) GetBackoffDuration() time.Duration {
 	qw.backoffMutex.Lock()
@@ -488,13 +1082,47 @@ func (qw *QueryWorkload) SetQueries(queries map[string]QueryDefinition) {
 
 // ExecuteNext executes the next query from the execution plan (JavaScript-friendly)
 func (qw *QueryWorkload) ExecuteNext() (*SearchResponse, error) {
-	ctx := context.Background()
+	ctx := vuContext(qw.state.VU)
 	return qw.executeNext(ctx)
 }
 
+// ExecuteKnownTraceFetch fetches a random trace ID recorded by ingest clients; it returns null while
+// no recorded trace is old enough (JavaScript-friendly)
+func (qw *QueryWorkload) ExecuteKnownTraceFetch() (*KnownTraceFetch, error) {
+	ctx := vuContext(qw.state.VU)
+	return qw.executeKnownTraceFetch(ctx)
+}
+
+// GetStats returns a snapshot of the queries and trace fetches this workload executed, and its
+// current backoff (JavaScript-friendly)
+func (qw *QueryWorkload) GetStats() *WorkloadStats {
+	qw.statsMutex.Lock()
+	defer qw.statsMutex.Unlock()
+
+	stats := qw.stats
+	stats.BackoffMs = float64(qw.GetBackoffDuration().Microseconds()) / 1000
+	stats.ByQuery = make(map[string]*WorkloadCount, len(qw.stats.ByQuery))
+	for name, count := range qw.stats.ByQuery {
+		c := *count
+		stats.ByQuery[name] = &c
+	}
+	stats.ByBucket = make(map[string]*WorkloadCount, len(qw.stats.ByBucket))
+	for name, count := range qw.stats.ByBucket {
+		c := *count
+		stats.ByBucket[name] = &c
+	}
+	return &stats
+}
+
+// AdaptiveReport returns the rates found by the adaptive controller; the totals cover all VUs, so
+// call it from teardown to get the sustainable QPS of the test (JavaScript-friendly)
+func (qw *QueryWorkload) AdaptiveReport() *AdaptiveQPSReport {
+	return qw.adaptive.report()
+}
+
 // ExecuteSearchAndFetch executes a search and optionally fetches the full trace (JavaScript-friendly)
 func (qw *QueryWorkload) ExecuteSearchAndFetch() error {
-	ctx := context.Background()
+	ctx := vuContext(qw.state.VU)
 	return qw.executeSearchAndFetch(ctx)
 }
 
@@ -0,0 +1,35 @@
+package tempo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryWorkloadApplyBackoffConcurrent(t *testing.T) {
+	const (
+		backoff  = 200 * time.Millisecond
+		fetchers = 4
+	)
+	qw := &QueryWorkload{
+		config:          QueryWorkloadConfig{EnableBackoff: true},
+		backoffDuration: backoff,
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < fetchers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			qw.applyBackoff(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	// Waited out one after the other, the backoffs would take fetchers * backoff
+	if elapsed := time.Since(start); elapsed >= 2*backoff {
+		t.Errorf("%d concurrent backoffs of %v took %v, want them waited out in parallel", fetchers, backoff, elapsed)
+	}
+}
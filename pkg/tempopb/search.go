@@ -0,0 +1,257 @@
+package tempopb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// streamingSearchMethod is the full gRPC method name of tempopb.StreamingQuerier.Search
+const streamingSearchMethod = "/tempopb.StreamingQuerier/Search"
+
+// Field numbers of the tempopb search messages (tempo.proto)
+const (
+	searchRequestLimit           = 4
+	searchRequestStart           = 5
+	searchRequestEnd             = 6
+	searchRequestQuery           = 8
+	searchRequestSpansPerSpanSet = 9
+
+	searchResponseTraces  = 1
+	searchResponseMetrics = 2
+
+	traceTraceID           = 1
+	traceRootServiceName   = 2
+	traceRootTraceName     = 3
+	traceStartTimeUnixNano = 4
+	traceDurationMs        = 5
+
+	metricsInspectedTraces = 1
+	metricsInspectedBytes  = 2
+	metricsTotalBlocks     = 3
+	metricsCompletedJobs   = 4
+	metricsTotalJobs       = 5
+)
+
+// SearchRequest is a tempopb.SearchRequest for a TraceQL query
+type SearchRequest struct {
+	Query           string
+	Start           uint32 // Unix seconds, 0 for Tempo's default
+	End             uint32 // Unix seconds, 0 for Tempo's default
+	Limit           uint32
+	SpansPerSpanSet uint32
+}
+
+// SearchResponse is a tempopb.SearchResponse, one message of a streamed search
+type SearchResponse struct {
+	Traces  []TraceSearchMetadata
+	Metrics SearchMetrics
+}
+
+// TraceSearchMetadata is a trace matching a search; span sets and service stats are not decoded
+type TraceSearchMetadata struct {
+	TraceID           string
+	RootServiceName   string
+	RootTraceName     string
+	StartTimeUnixNano uint64
+	DurationMs        uint32
+}
+
+// SearchMetrics holds the backend work counters of a search
+type SearchMetrics struct {
+	InspectedTraces uint32
+	InspectedBytes  uint64
+	TotalBlocks     uint32
+	CompletedJobs   uint32
+	TotalJobs       uint32
+}
+
+// Marshal encodes the request
+func (r SearchRequest) Marshal() []byte {
+	var b []byte
+	b = appendVarintField(b, searchRequestLimit, uint64(r.Limit))
+	b = appendVarintField(b, searchRequestStart, uint64(r.Start))
+	b = appendVarintField(b, searchRequestEnd, uint64(r.End))
+	if r.Query != "" {
+		b = protowire.AppendTag(b, searchRequestQuery, protowire.BytesType)
+		b = protowire.AppendString(b, r.Query)
+	}
+	b = appendVarintField(b, searchRequestSpansPerSpanSet, uint64(r.SpansPerSpanSet))
+	return b
+}
+
+// UnmarshalSearchResponse decodes a tempopb.SearchResponse
+func UnmarshalSearchResponse(b []byte) (SearchResponse, error) {
+	var resp SearchResponse
+	err := forEachField(b, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch {
+		case num == searchResponseTraces && typ == protowire.BytesType:
+			trace, err := unmarshalTrace(v)
+			if err != nil {
+				return err
+			}
+			resp.Traces = append(resp.Traces, trace)
+		case num == searchResponseMetrics && typ == protowire.BytesType:
+			metrics, err := unmarshalMetrics(v)
+			if err != nil {
+				return err
+			}
+			resp.Metrics = metrics
+		}
+		return nil
+	})
+	return resp, err
+}
+
+// unmarshalTrace decodes a tempopb.TraceSearchMetadata
+func unmarshalTrace(b []byte) (TraceSearchMetadata, error) {
+	var trace TraceSearchMetadata
+	err := forEachField(b, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch num {
+		case traceTraceID:
+			trace.TraceID = string(v)
+		case traceRootServiceName:
+			trace.RootServiceName = string(v)
+		case traceRootTraceName:
+			trace.RootTraceName = string(v)
+		case traceStartTimeUnixNano:
+			trace.StartTimeUnixNano = n
+		case traceDurationMs:
+			trace.DurationMs = uint32(n)
+		}
+		return nil
+	})
+	return trace, err
+}
+
+// unmarshalMetrics decodes a tempopb.SearchMetrics
+func unmarshalMetrics(b []byte) (SearchMetrics, error) {
+	var metrics SearchMetrics
+	err := forEachField(b, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch num {
+		case metricsInspectedTraces:
+			metrics.InspectedTraces = uint32(n)
+		case metricsInspectedBytes:
+			metrics.InspectedBytes = n
+		case metricsTotalBlocks:
+			metrics.TotalBlocks = uint32(n)
+		case metricsCompletedJobs:
+			metrics.CompletedJobs = uint32(n)
+		case metricsTotalJobs:
+			metrics.TotalJobs = uint32(n)
+		}
+		return nil
+	})
+	return metrics, err
+}
+
+// forEachField calls fn for every field of an encoded message with the bytes of length-delimited
+// fields in v and the value of varint fields in n. Other wire types are skipped.
+func forEachField(b []byte, fn func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error) error {
+	for len(b) > 0 {
+		num, typ, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			return protowire.ParseError(tagLen)
+		}
+		b = b[tagLen:]
+
+		var v []byte
+		var n uint64
+		var fieldLen int
+		switch typ {
+		case protowire.BytesType:
+			v, fieldLen = protowire.ConsumeBytes(b)
+		case protowire.VarintType:
+			n, fieldLen = protowire.ConsumeVarint(b)
+		default:
+			fieldLen = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if fieldLen < 0 {
+			return protowire.ParseError(fieldLen)
+		}
+		b = b[fieldLen:]
+
+		if typ == protowire.BytesType || typ == protowire.VarintType {
+			if err := fn(num, typ, v, n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// appendVarintField appends a varint field, omitting zero values like proto3 does
+func appendVarintField(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// StreamingSearch runs a search over Tempo's StreamingQuerier service and calls onResponse
+// for every streamed message, in order. Depending on the Tempo version, a message holds
+// either all results so far or only the traces that are new or changed.
+func StreamingSearch(ctx context.Context, conn *grpc.ClientConn, req SearchRequest, onResponse func(SearchResponse) error) error {
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, streamingSearchMethod, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return fmt.Errorf("failed to start search: %w", err)
+	}
+
+	reqBytes := req.Marshal()
+	if err := stream.SendMsg(&reqBytes); err != nil {
+		return fmt.Errorf("failed to send search request: %w", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return fmt.Errorf("failed to send search request: %w", err)
+	}
+
+	for {
+		var msg []byte
+		if err := stream.RecvMsg(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("search stream failed: %w", err)
+		}
+
+		resp, err := UnmarshalSearchResponse(msg)
+		if err != nil {
+			return fmt.Errorf("failed to decode search response: %w", err)
+		}
+		if err := onResponse(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// rawCodec passes pre-encoded protobuf messages through gRPC unchanged
+type rawCodec struct{}
+
+// Marshal returns the encoded message
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	return *b, nil
+}
+
+// Unmarshal stores the encoded message
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// Name returns "proto" so requests carry the standard application/grpc+proto content type
+func (rawCodec) Name() string {
+	return "proto"
+}
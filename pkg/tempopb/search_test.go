@@ -0,0 +1,180 @@
+package tempopb
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// searchRequestBytes is the encoding of testSearchRequest
+var searchRequestBytes = []byte{
+	0x20, 0x14, // Limit: 20
+	0x28, 0x80, 0xe2, 0xcf, 0xaa, 0x06, // start: 1700000000
+	0x30, 0x90, 0xfe, 0xcf, 0xaa, 0x06, // end: 1700003600
+	0x42, 0x02, '{', '}', // Query: "{}"
+	0x48, 0x03, // SpansPerSpanSet: 3
+}
+
+var testSearchRequest = SearchRequest{
+	Query:           "{}",
+	Start:           1700000000,
+	End:             1700003600,
+	Limit:           20,
+	SpansPerSpanSet: 3,
+}
+
+// searchMetricsBytes is the encoding of testSearchMetrics, plus inspectedSpans, which is not decoded
+var searchMetricsBytes = []byte{
+	0x08, 0x02, // inspectedTraces: 2
+	0x10, 0x80, 0x80, 0x40, // inspectedBytes: 1048576
+	0x18, 0x03, // totalBlocks: 3
+	0x20, 0x04, // completedJobs: 4
+	0x28, 0x05, // totalJobs: 5
+	0x38, 0x0a, // inspectedSpans: 10
+}
+
+var testSearchMetrics = SearchMetrics{
+	InspectedTraces: 2,
+	InspectedBytes:  1048576,
+	TotalBlocks:     3,
+	CompletedJobs:   4,
+	TotalJobs:       5,
+}
+
+// traceSearchMetadataBytes is the encoding of testTraceSearchMetadata, plus a span set, which is
+// not decoded
+var traceSearchMetadataBytes = bytes.Join([][]byte{
+	{0x0a, 0x20}, []byte("4bf92f3577b34da6a3ce929d0e0e4736"), // traceID
+	{0x12, 0x03}, []byte("api"), // rootServiceName
+	{0x1a, 0x07}, []byte("GET /v1"), // rootTraceName
+	{0x20, 0x80, 0x80, 0xa8, 0xb1, 0xe3, 0x9f, 0xe7, 0xcb, 0x17}, // startTimeUnixNano: 1700000000000000000
+	{0x28, 0xe2, 0x09},       // durationMs: 1250
+	{0x3a, 0x02, 0x08, 0x01}, // spanSets: { matched: 1 }
+}, nil)
+
+var testTraceSearchMetadata = TraceSearchMetadata{
+	TraceID:           "4bf92f3577b34da6a3ce929d0e0e4736",
+	RootServiceName:   "api",
+	RootTraceName:     "GET /v1",
+	StartTimeUnixNano: 1700000000000000000,
+	DurationMs:        1250,
+}
+
+// unmarshalSearchRequest decodes a tempopb.SearchRequest, for the round trip of Marshal
+func unmarshalSearchRequest(b []byte) (SearchRequest, error) {
+	var req SearchRequest
+	err := forEachField(b, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		switch num {
+		case searchRequestLimit:
+			req.Limit = uint32(n)
+		case searchRequestStart:
+			req.Start = uint32(n)
+		case searchRequestEnd:
+			req.End = uint32(n)
+		case searchRequestQuery:
+			req.Query = string(v)
+		case searchRequestSpansPerSpanSet:
+			req.SpansPerSpanSet = uint32(n)
+		}
+		return nil
+	})
+	return req, err
+}
+
+func TestSearchRequestMarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		req  SearchRequest
+		want []byte
+	}{
+		{name: "all fields", req: testSearchRequest, want: searchRequestBytes},
+		{name: "zero values omitted", req: SearchRequest{}, want: nil},
+		{name: "query only", req: SearchRequest{Query: "{}"}, want: []byte{0x42, 0x02, '{', '}'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.req.Marshal()
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("Marshal() = % x, want % x", got, tt.want)
+			}
+
+			decoded, err := unmarshalSearchRequest(got)
+			if err != nil {
+				t.Fatalf("decoding the marshaled request: %v", err)
+			}
+			if decoded != tt.req {
+				t.Errorf("round trip = %+v, want %+v", decoded, tt.req)
+			}
+		})
+	}
+}
+
+func TestUnmarshalSearchMetrics(t *testing.T) {
+	got, err := unmarshalMetrics(searchMetricsBytes)
+	if err != nil {
+		t.Fatalf("unmarshalMetrics() error = %v", err)
+	}
+	if got != testSearchMetrics {
+		t.Errorf("unmarshalMetrics() = %+v, want %+v", got, testSearchMetrics)
+	}
+}
+
+func TestUnmarshalSearchResponse(t *testing.T) {
+	var b []byte
+	for i := 0; i < 2; i++ {
+		b = protowire.AppendTag(b, searchResponseTraces, protowire.BytesType)
+		b = protowire.AppendBytes(b, traceSearchMetadataBytes)
+	}
+	b = protowire.AppendTag(b, searchResponseMetrics, protowire.BytesType)
+	b = protowire.AppendBytes(b, searchMetricsBytes)
+	// A fixed64 field of a later Tempo version is skipped
+	b = append(b, 0x79, 1, 2, 3, 4, 5, 6, 7, 8)
+
+	got, err := UnmarshalSearchResponse(b)
+	if err != nil {
+		t.Fatalf("UnmarshalSearchResponse() error = %v", err)
+	}
+	if len(got.Traces) != 2 {
+		t.Fatalf("got %d traces, want 2", len(got.Traces))
+	}
+	for i, trace := range got.Traces {
+		if trace != testTraceSearchMetadata {
+			t.Errorf("trace %d = %+v, want %+v", i, trace, testTraceSearchMetadata)
+		}
+	}
+	if got.Metrics != testSearchMetrics {
+		t.Errorf("metrics = %+v, want %+v", got.Metrics, testSearchMetrics)
+	}
+}
+
+func TestUnmarshalSearchResponseEmpty(t *testing.T) {
+	got, err := UnmarshalSearchResponse(nil)
+	if err != nil {
+		t.Fatalf("UnmarshalSearchResponse() error = %v", err)
+	}
+	if len(got.Traces) != 0 || got.Metrics != (SearchMetrics{}) {
+		t.Errorf("UnmarshalSearchResponse(nil) = %+v, want an empty response", got)
+	}
+}
+
+func TestUnmarshalSearchResponseTruncated(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{name: "truncated tag", b: []byte{0x80}},
+		{name: "truncated length", b: []byte{0x0a, 0x05, 0x0a}},
+		{name: "truncated trace", b: []byte{0x0a, 0x02, 0x0a, 0x05}},
+		{name: "truncated metrics", b: []byte{0x12, 0x01, 0x08}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UnmarshalSearchResponse(tt.b); err == nil {
+				t.Errorf("UnmarshalSearchResponse(% x) succeeded, want an error", tt.b)
+			}
+		})
+	}
+}
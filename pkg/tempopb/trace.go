@@ -0,0 +1,29 @@
+package tempopb
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of tempopb.TraceByIDResponse (tempo.proto)
+const traceByIDResponseTrace = 1
+
+// UnmarshalTraceByIDResponse decodes the trace of a tempopb.TraceByIDResponse, as returned by
+// /api/v2/traces/{id}. tempopb.Trace shares its wire format with OTLP TracesData.
+func UnmarshalTraceByIDResponse(b []byte) (ptrace.Traces, error) {
+	var trace []byte
+	err := forEachField(b, func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error {
+		if num == traceByIDResponseTrace && typ == protowire.BytesType {
+			trace = v
+		}
+		return nil
+	})
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("failed to decode trace response: %w", err)
+	}
+
+	unmarshaler := &ptrace.ProtoUnmarshaler{}
+	return unmarshaler.UnmarshalTraces(trace)
+}
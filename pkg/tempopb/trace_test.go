@@ -0,0 +1,76 @@
+package tempopb
+
+import (
+	"bytes"
+	"testing"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// tracesDataBytes is the OTLP TracesData encoding of a trace with one span, as pdata marshals it
+var tracesDataBytes = []byte{
+	0x0a, 0x2c, // resource_spans
+	0x0a, 0x00, // resource
+	0x12, 0x28, // scope_spans
+	0x0a, 0x00, // scope
+	0x12, 0x24, // spans
+	0x0a, 0x10, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, // trace_id
+	0x12, 0x08, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, // span_id
+	0x22, 0x00, // trace_state
+	0x2a, 0x02, 'o', 'p', // name
+	0x7a, 0x00, // status
+}
+
+func TestUnmarshalTraceByIDResponse(t *testing.T) {
+	b := append([]byte{0x0a, byte(len(tracesDataBytes))}, tracesDataBytes...) // trace
+	b = append(b, 0x18, 0x01)                                                 // status: PARTIAL
+	b = append(b, 0x22, 0x02, 'o', 'k')                                       // message
+
+	traces, err := UnmarshalTraceByIDResponse(b)
+	if err != nil {
+		t.Fatalf("UnmarshalTraceByIDResponse() error = %v", err)
+	}
+	if traces.SpanCount() != 1 {
+		t.Fatalf("got %d spans, want 1", traces.SpanCount())
+	}
+
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	wantTraceID := pcommon.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}
+	if span.TraceID() != wantTraceID {
+		t.Errorf("trace ID = %s, want %s", span.TraceID(), wantTraceID)
+	}
+	wantSpanID := pcommon.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+	if span.SpanID() != wantSpanID {
+		t.Errorf("span ID = %s, want %s", span.SpanID(), wantSpanID)
+	}
+	if span.Name() != "op" {
+		t.Errorf("name = %q, want %q", span.Name(), "op")
+	}
+
+	marshaler := &ptrace.ProtoMarshaler{}
+	got, err := marshaler.MarshalTraces(traces)
+	if err != nil {
+		t.Fatalf("marshaling the decoded trace: %v", err)
+	}
+	if !bytes.Equal(got, tracesDataBytes) {
+		t.Errorf("round trip = % x, want % x", got, tracesDataBytes)
+	}
+}
+
+func TestUnmarshalTraceByIDResponseEmpty(t *testing.T) {
+	traces, err := UnmarshalTraceByIDResponse(nil)
+	if err != nil {
+		t.Fatalf("UnmarshalTraceByIDResponse() error = %v", err)
+	}
+	if traces.SpanCount() != 0 {
+		t.Errorf("got %d spans, want 0", traces.SpanCount())
+	}
+}
+
+func TestUnmarshalTraceByIDResponseTruncated(t *testing.T) {
+	b := append([]byte{0x0a, byte(len(tracesDataBytes))}, tracesDataBytes[:10]...)
+	if _, err := UnmarshalTraceByIDResponse(b); err == nil {
+		t.Errorf("UnmarshalTraceByIDResponse(% x) succeeded, want an error", b)
+	}
+}
@@ -0,0 +1,255 @@
+package zipkin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rvargasp/xk6-tempo/pkg/otlp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// JSONExporter exports traces as Zipkin v2 JSON spans
+type JSONExporter struct {
+	client   *http.Client
+	endpoint string
+	tenant   string
+	headers  map[string]string
+	auth     func(ctx context.Context) (string, error)
+}
+
+// Span is a Zipkin v2 span
+type Span struct {
+	TraceID        string            `json:"traceId"`
+	ID             string            `json:"id"`
+	ParentID       string            `json:"parentId,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	Kind           string            `json:"kind,omitempty"`
+	Timestamp      int64             `json:"timestamp"` // Microseconds since epoch
+	Duration       int64             `json:"duration"`  // Microseconds
+	LocalEndpoint  *Endpoint         `json:"localEndpoint,omitempty"`
+	RemoteEndpoint *Endpoint         `json:"remoteEndpoint,omitempty"`
+	Annotations    []Annotation      `json:"annotations,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+}
+
+// Endpoint is a Zipkin v2 endpoint
+type Endpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+// Annotation is a Zipkin v2 annotation
+type Annotation struct {
+	Timestamp int64  `json:"timestamp"` // Microseconds since epoch
+	Value     string `json:"value"`
+}
+
+// NewJSONExporter creates a new Zipkin JSON exporter
+func NewJSONExporter(endpoint string, tenant string, timeout time.Duration, opts otlp.Options) *JSONExporter {
+	endpoint, opts = otlp.UnixHTTPEndpoint(endpoint, opts)
+
+	// Ensure endpoint ends with /api/v2/spans
+	if endpoint[len(endpoint)-1] != '/' {
+		endpoint += "/"
+	}
+	endpoint += "api/v2/spans"
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	if tenant != "" {
+		headers["X-Scope-OrgID"] = tenant
+	}
+	for key, value := range opts.Headers {
+		headers[key] = value
+	}
+
+	return &JSONExporter{
+		client:   opts.HTTPClient(timeout),
+		endpoint: endpoint,
+		tenant:   tenant,
+		headers:  headers,
+		auth:     opts.Authorization,
+	}
+}
+
+// ExportTraces exports traces to Tempo's Zipkin receiver. Zipkin has no partial success,
+// so the result only carries the payload size.
+func (e *JSONExporter) ExportTraces(ctx context.Context, traces ptrace.Traces) (otlp.ExportResult, error) {
+	data, err := json.Marshal(FromTraces(traces))
+	if err != nil {
+		return otlp.ExportResult{}, fmt.Errorf("failed to marshal spans: %w", err)
+	}
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return otlp.ExportResult{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	for key, value := range e.headers {
+		httpReq.Header.Set(key, value)
+	}
+	if tenant, ok := otlp.TenantFromContext(ctx); ok {
+		httpReq.Header.Set("X-Scope-OrgID", tenant)
+	}
+	if e.auth != nil {
+		authorization, err := e.auth(ctx)
+		if err != nil {
+			return otlp.ExportResult{}, fmt.Errorf("failed to get authorization: %w", err)
+		}
+		httpReq.Header.Set("Authorization", authorization)
+	}
+
+	// Send request
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return otlp.ExportResult{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check status code
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return otlp.ExportResult{PayloadBytes: int64(len(data))}, otlp.NewStatusError(resp, body)
+	}
+
+	return otlp.ExportResult{PayloadBytes: int64(len(data))}, nil
+}
+
+// ExportBatch exports multiple traces in a batch
+func (e *JSONExporter) ExportBatch(ctx context.Context, traces []ptrace.Traces) (otlp.ExportResult, error) {
+	// Combine all traces into a single request
+	combined := ptrace.NewTraces()
+	for _, trace := range traces {
+		// Merge resource spans
+		trace.ResourceSpans().MoveAndAppendTo(combined.ResourceSpans())
+	}
+
+	return e.ExportTraces(ctx, combined)
+}
+
+// Shutdown closes the exporter
+func (e *JSONExporter) Shutdown(ctx context.Context) error {
+	// The HTTP client only holds idle keep-alive connections
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// FromTraces converts OTLP traces to Zipkin v2 spans following the OpenTelemetry Zipkin
+// exporter mapping: resource and span attributes become string tags, events become
+// annotations, and status and instrumentation scope are kept as otel.* tags
+func FromTraces(traces ptrace.Traces) []Span {
+	spans := []Span{}
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+
+		serviceName := "unknown_service"
+		if v, ok := rs.Resource().Attributes().Get("service.name"); ok {
+			serviceName = v.AsString()
+		}
+
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := fromSpan(ss.Spans().At(k), serviceName)
+				putTags(span.Tags, rs.Resource().Attributes(), "service.name")
+				if name := ss.Scope().Name(); name != "" {
+					span.Tags["otel.scope.name"] = name
+				}
+				if version := ss.Scope().Version(); version != "" {
+					span.Tags["otel.scope.version"] = version
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+	return spans
+}
+
+// fromSpan converts a single span
+func fromSpan(s ptrace.Span, serviceName string) Span {
+	span := Span{
+		TraceID:       s.TraceID().String(),
+		ID:            s.SpanID().String(),
+		Name:          s.Name(),
+		Kind:          zipkinKind(s.Kind()),
+		Timestamp:     toMicros(s.StartTimestamp()),
+		Duration:      toMicros(s.EndTimestamp()) - toMicros(s.StartTimestamp()),
+		LocalEndpoint: &Endpoint{ServiceName: serviceName},
+		Tags:          make(map[string]string),
+	}
+	if !s.ParentSpanID().IsEmpty() {
+		span.ParentID = s.ParentSpanID().String()
+	}
+
+	putTags(span.Tags, s.Attributes())
+	if peer, ok := s.Attributes().Get("peer.service"); ok && (span.Kind == "CLIENT" || span.Kind == "PRODUCER") {
+		span.RemoteEndpoint = &Endpoint{ServiceName: peer.AsString()}
+	}
+
+	switch s.Status().Code() {
+	case ptrace.StatusCodeError:
+		span.Tags["otel.status_code"] = "ERROR"
+		span.Tags["error"] = s.Status().Message()
+		if span.Tags["error"] == "" {
+			span.Tags["error"] = "true"
+		}
+	case ptrace.StatusCodeOk:
+		span.Tags["otel.status_code"] = "OK"
+	}
+
+	for i := 0; i < s.Events().Len(); i++ {
+		event := s.Events().At(i)
+		value := event.Name()
+		if event.Attributes().Len() > 0 {
+			attrs, _ := json.Marshal(event.Attributes().AsRaw())
+			value = fmt.Sprintf(`"%s":%s`, event.Name(), attrs)
+		}
+		span.Annotations = append(span.Annotations, Annotation{
+			Timestamp: toMicros(event.Timestamp()),
+			Value:     value,
+		})
+	}
+
+	return span
+}
+
+// putTags copies attributes into tags as strings, skipping the given keys
+func putTags(tags map[string]string, attrs pcommon.Map, skip ...string) {
+	attrs.Range(func(key string, value pcommon.Value) bool {
+		for _, s := range skip {
+			if key == s {
+				return true
+			}
+		}
+		tags[key] = value.AsString()
+		return true
+	})
+}
+
+// zipkinKind maps an OTLP span kind to a Zipkin kind; internal spans have no kind
+func zipkinKind(kind ptrace.SpanKind) string {
+	switch kind {
+	case ptrace.SpanKindClient:
+		return "CLIENT"
+	case ptrace.SpanKindServer:
+		return "SERVER"
+	case ptrace.SpanKindProducer:
+		return "PRODUCER"
+	case ptrace.SpanKindConsumer:
+		return "CONSUMER"
+	default:
+		return ""
+	}
+}
+
+// toMicros converts a timestamp to microseconds since epoch
+func toMicros(ts pcommon.Timestamp) int64 {
+	return int64(ts) / int64(time.Microsecond)
+}